@@ -14,7 +14,13 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	appauthz "github.com/huron-portland/grants-management/internal/application/authz"
+	appcalendar "github.com/huron-portland/grants-management/internal/application/calendar"
+	appdelegation "github.com/huron-portland/grants-management/internal/application/delegation"
 	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/application/sla"
+	appworkflow "github.com/huron-portland/grants-management/internal/application/workflow"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
 	"github.com/huron-portland/grants-management/internal/infrastructure/postgres"
 	"github.com/huron-portland/grants-management/internal/infrastructure/ruvector"
 	httpapi "github.com/huron-portland/grants-management/internal/interfaces/http"
@@ -44,6 +50,13 @@ type Config struct {
 	// Auth settings
 	JWTSecret string
 
+	// SLA scheduler settings
+	EnableSLAScheduler bool
+	SLAScanInterval    time.Duration
+
+	// Effort settings
+	EffortCapPercent int
+
 	// Feature flags
 	EnableProfiling bool
 	LogLevel        string
@@ -75,6 +88,13 @@ func LoadConfig() Config {
 		// Auth
 		JWTSecret: getEnv("JWT_SECRET", "development-secret-change-in-production"),
 
+		// SLA scheduler
+		EnableSLAScheduler: getEnv("ENABLE_SLA_SCHEDULER", "true") == "true",
+		SLAScanInterval:    time.Duration(getEnvInt("SLA_SCAN_INTERVAL_SECONDS", 300)) * time.Second,
+
+		// Effort
+		EffortCapPercent: getEnvInt("EFFORT_CAP_PERCENT", 100),
+
 		// Features
 		EnableProfiling: getEnv("ENABLE_PROFILING", "false") == "true",
 		LogLevel:        getEnv("LOG_LEVEL", "info"),
@@ -120,18 +140,93 @@ func main() {
 
 	// Initialize repositories
 	proposalRepo := postgres.NewProposalRepository(dbPool)
+	budgetRepo := postgres.NewBudgetRepository(dbPool)
+	workflowRepo := postgres.NewWorkflowRepository(dbPool)
+	complianceRepo := postgres.NewComplianceRepository(dbPool)
+	approvalRepo := postgres.NewApprovalRepository(dbPool)
+	delegationRepo := postgres.NewDelegationRepository(dbPool)
+	policyRepo := postgres.NewPolicyRepository(dbPool)
+	snapshotRepo := postgres.NewSnapshotRepository(dbPool)
+	sectionLockRepo := postgres.NewSectionLockRepository(dbPool)
+	numberingRepo := postgres.NewNumberingRepository(dbPool)
+	calendarRepo := postgres.NewCalendarRepository(dbPool)
+	awardRepo := postgres.NewAwardRepository(dbPool)
+	closeoutRepo := postgres.NewCloseoutRepository(dbPool)
+	templateRepo := postgres.NewTemplateRepository(dbPool)
+	rateAgreementRepo := postgres.NewRateAgreementRepository(dbPool)
+	effortLedger := proposal.NewEffortLedger(postgres.NewEffortRepository(dbPool), float64(cfg.EffortCapPercent))
+
+	// Load the stored workflow versions of every tenant
+	workflows := proposal.NewWorkflowRegistry()
+	workflows.UseComplianceChecker(complianceRepo)
+	workflows.UseApprovalRepository(approvalRepo)
+	workflows.UseEffortLedger(effortLedger)
+	workflows.UseAwardRepository(awardRepo)
+	workflows.UseCloseoutRepository(closeoutRepo)
+	workflows.UseBudgetRepository(budgetRepo)
+	if err := workflows.Load(ctx, workflowRepo); err != nil {
+		log.Fatal().Err(err).Msg("Failed to load workflow definitions")
+	}
 
 	// Initialize embedding generator
 	embeddingGenerator := ruvector.NewEmbeddingGenerator(ruVectorClient, 1000)
 
+	// Initialize the policy engine shared by all services
+	authzService := appauthz.NewService(appauthz.ServiceConfig{
+		Policies:    policyRepo,
+		Proposals:   proposalRepo,
+		Workflows:   workflows,
+		Delegations: delegationRepo,
+	})
+
+	// Section lock events reach every replica over LISTEN/NOTIFY
+	lockRelay := postgres.NewLockEventRelay(dbPool)
+	lockBroker := appproposal.NewLockBroker().UseRelay(lockRelay)
+	go lockRelay.Listen(ctx, lockBroker.Receive)
+
 	// Initialize application services
 	proposalService := appproposal.NewService(appproposal.ServiceConfig{
-		Repo:           proposalRepo,
-		EmbedGenerator: embeddingGenerator,
+		Repo:             proposalRepo,
+		BudgetRepo:       budgetRepo,
+		EmbedGenerator:   embeddingGenerator,
+		Workflows:        workflows,
+		Approvals:        approvalRepo,
+		Delegations:      delegationRepo,
+		Authorizer:       authzService,
+		Snapshots:        snapshotRepo,
+		SectionLocks:     sectionLockRepo,
+		LockBroker:       lockBroker,
+		Numbering:        numberingRepo,
+		EffortLedger:     effortLedger,
+		DeadlineCalendar: calendarRepo,
+		Awards:           awardRepo,
+		Closeouts:        closeoutRepo,
+		Templates:        templateRepo,
+		RateAgreements:   rateAgreementRepo,
 	})
+	workflowService := appworkflow.NewService(workflows).UseAuthorizer(authzService)
+	delegationService := appdelegation.NewService(delegationRepo).UseAuthorizer(authzService)
+	calendarService := appcalendar.NewService(calendarRepo, calendarRepo).UseAuthorizer(authzService)
+
+	// Start SLA escalation scheduler; replicas elect a leader via advisory lock
+	if cfg.EnableSLAScheduler {
+		scheduler := sla.NewScheduler(sla.SchedulerConfig{
+			Interval:   cfg.SLAScanInterval,
+			Leader:     postgres.NewAdvisoryLock(dbPool, "sla_scheduler"),
+			Proposals:  proposalRepo,
+			Breaches:   postgres.NewSLABreachRepository(dbPool),
+			Workflows:  workflows,
+			Recipients: postgres.NewPersonRepository(dbPool),
+		})
+		go scheduler.Run(ctx)
+	}
 
 	// Initialize handlers
 	proposalHandler := handlers.NewProposalHandler(proposalService)
+	workflowHandler := handlers.NewWorkflowHandler(workflowService)
+	delegationHandler := handlers.NewDelegationHandler(delegationService)
+	authzHandler := handlers.NewAuthzHandler(authzService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
 
 	// Create router
 	routerCfg := httpapi.RouterConfig{
@@ -145,10 +240,15 @@ func main() {
 		RateLimitReqs:   100,
 		RateLimitWindow: time.Minute,
 		EnableProfiling: cfg.EnableProfiling,
+		Authorizer:      authzService,
 	}
 
 	router := httpapi.NewRouter(routerCfg, httpapi.Handlers{
-		Proposal: proposalHandler,
+		Proposal:   proposalHandler,
+		Workflow:   workflowHandler,
+		Delegation: delegationHandler,
+		Authz:      authzHandler,
+		Calendar:   calendarHandler,
 	})
 
 	// Create HTTP server
@@ -187,6 +287,9 @@ func main() {
 		log.Error().Err(err).Msg("HTTP server shutdown error")
 	}
 
+	// Stop background workers
+	cancel()
+
 	log.Info().Msg("Server shutdown complete")
 }
 
@@ -13,6 +13,7 @@ require (
 	github.com/pgvector/pgvector-go v0.2.1
 	github.com/rs/zerolog v1.33.0
 	github.com/shopspring/decimal v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
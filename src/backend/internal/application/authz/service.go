@@ -0,0 +1,371 @@
+// Package authz provides the application service for tenant authorization policies.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/pkg/policy"
+)
+
+// ErrUnauthorized is returned when a user may not manage or inspect policies.
+var ErrUnauthorized = errors.New("unauthorized to manage policies")
+
+// ErrLockout is returned when a policy change would deny the caller policy management.
+var ErrLockout = errors.New("policy change would revoke your own policy management access")
+
+// ErrActionRequired is returned when an explain request names no action.
+var ErrActionRequired = errors.New("action is required")
+
+// Service decides requests against the built-in and tenant policies and
+// manages the tenant policies.
+type Service struct {
+	repo        authz.PolicyRepository
+	proposals   ports.ProposalRepository
+	workflows   *proposal.WorkflowRegistry
+	delegations proposal.DelegationRepository
+	ttl         time.Duration
+
+	mu    sync.RWMutex
+	cache map[common.TenantID]cachedSet
+}
+
+// cachedSet is a tenant's compiled policy set.
+type cachedSet struct {
+	set      *policy.Set
+	loadedAt time.Time
+}
+
+// ServiceConfig contains configuration for the service.
+type ServiceConfig struct {
+	Policies    authz.PolicyRepository
+	Proposals   ports.ProposalRepository
+	Workflows   *proposal.WorkflowRegistry
+	Delegations proposal.DelegationRepository
+	CacheTTL    time.Duration // how long compiled tenant policies are reused
+}
+
+// NewService creates a new authorization application service.
+func NewService(cfg ServiceConfig) *Service {
+	ttl := cfg.CacheTTL
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	workflows := cfg.Workflows
+	if workflows == nil {
+		workflows = proposal.NewWorkflowRegistry()
+	}
+
+	return &Service{
+		repo:        cfg.Policies,
+		proposals:   cfg.Proposals,
+		workflows:   workflows,
+		delegations: cfg.Delegations,
+		ttl:         ttl,
+		cache:       make(map[common.TenantID]cachedSet),
+	}
+}
+
+// Decide evaluates a request against the policies of the caller's tenant.
+func (s *Service) Decide(ctx context.Context, tenantCtx common.TenantContext, req authz.Request) (*policy.Decision, error) {
+	if s.repo == nil {
+		return authz.Defaults().Decide(ctx, tenantCtx, req)
+	}
+
+	set, err := s.policySet(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	return set.Evaluate(req.Action, authz.Input(tenantCtx, req)), nil
+}
+
+// policySet returns the compiled effective policies of a tenant.
+func (s *Service) policySet(ctx context.Context, tenantID common.TenantID) (*policy.Set, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[tenantID]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < s.ttl {
+		return cached.set, nil
+	}
+
+	stored, err := s.repo.ListPolicies(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+	set, err := policy.NewSet(authz.Effective(stored))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedSet{set: set, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return set, nil
+}
+
+// invalidate drops a tenant's compiled policies.
+func (s *Service) invalidate(tenantID common.TenantID) {
+	s.mu.Lock()
+	delete(s.cache, tenantID)
+	s.mu.Unlock()
+}
+
+// authorize checks a policy management action.
+func (s *Service) authorize(ctx context.Context, tenantCtx common.TenantContext, action string) error {
+	err := authz.Check(ctx, s, tenantCtx, authz.Request{Action: action})
+	if errors.Is(err, authz.ErrDenied) {
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return err
+}
+
+// PolicyView describes an effective or overridden policy of a tenant.
+type PolicyView struct {
+	policy.Policy
+	Source    string     `json:"source"` // "default" or "tenant"
+	Enabled   bool       `json:"enabled"`
+	Overrides bool       `json:"overrides,omitempty"` // replaces a built-in policy
+	UpdatedBy *uuid.UUID `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ListPolicies lists the built-in policies and the caller's tenant policies.
+func (s *Service) ListPolicies(ctx context.Context, tenantCtx common.TenantContext) ([]PolicyView, error) {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionPolicyManage); err != nil {
+		return nil, err
+	}
+
+	var stored []authz.TenantPolicy
+	if s.repo != nil {
+		var err error
+		stored, err = s.repo.ListPolicies(ctx, tenantCtx.TenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load policies: %w", err)
+		}
+	}
+
+	overridden := make(map[string]bool, len(stored))
+	for _, p := range stored {
+		overridden[p.ID] = true
+	}
+
+	views := make([]PolicyView, 0)
+	defaults := make(map[string]bool)
+	for _, p := range authz.DefaultPolicies() {
+		defaults[p.ID] = true
+		if !overridden[p.ID] {
+			views = append(views, PolicyView{Policy: p, Source: "default", Enabled: true})
+		}
+	}
+	for _, p := range stored {
+		p := p
+		views = append(views, PolicyView{
+			Policy:    p.Policy,
+			Source:    "tenant",
+			Enabled:   p.Enabled,
+			Overrides: defaults[p.ID],
+			UpdatedBy: &p.UpdatedBy,
+			UpdatedAt: &p.UpdatedAt,
+		})
+	}
+	return views, nil
+}
+
+// SavePolicyCommand represents the command to create or replace a tenant policy.
+type SavePolicyCommand struct {
+	ID          string        `json:"id"`
+	Description string        `json:"description,omitempty"`
+	Effect      policy.Effect `json:"effect"`
+	Actions     []string      `json:"actions"`
+	Condition   string        `json:"condition,omitempty"`
+	Enabled     *bool         `json:"enabled,omitempty"` // defaults to true
+}
+
+// SavePolicy creates or replaces a tenant policy. A policy with the ID of a
+// built-in policy overrides it; saving it disabled switches the built-in off.
+func (s *Service) SavePolicy(ctx context.Context, tenantCtx common.TenantContext, cmd SavePolicyCommand) (*authz.TenantPolicy, error) {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionPolicyManage); err != nil {
+		return nil, err
+	}
+	if s.repo == nil {
+		return nil, errors.New("policy storage not configured")
+	}
+
+	enabled := true
+	if cmd.Enabled != nil {
+		enabled = *cmd.Enabled
+	}
+
+	p := authz.TenantPolicy{
+		Policy: policy.Policy{
+			ID:          cmd.ID,
+			Description: cmd.Description,
+			Effect:      cmd.Effect,
+			Actions:     cmd.Actions,
+			Condition:   cmd.Condition,
+		},
+		Enabled:   enabled,
+		UpdatedBy: tenantCtx.UserID,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkLockout(ctx, tenantCtx, p.ID, &p); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SavePolicy(ctx, tenantCtx.TenantID, p); err != nil {
+		return nil, fmt.Errorf("failed to save policy: %w", err)
+	}
+	s.invalidate(tenantCtx.TenantID)
+
+	return &p, nil
+}
+
+// checkLockout rejects replacing (or, with a nil replacement, deleting) a
+// tenant policy if the caller could no longer manage policies afterwards.
+func (s *Service) checkLockout(ctx context.Context, tenantCtx common.TenantContext, id string, replacement *authz.TenantPolicy) error {
+	stored, err := s.repo.ListPolicies(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load policies: %w", err)
+	}
+
+	var next []authz.TenantPolicy
+	if replacement != nil {
+		next = append(next, *replacement)
+	}
+	for _, p := range stored {
+		if p.ID != id {
+			next = append(next, p)
+		}
+	}
+
+	set, err := policy.NewSet(authz.Effective(next))
+	if err != nil {
+		return err
+	}
+	if !set.Evaluate(authz.ActionPolicyManage, authz.Input(tenantCtx, authz.Request{Action: authz.ActionPolicyManage})).Allowed {
+		return ErrLockout
+	}
+	return nil
+}
+
+// DeletePolicy removes a tenant policy, restoring the built-in policy it overrode.
+func (s *Service) DeletePolicy(ctx context.Context, tenantCtx common.TenantContext, id string) error {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionPolicyManage); err != nil {
+		return err
+	}
+	if s.repo == nil {
+		return authz.ErrPolicyNotFound
+	}
+	if err := s.checkLockout(ctx, tenantCtx, id, nil); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeletePolicy(ctx, tenantCtx.TenantID, id); err != nil {
+		return err
+	}
+	s.invalidate(tenantCtx.TenantID)
+
+	return nil
+}
+
+// ExplainCommand describes a request to explain.
+type ExplainCommand struct {
+	Action string `json:"action"`
+
+	// ProposalID loads the proposal as the resource.
+	ProposalID *uuid.UUID `json:"proposal_id,omitempty"`
+
+	// Transition, with a proposal, explains firing it from the current state.
+	Transition proposal.ProposalTransition `json:"transition,omitempty"`
+
+	// Resource and Context supply attributes directly.
+	Resource map[string]interface{} `json:"resource,omitempty"`
+	Context  map[string]interface{} `json:"context,omitempty"`
+
+	// Subject explains the decision for another user; requires policy.manage.
+	Subject *SubjectOverride `json:"subject,omitempty"`
+}
+
+// SubjectOverride describes the user a decision is explained for.
+type SubjectOverride struct {
+	UserID     uuid.UUID `json:"user_id"`
+	Roles      []string  `json:"roles"`
+	Department string    `json:"department,omitempty"`
+}
+
+// Explain evaluates a request without performing it and reports which
+// policies held and why the others did not.
+func (s *Service) Explain(ctx context.Context, tenantCtx common.TenantContext, cmd ExplainCommand) (*policy.Decision, error) {
+	subject := tenantCtx
+	if cmd.Subject != nil {
+		if err := s.authorize(ctx, tenantCtx, authz.ActionPolicyManage); err != nil {
+			return nil, err
+		}
+		subject = common.TenantContext{
+			TenantID:   tenantCtx.TenantID,
+			UserID:     cmd.Subject.UserID,
+			Roles:      cmd.Subject.Roles,
+			Department: cmd.Subject.Department,
+		}
+	}
+
+	req := authz.Request{Action: cmd.Action, Resource: cmd.Resource, Context: cmd.Context}
+
+	if cmd.ProposalID != nil {
+		if s.proposals == nil {
+			return nil, proposal.ErrProposalNotFound
+		}
+		prop, err := s.proposals.FindByID(ctx, tenantCtx.TenantID, *cmd.ProposalID)
+		if err != nil {
+			return nil, err
+		}
+		if prop == nil {
+			return nil, proposal.ErrProposalNotFound
+		}
+		req.Resource = prop.PolicyAttributes()
+
+		if cmd.Transition != "" {
+			sm, err := s.workflows.StateMachine(ctx, prop.TenantID, prop.WorkflowVersion)
+			if err != nil {
+				return nil, err
+			}
+			delegated, err := s.delegatedRoles(ctx, subject, prop)
+			if err != nil {
+				return nil, err
+			}
+			req = proposal.TransitionRequest(prop, cmd.Transition, sm.Metadata(prop.State), delegated)
+		}
+	}
+
+	if req.Action == "" {
+		return nil, ErrActionRequired
+	}
+
+	return s.Decide(ctx, subject, req)
+}
+
+// delegatedRoles returns the roles delegated to the subject for a proposal.
+func (s *Service) delegatedRoles(ctx context.Context, subject common.TenantContext, prop *proposal.Proposal) ([]string, error) {
+	if s.delegations == nil {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	delegations, err := s.delegations.FindActiveDelegations(ctx, subject.TenantID, subject.UserID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load delegations: %w", err)
+	}
+	return proposal.DelegatedRoles(delegations, subject.UserID, prop.Department, now), nil
+}
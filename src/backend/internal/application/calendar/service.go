@@ -0,0 +1,286 @@
+// Package calendar provides the application service for deadline rules,
+// holiday calendars and iCal deadline feeds.
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/pkg/ical"
+)
+
+// ErrUnauthorized is returned when a user may not manage the calendar or a feed.
+var ErrUnauthorized = errors.New("unauthorized to manage calendar")
+
+// ErrDeadlineRuleNotFound is returned when a tenant has no deadline rule.
+var ErrDeadlineRuleNotFound = errors.New("deadline rule not found")
+
+// Feed window relative to the time a feed is fetched.
+const (
+	feedLookback  = 30 * 24 * time.Hour
+	feedLookahead = 365 * 24 * time.Hour
+)
+
+// productID identifies this application in iCalendar output.
+const productID = "-//Huron//Grants Management//EN"
+
+// Service provides application-level operations for deadline calendars.
+type Service struct {
+	rules      proposal.DeadlineCalendarRepository
+	feeds      proposal.CalendarFeedRepository
+	authorizer authz.Authorizer
+}
+
+// NewService creates a new calendar application service.
+func NewService(rules proposal.DeadlineCalendarRepository, feeds proposal.CalendarFeedRepository) *Service {
+	return &Service{rules: rules, feeds: feeds}
+}
+
+// UseAuthorizer makes the service decide access with the given policy
+// engine instead of the built-in policies.
+func (s *Service) UseAuthorizer(az authz.Authorizer) *Service {
+	s.authorizer = az
+	return s
+}
+
+// authorize checks an action on a feed, or on the tenant's calendar
+// settings if feed is nil.
+func (s *Service) authorize(ctx context.Context, tenantCtx common.TenantContext, action string, feed *proposal.CalendarFeed) error {
+	req := authz.Request{Action: action}
+	if feed != nil {
+		req.Resource = feed.PolicyAttributes()
+	}
+	err := authz.Check(ctx, s.authorizer, tenantCtx, req)
+	if errors.Is(err, authz.ErrDenied) {
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return err
+}
+
+// GetDeadlineRule returns the tenant's internal deadline rule.
+func (s *Service) GetDeadlineRule(ctx context.Context, tenantCtx common.TenantContext) (*proposal.DeadlineRule, error) {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarManage, nil); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.rules.FindDeadlineRule(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrDeadlineRuleNotFound
+	}
+	return rule, nil
+}
+
+// PutDeadlineRule replaces the tenant's internal deadline rule. Internal
+// deadlines already set are kept; the rule applies when a sponsor deadline
+// is next entered.
+func (s *Service) PutDeadlineRule(ctx context.Context, tenantCtx common.TenantContext, rule proposal.DeadlineRule) (*proposal.DeadlineRule, error) {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarManage, nil); err != nil {
+		return nil, err
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	rule.SponsorTime = rule.SponsorTimeOfDay()
+	if err := s.rules.SaveDeadlineRule(ctx, tenantCtx.TenantID, rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteDeadlineRule removes the tenant's rule so internal deadlines are
+// entered by hand.
+func (s *Service) DeleteDeadlineRule(ctx context.Context, tenantCtx common.TenantContext) error {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarManage, nil); err != nil {
+		return err
+	}
+	return s.rules.DeleteDeadlineRule(ctx, tenantCtx.TenantID)
+}
+
+// ListHolidays returns the tenant's holidays between two dates, inclusive.
+func (s *Service) ListHolidays(ctx context.Context, tenantCtx common.TenantContext, from, to time.Time) ([]proposal.Holiday, error) {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarManage, nil); err != nil {
+		return nil, err
+	}
+	return s.rules.ListHolidays(ctx, tenantCtx.TenantID, from, to)
+}
+
+// PutHolidays adds or renames tenant holidays.
+func (s *Service) PutHolidays(ctx context.Context, tenantCtx common.TenantContext, holidays []proposal.Holiday) error {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarManage, nil); err != nil {
+		return err
+	}
+
+	for _, h := range holidays {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, h := range holidays {
+		if err := s.rules.SaveHoliday(ctx, tenantCtx.TenantID, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteHoliday removes a tenant holiday.
+func (s *Service) DeleteHoliday(ctx context.Context, tenantCtx common.TenantContext, date string) error {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarManage, nil); err != nil {
+		return err
+	}
+	if err := (proposal.Holiday{Date: date, Name: date}).Validate(); err != nil {
+		return err
+	}
+	return s.rules.DeleteHoliday(ctx, tenantCtx.TenantID, date)
+}
+
+// CreateFeedCommand represents the command to create a calendar feed.
+type CreateFeedCommand struct {
+	Scope      proposal.FeedScope `json:"scope"`
+	UserID     *uuid.UUID         `json:"user_id,omitempty"`    // user feeds; defaults to the caller
+	Department string             `json:"department,omitempty"` // department feeds; defaults to the caller's
+}
+
+// CreatedFeed is a new feed with its subscription token, which is not
+// shown again.
+type CreatedFeed struct {
+	*proposal.CalendarFeed
+	Token string `json:"token"`
+	Path  string `json:"path"` // subscription path, relative to the API host
+}
+
+// FeedPath returns the subscription path of a feed token.
+func FeedPath(token string) string {
+	return "/calendar/" + token + ".ics"
+}
+
+// CreateFeed creates a calendar feed. By default users may subscribe to
+// their own and their department's deadlines and research administrators
+// to any.
+func (s *Service) CreateFeed(ctx context.Context, tenantCtx common.TenantContext, cmd CreateFeedCommand) (*CreatedFeed, error) {
+	userID, department := cmd.UserID, cmd.Department
+	switch cmd.Scope {
+	case proposal.FeedScopeUser:
+		if userID == nil {
+			userID = &tenantCtx.UserID
+		}
+	case proposal.FeedScopeDepartment:
+		if department == "" {
+			department = tenantCtx.Department
+		}
+	}
+
+	feed, token, err := proposal.NewCalendarFeed(tenantCtx.TenantID, tenantCtx.UserID, cmd.Scope, userID, department)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarSubscribe, feed); err != nil {
+		return nil, err
+	}
+
+	if err := s.feeds.SaveFeed(ctx, feed, proposal.HashFeedToken(token)); err != nil {
+		return nil, fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+
+	return &CreatedFeed{CalendarFeed: feed, Token: token, Path: FeedPath(token)}, nil
+}
+
+// ListFeeds returns the caller's active feeds.
+func (s *Service) ListFeeds(ctx context.Context, tenantCtx common.TenantContext) ([]*proposal.CalendarFeed, error) {
+	return s.feeds.ListFeeds(ctx, tenantCtx.TenantID, tenantCtx.UserID)
+}
+
+// RevokeFeed disables a feed's URL. By default only its creator or an
+// administrator may revoke it.
+func (s *Service) RevokeFeed(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) error {
+	feed, err := s.feeds.FindFeed(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return err
+	}
+	if feed == nil || feed.RevokedAt != nil {
+		return proposal.ErrCalendarFeedNotFound
+	}
+
+	if err := s.authorize(ctx, tenantCtx, authz.ActionCalendarRevoke, feed); err != nil {
+		return err
+	}
+
+	return s.feeds.RevokeFeed(ctx, tenantCtx.TenantID, id, time.Now().UTC())
+}
+
+// RenderFeed returns the iCalendar document of the feed with the token,
+// covering deadlines from 30 days ago to a year ahead. The token is the
+// only credential; access was decided when the feed was created.
+func (s *Service) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.feeds.FindFeedByToken(ctx, proposal.HashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feed == nil {
+		return nil, proposal.ErrCalendarFeedNotFound
+	}
+
+	now := time.Now().UTC()
+	entries, err := s.feeds.FindDeadlines(ctx, feed, now.Add(-feedLookback), now.Add(feedLookahead))
+	if err != nil {
+		return nil, err
+	}
+
+	cal := ical.Calendar{ProductID: productID, Name: feedName(feed)}
+	for _, e := range entries {
+		cal.Events = append(cal.Events, deadlineEvent(e))
+	}
+
+	var buf bytes.Buffer
+	if _, err := cal.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render calendar feed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// feedName returns the calendar name shown by subscribing apps.
+func feedName(feed *proposal.CalendarFeed) string {
+	switch feed.Scope {
+	case proposal.FeedScopeUser:
+		return "My proposal deadlines"
+	case proposal.FeedScopeDepartment:
+		return feed.Department + " proposal deadlines"
+	default:
+		return "Proposal deadlines"
+	}
+}
+
+// deadlineEvent converts a deadline to an event. Deadlines are written in
+// UTC; the description repeats them in the zone the sponsor quoted.
+func deadlineEvent(e proposal.DeadlineEntry) ical.Event {
+	label := "Sponsor deadline"
+	if e.Kind == proposal.DeadlineInternal {
+		label = "Internal deadline"
+	}
+
+	description := fmt.Sprintf("%s for proposal %s (%s).", label, e.ProposalNumber, e.State)
+	if loc, err := proposal.LoadTimeZone(e.TimeZone); err == nil && e.TimeZone != "" {
+		description += fmt.Sprintf("\nIn %s: %s", e.TimeZone, e.At.In(loc).Format("Mon Jan 2, 2006 3:04 PM MST"))
+	}
+
+	return ical.Event{
+		UID:         fmt.Sprintf("%s-%s@grants-management", e.ProposalID, e.Kind),
+		Stamp:       e.UpdatedAt,
+		Start:       e.At,
+		Summary:     fmt.Sprintf("%s: %s %s", label, e.ProposalNumber, e.Title),
+		Description: description,
+		Categories:  []string{label},
+	}
+}
@@ -0,0 +1,232 @@
+// Package proposal provides approval voting for the proposal application service.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// ErrApprovalsUnavailable is returned when no approval repository is configured.
+var ErrApprovalsUnavailable = errors.New("approvals not available - approval repository not configured")
+
+// ErrAmbiguousApprovalSet is returned when a vote matches several approval sets.
+var ErrAmbiguousApprovalSet = errors.New("several approval sets are active; specify the transition")
+
+// ApprovalCommand represents a vote on the active approval set of a proposal.
+type ApprovalCommand struct {
+	ProposalID uuid.UUID                   `json:"proposal_id"`
+	Transition proposal.ProposalTransition `json:"transition,omitempty"`
+	Decision   proposal.ApprovalDecision   `json:"decision"`
+	Comment    string                      `json:"comment,omitempty"`
+}
+
+// ApprovalResult contains the recorded vote and its effect.
+type ApprovalResult struct {
+	Approval *proposal.Approval          `json:"approval"`
+	Tally    proposal.ApprovalTally      `json:"tally"`
+	Fired    proposal.ProposalTransition `json:"fired,omitempty"`
+	Proposal *proposal.Proposal          `json:"proposal"`
+
+	// FireError reports why the decided set's transition could not be
+	// fired. The vote is recorded regardless, and the transition can be
+	// fired directly once the cause is resolved.
+	FireError string `json:"fire_error,omitempty"`
+}
+
+// ApprovalOverview lists the active approval sets of a proposal and every vote cast on it.
+type ApprovalOverview struct {
+	Active  []proposal.ApprovalTally `json:"active"`
+	History []proposal.Approval      `json:"history"`
+}
+
+// CastApproval records a vote. Once the approval set is decided, its
+// transition (or reject transition) is fired on behalf of the approvers. A
+// failure to fire does not undo the recorded vote and is reported in the
+// result.
+func (s *Service) CastApproval(ctx context.Context, tenantCtx common.TenantContext, cmd ApprovalCommand) (*ApprovalResult, error) {
+	if s.approvals == nil {
+		return nil, ErrApprovalsUnavailable
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, cmd.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, errors.New("proposal not found")
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalApprove, prop); err != nil {
+		return nil, err
+	}
+
+	sm, err := s.workflows.StateMachine(ctx, prop.TenantID, prop.WorkflowVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := selectApprovalSet(sm.ActiveApprovalSets(prop), cmd.Transition)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.approvals.FindApprovals(ctx, tenantCtx.TenantID, prop.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load approvals: %w", err)
+	}
+
+	// Approvers may vote under their own roles or roles delegated to them
+	now := time.Now().UTC()
+	var delegations []proposal.Delegation
+	if s.delegations != nil {
+		delegations, err = s.delegations.FindActiveDelegations(ctx, tenantCtx.TenantID, tenantCtx.UserID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load delegations: %w", err)
+		}
+	}
+	roles := append(append([]string(nil), tenantCtx.Roles...), proposal.DelegatedRoles(delegations, tenantCtx.UserID, prop.Department, now)...)
+
+	approval, err := prop.CastApproval(set, votes, tenantCtx.UserID, roles, cmd.Decision, cmd.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if !tenantCtx.HasRole(approval.Role) {
+		if d := proposal.FindDelegation(delegations, tenantCtx.UserID, approval.Role, prop.Department, now); d != nil {
+			approval.OnBehalfOf = &d.DelegatorID
+		}
+	}
+	if err := s.approvals.SaveApproval(ctx, approval); err != nil {
+		return nil, fmt.Errorf("failed to save approval: %w", err)
+	}
+
+	// Log audit event
+	if s.auditLogger != nil {
+		_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+			ID:          uuid.New(),
+			TenantID:    tenantCtx.TenantID,
+			EntityType:  "proposal",
+			EntityID:    prop.ID,
+			Action:      "approval_cast",
+			PerformedBy: tenantCtx.UserID,
+			NewValues: map[string]interface{}{
+				"state":      set.State.String(),
+				"transition": string(set.Transition),
+				"role":       approval.Role,
+				"decision":   string(approval.Decision),
+			},
+		})
+	}
+
+	result := &ApprovalResult{
+		Approval: approval,
+		Tally:    prop.ApprovalTally(set, append(votes, *approval)),
+		Proposal: prop,
+	}
+
+	switch result.Tally.Status {
+	case proposal.ApprovalApproved:
+		result.Fired = set.Transition
+	case proposal.ApprovalRejected:
+		result.Fired = set.RejectTransition
+	}
+	if result.Fired == "" {
+		return result, nil
+	}
+
+	// The decided approval set authorizes the transition
+	updated, err := s.transition(ctx, tenantCtx, TransitionCommand{
+		ProposalID: prop.ID,
+		Transition: result.Fired,
+		Region:     approvalRegion(prop, set),
+		Comment:    "Approval set decided: " + result.Tally.Summary(),
+	}, false)
+	if err != nil {
+		result.FireError = fmt.Sprintf("failed to fire %s after approval: %v", result.Fired, err)
+		result.Fired = ""
+		return result, nil
+	}
+	result.Proposal = updated
+
+	return result, nil
+}
+
+// ListApprovals returns the tallies of the active approval sets of a
+// proposal and its full voting history.
+func (s *Service) ListApprovals(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*ApprovalOverview, error) {
+	if s.approvals == nil {
+		return nil, ErrApprovalsUnavailable
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, errors.New("proposal not found")
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, err
+	}
+
+	sm, err := s.workflows.StateMachine(ctx, prop.TenantID, prop.WorkflowVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.approvals.FindApprovals(ctx, tenantCtx.TenantID, prop.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load approvals: %w", err)
+	}
+
+	overview := &ApprovalOverview{
+		Active:  make([]proposal.ApprovalTally, 0),
+		History: votes,
+	}
+	for _, set := range sm.ActiveApprovalSets(prop) {
+		overview.Active = append(overview.Active, prop.ApprovalTally(set, votes))
+	}
+
+	return overview, nil
+}
+
+// selectApprovalSet picks the approval set a vote applies to.
+func selectApprovalSet(sets []proposal.ApprovalSet, transition proposal.ProposalTransition) (proposal.ApprovalSet, error) {
+	var matches []proposal.ApprovalSet
+	for _, set := range sets {
+		if transition == "" || set.Transition == transition {
+			matches = append(matches, set)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return proposal.ApprovalSet{}, proposal.ErrNoApprovalSet
+	case 1:
+		return matches[0], nil
+	default:
+		return proposal.ApprovalSet{}, ErrAmbiguousApprovalSet
+	}
+}
+
+// approvalRegion returns the region whose active sub-state the approval set
+// gates, or the empty string for the top-level state.
+func approvalRegion(prop *proposal.Proposal, set proposal.ApprovalSet) string {
+	if set.State == prop.State {
+		return ""
+	}
+	for region, state := range prop.SubStates {
+		if state == set.State {
+			return region
+		}
+	}
+	return ""
+}
@@ -0,0 +1,203 @@
+// Package proposal provides award setup and modifications of awarded proposals.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/shopspring/decimal"
+)
+
+// ErrAwardsUnavailable is returned when no award repository is configured.
+var ErrAwardsUnavailable = errors.New("awards not available - award repository not configured")
+
+// AwardDetail is an award with its totals and what its setup still lacks.
+type AwardDetail struct {
+	*proposal.Award
+	TotalObligated   decimal.Decimal `json:"total_obligated"`
+	TotalAnticipated decimal.Decimal `json:"total_anticipated"`
+	SetupComplete    bool            `json:"setup_complete"`
+	SetupIssues      []string        `json:"setup_issues"`
+}
+
+// newAwardDetail describes an award.
+func newAwardDetail(a *proposal.Award) *AwardDetail {
+	return &AwardDetail{
+		Award:            a,
+		TotalObligated:   a.TotalObligated(),
+		TotalAnticipated: a.TotalAnticipated(),
+		SetupComplete:    a.IsSetupComplete(),
+		SetupIssues:      a.SetupIssues(),
+	}
+}
+
+// openAward opens the award of an awarded or active proposal that has none,
+// with budget periods from the proposal's budget. It returns the existing
+// award otherwise.
+func (s *Service) openAward(ctx context.Context, tenantCtx common.TenantContext, prop *proposal.Proposal) (*proposal.Award, error) {
+	if s.awards == nil || !prop.State.HasAward() {
+		return nil, nil
+	}
+
+	var b *budget.Budget
+	if s.budgetRepo != nil {
+		var err error
+		if b, err = s.budgetRepo.FindByProposalID(ctx, tenantCtx.TenantID, prop.ID); err != nil {
+			return nil, fmt.Errorf("failed to find budget: %w", err)
+		}
+	}
+
+	a, err := proposal.OpenAward(ctx, s.awards, prop, b, tenantCtx.UserID)
+	if err != nil || a == nil {
+		return a, err
+	}
+
+	s.logAwardEvent(ctx, tenantCtx, a, "created", map[string]interface{}{"proposal_id": prop.ID.String()})
+	return a, nil
+}
+
+// findAward loads the award of a proposal the actor may read.
+func (s *Service) findAward(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*proposal.Award, error) {
+	if s.awards == nil {
+		return nil, ErrAwardsUnavailable
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, err
+	}
+
+	a, err := s.awards.FindAwardByProposal(ctx, tenantCtx.TenantID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		// Open the award the proposal missed when it was awarded
+		if a, err = s.openAward(ctx, tenantCtx, prop); err != nil {
+			return nil, err
+		}
+	}
+	if a == nil {
+		return nil, proposal.ErrAwardNotFound
+	}
+	return a, nil
+}
+
+// GetAward returns the award of a proposal.
+func (s *Service) GetAward(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*AwardDetail, error) {
+	a, err := s.findAward(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	return newAwardDetail(a), nil
+}
+
+// UpdateAwardSetupCommand represents the command to change an award's setup.
+type UpdateAwardSetupCommand struct {
+	proposal.AwardSetup
+	ExpectedVersion int `json:"expected_version,omitempty"`
+}
+
+// UpdateAwardSetup changes the setup data of a proposal's award.
+func (s *Service) UpdateAwardSetup(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID, cmd UpdateAwardSetupCommand) (*AwardDetail, error) {
+	a, err := s.findAward(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.ExpectedVersion > 0 && a.Version != cmd.ExpectedVersion {
+		return nil, proposal.ErrVersionMismatch
+	}
+	if err := proposal.AuthorizeAward(ctx, s.authorizer, tenantCtx, authz.ActionAwardManage, a); err != nil {
+		return nil, err
+	}
+
+	if err := a.UpdateSetup(tenantCtx.UserID, cmd.AwardSetup); err != nil {
+		return nil, err
+	}
+	if err := s.awards.SaveAward(ctx, a); err != nil {
+		return nil, err
+	}
+
+	s.logAwardEvent(ctx, tenantCtx, a, "setup_updated", nil)
+	return newAwardDetail(a), nil
+}
+
+// CompleteAwardSetup marks a proposal's award as set up, allowing the
+// proposal to be activated.
+func (s *Service) CompleteAwardSetup(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*AwardDetail, error) {
+	a, err := s.findAward(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := proposal.AuthorizeAward(ctx, s.authorizer, tenantCtx, authz.ActionAwardManage, a); err != nil {
+		return nil, err
+	}
+
+	if err := a.CompleteSetup(tenantCtx.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.awards.SaveAward(ctx, a); err != nil {
+		return nil, err
+	}
+
+	s.logAwardEvent(ctx, tenantCtx, a, "setup_completed", map[string]interface{}{
+		"total_obligated": a.TotalObligated().StringFixed(2),
+	})
+	return newAwardDetail(a), nil
+}
+
+// RecordAwardModification records a supplement, no-cost extension or
+// de-obligation on a proposal's award.
+func (s *Service) RecordAwardModification(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID, input proposal.ModificationInput) (*proposal.AwardModification, error) {
+	a, err := s.findAward(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := proposal.AuthorizeAward(ctx, s.authorizer, tenantCtx, authz.ActionAwardManage, a); err != nil {
+		return nil, err
+	}
+
+	m, err := a.Modify(tenantCtx.UserID, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.awards.SaveAward(ctx, a); err != nil {
+		return nil, err
+	}
+
+	s.logAwardEvent(ctx, tenantCtx, a, "modified", map[string]interface{}{
+		"modification": m.Number,
+		"type":         string(m.Type),
+		"amount":       m.Amount.StringFixed(2),
+	})
+	return m, nil
+}
+
+// logAwardEvent records an audit event for an award.
+func (s *Service) logAwardEvent(ctx context.Context, tenantCtx common.TenantContext, a *proposal.Award, action string, values map[string]interface{}) {
+	if s.auditLogger == nil {
+		return
+	}
+	_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+		ID:          uuid.New(),
+		TenantID:    tenantCtx.TenantID,
+		EntityType:  "award",
+		EntityID:    a.ID,
+		Action:      action,
+		PerformedBy: tenantCtx.UserID,
+		NewValues:   values,
+	})
+}
@@ -0,0 +1,444 @@
+// Package proposal provides editing of proposal budgets, their periods and
+// line items.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/shopspring/decimal"
+)
+
+// ErrBudgetsUnavailable is returned when no budget repository is configured.
+var ErrBudgetsUnavailable = errors.New("budgets not available - budget repository not configured")
+
+// BudgetDetail is a budget with the totals the budget calculator derives
+// and the problems it finds.
+type BudgetDetail struct {
+	*budget.Budget
+	Summary      budget.BudgetSummary           `json:"summary"`
+	PeriodTotals []budget.PeriodTotals          `json:"period_totals"`
+	Modular      *budget.ModularBudget          `json:"modular,omitempty"` // modular budgets only
+	CostSharing  budget.CostShareBreakdown      `json:"cost_sharing"`
+	Validation   []budget.BudgetValidationError `json:"validation"`
+}
+
+// newBudgetDetail describes a budget.
+func newBudgetDetail(b *budget.Budget) *BudgetDetail {
+	calc := budget.NewCalculator(b.FARate)
+	detail := &BudgetDetail{
+		Budget:       b,
+		Summary:      b.Summary(),
+		PeriodTotals: calc.CalculatePeriodTotals(b),
+		CostSharing:  calc.CalculateCostShare(b),
+		Validation:   calc.ValidateBudget(b),
+	}
+	if detail.Validation == nil {
+		detail.Validation = []budget.BudgetValidationError{}
+	}
+	if b.IsModular {
+		modular := calc.CalculateModular(b)
+		detail.Modular = &modular
+	}
+	return detail
+}
+
+// BudgetListResult contains a page of budgets.
+type BudgetListResult struct {
+	Budgets []*BudgetDetail `json:"budgets"`
+	Total   int64           `json:"total"`
+	Offset  int             `json:"offset"`
+	Limit   int             `json:"limit"`
+}
+
+// loadBudget loads a budget and its proposal, authorizing the action on the
+// proposal.
+func (s *Service) loadBudget(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID, action string) (*budget.Budget, *proposal.Proposal, error) {
+	if s.budgetRepo == nil {
+		return nil, nil, ErrBudgetsUnavailable
+	}
+
+	b, err := s.budgetRepo.FindByID(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b == nil {
+		return nil, nil, budget.ErrBudgetNotFound
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, b.ProposalID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if prop == nil {
+		return nil, nil, budget.ErrBudgetNotFound
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, action, prop); err != nil {
+		return nil, nil, err
+	}
+	return b, prop, nil
+}
+
+// GetBudget returns a budget with its totals.
+func (s *Service) GetBudget(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) (*BudgetDetail, error) {
+	b, _, err := s.loadBudget(ctx, tenantCtx, id, authz.ActionProposalRead)
+	if err != nil {
+		return nil, err
+	}
+	return newBudgetDetail(b), nil
+}
+
+// ListBudgets retrieves budgets with filtering.
+func (s *Service) ListBudgets(ctx context.Context, tenantCtx common.TenantContext, filter ports.BudgetListFilter) (*BudgetListResult, error) {
+	if s.budgetRepo == nil {
+		return nil, ErrBudgetsUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+
+	budgets, total, err := s.budgetRepo.List(ctx, tenantCtx.TenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BudgetListResult{
+		Budgets: make([]*BudgetDetail, len(budgets)),
+		Total:   total,
+		Offset:  filter.Offset,
+		Limit:   filter.Limit,
+	}
+	for i, b := range budgets {
+		result.Budgets[i] = newBudgetDetail(b)
+	}
+	return result, nil
+}
+
+// CreateBudgetCommand represents the command to create a proposal's budget.
+type CreateBudgetCommand struct {
+	ProposalID           uuid.UUID           `json:"proposal_id"`
+	Currency             string              `json:"currency,omitempty"`
+	FARate               *budget.FARate      `json:"fa_rate,omitempty"`           // nil looks the rate up from a rate agreement
+	RateAgreementID      *uuid.UUID          `json:"rate_agreement_id,omitempty"` // nil selects the tenant's agreement
+	ActivityType         budget.ActivityType `json:"activity_type,omitempty"`     // defaults to research
+	IsOnCampus           *bool               `json:"is_on_campus,omitempty"`
+	IsModular            bool                `json:"is_modular,omitempty"`
+	RequiredMatchPercent decimal.Decimal     `json:"required_match_percent,omitempty"` // sponsor's required cost share, in percent of the request; needs costshare.configure
+	Notes                string              `json:"notes,omitempty"`
+}
+
+// CreateBudget creates the budget of a proposal, with a yearly period for
+// each year of the project period. Unless rates are given, they are looked
+// up from the tenant's rate agreement covering the budget's start, else the
+// default rate is used.
+func (s *Service) CreateBudget(ctx context.Context, tenantCtx common.TenantContext, cmd CreateBudgetCommand) (*BudgetDetail, error) {
+	if s.budgetRepo == nil {
+		return nil, ErrBudgetsUnavailable
+	}
+	if cmd.Currency != "" && len(cmd.Currency) != 3 {
+		return nil, fmt.Errorf("%w: currency must be an ISO 4217 code", budget.ErrInvalidBudget)
+	}
+	if cmd.ActivityType != "" && !cmd.ActivityType.IsValid() {
+		return nil, fmt.Errorf("%w: unknown activity type %q", budget.ErrInvalidBudget, cmd.ActivityType)
+	}
+	if cmd.FARate != nil && cmd.RateAgreementID != nil {
+		return nil, fmt.Errorf("%w: give either F&A rates or a rate agreement", budget.ErrInvalidBudget)
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, cmd.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalUpdate, prop); err != nil {
+		return nil, err
+	}
+	if !prop.CanEdit() {
+		return nil, proposal.ErrProposalNotEditable
+	}
+
+	existing, err := s.findBudget(ctx, tenantCtx, prop)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, budget.ErrBudgetExists
+	}
+
+	var b *budget.Budget
+	if prop.ProjectPeriod.StartDate.IsZero() {
+		b = budget.NewBudget(tenantCtx.TenantID, tenantCtx.UserID, prop.ID, cmd.Currency)
+		if cmd.FARate != nil {
+			b.FARate = *cmd.FARate
+		}
+	} else {
+		skeleton := proposal.BudgetSkeleton{FARate: cmd.FARate, Currency: cmd.Currency}
+		b = skeleton.Build(prop, tenantCtx.UserID)
+	}
+	if b.Currency == "" {
+		b.Currency = "USD"
+	}
+	b.Notes = cmd.Notes
+	b.ActivityType = cmd.ActivityType
+	if cmd.IsOnCampus != nil {
+		b.FARate.IsOnCampus = *cmd.IsOnCampus
+	}
+	if cmd.FARate == nil {
+		if err := s.linkRateAgreement(ctx, tenantCtx.TenantID, b, cmd.RateAgreementID); err != nil {
+			return nil, err
+		}
+		budget.NewCalculator(b.FARate).Recalculate(b)
+	}
+	if err := b.FARate.Validate(); err != nil {
+		return nil, err
+	}
+	if err := b.SetModular(cmd.IsModular); err != nil {
+		return nil, err
+	}
+	if !cmd.RequiredMatchPercent.IsZero() {
+		if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionCostShareConfigure, prop); err != nil {
+			return nil, err
+		}
+		if err := b.SetRequiredMatch(cmd.RequiredMatchPercent); err != nil {
+			return nil, err
+		}
+	}
+
+	// The proposal is saved first so a failed budget save leaves no budget
+	// that the proposal does not know of
+	prop.BudgetID = &b.ID
+	prop.Touch(tenantCtx.UserID)
+	if err := s.repo.Save(ctx, prop); err != nil {
+		return nil, fmt.Errorf("failed to save proposal: %w", err)
+	}
+	if err := s.budgetRepo.Save(ctx, b); err != nil {
+		return nil, fmt.Errorf("failed to save budget: %w", err)
+	}
+
+	s.logBudgetEvent(ctx, tenantCtx, b, "created", map[string]interface{}{"proposal_id": prop.ID.String()})
+	return newBudgetDetail(b), nil
+}
+
+// editBudget applies an edit to a budget, recalculates it and saves it.
+func (s *Service) editBudget(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID, expectedVersion int, action string, edit func(b *budget.Budget) error) (*BudgetDetail, error) {
+	b, prop, err := s.loadBudget(ctx, tenantCtx, id, authz.ActionProposalUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if expectedVersion > 0 && b.Version != expectedVersion {
+		return nil, proposal.ErrVersionMismatch
+	}
+	if !prop.CanEdit() {
+		return nil, proposal.ErrProposalNotEditable
+	}
+	if !b.IsEditable() {
+		return nil, budget.ErrBudgetNotEditable
+	}
+
+	if err := edit(b); err != nil {
+		return nil, err
+	}
+	// Rates follow the budget's dates and activity type
+	if err := s.applyRateAgreement(ctx, tenantCtx.TenantID, b); err != nil {
+		return nil, err
+	}
+	budget.NewCalculator(b.FARate).Recalculate(b)
+	if err := b.CheckModular(); err != nil {
+		return nil, err
+	}
+	b.Touch(tenantCtx.UserID)
+
+	if err := s.budgetRepo.Save(ctx, b); err != nil {
+		return nil, err
+	}
+
+	s.logBudgetEvent(ctx, tenantCtx, b, action, nil)
+	return newBudgetDetail(b), nil
+}
+
+// UpdateBudgetCommand represents the command to change a budget's settings.
+// Unset fields are left unchanged.
+type UpdateBudgetCommand struct {
+	Currency             *string              `json:"currency,omitempty"`
+	FARate               *budget.FARate       `json:"fa_rate,omitempty"`           // replaces the rate agreement
+	RateAgreementID      *uuid.UUID           `json:"rate_agreement_id,omitempty"` // looks the rates up from the agreement
+	ActivityType         *budget.ActivityType `json:"activity_type,omitempty"`
+	IsOnCampus           *bool                `json:"is_on_campus,omitempty"`
+	IsModular            *bool                `json:"is_modular,omitempty"`
+	RequiredMatchPercent *decimal.Decimal     `json:"required_match_percent,omitempty"` // needs costshare.configure
+	Notes                *string              `json:"notes,omitempty"`
+	ExpectedVersion      int                  `json:"expected_version,omitempty"`
+}
+
+// UpdateBudget changes a budget's currency, rates, modular mode, required
+// match and notes.
+// Given F&A rates unlink the budget from its rate agreement.
+func (s *Service) UpdateBudget(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID, cmd UpdateBudgetCommand) (*BudgetDetail, error) {
+	if cmd.FARate != nil && cmd.RateAgreementID != nil {
+		return nil, fmt.Errorf("%w: give either F&A rates or a rate agreement", budget.ErrInvalidBudget)
+	}
+	if cmd.RateAgreementID != nil && s.rateAgreements == nil {
+		return nil, ErrRateAgreementsUnavailable
+	}
+	// The required match is the sponsor's terms, not the budget editor's
+	if cmd.RequiredMatchPercent != nil {
+		if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionCostShareConfigure, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.editBudget(ctx, tenantCtx, id, cmd.ExpectedVersion, "updated", func(b *budget.Budget) error {
+		if cmd.Currency != nil {
+			if len(*cmd.Currency) != 3 {
+				return fmt.Errorf("%w: currency must be an ISO 4217 code", budget.ErrInvalidBudget)
+			}
+			b.Currency = *cmd.Currency
+		}
+		if cmd.ActivityType != nil {
+			if !cmd.ActivityType.IsValid() {
+				return fmt.Errorf("%w: unknown activity type %q", budget.ErrInvalidBudget, *cmd.ActivityType)
+			}
+			b.ActivityType = *cmd.ActivityType
+		}
+		if cmd.FARate != nil {
+			if err := cmd.FARate.Validate(); err != nil {
+				return err
+			}
+			b.FARate = *cmd.FARate
+			b.RateAgreementID = nil
+		}
+		if cmd.RateAgreementID != nil {
+			b.RateAgreementID = cmd.RateAgreementID
+		}
+		if cmd.IsOnCampus != nil {
+			b.FARate.IsOnCampus = *cmd.IsOnCampus
+		}
+		if cmd.IsModular != nil {
+			if err := b.SetModular(*cmd.IsModular); err != nil {
+				return err
+			}
+		}
+		if cmd.RequiredMatchPercent != nil {
+			if err := b.SetRequiredMatch(*cmd.RequiredMatchPercent); err != nil {
+				return err
+			}
+		}
+		if cmd.Notes != nil {
+			b.Notes = *cmd.Notes
+		}
+		return nil
+	})
+}
+
+// AddBudgetPeriodCommand represents the command to add a budget period.
+// Zero dates continue the budget for a year after its last period.
+type AddBudgetPeriodCommand struct {
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	ExpectedVersion int       `json:"expected_version,omitempty"`
+}
+
+// AddBudgetPeriod appends an empty period to a budget.
+func (s *Service) AddBudgetPeriod(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID, cmd AddBudgetPeriodCommand) (*BudgetDetail, error) {
+	return s.editBudget(ctx, tenantCtx, id, cmd.ExpectedVersion, "period_added", func(b *budget.Budget) error {
+		_, err := b.OpenPeriod(cmd.StartDate, cmd.EndDate)
+		return err
+	})
+}
+
+// RemoveBudgetPeriod removes a period and its line items from a budget.
+func (s *Service) RemoveBudgetPeriod(ctx context.Context, tenantCtx common.TenantContext, id, periodID uuid.UUID) (*BudgetDetail, error) {
+	return s.editBudget(ctx, tenantCtx, id, 0, "period_removed", func(b *budget.Budget) error {
+		return b.RemovePeriod(periodID)
+	})
+}
+
+// GetLineItem returns a line item of a budget period.
+func (s *Service) GetLineItem(ctx context.Context, tenantCtx common.TenantContext, id, periodID, itemID uuid.UUID) (*budget.LineItem, error) {
+	b, _, err := s.loadBudget(ctx, tenantCtx, id, authz.ActionProposalRead)
+	if err != nil {
+		return nil, err
+	}
+	period, err := b.Period(periodID)
+	if err != nil {
+		return nil, err
+	}
+	item, err := period.FindLineItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// LineItemCommand represents the command to add or replace a line item.
+// Derived costs are calculated and need not be set.
+type LineItemCommand struct {
+	budget.LineItem
+	ExpectedVersion int `json:"expected_version,omitempty"`
+}
+
+// AddLineItem adds a line item to a budget period.
+func (s *Service) AddLineItem(ctx context.Context, tenantCtx common.TenantContext, id, periodID uuid.UUID, cmd LineItemCommand) (*BudgetDetail, error) {
+	return s.editBudget(ctx, tenantCtx, id, cmd.ExpectedVersion, "line_item_added", func(b *budget.Budget) error {
+		period, err := b.Period(periodID)
+		if err != nil {
+			return err
+		}
+		_, err = period.AddLineItem(cmd.LineItem)
+		return err
+	})
+}
+
+// UpdateLineItem replaces a line item of a budget period.
+func (s *Service) UpdateLineItem(ctx context.Context, tenantCtx common.TenantContext, id, periodID, itemID uuid.UUID, cmd LineItemCommand) (*BudgetDetail, error) {
+	return s.editBudget(ctx, tenantCtx, id, cmd.ExpectedVersion, "line_item_updated", func(b *budget.Budget) error {
+		period, err := b.Period(periodID)
+		if err != nil {
+			return err
+		}
+		_, err = period.UpdateLineItem(itemID, cmd.LineItem)
+		return err
+	})
+}
+
+// RemoveLineItem removes a line item from a budget period.
+func (s *Service) RemoveLineItem(ctx context.Context, tenantCtx common.TenantContext, id, periodID, itemID uuid.UUID) (*BudgetDetail, error) {
+	return s.editBudget(ctx, tenantCtx, id, 0, "line_item_removed", func(b *budget.Budget) error {
+		period, err := b.Period(periodID)
+		if err != nil {
+			return err
+		}
+		return period.RemoveLineItem(itemID)
+	})
+}
+
+// logBudgetEvent records an audit event for a budget.
+func (s *Service) logBudgetEvent(ctx context.Context, tenantCtx common.TenantContext, b *budget.Budget, action string, values map[string]interface{}) {
+	if s.auditLogger == nil {
+		return
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	values["version"] = b.Version
+	values["grand_total"] = b.GrandTotal().String()
+	_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+		ID:          uuid.New(),
+		TenantID:    tenantCtx.TenantID,
+		EntityType:  "budget",
+		EntityID:    b.ID,
+		Action:      action,
+		PerformedBy: tenantCtx.UserID,
+		NewValues:   values,
+	})
+}
@@ -0,0 +1,231 @@
+// Package proposal provides closeout checklists and their templates.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// ErrCloseoutUnavailable is returned when no closeout repository is configured.
+var ErrCloseoutUnavailable = errors.New("closeout not available - closeout repository not configured")
+
+// CloseoutDetail is a closeout checklist with a summary of what remains.
+type CloseoutDetail struct {
+	*proposal.CloseoutChecklist
+	Done        bool `json:"done"`
+	Outstanding int  `json:"outstanding"`
+	Overdue     int  `json:"overdue"`
+}
+
+// newCloseoutDetail describes a checklist as of now.
+func newCloseoutDetail(c *proposal.CloseoutChecklist) *CloseoutDetail {
+	return &CloseoutDetail{
+		CloseoutChecklist: c,
+		Done:              c.IsDone(),
+		Outstanding:       len(c.Outstanding()),
+		Overdue:           len(c.Overdue(time.Now().UTC())),
+	}
+}
+
+// openCloseout generates the closeout checklist of a proposal in closeout
+// that has none, from the template for its sponsor's type. It returns the
+// existing checklist otherwise.
+func (s *Service) openCloseout(ctx context.Context, tenantCtx common.TenantContext, prop *proposal.Proposal) (*proposal.CloseoutChecklist, error) {
+	if s.closeouts == nil || prop.State != proposal.StateCloseout {
+		return nil, nil
+	}
+
+	sponsorType := proposal.DefaultCloseoutSponsorType
+	if s.sponsorRepo != nil {
+		if sponsor, _ := s.sponsorRepo.FindByID(ctx, tenantCtx.TenantID, prop.SponsorID); sponsor != nil && sponsor.Type != "" {
+			sponsorType = sponsor.Type
+		}
+	}
+
+	c, err := proposal.OpenCloseout(ctx, s.closeouts, prop, sponsorType, tenantCtx.UserID)
+	if err != nil || c == nil {
+		return c, err
+	}
+
+	s.logCloseoutEvent(ctx, tenantCtx, c, "created", map[string]interface{}{
+		"proposal_id":  prop.ID.String(),
+		"sponsor_type": c.SponsorType,
+		"items":        len(c.Items),
+	})
+	return c, nil
+}
+
+// findCloseout loads a proposal and its closeout checklist if the actor may
+// read the proposal.
+func (s *Service) findCloseout(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*proposal.Proposal, *proposal.CloseoutChecklist, error) {
+	if s.closeouts == nil {
+		return nil, nil, ErrCloseoutUnavailable
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, proposalID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if prop == nil {
+		return nil, nil, proposal.ErrProposalNotFound
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, nil, err
+	}
+
+	c, err := s.closeouts.FindChecklistByProposal(ctx, tenantCtx.TenantID, proposalID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c == nil {
+		// Generate the checklist the proposal missed when it entered closeout
+		if c, err = s.openCloseout(ctx, tenantCtx, prop); err != nil {
+			return nil, nil, err
+		}
+	}
+	if c == nil {
+		return nil, nil, proposal.ErrCloseoutNotFound
+	}
+	return prop, c, nil
+}
+
+// GetCloseout returns the closeout checklist of a proposal.
+func (s *Service) GetCloseout(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*CloseoutDetail, error) {
+	_, c, err := s.findCloseout(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	return newCloseoutDetail(c), nil
+}
+
+// ResolveCloseoutItemCommand represents the command to change the status of
+// a closeout item.
+type ResolveCloseoutItemCommand struct {
+	Status          proposal.CloseoutItemStatus `json:"status"`
+	Note            string                      `json:"note,omitempty"`
+	ExpectedVersion int                         `json:"expected_version,omitempty"`
+}
+
+// ResolveCloseoutItem completes, waives or reopens an item of a proposal's
+// closeout checklist.
+func (s *Service) ResolveCloseoutItem(ctx context.Context, tenantCtx common.TenantContext, proposalID, itemID uuid.UUID, cmd ResolveCloseoutItemCommand) (*CloseoutDetail, error) {
+	prop, c, err := s.findCloseout(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.ExpectedVersion > 0 && c.Version != cmd.ExpectedVersion {
+		return nil, proposal.ErrVersionMismatch
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionCloseoutManage, prop); err != nil {
+		return nil, err
+	}
+
+	item, err := c.ResolveItem(tenantCtx.UserID, itemID, cmd.Status, cmd.Note)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.closeouts.SaveChecklist(ctx, c); err != nil {
+		return nil, err
+	}
+
+	s.logCloseoutEvent(ctx, tenantCtx, c, "item_resolved", map[string]interface{}{
+		"item_id": item.ID.String(),
+		"title":   item.Title,
+		"status":  string(item.Status),
+		"note":    item.Note,
+	})
+	return newCloseoutDetail(c), nil
+}
+
+// CloseoutTemplateSettings is the template in effect for a sponsor type.
+type CloseoutTemplateSettings struct {
+	proposal.CloseoutTemplate
+	IsDefault bool `json:"is_default"` // built in, not configured by the tenant
+}
+
+// ListCloseoutTemplates returns the template in effect for every sponsor type.
+func (s *Service) ListCloseoutTemplates(ctx context.Context, tenantCtx common.TenantContext) ([]CloseoutTemplateSettings, error) {
+	if s.closeouts == nil {
+		return nil, ErrCloseoutUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionCloseoutConfigure, nil); err != nil {
+		return nil, err
+	}
+
+	configured, err := s.closeouts.ListCloseoutTemplates(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	bySponsorType := make(map[string]proposal.CloseoutTemplate, len(configured))
+	for _, tpl := range configured {
+		bySponsorType[tpl.SponsorType] = tpl
+	}
+
+	sponsorTypes := proposal.CloseoutSponsorTypes()
+	templates := make([]CloseoutTemplateSettings, 0, len(sponsorTypes))
+	for _, sponsorType := range sponsorTypes {
+		if tpl, ok := bySponsorType[sponsorType]; ok {
+			templates = append(templates, CloseoutTemplateSettings{CloseoutTemplate: tpl})
+			continue
+		}
+		tpl := proposal.DefaultCloseoutTemplate(sponsorType)
+		tpl.SponsorType = sponsorType
+		templates = append(templates, CloseoutTemplateSettings{CloseoutTemplate: tpl, IsDefault: true})
+	}
+	return templates, nil
+}
+
+// PutCloseoutTemplate replaces the tenant's template for a sponsor type.
+// Checklists already generated keep their items.
+func (s *Service) PutCloseoutTemplate(ctx context.Context, tenantCtx common.TenantContext, tpl proposal.CloseoutTemplate) (*CloseoutTemplateSettings, error) {
+	if s.closeouts == nil {
+		return nil, ErrCloseoutUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionCloseoutConfigure, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tpl.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.closeouts.SaveCloseoutTemplate(ctx, tenantCtx.TenantID, tpl, tenantCtx.UserID); err != nil {
+		return nil, err
+	}
+	return &CloseoutTemplateSettings{CloseoutTemplate: tpl}, nil
+}
+
+// DeleteCloseoutTemplate removes the tenant's template for a sponsor type,
+// restoring the built-in one.
+func (s *Service) DeleteCloseoutTemplate(ctx context.Context, tenantCtx common.TenantContext, sponsorType string) error {
+	if s.closeouts == nil {
+		return ErrCloseoutUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionCloseoutConfigure, nil); err != nil {
+		return err
+	}
+
+	return s.closeouts.DeleteCloseoutTemplate(ctx, tenantCtx.TenantID, sponsorType)
+}
+
+// logCloseoutEvent records an audit event for a closeout checklist.
+func (s *Service) logCloseoutEvent(ctx context.Context, tenantCtx common.TenantContext, c *proposal.CloseoutChecklist, action string, values map[string]interface{}) {
+	if s.auditLogger == nil {
+		return
+	}
+	_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+		ID:          uuid.New(),
+		TenantID:    tenantCtx.TenantID,
+		EntityType:  "closeout_checklist",
+		EntityID:    c.ID,
+		Action:      action,
+		PerformedBy: tenantCtx.UserID,
+		NewValues:   values,
+	})
+}
@@ -0,0 +1,86 @@
+// Package proposal provides time-zone-aware deadline entry and internal
+// deadlines derived from tenant rules.
+package proposal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// sponsorDeadlineTime returns the time of day of sponsor deadlines entered
+// as a date.
+func (s *Service) sponsorDeadlineTime(ctx context.Context, tenantID common.TenantID) (string, error) {
+	if s.deadlines == nil {
+		return proposal.DefaultSponsorDeadlineTime, nil
+	}
+	rule, err := s.deadlines.FindDeadlineRule(ctx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load deadline rule: %w", err)
+	}
+	if rule == nil {
+		return proposal.DefaultSponsorDeadlineTime, nil
+	}
+	return rule.SponsorTimeOfDay(), nil
+}
+
+// parseSponsorDeadline parses a sponsor deadline entered in the zone the
+// sponsor quotes it in.
+func (s *Service) parseSponsorDeadline(ctx context.Context, tenantID common.TenantID, value, timeZone string) (*time.Time, error) {
+	sponsorTime, err := s.sponsorDeadlineTime(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	deadline, err := proposal.ParseDeadline(value, timeZone, sponsorTime)
+	if err != nil {
+		return nil, err
+	}
+	return &deadline, nil
+}
+
+// deriveInternalDeadline sets the internal deadline from the sponsor
+// deadline under the tenant's rule, unless one was given or the tenant has
+// no rule.
+func (s *Service) deriveInternalDeadline(ctx context.Context, tenantID common.TenantID, sponsor *time.Time, internal **time.Time) error {
+	if s.deadlines == nil || sponsor == nil || *internal != nil {
+		return nil
+	}
+	derived, err := proposal.DeriveInternalDeadline(ctx, s.deadlines, tenantID, *sponsor)
+	if err != nil {
+		return err
+	}
+	*internal = derived
+	return nil
+}
+
+// prepareDeadlineUpdates parses a local sponsor deadline in the update's or
+// the proposal's time zone and derives the internal deadline from a changed
+// sponsor deadline.
+func (s *Service) prepareDeadlineUpdates(ctx context.Context, tenantCtx common.TenantContext, cmd *UpdateCommand) error {
+	if cmd.SponsorDeadlineLocal != nil {
+		timeZone := ""
+		if cmd.Updates.DeadlineTimeZone != nil {
+			timeZone = *cmd.Updates.DeadlineTimeZone
+		} else {
+			prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, cmd.ProposalID)
+			if err != nil {
+				return err
+			}
+			if prop == nil {
+				return proposal.ErrProposalNotFound
+			}
+			timeZone = prop.DeadlineTimeZone
+		}
+
+		deadline, err := s.parseSponsorDeadline(ctx, tenantCtx.TenantID, *cmd.SponsorDeadlineLocal, timeZone)
+		if err != nil {
+			return err
+		}
+		cmd.Updates.SponsorDeadline = deadline
+	}
+
+	return s.deriveInternalDeadline(ctx, tenantCtx.TenantID, cmd.Updates.SponsorDeadline, &cmd.Updates.InternalDeadline)
+}
@@ -0,0 +1,55 @@
+// Package proposal provides per-person effort timelines across proposals and awards.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// ErrEffortUnavailable is returned when no effort ledger is configured.
+var ErrEffortUnavailable = errors.New("effort ledger not available - effort repository not configured")
+
+// ErrPersonNotFound is returned when a person does not exist in the tenant.
+var ErrPersonNotFound = errors.New("person not found")
+
+// EffortTimeline is a person's monthly effort with their details.
+type EffortTimeline struct {
+	*proposal.EffortTimeline
+	Person *ports.Person `json:"person,omitempty"`
+}
+
+// GetEffortTimeline returns a person's monthly effort on awards and pending
+// proposals over the window, flagging months above the tenant's cap.
+func (s *Service) GetEffortTimeline(ctx context.Context, tenantCtx common.TenantContext, personID uuid.UUID, window common.DateRange) (*EffortTimeline, error) {
+	if s.effort == nil {
+		return nil, ErrEffortUnavailable
+	}
+	if err := proposal.AuthorizeEffort(ctx, s.authorizer, tenantCtx, personID); err != nil {
+		return nil, err
+	}
+
+	result := &EffortTimeline{}
+	if s.personRepo != nil {
+		person, err := s.personRepo.FindByID(ctx, tenantCtx.TenantID, personID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find person: %w", err)
+		}
+		if person == nil {
+			return nil, ErrPersonNotFound
+		}
+		result.Person = person
+	}
+
+	timeline, err := s.effort.Timeline(ctx, tenantCtx.TenantID, personID, window)
+	if err != nil {
+		return nil, err
+	}
+	result.EffortTimeline = timeline
+	return result, nil
+}
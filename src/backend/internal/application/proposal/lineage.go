@@ -0,0 +1,138 @@
+// Package proposal provides resubmissions, renewals and supplements for the
+// proposal application service.
+package proposal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// DeriveCommand creates a proposal that follows an earlier one.
+type DeriveCommand struct {
+	PredecessorID uuid.UUID             `json:"predecessor_id"`
+	Type          proposal.ProposalType `json:"proposal_type"`
+}
+
+// Derive creates a resubmission, renewal, continuation or supplement of a
+// proposal. The new draft carries over personnel, keywords and a copy of the
+// predecessor's budget and starts its own lifecycle under the tenant's
+// pinned workflow.
+func (s *Service) Derive(ctx context.Context, tenantCtx common.TenantContext, cmd DeriveCommand) (*proposal.Proposal, error) {
+	predecessor, err := s.repo.FindByID(ctx, tenantCtx.TenantID, cmd.PredecessorID)
+	if err != nil {
+		return nil, err
+	}
+	if predecessor == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, predecessor); err != nil {
+		return nil, err
+	}
+
+	next, err := predecessor.Derive(tenantCtx.UserID, cmd.Type)
+	if err != nil {
+		return nil, err
+	}
+	next.WorkflowVersion, err = s.workflows.PinnedVersion(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalCreate, next); err != nil {
+		return nil, err
+	}
+
+	// Copy the predecessor's budget; it is saved once the proposal exists
+	var budgetCopy *budget.Budget
+	if s.budgetRepo != nil {
+		b, err := s.findBudget(ctx, tenantCtx, predecessor)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			budgetCopy = b.CopyFor(tenantCtx.UserID, next.ID)
+		}
+	}
+
+	if err := s.repo.Save(ctx, next); err != nil {
+		return nil, fmt.Errorf("failed to save proposal: %w", err)
+	}
+	next.ClearUncommittedEvents()
+
+	// Link the copy only once it is saved, so a failed copy leaves the
+	// proposal without a budget rather than pointing at a missing one
+	if budgetCopy != nil {
+		if err := s.budgetRepo.Save(ctx, budgetCopy); err != nil {
+			return nil, fmt.Errorf("failed to copy budget: %w", err)
+		}
+		next.BudgetID = &budgetCopy.ID
+		next.Touch(tenantCtx.UserID)
+		if err := s.repo.Save(ctx, next); err != nil {
+			return nil, fmt.Errorf("failed to link budget: %w", err)
+		}
+	}
+
+	// Log audit event
+	if s.auditLogger != nil {
+		_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+			ID:          uuid.New(),
+			TenantID:    tenantCtx.TenantID,
+			EntityType:  "proposal",
+			EntityID:    next.ID,
+			Action:      "derived",
+			PerformedBy: tenantCtx.UserID,
+			NewValues: map[string]interface{}{
+				"proposal_type":  string(next.Type),
+				"predecessor_id": predecessor.ID.String(),
+			},
+		})
+	}
+
+	return next, nil
+}
+
+// GetLineage returns the predecessor chain of a proposal and every proposal
+// derived from it.
+func (s *Service) GetLineage(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) (*proposal.Lineage, error) {
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, err
+	}
+
+	related, err := s.repo.FindLineage(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lineage: %w", err)
+	}
+
+	return proposal.BuildLineage(id, related)
+}
+
+// findBudget loads the budget of a proposal, or nil if it has none.
+func (s *Service) findBudget(ctx context.Context, tenantCtx common.TenantContext, prop *proposal.Proposal) (*budget.Budget, error) {
+	var b *budget.Budget
+	var err error
+	if prop.BudgetID != nil {
+		b, err = s.budgetRepo.FindByID(ctx, tenantCtx.TenantID, *prop.BudgetID)
+	} else {
+		b, err = s.budgetRepo.FindByProposalID(ctx, tenantCtx.TenantID, prop.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load budget: %w", err)
+	}
+	return b, nil
+}
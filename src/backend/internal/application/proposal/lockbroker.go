@@ -0,0 +1,172 @@
+// Package proposal provides fan-out of section lock changes.
+package proposal
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// LockEventType identifies a change to a section lock.
+type LockEventType string
+
+const (
+	LockAcquired LockEventType = "acquired"
+	LockRenewed  LockEventType = "renewed"
+	LockReleased LockEventType = "released"
+	LockExpired  LockEventType = "expired"
+)
+
+// LockEvent reports a change to a section lock of a proposal.
+type LockEvent struct {
+	Type       LockEventType        `json:"type"`
+	Lock       proposal.SectionLock `json:"lock"`
+	OccurredAt time.Time            `json:"occurred_at"`
+}
+
+// lockEventBuffer is how many events a slow subscriber may fall behind
+// before further events are dropped for it.
+const lockEventBuffer = 16
+
+// LockRelay carries encoded lock events between the processes of a
+// deployment.
+type LockRelay interface {
+	// Send broadcasts an encoded event to every process, this one included.
+	Send(ctx context.Context, payload []byte) error
+}
+
+// lockKey identifies a section lock.
+type lockKey struct {
+	proposalID uuid.UUID
+	section    proposal.Section
+}
+
+// LockBroker fans section lock events out to the subscribers of a proposal.
+// Without a relay, events reach only the subscribers of this process. With
+// one, every process receives every event. A lock that is neither renewed
+// nor released is reported as expired when its TTL ends. Delivery is best
+// effort, so subscribers should treat events as hints and reload the lock
+// list when they reconnect.
+type LockBroker struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan LockEvent]struct{}
+	expiries    map[lockKey]*time.Timer
+	relay       LockRelay
+}
+
+// NewLockBroker creates an empty lock broker.
+func NewLockBroker() *LockBroker {
+	return &LockBroker{
+		subscribers: make(map[uuid.UUID]map[chan LockEvent]struct{}),
+		expiries:    make(map[lockKey]*time.Timer),
+	}
+}
+
+// UseRelay makes the broker publish events through the relay. Events the
+// relay delivers must be passed to Receive.
+func (b *LockBroker) UseRelay(relay LockRelay) *LockBroker {
+	b.relay = relay
+	return b
+}
+
+// Subscribe registers for the lock events of a proposal. The returned
+// function unsubscribes and closes the channel.
+func (b *LockBroker) Subscribe(proposalID uuid.UUID) (<-chan LockEvent, func()) {
+	ch := make(chan LockEvent, lockEventBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[proposalID] == nil {
+		b.subscribers[proposalID] = make(map[chan LockEvent]struct{})
+	}
+	b.subscribers[proposalID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[proposalID], ch)
+			if len(b.subscribers[proposalID]) == 0 {
+				delete(b.subscribers, proposalID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish announces an event. With a relay the event is delivered once the
+// relay hands it back; if the relay fails, it is delivered in this process
+// only.
+func (b *LockBroker) Publish(ctx context.Context, event LockEvent) {
+	if b.relay != nil {
+		payload, err := json.Marshal(event)
+		if err == nil && b.relay.Send(ctx, payload) == nil {
+			return
+		}
+	}
+	b.deliver(event)
+}
+
+// Receive delivers an encoded event handed back by the relay. Payloads that
+// do not decode are ignored.
+func (b *LockBroker) Receive(payload []byte) {
+	var event LockEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return
+	}
+	b.deliver(event)
+}
+
+// deliver tracks the expiry of the event's lock and fans the event out.
+func (b *LockBroker) deliver(event LockEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.track(event)
+	b.fanOut(event)
+}
+
+// track schedules an expired event for an acquired or renewed lock,
+// replacing any earlier schedule, and cancels it once the lock is released.
+// The caller must hold b.mu.
+func (b *LockBroker) track(event LockEvent) {
+	key := lockKey{proposalID: event.Lock.ProposalID, section: event.Lock.Section}
+	if timer, ok := b.expiries[key]; ok {
+		timer.Stop()
+		delete(b.expiries, key)
+	}
+	if event.Type != LockAcquired && event.Type != LockRenewed {
+		return
+	}
+
+	lock := event.Lock
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(lock.ExpiresAt), func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		// A renewal or release since may have replaced the schedule
+		if b.expiries[key] != timer {
+			return
+		}
+		delete(b.expiries, key)
+		b.fanOut(LockEvent{Type: LockExpired, Lock: lock, OccurredAt: lock.ExpiresAt})
+	})
+	b.expiries[key] = timer
+}
+
+// fanOut delivers an event to the subscribers of the lock's proposal
+// without blocking. The caller must hold b.mu.
+func (b *LockBroker) fanOut(event LockEvent) {
+	for ch := range b.subscribers[event.Lock.ProposalID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
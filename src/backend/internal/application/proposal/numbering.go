@@ -0,0 +1,94 @@
+// Package proposal provides proposal numbering settings and lookup by number.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// ErrNumberingUnavailable is returned when no numbering repository is configured.
+var ErrNumberingUnavailable = errors.New("numbering not available - numbering repository not configured")
+
+// NumberingSettings describes a tenant's numbering scheme.
+type NumberingSettings struct {
+	Scheme     proposal.NumberingScheme `json:"scheme"`
+	IsDefault  bool                     `json:"is_default"`
+	FiscalYear int                      `json:"current_fiscal_year"`
+	Example    string                   `json:"example"` // first number of the current fiscal year
+}
+
+// GetNumberingScheme returns the tenant's numbering scheme.
+func (s *Service) GetNumberingScheme(ctx context.Context, tenantCtx common.TenantContext) (*NumberingSettings, error) {
+	if s.numbering == nil {
+		return nil, ErrNumberingUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionNumberingManage, nil); err != nil {
+		return nil, err
+	}
+
+	scheme, err := s.numbering.FindNumberingScheme(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == nil {
+		return numberingSettings(proposal.DefaultNumberingScheme(), true)
+	}
+	return numberingSettings(*scheme, false)
+}
+
+// UpdateNumberingScheme replaces the tenant's numbering scheme. Numbers
+// already assigned are kept; counters continue within each fiscal year.
+func (s *Service) UpdateNumberingScheme(ctx context.Context, tenantCtx common.TenantContext, scheme proposal.NumberingScheme) (*NumberingSettings, error) {
+	if s.numbering == nil {
+		return nil, ErrNumberingUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionNumberingManage, nil); err != nil {
+		return nil, err
+	}
+
+	if err := scheme.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.numbering.SaveNumberingScheme(ctx, tenantCtx.TenantID, scheme); err != nil {
+		return nil, err
+	}
+	return numberingSettings(scheme, false)
+}
+
+// numberingSettings describes a scheme as of now.
+func numberingSettings(scheme proposal.NumberingScheme, isDefault bool) (*NumberingSettings, error) {
+	now := time.Now().UTC()
+	example, err := scheme.Format("TENANT", now, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &NumberingSettings{
+		Scheme:     scheme,
+		IsDefault:  isDefault,
+		FiscalYear: scheme.FiscalYear(now),
+		Example:    example,
+	}, nil
+}
+
+// GetByNumber retrieves a proposal with related data by its proposal number.
+func (s *Service) GetByNumber(ctx context.Context, tenantCtx common.TenantContext, number string) (*ProposalDetail, error) {
+	prop, err := s.repo.FindByNumber(ctx, tenantCtx.TenantID, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find proposal %s: %w", number, err)
+	}
+	if prop == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, err
+	}
+
+	return s.enrichProposal(ctx, tenantCtx, prop)
+}
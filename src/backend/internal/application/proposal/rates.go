@@ -0,0 +1,238 @@
+// Package proposal provides the tenant's negotiated rate agreement registry
+// and looking up budget rates from it.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateAgreementsUnavailable is returned when no rate agreement repository is configured.
+var ErrRateAgreementsUnavailable = errors.New("rate agreements not available - rate agreement repository not configured")
+
+// ListRateAgreements returns the tenant's rate agreements, latest first.
+func (s *Service) ListRateAgreements(ctx context.Context, tenantCtx common.TenantContext) ([]*budget.RateAgreement, error) {
+	if s.rateAgreements == nil {
+		return nil, ErrRateAgreementsUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+	return s.rateAgreements.ListAgreements(ctx, tenantCtx.TenantID)
+}
+
+// GetRateAgreement returns a rate agreement.
+func (s *Service) GetRateAgreement(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) (*budget.RateAgreement, error) {
+	if s.rateAgreements == nil {
+		return nil, ErrRateAgreementsUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+	return s.findRateAgreement(ctx, tenantCtx.TenantID, id)
+}
+
+// findRateAgreement loads a rate agreement.
+func (s *Service) findRateAgreement(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*budget.RateAgreement, error) {
+	a, err := s.rateAgreements.FindAgreement(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, budget.ErrRateAgreementNotFound
+	}
+	return a, nil
+}
+
+// RateAgreementCommand represents the command to create or replace a rate
+// agreement.
+type RateAgreementCommand struct {
+	Name            string                  `json:"name"`
+	CognizantAgency string                  `json:"cognizant_agency,omitempty"`
+	AgreementDate   *time.Time              `json:"agreement_date,omitempty"`
+	Status          budget.AgreementStatus  `json:"status,omitempty"`    // defaults to provisional
+	RateType        string                  `json:"rate_type,omitempty"` // defaults to MTDC
+	SubawardCap     *decimal.Decimal        `json:"subaward_cap,omitempty"`
+	EquipmentCap    *decimal.Decimal        `json:"equipment_cap,omitempty"`
+	ExcludedItems   []string                `json:"excluded_items,omitempty"` // nil keeps the default exclusions
+	Rates           []budget.NegotiatedRate `json:"rates"`
+	FringeRates     []budget.FringeRate     `json:"fringe_rates,omitempty"`
+	Notes           string                  `json:"notes,omitempty"`
+	ExpectedVersion int                     `json:"expected_version,omitempty"`
+}
+
+// applyTo copies the command's fields onto an agreement. Unset fields take
+// the base, caps and exclusions of the default F&A rate.
+func (cmd RateAgreementCommand) applyTo(a *budget.RateAgreement) {
+	defaults := budget.DefaultFARate()
+	a.Name = cmd.Name
+	a.CognizantAgency = cmd.CognizantAgency
+	a.AgreementDate = cmd.AgreementDate
+	a.Status = budget.AgreementProvisional
+	if cmd.Status != "" {
+		a.Status = cmd.Status
+	}
+	a.RateType = defaults.RateType
+	if cmd.RateType != "" {
+		a.RateType = cmd.RateType
+	}
+	a.SubawardCap = defaults.SubawardCap
+	if cmd.SubawardCap != nil {
+		a.SubawardCap = *cmd.SubawardCap
+	}
+	a.EquipmentCap = defaults.EquipmentCap
+	if cmd.EquipmentCap != nil {
+		a.EquipmentCap = *cmd.EquipmentCap
+	}
+	a.ExcludedItems = defaults.ExcludedItems
+	if cmd.ExcludedItems != nil {
+		a.ExcludedItems = cmd.ExcludedItems
+	}
+	a.Rates = append([]budget.NegotiatedRate{}, cmd.Rates...)
+	a.FringeRates = append([]budget.FringeRate{}, cmd.FringeRates...)
+	a.Notes = cmd.Notes
+}
+
+// CreateRateAgreement adds a rate agreement to the tenant's registry.
+func (s *Service) CreateRateAgreement(ctx context.Context, tenantCtx common.TenantContext, cmd RateAgreementCommand) (*budget.RateAgreement, error) {
+	if s.rateAgreements == nil {
+		return nil, ErrRateAgreementsUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionRateManage, nil); err != nil {
+		return nil, err
+	}
+
+	a := budget.NewRateAgreement(tenantCtx.TenantID, tenantCtx.UserID, cmd.Name)
+	cmd.applyTo(a)
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.rateAgreements.SaveAgreement(ctx, a); err != nil {
+		return nil, err
+	}
+
+	s.logRateAgreementEvent(ctx, tenantCtx, a, "created")
+	return a, nil
+}
+
+// UpdateRateAgreement replaces a rate agreement. Budgets pick up the new
+// rates when they are next edited.
+func (s *Service) UpdateRateAgreement(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID, cmd RateAgreementCommand) (*budget.RateAgreement, error) {
+	if s.rateAgreements == nil {
+		return nil, ErrRateAgreementsUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionRateManage, nil); err != nil {
+		return nil, err
+	}
+
+	a, err := s.findRateAgreement(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.ExpectedVersion > 0 && a.Version != cmd.ExpectedVersion {
+		return nil, proposal.ErrVersionMismatch
+	}
+
+	cmd.applyTo(a)
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	a.Touch(tenantCtx.UserID)
+	if err := s.rateAgreements.SaveAgreement(ctx, a); err != nil {
+		return nil, err
+	}
+
+	s.logRateAgreementEvent(ctx, tenantCtx, a, "updated")
+	return a, nil
+}
+
+// DeleteRateAgreement removes a rate agreement no budget uses.
+func (s *Service) DeleteRateAgreement(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) error {
+	if s.rateAgreements == nil {
+		return ErrRateAgreementsUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionRateManage, nil); err != nil {
+		return err
+	}
+
+	a, err := s.findRateAgreement(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.rateAgreements.DeleteAgreement(ctx, tenantCtx.TenantID, id); err != nil {
+		return err
+	}
+
+	s.logRateAgreementEvent(ctx, tenantCtx, a, "deleted")
+	return nil
+}
+
+// linkRateAgreement records the agreement a new budget uses: the given one,
+// else the tenant agreement covering the budget's start for its activity
+// type. Without a registry or a covering agreement the budget keeps its
+// rates.
+func (s *Service) linkRateAgreement(ctx context.Context, tenantID common.TenantID, b *budget.Budget, agreementID *uuid.UUID) error {
+	if agreementID != nil {
+		if s.rateAgreements == nil {
+			return ErrRateAgreementsUnavailable
+		}
+		b.RateAgreementID = agreementID
+		return s.applyRateAgreement(ctx, tenantID, b)
+	}
+	if s.rateAgreements == nil {
+		return nil
+	}
+
+	agreements, err := s.rateAgreements.ListAgreements(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	start, _ := b.DateRange()
+	a := budget.SelectAgreement(agreements, b.ActivityType, start)
+	if a == nil {
+		return nil
+	}
+	return budget.NewCalculator(b.FARate).ApplyAgreement(b, a)
+}
+
+// applyRateAgreement looks the budget's rates up again from the agreement it
+// records, for its current dates and activity type.
+func (s *Service) applyRateAgreement(ctx context.Context, tenantID common.TenantID, b *budget.Budget) error {
+	if b.RateAgreementID == nil || s.rateAgreements == nil {
+		return nil
+	}
+	a, err := s.findRateAgreement(ctx, tenantID, *b.RateAgreementID)
+	if err != nil {
+		return err
+	}
+	return budget.NewCalculator(b.FARate).ApplyAgreement(b, a)
+}
+
+// logRateAgreementEvent records an audit event for a rate agreement.
+func (s *Service) logRateAgreementEvent(ctx context.Context, tenantCtx common.TenantContext, a *budget.RateAgreement, action string) {
+	if s.auditLogger == nil {
+		return
+	}
+	_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+		ID:          uuid.New(),
+		TenantID:    tenantCtx.TenantID,
+		EntityType:  "rate_agreement",
+		EntityID:    a.ID,
+		Action:      action,
+		PerformedBy: tenantCtx.UserID,
+		NewValues: map[string]interface{}{
+			"name":    a.Name,
+			"status":  string(a.Status),
+			"version": a.Version,
+		},
+	})
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
 	"github.com/huron-portland/grants-management/internal/domain/common"
 	"github.com/huron-portland/grants-management/internal/domain/proposal"
 )
@@ -23,6 +25,20 @@ type Service struct {
 	notifier       ports.NotificationService
 	auditLogger    ports.AuditLogger
 	uow            ports.UnitOfWork
+	workflows      *proposal.WorkflowRegistry
+	approvals      proposal.ApprovalRepository
+	delegations    proposal.DelegationRepository
+	authorizer     authz.Authorizer
+	snapshots      proposal.SnapshotRepository
+	locks          proposal.SectionLockRepository
+	lockBroker     *LockBroker
+	numbering      proposal.NumberingRepository
+	effort         *proposal.EffortLedger
+	deadlines      proposal.DeadlineCalendarRepository
+	awards         proposal.AwardRepository
+	closeouts      proposal.CloseoutRepository
+	templates      proposal.ProposalTemplateRepository
+	rateAgreements budget.RateAgreementRepository
 }
 
 // ServiceConfig contains configuration for the service.
@@ -35,10 +51,33 @@ type ServiceConfig struct {
 	Notifier       ports.NotificationService
 	AuditLogger    ports.AuditLogger
 	UoW            ports.UnitOfWork
+	Workflows      *proposal.WorkflowRegistry
+	Approvals      proposal.ApprovalRepository
+	Delegations    proposal.DelegationRepository
+	Authorizer     authz.Authorizer
+	Snapshots      proposal.SnapshotRepository
+	SectionLocks   proposal.SectionLockRepository
+	LockBroker     *LockBroker // nil creates a broker for this service
+	Numbering      proposal.NumberingRepository
+	EffortLedger   *proposal.EffortLedger
+	DeadlineCalendar proposal.DeadlineCalendarRepository
+	Awards           proposal.AwardRepository
+	Closeouts        proposal.CloseoutRepository
+	Templates        proposal.ProposalTemplateRepository
+	RateAgreements   budget.RateAgreementRepository
 }
 
 // NewService creates a new proposal application service.
 func NewService(cfg ServiceConfig) *Service {
+	workflows := cfg.Workflows
+	if workflows == nil {
+		workflows = proposal.NewWorkflowRegistry()
+	}
+	lockBroker := cfg.LockBroker
+	if lockBroker == nil {
+		lockBroker = NewLockBroker()
+	}
+
 	return &Service{
 		repo:           cfg.Repo,
 		budgetRepo:     cfg.BudgetRepo,
@@ -48,6 +87,20 @@ func NewService(cfg ServiceConfig) *Service {
 		notifier:       cfg.Notifier,
 		auditLogger:    cfg.AuditLogger,
 		uow:            cfg.UoW,
+		workflows:      workflows,
+		approvals:      cfg.Approvals,
+		delegations:    cfg.Delegations,
+		authorizer:     cfg.Authorizer,
+		snapshots:      cfg.Snapshots,
+		locks:          cfg.SectionLocks,
+		lockBroker:     lockBroker,
+		numbering:      cfg.Numbering,
+		effort:         cfg.EffortLedger,
+		deadlines:      cfg.DeadlineCalendar,
+		awards:         cfg.Awards,
+		closeouts:      cfg.Closeouts,
+		templates:      cfg.Templates,
+		rateAgreements: cfg.RateAgreements,
 	}
 }
 
@@ -56,6 +109,7 @@ type CreateProposalCommand struct {
 	Title            string     `json:"title"`
 	ShortTitle       string     `json:"short_title,omitempty"`
 	Abstract         string     `json:"abstract,omitempty"`
+	ProposalType     proposal.ProposalType `json:"proposal_type,omitempty"`
 	PIID             uuid.UUID  `json:"pi_id"`
 	CoInvestigators  []uuid.UUID `json:"co_investigators,omitempty"`
 	SponsorID        uuid.UUID  `json:"sponsor_id"`
@@ -63,14 +117,16 @@ type CreateProposalCommand struct {
 	Department       string     `json:"department"`
 	ProjectStartDate string     `json:"project_start_date"` // RFC3339
 	ProjectEndDate   string     `json:"project_end_date"`   // RFC3339
-	SponsorDeadline  *string    `json:"sponsor_deadline,omitempty"`
-	InternalDeadline *string    `json:"internal_deadline,omitempty"`
+	SponsorDeadline  *string    `json:"sponsor_deadline,omitempty"` // RFC3339, or YYYY-MM-DD[THH:MM] in DeadlineTimeZone
+	InternalDeadline *string    `json:"internal_deadline,omitempty"` // RFC3339; derived from the tenant's rule if omitted
+	DeadlineTimeZone string     `json:"deadline_time_zone,omitempty"` // IANA zone the sponsor quotes its deadline in
 	ResearchArea     string     `json:"research_area,omitempty"`
 	Keywords         []string   `json:"keywords,omitempty"`
 	IRBRequired      bool       `json:"irb_required"`
 	IACUCRequired    bool       `json:"iacuc_required"`
 	IBCRequired      bool       `json:"ibc_required"`
 	ExportControl    bool       `json:"export_control"`
+	TemplateID       *uuid.UUID `json:"template_id,omitempty"` // template to instantiate the proposal from
 }
 
 // CreateProposalResult contains the result of creating a proposal.
@@ -78,6 +134,7 @@ type CreateProposalResult struct {
 	Proposal    *proposal.Proposal `json:"proposal"`
 	PIName      string             `json:"pi_name"`
 	SponsorName string             `json:"sponsor_name"`
+	Template    *TemplateUse       `json:"template,omitempty"`
 }
 
 // Create creates a new proposal.
@@ -105,9 +162,34 @@ func (s *Service) Create(ctx context.Context, tenantCtx common.TenantContext, cm
 	if err != nil {
 		return nil, err
 	}
+	if cmd.SponsorDeadline != nil {
+		input.SponsorDeadline, err = s.parseSponsorDeadline(ctx, tenantCtx.TenantID, *cmd.SponsorDeadline, cmd.DeadlineTimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sponsor deadline: %w", err)
+		}
+	}
+	if err := s.deriveInternalDeadline(ctx, tenantCtx.TenantID, input.SponsorDeadline, &input.InternalDeadline); err != nil {
+		return nil, err
+	}
+	version, err := s.workflows.PinnedVersion(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	input.WorkflowVersion = version
+
+	// Fill defaults from the template
+	var tpl *proposal.ProposalTemplate
+	if cmd.TemplateID != nil {
+		if tpl, err = s.findTemplate(ctx, tenantCtx, *cmd.TemplateID); err != nil {
+			return nil, err
+		}
+		if err := tpl.Defaults(&input); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create domain service
-	domainService := proposal.NewService(s.repo, nil, nil)
+	domainService := s.domainService()
 
 	// Create the proposal
 	prop, err := domainService.CreateProposal(ctx, tenantCtx, input)
@@ -131,10 +213,24 @@ func (s *Service) Create(ctx context.Context, tenantCtx common.TenantContext, cm
 	}
 	_ = prop.Update(tenantCtx.UserID, updates)
 
+	// Staff the proposal and build its budget from the template
+	var templateUse *TemplateUse
+	var templateBudget *budget.Budget
+	if tpl != nil {
+		if templateUse, templateBudget, err = s.instantiateTemplate(ctx, tenantCtx, tpl, prop); err != nil {
+			return nil, err
+		}
+	}
+
 	// Save
 	if err := s.repo.Save(ctx, prop); err != nil {
 		return nil, fmt.Errorf("failed to save proposal: %w", err)
 	}
+	if templateBudget != nil {
+		if err := s.budgetRepo.Save(ctx, templateBudget); err != nil {
+			return nil, fmt.Errorf("failed to save template budget: %w", err)
+		}
+	}
 
 	// Generate and store embedding asynchronously
 	if s.embedGenerator != nil {
@@ -157,9 +253,15 @@ func (s *Service) Create(ctx context.Context, tenantCtx common.TenantContext, cm
 		Proposal:    prop,
 		PIName:      pi.FullName(),
 		SponsorName: sponsor.Name,
+		Template:    templateUse,
 	}, nil
 }
 
+// domainService creates the proposal domain service for a request.
+func (s *Service) domainService() *proposal.Service {
+	return proposal.NewService(s.repo, nil, nil).UseAuthorizer(s.authorizer).UseWorkflows(s.workflows).UseSectionLocks(s.locks)
+}
+
 // parseCreateInput parses the create command into domain input.
 func parseCreateInput(cmd CreateProposalCommand) (proposal.CreateProposalInput, error) {
 	input := proposal.CreateProposalInput{
@@ -168,9 +270,12 @@ func parseCreateInput(cmd CreateProposalCommand) (proposal.CreateProposalInput,
 		Abstract:     cmd.Abstract,
 		PIID:         cmd.PIID,
 		SponsorID:    cmd.SponsorID,
+		OpportunityID: cmd.OpportunityID,
 		Department:   cmd.Department,
 		ResearchArea: cmd.ResearchArea,
 		Keywords:     cmd.Keywords,
+		Type:         cmd.ProposalType,
+		DeadlineTimeZone: cmd.DeadlineTimeZone,
 	}
 
 	// Parse project dates
@@ -186,15 +291,8 @@ func parseCreateInput(cmd CreateProposalCommand) (proposal.CreateProposalInput,
 	}
 	input.ProjectEndDate = endDate
 
-	// Parse optional deadlines
-	if cmd.SponsorDeadline != nil {
-		deadline, err := parseDate(*cmd.SponsorDeadline)
-		if err != nil {
-			return input, fmt.Errorf("invalid sponsor deadline: %w", err)
-		}
-		input.SponsorDeadline = &deadline
-	}
-
+	// Parse optional deadlines; the sponsor deadline is parsed by the
+	// service in the tenant's default time of day
 	if cmd.InternalDeadline != nil {
 		deadline, err := parseDate(*cmd.InternalDeadline)
 		if err != nil {
@@ -245,6 +343,10 @@ func (s *Service) GetByID(ctx context.Context, tenantCtx common.TenantContext, i
 		return nil, errors.New("proposal not found")
 	}
 
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, err
+	}
+
 	return s.enrichProposal(ctx, tenantCtx, prop)
 }
 
@@ -255,14 +357,36 @@ type ProposalDetail struct {
 	Sponsor            *ports.Sponsor       `json:"sponsor"`
 	CoInvestigators    []*ports.Person      `json:"co_investigators,omitempty"`
 	AvailableActions   []proposal.ProposalTransition `json:"available_actions"`
+	RegionActions      map[string][]proposal.ProposalTransition `json:"region_actions,omitempty"`
 	BudgetSummary      interface{}          `json:"budget_summary,omitempty"`
+	EditableFields     []proposal.FieldPermission `json:"editable_fields"`
+	SectionLocks       []proposal.SectionLock     `json:"section_locks,omitempty"`
+	MissingAttachments []string                   `json:"missing_attachments,omitempty"` // required by the proposal's template
 }
 
 // enrichProposal adds related data to a proposal.
 func (s *Service) enrichProposal(ctx context.Context, tenantCtx common.TenantContext, prop *proposal.Proposal) (*ProposalDetail, error) {
+	sm, err := s.workflows.StateMachine(ctx, prop.TenantID, prop.WorkflowVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	detail := &ProposalDetail{
 		Proposal:         prop,
-		AvailableActions: prop.GetAvailableTransitions(),
+		AvailableActions: prop.AvailableTransitionsWith(ctx, sm, tenantCtx.UserID),
+	}
+	if len(prop.SubStates) > 0 {
+		detail.RegionActions = prop.AvailableRegionTransitionsWith(ctx, sm, tenantCtx.UserID)
+	}
+	detail.EditableFields, err = s.editableFields(ctx, tenantCtx, prop)
+	if err != nil {
+		return nil, err
+	}
+	if s.locks != nil {
+		detail.SectionLocks, err = s.locks.FindLocks(ctx, tenantCtx.TenantID, prop.ID, time.Now().UTC())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load section locks: %w", err)
+		}
 	}
 
 	// Get PI
@@ -283,6 +407,17 @@ func (s *Service) enrichProposal(ctx context.Context, tenantCtx common.TenantCon
 		detail.CoInvestigators = cois
 	}
 
+	// Get attachments the template requires
+	if s.templates != nil && prop.TemplateID != nil {
+		tpl, err := s.templates.FindTemplate(ctx, tenantCtx.TenantID, *prop.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if tpl != nil {
+			detail.MissingAttachments = tpl.MissingAttachments(prop)
+		}
+	}
+
 	// Get Budget Summary
 	if s.budgetRepo != nil && prop.BudgetID != nil {
 		budget, _ := s.budgetRepo.FindByID(ctx, tenantCtx.TenantID, *prop.BudgetID)
@@ -296,6 +431,10 @@ func (s *Service) enrichProposal(ctx context.Context, tenantCtx common.TenantCon
 
 // List retrieves proposals with filtering and enrichment.
 func (s *Service) List(ctx context.Context, tenantCtx common.TenantContext, filter proposal.ListFilter) (*ListResult, error) {
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+
 	proposals, total, err := s.repo.List(ctx, tenantCtx.TenantID, filter)
 	if err != nil {
 		return nil, err
@@ -321,12 +460,20 @@ type ListResult struct {
 type TransitionCommand struct {
 	ProposalID      uuid.UUID                  `json:"proposal_id"`
 	Transition      proposal.ProposalTransition `json:"transition"`
+	Region          string                     `json:"region,omitempty"`
 	Comment         string                     `json:"comment,omitempty"`
 	ExpectedVersion int                        `json:"expected_version,omitempty"`
 }
 
-// Transition transitions a proposal to a new state.
+// Transition transitions a proposal to a new state. The policy engine decides
+// whether the actor may fire it; by default the actor must hold one of the
+// state's required roles, directly or through an active delegation.
 func (s *Service) Transition(ctx context.Context, tenantCtx common.TenantContext, cmd TransitionCommand) (*proposal.Proposal, error) {
+	return s.transition(ctx, tenantCtx, cmd, true)
+}
+
+// transition performs a transition, optionally checking the actor's roles.
+func (s *Service) transition(ctx context.Context, tenantCtx common.TenantContext, cmd TransitionCommand, authorize bool) (*proposal.Proposal, error) {
 	// Get current proposal
 	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, cmd.ProposalID)
 	if err != nil {
@@ -344,8 +491,32 @@ func (s *Service) Transition(ctx context.Context, tenantCtx common.TenantContext
 	// Store old state for notification
 	oldState := prop.State
 
+	// Resolve the workflow the proposal was created under
+	sm, err := s.workflows.StateMachine(ctx, prop.TenantID, prop.WorkflowVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the actor's authority, honoring delegations
+	var authority proposal.Authority
+	if authorize {
+		authority, err = s.authorizeTransition(ctx, tenantCtx, prop, sm, cmd.Transition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Open an award or closeout checklist the proposal missed, so that their
+	// guards see the proposal's budget and sponsor
+	if _, err := s.openAward(ctx, tenantCtx, prop); err != nil {
+		return nil, err
+	}
+	if _, err := s.openCloseout(ctx, tenantCtx, prop); err != nil {
+		return nil, err
+	}
+
 	// Perform transition
-	if err := prop.TransitionTo(cmd.Transition, tenantCtx.UserID, cmd.Comment); err != nil {
+	if err := prop.TransitionOnBehalf(ctx, sm, cmd.Region, cmd.Transition, tenantCtx.UserID, authority.OnBehalfOf, cmd.Comment); err != nil {
 		return nil, err
 	}
 
@@ -354,9 +525,20 @@ func (s *Service) Transition(ctx context.Context, tenantCtx common.TenantContext
 		return nil, fmt.Errorf("failed to save proposal: %w", err)
 	}
 
+	// Keep an immutable copy of what was submitted
+	if _, err := proposal.CaptureSnapshot(ctx, s.snapshots, prop, cmd.Transition, tenantCtx.UserID); err != nil {
+		return nil, err
+	}
+
+	// Open the award to be set up before activation and the closeout
+	// checklist that gates closing. The transition is already saved, so a
+	// failure does not fail it; both are opened again on first access.
+	_, _ = s.openAward(ctx, tenantCtx, prop)
+	_, _ = s.openCloseout(ctx, tenantCtx, prop)
+
 	// Send notifications
 	if s.notifier != nil {
-		metadata := proposal.GetStateMetadata(prop.State)
+		metadata := sm.Metadata(prop.State)
 		_ = s.notifier.SendProposalNotification(ctx, prop.ID, "state_changed", s.getNotificationRecipients(ctx, tenantCtx, prop, metadata))
 	}
 
@@ -370,13 +552,40 @@ func (s *Service) Transition(ctx context.Context, tenantCtx common.TenantContext
 			Action:      "state_changed",
 			PerformedBy: tenantCtx.UserID,
 			OldValues:   map[string]interface{}{"state": oldState.String()},
-			NewValues:   map[string]interface{}{"state": prop.State.String()},
+			NewValues:   transitionAuditValues(prop, authority),
 		})
 	}
 
 	return prop, nil
 }
 
+// authorizeTransition asks the policy engine whether the actor may fire a
+// transition from the proposal's current state in its workflow.
+func (s *Service) authorizeTransition(ctx context.Context, tenantCtx common.TenantContext, prop *proposal.Proposal, sm *proposal.ProposalStateMachine, transition proposal.ProposalTransition) (proposal.Authority, error) {
+	now := time.Now().UTC()
+
+	var delegations []proposal.Delegation
+	if s.delegations != nil {
+		var err error
+		delegations, err = s.delegations.FindActiveDelegations(ctx, tenantCtx.TenantID, tenantCtx.UserID, now)
+		if err != nil {
+			return proposal.Authority{}, fmt.Errorf("failed to load delegations: %w", err)
+		}
+	}
+
+	return proposal.AuthorizeTransition(ctx, s.authorizer, tenantCtx, prop, transition, sm.Metadata(prop.State), delegations, now)
+}
+
+// transitionAuditValues returns the audit values of a transition.
+func transitionAuditValues(prop *proposal.Proposal, authority proposal.Authority) map[string]interface{} {
+	values := map[string]interface{}{"state": prop.State.String()}
+	if authority.OnBehalfOf != nil {
+		values["on_behalf_of"] = authority.OnBehalfOf.String()
+		values["delegation_id"] = authority.DelegationID.String()
+	}
+	return values
+}
+
 // getNotificationRecipients returns user IDs for notification.
 func (s *Service) getNotificationRecipients(ctx context.Context, tenantCtx common.TenantContext, prop *proposal.Proposal, metadata proposal.StateMetadata) []uuid.UUID {
 	recipients := []uuid.UUID{prop.PrincipalInvestigatorID}
@@ -391,6 +600,10 @@ func (s *Service) Search(ctx context.Context, tenantCtx common.TenantContext, qu
 		return nil, errors.New("search not available - embedding generator not configured")
 	}
 
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+
 	// Generate query embedding
 	embedding, err := s.embedGenerator.Generate(ctx, query)
 	if err != nil {
@@ -405,22 +618,122 @@ type UpdateCommand struct {
 	ProposalID      uuid.UUID                `json:"proposal_id"`
 	Updates         proposal.ProposalUpdates `json:"updates"`
 	ExpectedVersion int                      `json:"expected_version,omitempty"`
+
+	// ExpectedSectionVersions checks conflicts per section instead of for the
+	// whole proposal; ExpectedVersion is ignored when it is set.
+	ExpectedSectionVersions map[proposal.Section]int `json:"expected_section_versions,omitempty"`
+
+	// SponsorDeadlineLocal sets the sponsor deadline as YYYY-MM-DD[THH:MM]
+	// in the deadline time zone, as sponsors quote it.
+	SponsorDeadlineLocal *string `json:"sponsor_deadline_local,omitempty"`
 }
 
 // Update updates a proposal.
 func (s *Service) Update(ctx context.Context, tenantCtx common.TenantContext, cmd UpdateCommand) (*proposal.Proposal, error) {
-	domainService := proposal.NewService(s.repo, nil, nil)
-	return domainService.UpdateProposal(ctx, tenantCtx, cmd.ProposalID, cmd.Updates, cmd.ExpectedVersion)
+	if err := s.prepareDeadlineUpdates(ctx, tenantCtx, &cmd); err != nil {
+		return nil, err
+	}
+
+	domainService := s.domainService()
+	var prop *proposal.Proposal
+	var err error
+	if cmd.ExpectedSectionVersions != nil {
+		prop, err = domainService.UpdateSections(ctx, tenantCtx, cmd.ProposalID, cmd.Updates, cmd.ExpectedSectionVersions)
+	} else {
+		prop, err = domainService.UpdateProposal(ctx, tenantCtx, cmd.ProposalID, cmd.Updates, cmd.ExpectedVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Log audit event
+	if s.auditLogger != nil {
+		fields := make([]string, 0)
+		for _, f := range cmd.Updates.Fields() {
+			fields = append(fields, string(f))
+		}
+		values := map[string]interface{}{
+			"state":  string(prop.State),
+			"fields": fields,
+		}
+		if cmd.Updates.PriorApproval != "" {
+			values["prior_approval"] = cmd.Updates.PriorApproval
+		}
+		_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+			ID:          uuid.New(),
+			TenantID:    tenantCtx.TenantID,
+			EntityType:  "proposal",
+			EntityID:    prop.ID,
+			Action:      "updated",
+			PerformedBy: tenantCtx.UserID,
+			NewValues:   values,
+		})
+	}
+
+	return prop, nil
+}
+
+// EditableFields describes what the actor may change in a proposal.
+type EditableFields struct {
+	ProposalID uuid.UUID                  `json:"proposal_id"`
+	State      proposal.ProposalState     `json:"state"`
+	Fields     []proposal.FieldPermission `json:"fields"`
+}
+
+// GetEditableFields returns the fields the actor's roles may change in the
+// proposal's current state.
+func (s *Service) GetEditableFields(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) (*EditableFields, error) {
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.editableFields(ctx, tenantCtx, prop)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EditableFields{ProposalID: prop.ID, State: prop.State, Fields: fields}, nil
+}
+
+// editableFields returns the fields the actor may change under the
+// proposal's workflow version. Users the policies do not let update the
+// proposal may change nothing.
+func (s *Service) editableFields(ctx context.Context, tenantCtx common.TenantContext, prop *proposal.Proposal) ([]proposal.FieldPermission, error) {
+	err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalUpdate, prop)
+	if errors.Is(err, proposal.ErrUnauthorized) {
+		return []proposal.FieldPermission{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matrix, err := s.domainService().EditMatrix(ctx, prop)
+	if err != nil {
+		return nil, err
+	}
+	return prop.EditableFields(matrix, tenantCtx.Roles), nil
 }
 
 // Delete soft-deletes a proposal.
 func (s *Service) Delete(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) error {
-	domainService := proposal.NewService(s.repo, nil, nil)
+	domainService := s.domainService()
 	return domainService.DeleteProposal(ctx, tenantCtx, id)
 }
 
 // GetDashboard retrieves dashboard statistics.
 func (s *Service) GetDashboard(ctx context.Context, tenantCtx common.TenantContext) (*DashboardData, error) {
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+
 	// Get counts by state
 	counts, err := s.repo.CountByState(ctx, tenantCtx.TenantID)
 	if err != nil {
@@ -439,10 +752,20 @@ func (s *Service) GetDashboard(ctx context.Context, tenantCtx common.TenantConte
 		return nil, err
 	}
 
+	// Get overdue closeout items
+	overdueCloseout := make([]proposal.OverdueCloseoutItem, 0)
+	if s.closeouts != nil {
+		overdueCloseout, err = s.closeouts.ListOverdueCloseoutItems(ctx, tenantCtx.TenantID, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &DashboardData{
-		ProposalsByState:  counts,
-		UpcomingDeadlines: upcoming,
-		OverdueProposals:  overdue,
+		ProposalsByState:     counts,
+		UpcomingDeadlines:    upcoming,
+		OverdueProposals:     overdue,
+		OverdueCloseoutItems: overdueCloseout,
 	}, nil
 }
 
@@ -451,4 +774,5 @@ type DashboardData struct {
 	ProposalsByState  map[proposal.ProposalState]int64 `json:"proposals_by_state"`
 	UpcomingDeadlines []*proposal.Proposal             `json:"upcoming_deadlines"`
 	OverdueProposals  []*proposal.Proposal             `json:"overdue_proposals"`
+	OverdueCloseoutItems []proposal.OverdueCloseoutItem `json:"overdue_closeout_items"`
 }
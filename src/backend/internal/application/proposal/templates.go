@@ -0,0 +1,216 @@
+// Package proposal provides proposal templates and instantiating proposals
+// from them.
+package proposal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// ErrTemplatesUnavailable is returned when no template repository is configured.
+var ErrTemplatesUnavailable = errors.New("proposal templates not available - template repository not configured")
+
+// TemplateUse describes what instantiating a template left to be done.
+type TemplateUse struct {
+	TemplateID          uuid.UUID               `json:"template_id"`
+	Name                string                  `json:"name"`
+	OpenRoles           []proposal.TemplateRole `json:"open_roles"`           // roles no person was named for
+	RequiredAttachments []string                `json:"required_attachments"` // attachment categories to upload
+	BudgetID            *uuid.UUID              `json:"budget_id,omitempty"`
+}
+
+// instantiateTemplate staffs a new proposal from a template and builds its
+// budget from the template's skeleton. Skeletons without an F&A rate take
+// the tenant's rate agreement. The budget is returned for the caller to
+// save once the proposal is saved; it is nil without a skeleton or a budget
+// repository.
+func (s *Service) instantiateTemplate(ctx context.Context, tenantCtx common.TenantContext, tpl *proposal.ProposalTemplate, prop *proposal.Proposal) (*TemplateUse, *budget.Budget, error) {
+	openRoles, err := tpl.Apply(tenantCtx.UserID, prop)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	use := &TemplateUse{
+		TemplateID:          tpl.ID,
+		Name:                tpl.Name,
+		OpenRoles:           openRoles,
+		RequiredAttachments: append([]string{}, tpl.RequiredAttachments...),
+	}
+
+	var b *budget.Budget
+	if tpl.Budget != nil && s.budgetRepo != nil {
+		b = tpl.Budget.Build(prop, tenantCtx.UserID)
+		if tpl.Budget.FARate == nil {
+			if err := s.linkRateAgreement(ctx, tenantCtx.TenantID, b, nil); err != nil {
+				return nil, nil, err
+			}
+			budget.NewCalculator(b.FARate).Recalculate(b)
+		}
+		prop.BudgetID = &b.ID
+		use.BudgetID = &b.ID
+	}
+	return use, b, nil
+}
+
+// findTemplate loads a template the actor may see.
+func (s *Service) findTemplate(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) (*proposal.ProposalTemplate, error) {
+	if s.templates == nil {
+		return nil, ErrTemplatesUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+
+	tpl, err := s.templates.FindTemplate(ctx, tenantCtx.TenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if tpl == nil {
+		return nil, proposal.ErrTemplateNotFound
+	}
+	return tpl, nil
+}
+
+// GetTemplate returns a proposal template.
+func (s *Service) GetTemplate(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) (*proposal.ProposalTemplate, error) {
+	return s.findTemplate(ctx, tenantCtx, id)
+}
+
+// ListTemplates returns the templates usable for a department and
+// opportunity.
+func (s *Service) ListTemplates(ctx context.Context, tenantCtx common.TenantContext, filter proposal.TemplateFilter) ([]*proposal.ProposalTemplate, error) {
+	if s.templates == nil {
+		return nil, ErrTemplatesUnavailable
+	}
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalList, nil); err != nil {
+		return nil, err
+	}
+
+	return s.templates.ListTemplates(ctx, tenantCtx.TenantID, filter)
+}
+
+// TemplateCommand represents the command to create or replace a template.
+type TemplateCommand struct {
+	Name                string                   `json:"name"`
+	Description         string                   `json:"description,omitempty"`
+	Department          string                   `json:"department,omitempty"`
+	OpportunityID       *uuid.UUID               `json:"opportunity_id,omitempty"`
+	ProposalType        proposal.ProposalType    `json:"proposal_type,omitempty"`
+	ResearchArea        string                   `json:"research_area,omitempty"`
+	Keywords            []string                 `json:"keywords,omitempty"`
+	Personnel           []proposal.TemplateRole  `json:"personnel,omitempty"`
+	Compliance          proposal.ComplianceFlags `json:"compliance"`
+	Budget              *proposal.BudgetSkeleton `json:"budget,omitempty"`
+	RequiredAttachments []string                 `json:"required_attachments,omitempty"`
+	ExpectedVersion     int                      `json:"expected_version,omitempty"`
+}
+
+// applyTo copies the command's fields onto a template.
+func (cmd TemplateCommand) applyTo(t *proposal.ProposalTemplate) {
+	t.Name = cmd.Name
+	t.Description = cmd.Description
+	t.Department = cmd.Department
+	t.OpportunityID = cmd.OpportunityID
+	t.ProposalType = cmd.ProposalType
+	t.ResearchArea = cmd.ResearchArea
+	t.Keywords = cmd.Keywords
+	t.Personnel = cmd.Personnel
+	t.Compliance = cmd.Compliance
+	t.Budget = cmd.Budget
+	t.RequiredAttachments = cmd.RequiredAttachments
+}
+
+// CreateTemplate creates a proposal template.
+func (s *Service) CreateTemplate(ctx context.Context, tenantCtx common.TenantContext, cmd TemplateCommand) (*proposal.ProposalTemplate, error) {
+	if s.templates == nil {
+		return nil, ErrTemplatesUnavailable
+	}
+
+	tpl := proposal.NewProposalTemplate(tenantCtx.TenantID, tenantCtx.UserID, cmd.Name)
+	cmd.applyTo(tpl)
+	if err := proposal.AuthorizeTemplate(ctx, s.authorizer, tenantCtx, authz.ActionTemplateManage, tpl); err != nil {
+		return nil, err
+	}
+	if err := tpl.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.templates.SaveTemplate(ctx, tpl); err != nil {
+		return nil, err
+	}
+
+	s.logTemplateEvent(ctx, tenantCtx, tpl, "created")
+	return tpl, nil
+}
+
+// UpdateTemplate replaces a proposal template. Proposals already created
+// from it are not changed.
+func (s *Service) UpdateTemplate(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID, cmd TemplateCommand) (*proposal.ProposalTemplate, error) {
+	tpl, err := s.findTemplate(ctx, tenantCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.ExpectedVersion > 0 && tpl.Version != cmd.ExpectedVersion {
+		return nil, proposal.ErrVersionMismatch
+	}
+
+	// The actor must manage the template both before and after the change
+	if err := proposal.AuthorizeTemplate(ctx, s.authorizer, tenantCtx, authz.ActionTemplateManage, tpl); err != nil {
+		return nil, err
+	}
+	cmd.applyTo(tpl)
+	if err := proposal.AuthorizeTemplate(ctx, s.authorizer, tenantCtx, authz.ActionTemplateManage, tpl); err != nil {
+		return nil, err
+	}
+
+	if err := tpl.Validate(); err != nil {
+		return nil, err
+	}
+	tpl.Touch(tenantCtx.UserID)
+	if err := s.templates.SaveTemplate(ctx, tpl); err != nil {
+		return nil, err
+	}
+
+	s.logTemplateEvent(ctx, tenantCtx, tpl, "updated")
+	return tpl, nil
+}
+
+// DeleteTemplate removes a proposal template.
+func (s *Service) DeleteTemplate(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID) error {
+	tpl, err := s.findTemplate(ctx, tenantCtx, id)
+	if err != nil {
+		return err
+	}
+	if err := proposal.AuthorizeTemplate(ctx, s.authorizer, tenantCtx, authz.ActionTemplateManage, tpl); err != nil {
+		return err
+	}
+
+	if err := s.templates.DeleteTemplate(ctx, tenantCtx.TenantID, id); err != nil {
+		return err
+	}
+
+	s.logTemplateEvent(ctx, tenantCtx, tpl, "deleted")
+	return nil
+}
+
+// logTemplateEvent records an audit event for a template.
+func (s *Service) logTemplateEvent(ctx context.Context, tenantCtx common.TenantContext, tpl *proposal.ProposalTemplate, action string) {
+	if s.auditLogger == nil {
+		return
+	}
+	_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+		ID:          uuid.New(),
+		TenantID:    tenantCtx.TenantID,
+		EntityType:  "proposal_template",
+		EntityID:    tpl.ID,
+		Action:      action,
+		PerformedBy: tenantCtx.UserID,
+		NewValues:   map[string]interface{}{"name": tpl.Name},
+	})
+}
@@ -0,0 +1,219 @@
+// Package proposal provides proposal versioning for the proposal application service.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// ErrSnapshotsUnavailable is returned when no snapshot repository is configured.
+var ErrSnapshotsUnavailable = errors.New("versions not available - snapshot repository not configured")
+
+// VersionHistory lists the snapshots of a proposal and its lineage.
+type VersionHistory struct {
+	ProposalID      uuid.UUID           `json:"proposal_id"`
+	ParentVersionID *uuid.UUID          `json:"parent_version_id,omitempty"`
+	IsLatestVersion bool                `json:"is_latest_version"`
+	Versions        []proposal.Snapshot `json:"versions"`
+}
+
+// RestoreCommand restores a snapshot of a proposal as a new draft.
+type RestoreCommand struct {
+	ProposalID      uuid.UUID `json:"proposal_id"`
+	Version         int       `json:"version"`
+	ExpectedVersion int       `json:"expected_version,omitempty"`
+}
+
+// ReviewChanges describes what changed since the actor last reviewed a proposal.
+type ReviewChanges struct {
+	Review   proposal.StateTransition `json:"review"`
+	Baseline *proposal.Snapshot       `json:"baseline"`
+	Diff     *proposal.SnapshotDiff   `json:"diff"`
+}
+
+// ListVersions returns the snapshots captured for a proposal.
+func (s *Service) ListVersions(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*VersionHistory, error) {
+	prop, err := s.loadVersioned(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshots.FindSnapshots(ctx, tenantCtx.TenantID, prop.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load versions: %w", err)
+	}
+
+	return &VersionHistory{
+		ProposalID:      prop.ID,
+		ParentVersionID: prop.ParentVersionID,
+		IsLatestVersion: prop.IsLatestVersion,
+		Versions:        snapshots,
+	}, nil
+}
+
+// GetVersion returns a single snapshot of a proposal.
+func (s *Service) GetVersion(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID, number int) (*proposal.Snapshot, error) {
+	prop, err := s.loadVersioned(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.findSnapshot(ctx, tenantCtx, prop.ID, number)
+}
+
+// DiffVersions returns the field-level changes between two snapshots of a
+// proposal. A target version of 0 compares against the current working copy.
+func (s *Service) DiffVersions(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID, from, to int) (*proposal.SnapshotDiff, error) {
+	prop, err := s.loadVersioned(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := s.findSnapshot(ctx, tenantCtx, prop.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		return base.DiffWorkingCopy(prop), nil
+	}
+
+	target, err := s.findSnapshot(ctx, tenantCtx, prop.ID, to)
+	if err != nil {
+		return nil, err
+	}
+	return base.Diff(target), nil
+}
+
+// RestoreVersion creates a new draft proposal from a snapshot. The draft
+// becomes the latest version and the source proposal is marked superseded.
+func (s *Service) RestoreVersion(ctx context.Context, tenantCtx common.TenantContext, cmd RestoreCommand) (*proposal.Proposal, error) {
+	if s.snapshots == nil {
+		return nil, ErrSnapshotsUnavailable
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, cmd.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+
+	// Check version for optimistic locking
+	if cmd.ExpectedVersion > 0 && prop.Version != cmd.ExpectedVersion {
+		return nil, proposal.ErrVersionMismatch
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRestore, prop); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.findSnapshot(ctx, tenantCtx, prop.ID, cmd.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	draft, err := prop.RestoreDraft(tenantCtx.UserID, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalCreate, draft); err != nil {
+		return nil, err
+	}
+
+	// Supersede the source and save the draft together, so a concurrent
+	// edit of the source fails the restore without leaving a draft behind
+	if err := s.repo.SaveAll(ctx, prop, draft); err != nil {
+		return nil, fmt.Errorf("failed to save restored draft: %w", err)
+	}
+	draft.ClearUncommittedEvents()
+
+	// Log audit event
+	if s.auditLogger != nil {
+		_ = s.auditLogger.Log(ctx, ports.AuditEvent{
+			ID:          uuid.New(),
+			TenantID:    tenantCtx.TenantID,
+			EntityType:  "proposal",
+			EntityID:    draft.ID,
+			Action:      "version_restored",
+			PerformedBy: tenantCtx.UserID,
+			NewValues: map[string]interface{}{
+				"parent_version_id": prop.ID.String(),
+				"restored_version":  snapshot.Number,
+			},
+		})
+	}
+
+	return draft, nil
+}
+
+// ChangesSinceReview returns what changed in a proposal since the actor last
+// reviewed it: the snapshot current at their last review compared against
+// the proposal's working copy.
+func (s *Service) ChangesSinceReview(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*ReviewChanges, error) {
+	prop, err := s.loadVersioned(ctx, tenantCtx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := prop.LastReviewBy(tenantCtx.UserID)
+	if review == nil {
+		return nil, proposal.ErrNoPriorReview
+	}
+
+	snapshots, err := s.snapshots.FindSnapshots(ctx, tenantCtx.TenantID, prop.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load versions: %w", err)
+	}
+
+	baseline := proposal.SnapshotAt(snapshots, review.PerformedAt)
+	if baseline == nil {
+		return nil, fmt.Errorf("%w: no version was submitted before the review at %s", proposal.ErrSnapshotNotFound, review.PerformedAt.Format(time.RFC3339))
+	}
+
+	return &ReviewChanges{
+		Review:   *review,
+		Baseline: baseline,
+		Diff:     baseline.DiffWorkingCopy(prop),
+	}, nil
+}
+
+// loadVersioned loads a proposal the actor may read for a versioning operation.
+func (s *Service) loadVersioned(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID) (*proposal.Proposal, error) {
+	if s.snapshots == nil {
+		return nil, ErrSnapshotsUnavailable
+	}
+
+	prop, err := s.repo.FindByID(ctx, tenantCtx.TenantID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+	if prop == nil {
+		return nil, proposal.ErrProposalNotFound
+	}
+
+	if err := proposal.Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalRead, prop); err != nil {
+		return nil, err
+	}
+	return prop, nil
+}
+
+// findSnapshot loads a snapshot, failing if it does not exist.
+func (s *Service) findSnapshot(ctx context.Context, tenantCtx common.TenantContext, proposalID uuid.UUID, number int) (*proposal.Snapshot, error) {
+	snapshot, err := s.snapshots.FindSnapshot(ctx, tenantCtx.TenantID, proposalID, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load version %d: %w", number, err)
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("%w: version %d", proposal.ErrSnapshotNotFound, number)
+	}
+	return snapshot, nil
+}
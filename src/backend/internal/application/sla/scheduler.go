@@ -0,0 +1,323 @@
+// Package sla provides the SLA escalation scheduler for proposal workflows.
+package sla
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/rs/zerolog/log"
+)
+
+// Clock provides the current time. It is injectable so the scheduler can be
+// driven deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock backed by time.Now.
+type SystemClock struct{}
+
+// Now returns the current UTC time.
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Leader elects a single scheduler instance across replicas.
+type Leader interface {
+	// TryAcquire attempts to become or remain leader.
+	TryAcquire(ctx context.Context) (bool, error)
+
+	// Release gives up leadership.
+	Release(ctx context.Context) error
+}
+
+// ProposalSource lists proposals that may be in breach of an SLA.
+type ProposalSource interface {
+	// FindInStates retrieves proposals of all tenants whose state or active
+	// region sub-state is one of the given states.
+	FindInStates(ctx context.Context, states []proposal.ProposalState) ([]*proposal.Proposal, error)
+
+	// Save persists a proposal.
+	Save(ctx context.Context, proposal *proposal.Proposal) error
+}
+
+// BreachStore records SLA breaches so each breach is handled only once. A
+// breach is recorded after its escalation succeeded, so a failed escalation
+// is retried on the next scan.
+type BreachStore interface {
+	// IsRecorded reports whether a breach has already been handled.
+	IsRecorded(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, breach proposal.SLABreach) (bool, error)
+
+	// RecordBreach stores a breach and reports whether it was new.
+	RecordBreach(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, breach proposal.SLABreach, action string) (bool, error)
+}
+
+// RecipientResolver resolves the users holding a set of roles.
+type RecipientResolver interface {
+	// UsersWithRoles returns the IDs of active users holding any of the roles.
+	UsersWithRoles(ctx context.Context, tenantID common.TenantID, roles []string) ([]uuid.UUID, error)
+}
+
+// Escalation configures what happens when a state's SLA is breached.
+type Escalation struct {
+	// Transition is fired automatically when set.
+	Transition proposal.ProposalTransition `json:"transition,omitempty"`
+
+	// NotifyRoles are notified of the breach.
+	NotifyRoles []string `json:"notify_roles,omitempty"`
+}
+
+// DefaultEscalations returns the built-in escalation rules.
+func DefaultEscalations() map[proposal.ProposalState]Escalation {
+	return map[proposal.ProposalState]Escalation{
+		proposal.StateInternalReview:  {NotifyRoles: []string{"DEPT_ADMIN"}},
+		proposal.StateDeptReview:      {NotifyRoles: []string{"DEPT_HEAD"}},
+		proposal.StateOSPReview:       {NotifyRoles: []string{"OSP_DIRECTOR"}},
+		proposal.StateReview:          {NotifyRoles: []string{"OSP_DIRECTOR"}},
+		proposal.StateCompliance:      {NotifyRoles: []string{"OSP_DIRECTOR"}},
+		proposal.StateBudgetReview:    {NotifyRoles: []string{"OSP_DIRECTOR"}},
+		proposal.StatePendingApproval: {NotifyRoles: []string{"OSP_DIRECTOR"}},
+	}
+}
+
+// SchedulerConfig contains configuration for the scheduler.
+type SchedulerConfig struct {
+	Interval    time.Duration
+	Clock       Clock
+	Leader      Leader
+	Proposals   ProposalSource
+	Breaches    BreachStore
+	Workflows   *proposal.WorkflowRegistry
+	Publisher   common.EventPublisher
+	Notifier    ports.NotificationService
+	Recipients  RecipientResolver
+	Escalations map[proposal.ProposalState]Escalation
+}
+
+// Scheduler periodically finds proposals whose time in a state exceeds the
+// state's SLA and escalates them.
+type Scheduler struct {
+	interval    time.Duration
+	clock       Clock
+	leader      Leader
+	proposals   ProposalSource
+	breaches    BreachStore
+	workflows   *proposal.WorkflowRegistry
+	publisher   common.EventPublisher
+	notifier    ports.NotificationService
+	recipients  RecipientResolver
+	escalations map[proposal.ProposalState]Escalation
+}
+
+// NewScheduler creates a new SLA scheduler.
+func NewScheduler(cfg SchedulerConfig) *Scheduler {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	clock := cfg.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	workflows := cfg.Workflows
+	if workflows == nil {
+		workflows = proposal.NewWorkflowRegistry()
+	}
+	escalations := cfg.Escalations
+	if escalations == nil {
+		escalations = DefaultEscalations()
+	}
+
+	return &Scheduler{
+		interval:    interval,
+		clock:       clock,
+		leader:      cfg.Leader,
+		proposals:   cfg.Proposals,
+		breaches:    cfg.Breaches,
+		workflows:   workflows,
+		publisher:   cfg.Publisher,
+		notifier:    cfg.Notifier,
+		recipients:  cfg.Recipients,
+		escalations: escalations,
+	}
+}
+
+// Run scans on every interval until the context is cancelled. Only the
+// leader scans; leadership is released on exit.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	defer func() {
+		if s.leader != nil {
+			if err := s.leader.Release(context.Background()); err != nil {
+				log.Error().Err(err).Msg("Failed to release SLA scheduler leadership")
+			}
+		}
+	}()
+
+	for {
+		if s.isLeader(ctx) {
+			if n, err := s.Tick(ctx); err != nil {
+				log.Error().Err(err).Msg("SLA scan failed")
+			} else if n > 0 {
+				log.Info().Int("breaches", n).Msg("SLA breaches escalated")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isLeader reports whether this instance should scan.
+func (s *Scheduler) isLeader(ctx context.Context) bool {
+	if s.leader == nil {
+		return true
+	}
+	ok, err := s.leader.TryAcquire(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to acquire SLA scheduler leadership")
+		return false
+	}
+	return ok
+}
+
+// Tick performs a single scan and returns the number of new breaches handled.
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+
+	// Pick up workflow versions published by other replicas
+	if err := s.workflows.Refresh(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to refresh workflow versions")
+	}
+
+	props, err := s.proposals.FindInStates(ctx, s.slaStates())
+	if err != nil {
+		return 0, fmt.Errorf("failed to find proposals: %w", err)
+	}
+
+	handled := 0
+	for _, prop := range props {
+		sm, err := s.workflows.StateMachine(ctx, prop.TenantID, prop.WorkflowVersion)
+		if err != nil {
+			log.Error().Err(err).Str("proposal_id", prop.ID.String()).Msg("Unknown workflow version")
+			continue
+		}
+
+		for _, breach := range prop.SLABreaches(sm, now) {
+			ok, err := s.handle(ctx, sm, prop, breach)
+			if err != nil {
+				log.Error().Err(err).
+					Str("proposal_id", prop.ID.String()).
+					Str("state", breach.State.String()).
+					Msg("Failed to escalate SLA breach")
+				continue
+			}
+			if ok {
+				handled++
+			}
+		}
+	}
+
+	return handled, nil
+}
+
+// slaStates returns every state with an SLA in any registered workflow.
+func (s *Scheduler) slaStates() []proposal.ProposalState {
+	seen := make(map[proposal.ProposalState]bool)
+	var states []proposal.ProposalState
+	for _, sm := range s.workflows.Machines() {
+		for _, state := range sm.States() {
+			if !seen[state] && sm.Metadata(state).SLAHours > 0 {
+				seen[state] = true
+				states = append(states, state)
+			}
+		}
+	}
+	return states
+}
+
+// handle publishes and escalates a single breach, then records it. It reports
+// false if the breach was already handled. A breach whose escalation fails is
+// not recorded and is retried on the next scan.
+func (s *Scheduler) handle(ctx context.Context, sm *proposal.ProposalStateMachine, prop *proposal.Proposal, breach proposal.SLABreach) (bool, error) {
+	escalation := s.escalations[breach.State]
+
+	action := "notify"
+	if escalation.Transition != "" {
+		action = string(escalation.Transition)
+	}
+
+	if s.breaches != nil {
+		recorded, err := s.breaches.IsRecorded(ctx, prop.TenantID, prop.ID, breach)
+		if err != nil {
+			return false, fmt.Errorf("failed to look up breach: %w", err)
+		}
+		if recorded {
+			return false, nil
+		}
+	}
+
+	event := common.NewProposalSLABreachedEvent(
+		prop.ID,
+		prop.TenantID,
+		prop.Version,
+		breach.State.String(),
+		breach.Region,
+		breach.SLAHours,
+		breach.EnteredAt,
+		breach.DueAt,
+	)
+	if s.publisher != nil {
+		if err := s.publisher.Publish(event); err != nil {
+			return false, fmt.Errorf("failed to publish breach: %w", err)
+		}
+	}
+
+	if s.notifier != nil && s.recipients != nil && len(escalation.NotifyRoles) > 0 {
+		recipients, err := s.recipients.UsersWithRoles(ctx, prop.TenantID, escalation.NotifyRoles)
+		if err != nil {
+			return false, fmt.Errorf("failed to resolve escalation recipients: %w", err)
+		}
+		if len(recipients) > 0 {
+			if err := s.notifier.SendProposalNotification(ctx, prop.ID, "sla_breached", recipients); err != nil {
+				return false, fmt.Errorf("failed to send escalation: %w", err)
+			}
+		}
+	}
+
+	if escalation.Transition != "" {
+		comment := fmt.Sprintf("SLA of %dh for %s breached", breach.SLAHours, breach.State)
+		if err := prop.TransitionInRegion(ctx, sm, breach.Region, escalation.Transition, uuid.Nil, comment); err != nil {
+			return false, fmt.Errorf("failed to fire automatic transition: %w", err)
+		}
+		if err := s.proposals.Save(ctx, prop); err != nil {
+			return false, fmt.Errorf("failed to save proposal: %w", err)
+		}
+	}
+
+	if s.breaches != nil {
+		if _, err := s.breaches.RecordBreach(ctx, prop.TenantID, prop.ID, breach, action); err != nil {
+			return false, fmt.Errorf("failed to record breach: %w", err)
+		}
+	}
+
+	log.Warn().
+		Str("proposal_id", prop.ID.String()).
+		Str("tenant_id", prop.TenantID.String()).
+		Str("state", breach.State.String()).
+		Str("action", action).
+		Time("due_at", breach.DueAt).
+		Msg("Proposal SLA breached")
+
+	return true, nil
+}
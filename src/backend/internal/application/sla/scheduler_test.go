@@ -0,0 +1,119 @@
+package sla
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+type fakeProposals struct{ props []*proposal.Proposal }
+
+func (f *fakeProposals) FindInStates(ctx context.Context, states []proposal.ProposalState) ([]*proposal.Proposal, error) {
+	return f.props, nil
+}
+
+func (f *fakeProposals) Save(ctx context.Context, p *proposal.Proposal) error { return nil }
+
+type fakeBreaches struct{ recorded map[proposal.SLABreach]bool }
+
+func (f *fakeBreaches) IsRecorded(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, breach proposal.SLABreach) (bool, error) {
+	return f.recorded[breach], nil
+}
+
+func (f *fakeBreaches) RecordBreach(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, breach proposal.SLABreach, action string) (bool, error) {
+	isNew := !f.recorded[breach]
+	f.recorded[breach] = true
+	return isNew, nil
+}
+
+type fakeRecipients struct{}
+
+func (fakeRecipients) UsersWithRoles(ctx context.Context, tenantID common.TenantID, roles []string) ([]uuid.UUID, error) {
+	return []uuid.UUID{uuid.New()}, nil
+}
+
+type fakeNotifier struct {
+	failures int
+	sent     int
+}
+
+func (f *fakeNotifier) SendEmail(ctx context.Context, to []string, subject, body string) error {
+	return nil
+}
+
+func (f *fakeNotifier) SendProposalNotification(ctx context.Context, proposalID uuid.UUID, notificationType string, recipients []uuid.UUID) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("mail server unavailable")
+	}
+	f.sent++
+	return nil
+}
+
+func TestSchedulerFiresBreachOnce(t *testing.T) {
+	slaHours := proposal.GetStateMetadata(proposal.StateInternalReview).SLAHours
+
+	tests := []struct {
+		name          string
+		elapsed       time.Duration
+		scans         int
+		notifyFailure int
+		wantHandled   int
+		wantSent      int
+	}{
+		{name: "within SLA", elapsed: time.Duration(slaHours-1) * time.Hour, scans: 2},
+		{name: "past SLA", elapsed: time.Duration(slaHours+1) * time.Hour, scans: 3, wantHandled: 1, wantSent: 1},
+		{name: "failed escalation is retried", elapsed: time.Duration(slaHours+1) * time.Hour, scans: 3, notifyFailure: 1, wantHandled: 1, wantSent: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+			clock := &fakeClock{now: start}
+			prop := &proposal.Proposal{State: proposal.StateInternalReview}
+			prop.ID = uuid.New()
+			prop.TenantID = common.TenantID(uuid.New())
+			prop.CreatedAt = start
+
+			notifier := &fakeNotifier{failures: tt.notifyFailure}
+			breaches := &fakeBreaches{recorded: make(map[proposal.SLABreach]bool)}
+			s := NewScheduler(SchedulerConfig{
+				Clock:      clock,
+				Proposals:  &fakeProposals{props: []*proposal.Proposal{prop}},
+				Breaches:   breaches,
+				Notifier:   notifier,
+				Recipients: fakeRecipients{},
+			})
+
+			clock.now = start.Add(tt.elapsed)
+			handled := 0
+			for i := 0; i < tt.scans; i++ {
+				n, err := s.Tick(context.Background())
+				if err != nil {
+					t.Fatalf("scan %d failed: %v", i, err)
+				}
+				handled += n
+				clock.now = clock.now.Add(time.Hour)
+			}
+
+			if handled != tt.wantHandled {
+				t.Errorf("handled %d breaches, want %d", handled, tt.wantHandled)
+			}
+			if notifier.sent != tt.wantSent {
+				t.Errorf("sent %d escalations, want %d", notifier.sent, tt.wantSent)
+			}
+			if len(breaches.recorded) != tt.wantHandled {
+				t.Errorf("recorded %d breaches, want %d", len(breaches.recorded), tt.wantHandled)
+			}
+		})
+	}
+}
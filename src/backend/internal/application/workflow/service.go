@@ -0,0 +1,142 @@
+// Package workflow provides the application service for workflow definitions.
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+)
+
+// ErrUnauthorized is returned when a user may not manage workflows.
+var ErrUnauthorized = errors.New("unauthorized to manage workflows")
+
+// Service provides application-level operations for workflow definitions.
+type Service struct {
+	registry   *proposal.WorkflowRegistry
+	authorizer authz.Authorizer
+}
+
+// NewService creates a new workflow application service. Workflow documents
+// and pins are stored through the registry's store.
+func NewService(registry *proposal.WorkflowRegistry) *Service {
+	return &Service{
+		registry: registry,
+	}
+}
+
+// UseAuthorizer makes the service decide access with the given policy
+// engine instead of the built-in policies.
+func (s *Service) UseAuthorizer(az authz.Authorizer) *Service {
+	s.authorizer = az
+	return s
+}
+
+// authorize checks an action that applies to the tenant's workflows.
+func (s *Service) authorize(ctx context.Context, tenantCtx common.TenantContext, action string) error {
+	err := authz.Check(ctx, s.authorizer, tenantCtx, authz.Request{Action: action})
+	if errors.Is(err, authz.ErrDenied) {
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return err
+}
+
+// VersionInfo describes a registered workflow version.
+type VersionInfo struct {
+	Version     int    `json:"version"`
+	Description string `json:"description,omitempty"`
+	States      int    `json:"states"`
+	Transitions int    `json:"transitions"`
+	Pinned      bool   `json:"pinned"`
+}
+
+// ListVersions lists the proposal workflow versions of the caller's tenant.
+func (s *Service) ListVersions(ctx context.Context, tenantCtx common.TenantContext) ([]VersionInfo, error) {
+	pinned, err := s.registry.PinnedVersion(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.registry.Versions(ctx, tenantCtx.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []VersionInfo
+	for _, v := range all {
+		def, err := s.registry.Definition(ctx, tenantCtx.TenantID, v)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, VersionInfo{
+			Version:     v,
+			Description: def.Description,
+			States:      len(def.States),
+			Transitions: len(def.Transitions),
+			Pinned:      v == pinned,
+		})
+	}
+	return versions, nil
+}
+
+// GetDefinition returns the workflow document for a version of the caller's
+// tenant.
+func (s *Service) GetDefinition(ctx context.Context, tenantCtx common.TenantContext, version int) (*statemachine.Definition, error) {
+	return s.registry.Definition(ctx, tenantCtx.TenantID, version)
+}
+
+// Diagram renders a workflow version as a DOT or Mermaid diagram. Version 0
+// renders the version the caller's tenant is pinned to.
+func (s *Service) Diagram(ctx context.Context, tenantCtx common.TenantContext, version int, format statemachine.DiagramFormat) (string, error) {
+	if version == 0 {
+		var err error
+		version, err = s.registry.PinnedVersion(ctx, tenantCtx.TenantID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sm, err := s.registry.StateMachine(ctx, tenantCtx.TenantID, version)
+	if err != nil {
+		return "", err
+	}
+
+	return sm.Diagram(format, sm.DiagramOptions())
+}
+
+// Publish validates, stores and registers a new workflow version of the
+// caller's tenant.
+func (s *Service) Publish(ctx context.Context, tenantCtx common.TenantContext, data []byte, format statemachine.Format) (*statemachine.Definition, error) {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionWorkflowPublish); err != nil {
+		return nil, err
+	}
+
+	def, err := statemachine.ParseDefinition(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", statemachine.ErrInvalidDefinition, err)
+	}
+
+	if err := s.registry.Publish(ctx, tenantCtx.TenantID, def); err != nil {
+		return nil, err
+	}
+
+	return def, nil
+}
+
+// Pin pins the caller's tenant to a workflow version for new proposals.
+func (s *Service) Pin(ctx context.Context, tenantCtx common.TenantContext, version int) error {
+	if err := s.authorize(ctx, tenantCtx, authz.ActionWorkflowPin); err != nil {
+		return err
+	}
+
+	return s.registry.Pin(ctx, tenantCtx.TenantID, version)
+}
+
+// PinnedVersion returns the workflow version the caller's tenant is pinned to.
+func (s *Service) PinnedVersion(ctx context.Context, tenantCtx common.TenantContext) (int, error) {
+	return s.registry.PinnedVersion(ctx, tenantCtx.TenantID)
+}
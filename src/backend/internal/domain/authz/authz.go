@@ -0,0 +1,169 @@
+// Package authz provides attribute-based authorization for domain services.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/policy"
+)
+
+// ErrDenied is returned when the policy engine denies an action.
+var ErrDenied = errors.New("access denied by policy")
+
+// ErrPolicyNotFound is returned when a tenant policy does not exist.
+var ErrPolicyNotFound = errors.New("policy not found")
+
+// Actions governed by policies.
+const (
+	ActionProposalCreate     = "proposal.create"
+	ActionProposalRead       = "proposal.read"
+	ActionProposalList       = "proposal.list"
+	ActionProposalUpdate     = "proposal.update"
+	ActionProposalDelete     = "proposal.delete"
+	ActionProposalTransition = "proposal.transition"
+	ActionProposalApprove    = "proposal.approve"
+	ActionProposalRestore    = "proposal.restore"
+	ActionWorkflowPublish    = "workflow.publish"
+	ActionWorkflowPin        = "workflow.pin"
+	ActionDelegationCreate   = "delegation.create"
+	ActionDelegationRevoke   = "delegation.revoke"
+	ActionPolicyManage       = "policy.manage"
+	ActionNumberingManage    = "numbering.manage"
+	ActionEffortRead         = "effort.read"
+	ActionCalendarManage     = "calendar.manage"
+	ActionCalendarSubscribe  = "calendar.subscribe"
+	ActionCalendarRevoke     = "calendar.revoke"
+	ActionAwardManage        = "award.manage"
+	ActionCloseoutManage     = "closeout.manage"
+	ActionCloseoutConfigure  = "closeout.configure"
+	ActionTemplateManage     = "template.manage"
+	ActionRateManage         = "rate.manage"
+	ActionCostShareConfigure = "costshare.configure"
+)
+
+// Request describes an action on a resource to authorize.
+type Request struct {
+	// Action is the action being performed, e.g. proposal.delete.
+	Action string
+
+	// Resource holds the attributes of the resource acted on, if any.
+	Resource map[string]interface{}
+
+	// Context holds attributes of the action itself, e.g. the transition.
+	Context map[string]interface{}
+
+	// DelegatedRoles are roles the subject holds through delegations.
+	DelegatedRoles []string
+}
+
+// Authorizer decides requests against the policies of the caller's tenant.
+type Authorizer interface {
+	Decide(ctx context.Context, tenantCtx common.TenantContext, req Request) (*policy.Decision, error)
+}
+
+// DeniedError reports a denied request together with the decision explaining it.
+type DeniedError struct {
+	Decision *policy.Decision
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDenied, e.Decision.Explain())
+}
+
+// Unwrap returns ErrDenied.
+func (e *DeniedError) Unwrap() error {
+	return ErrDenied
+}
+
+// Check authorizes a request and returns a *DeniedError if it is not allowed.
+// A nil authorizer evaluates the built-in policies.
+func Check(ctx context.Context, az Authorizer, tenantCtx common.TenantContext, req Request) error {
+	if az == nil {
+		az = Defaults()
+	}
+
+	decision, err := az.Decide(ctx, tenantCtx, req)
+	if err != nil {
+		return fmt.Errorf("failed to authorize %s: %w", req.Action, err)
+	}
+	if !decision.Allowed {
+		return &DeniedError{Decision: decision}
+	}
+	return nil
+}
+
+// Input builds the policy input of a request made by the caller.
+func Input(tenantCtx common.TenantContext, req Request) policy.Input {
+	roles := tenantCtx.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	delegated := req.DelegatedRoles
+	if delegated == nil {
+		delegated = []string{}
+	}
+
+	return policy.NewInput(map[string]map[string]interface{}{
+		"subject": {
+			"id":              tenantCtx.UserID,
+			"tenant_id":       tenantCtx.TenantID,
+			"roles":           roles,
+			"delegated_roles": delegated,
+			"department":      tenantCtx.Department,
+		},
+		"resource": req.Resource,
+		"context":  req.Context,
+	})
+}
+
+// TenantPolicy is a policy stored for a tenant. A tenant policy replaces the
+// built-in policy with the same ID; a disabled one removes it.
+type TenantPolicy struct {
+	policy.Policy
+	Enabled   bool      `json:"enabled"`
+	UpdatedBy uuid.UUID `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PolicyRepository persists tenant policies.
+type PolicyRepository interface {
+	// ListPolicies retrieves the policies of a tenant.
+	ListPolicies(ctx context.Context, tenantID common.TenantID) ([]TenantPolicy, error)
+
+	// SavePolicy persists a tenant policy (insert or update).
+	SavePolicy(ctx context.Context, tenantID common.TenantID, p TenantPolicy) error
+
+	// DeletePolicy removes a tenant policy, returning ErrPolicyNotFound if absent.
+	DeletePolicy(ctx context.Context, tenantID common.TenantID, id string) error
+}
+
+// Effective merges tenant policies over the built-in policies.
+func Effective(tenant []TenantPolicy) []policy.Policy {
+	overrides := make(map[string]TenantPolicy, len(tenant))
+	for _, p := range tenant {
+		overrides[p.ID] = p
+	}
+
+	var policies []policy.Policy
+	for _, p := range DefaultPolicies() {
+		if o, ok := overrides[p.ID]; ok {
+			delete(overrides, p.ID)
+			if o.Enabled {
+				policies = append(policies, o.Policy)
+			}
+			continue
+		}
+		policies = append(policies, p)
+	}
+	for _, p := range tenant {
+		if _, ok := overrides[p.ID]; ok && p.Enabled {
+			policies = append(policies, p.Policy)
+		}
+	}
+	return policies
+}
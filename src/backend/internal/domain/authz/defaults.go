@@ -0,0 +1,206 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/policy"
+)
+
+// DefaultPolicies returns the built-in policies every tenant starts with.
+func DefaultPolicies() []policy.Policy {
+	return []policy.Policy{
+		{
+			ID:          "proposal-access",
+			Description: "Any tenant member may create, read, list and edit proposals",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionProposalCreate, ActionProposalRead, ActionProposalList, ActionProposalUpdate},
+		},
+		{
+			ID:          "proposal-delete-owner",
+			Description: "The PI or an administrator may delete a proposal",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionProposalDelete},
+			Condition:   `subject.id == resource.pi_id || "ADMIN" in subject.roles`,
+		},
+		{
+			ID:          "proposal-restore-owner",
+			Description: "The PI or an administrator may restore a proposal version as a new draft",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionProposalRestore},
+			Condition:   `subject.id == resource.pi_id || "ADMIN" in subject.roles`,
+		},
+		{
+			ID:          "proposal-transition-role",
+			Description: "Holders of a required role of the current state may transition it",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionProposalTransition},
+			Condition:   `intersects(subject.roles, context.required_roles)`,
+		},
+		{
+			ID:          "proposal-transition-delegated",
+			Description: "Delegates of a required role of the current state may transition it",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionProposalTransition},
+			Condition:   `intersects(subject.delegated_roles, context.required_roles)`,
+		},
+		{
+			ID:          "proposal-withdraw-pi",
+			Description: "The PI may always withdraw their own proposal",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionProposalTransition},
+			Condition:   `context.transition == "WITHDRAW" && subject.id == resource.pi_id`,
+		},
+		{
+			ID:          "proposal-approve",
+			Description: "Any tenant member may vote; approval sets decide whose vote counts",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionProposalApprove},
+		},
+		{
+			ID:          "workflow-admin",
+			Description: "Administrators may publish and pin workflow versions",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{"workflow.*"},
+			Condition:   `"ADMIN" in subject.roles`,
+		},
+		{
+			ID:          "delegation-own-role",
+			Description: "Users may delegate roles they hold",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionDelegationCreate},
+			Condition:   `subject.id == resource.delegator_id && resource.role in subject.roles`,
+		},
+		{
+			ID:          "delegation-revoke-owner",
+			Description: "The delegator or creator of a delegation may revoke it",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionDelegationRevoke},
+			Condition:   `subject.id == resource.delegator_id || subject.id == resource.created_by`,
+		},
+		{
+			ID:          "delegation-admin",
+			Description: "Administrators may manage any delegation",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{"delegation.*"},
+			Condition:   `"ADMIN" in subject.roles`,
+		},
+		{
+			ID:          "policy-admin",
+			Description: "Administrators may manage tenant policies",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{"policy.*"},
+			Condition:   `"ADMIN" in subject.roles`,
+		},
+		{
+			ID:          "numbering-admin",
+			Description: "Administrators may configure proposal numbering",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionNumberingManage},
+			Condition:   `"ADMIN" in subject.roles`,
+		},
+		{
+			ID:          "effort-read",
+			Description: "People may view their own effort; research administrators may view anyone's",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionEffortRead},
+			Condition:   `subject.id == resource.person_id || intersects(subject.roles, ["DEPT_ADMIN", "DEPT_HEAD", "OSP_OFFICER", "OSP_DIRECTOR", "GRANTS_ADMIN", "ADMIN"])`,
+		},
+		{
+			ID:          "calendar-admin",
+			Description: "Administrators and the OSP director may configure deadline rules and holidays",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionCalendarManage},
+			Condition:   `intersects(subject.roles, ["OSP_DIRECTOR", "ADMIN"])`,
+		},
+		{
+			ID:          "calendar-subscribe-own",
+			Description: "Users may subscribe to their own deadlines and their department's",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionCalendarSubscribe},
+			Condition:   `(resource.scope == "user" && resource.user_id == subject.id) || (resource.scope == "department" && resource.department == subject.department)`,
+		},
+		{
+			ID:          "calendar-subscribe-admin",
+			Description: "Research administrators may subscribe to any deadlines",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionCalendarSubscribe},
+			Condition:   `intersects(subject.roles, ["OSP_OFFICER", "OSP_DIRECTOR", "GRANTS_ADMIN", "ADMIN"])`,
+		},
+		{
+			ID:          "calendar-revoke-owner",
+			Description: "The creator of a calendar feed or an administrator may revoke it",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionCalendarRevoke},
+			Condition:   `subject.id == resource.created_by || "ADMIN" in subject.roles`,
+		},
+		{
+			ID:          "award-admin",
+			Description: "Sponsored programs staff may set up awards and record modifications",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionAwardManage},
+			Condition:   `intersects(subject.roles, ["OSP_OFFICER", "OSP_DIRECTOR", "GRANTS_ADMIN", "ADMIN"])`,
+		},
+		{
+			ID:          "closeout-manage",
+			Description: "The PI and sponsored programs staff may work off a proposal's closeout checklist",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionCloseoutManage},
+			Condition:   `subject.id == resource.pi_id || intersects(subject.roles, ["OSP_OFFICER", "OSP_DIRECTOR", "GRANTS_ADMIN", "ADMIN"])`,
+		},
+		{
+			ID:          "closeout-configure",
+			Description: "Administrators and the OSP director may configure closeout templates",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionCloseoutConfigure},
+			Condition:   `intersects(subject.roles, ["OSP_DIRECTOR", "ADMIN"])`,
+		},
+		{
+			ID:          "template-manage",
+			Description: "Department administrators may manage their department's proposal templates; OSP and administrators may manage all",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionTemplateManage},
+			Condition:   `(resource.department == subject.department && intersects(subject.roles, ["DEPT_ADMIN", "DEPT_HEAD"])) || intersects(subject.roles, ["OSP_DIRECTOR", "GRANTS_ADMIN", "ADMIN"])`,
+		},
+		{
+			ID:          "rate-manage",
+			Description: "The OSP director, grants administrators and administrators may maintain negotiated rate agreements",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionRateManage},
+			Condition:   `intersects(subject.roles, ["OSP_DIRECTOR", "GRANTS_ADMIN", "ADMIN"])`,
+		},
+		{
+			ID:          "costshare-configure",
+			Description: "The OSP director, grants administrators and administrators may set the cost share match a sponsor requires",
+			Effect:      policy.EffectAllow,
+			Actions:     []string{ActionCostShareConfigure},
+			Condition:   `intersects(subject.roles, ["OSP_DIRECTOR", "GRANTS_ADMIN", "ADMIN"])`,
+		},
+	}
+}
+
+// defaultSet is the compiled set of built-in policies.
+var defaultSet = mustSet(DefaultPolicies())
+
+func mustSet(policies []policy.Policy) *policy.Set {
+	set, err := policy.NewSet(policies)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// SetAuthorizer decides every request against a fixed policy set.
+type SetAuthorizer struct {
+	Set *policy.Set
+}
+
+// Decide evaluates the request against the set.
+func (a SetAuthorizer) Decide(ctx context.Context, tenantCtx common.TenantContext, req Request) (*policy.Decision, error) {
+	return a.Set.Evaluate(req.Action, Input(tenantCtx, req)), nil
+}
+
+// Defaults returns an Authorizer evaluating only the built-in policies.
+func Defaults() Authorizer {
+	return SetAuthorizer{Set: defaultSet}
+}
@@ -0,0 +1,406 @@
+// Package budget provides negotiated rate agreements and looking up the
+// rates a budget uses from them.
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateAgreementNotFound is returned when a rate agreement does not exist.
+var ErrRateAgreementNotFound = errors.New("rate agreement not found")
+
+// ErrInvalidRateAgreement is returned when a rate agreement is malformed.
+var ErrInvalidRateAgreement = errors.New("invalid rate agreement")
+
+// ErrRateAgreementInUse is returned when deleting an agreement budgets use.
+var ErrRateAgreementInUse = errors.New("rate agreement is used by budgets")
+
+// ErrNoNegotiatedRate is returned when an agreement has no rate for a
+// budget's activity type on a day of the budget.
+var ErrNoNegotiatedRate = errors.New("no negotiated rate covers the budget")
+
+// ActivityType is the kind of sponsored activity a rate applies to.
+type ActivityType string
+
+const (
+	ActivityResearch       ActivityType = "research"
+	ActivityInstruction    ActivityType = "instruction"
+	ActivityOtherSponsored ActivityType = "other_sponsored"
+)
+
+// ActivityTypes returns the activity types in display order.
+func ActivityTypes() []ActivityType {
+	return []ActivityType{ActivityResearch, ActivityInstruction, ActivityOtherSponsored}
+}
+
+// IsValid reports whether the activity type is known.
+func (t ActivityType) IsValid() bool {
+	switch t {
+	case ActivityResearch, ActivityInstruction, ActivityOtherSponsored:
+		return true
+	}
+	return false
+}
+
+// orDefault returns the activity type, or research if it is empty.
+func (t ActivityType) orDefault() ActivityType {
+	if t == "" {
+		return ActivityResearch
+	}
+	return t
+}
+
+// AgreementStatus tells whether an agreement's rates are settled.
+type AgreementStatus string
+
+const (
+	// AgreementProvisional rates are billed until final rates are negotiated.
+	AgreementProvisional AgreementStatus = "provisional"
+	// AgreementFinal rates are settled for their effective dates.
+	AgreementFinal AgreementStatus = "final"
+)
+
+// NegotiatedRate is the F&A rate of an activity type over a date range.
+// EffectiveTo is inclusive; a zero EffectiveTo leaves the rate open-ended.
+type NegotiatedRate struct {
+	ActivityType  ActivityType    `json:"activity_type"`
+	OnCampusRate  decimal.Decimal `json:"on_campus_rate"`
+	OffCampusRate decimal.Decimal `json:"off_campus_rate"`
+	EffectiveFrom time.Time       `json:"effective_from"`
+	EffectiveTo   time.Time       `json:"effective_to,omitempty"`
+}
+
+// FringeRate is the fringe benefit rate of an employee class over a date
+// range. EffectiveTo is inclusive; a zero EffectiveTo leaves it open-ended.
+type FringeRate struct {
+	EmployeeClass string          `json:"employee_class"` // e.g. faculty, staff, postdoc, graduate_student
+	Rate          decimal.Decimal `json:"rate"`
+	EffectiveFrom time.Time       `json:"effective_from"`
+	EffectiveTo   time.Time       `json:"effective_to,omitempty"`
+}
+
+// covers reports whether a date falls in an inclusive date range.
+func covers(from, to, date time.Time) bool {
+	day := civilDate(date)
+	return !civilDate(from).After(day) && (to.IsZero() || !civilDate(to).Before(day))
+}
+
+// overlaps reports whether two inclusive date ranges share a day.
+func overlaps(from1, to1, from2, to2 time.Time) bool {
+	return (to2.IsZero() || !civilDate(from1).After(civilDate(to2))) &&
+		(to1.IsZero() || !civilDate(from2).After(civilDate(to1)))
+}
+
+// RateAgreement is a tenant's negotiated F&A and fringe rate agreement with
+// its cognizant agency.
+type RateAgreement struct {
+	common.BaseEntity
+	Name            string           `json:"name"`
+	CognizantAgency string           `json:"cognizant_agency,omitempty"` // e.g. DHHS, ONR
+	AgreementDate   *time.Time       `json:"agreement_date,omitempty"`
+	Status          AgreementStatus  `json:"status"`
+	RateType        string           `json:"rate_type"` // F&A base: MTDC, TDC or S&W
+	SubawardCap     decimal.Decimal  `json:"subaward_cap"`
+	EquipmentCap    decimal.Decimal  `json:"equipment_cap"`
+	ExcludedItems   []string         `json:"excluded_items"`
+	Rates           []NegotiatedRate `json:"rates"`
+	FringeRates     []FringeRate     `json:"fringe_rates"`
+	Notes           string           `json:"notes,omitempty"`
+}
+
+// NewRateAgreement creates a provisional agreement with the MTDC base and
+// exclusions of DefaultFARate.
+func NewRateAgreement(tenantID common.TenantID, userID uuid.UUID, name string) *RateAgreement {
+	defaults := DefaultFARate()
+	return &RateAgreement{
+		BaseEntity:    common.NewBaseEntity(tenantID, userID),
+		Name:          name,
+		Status:        AgreementProvisional,
+		RateType:      defaults.RateType,
+		SubawardCap:   defaults.SubawardCap,
+		EquipmentCap:  defaults.EquipmentCap,
+		ExcludedItems: defaults.ExcludedItems,
+		Rates:         make([]NegotiatedRate, 0),
+		FringeRates:   make([]FringeRate, 0),
+	}
+}
+
+// Validate checks the agreement's settings and that the date ranges of an
+// activity type's rates, and of an employee class's fringe rates, do not
+// overlap.
+func (a *RateAgreement) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRateAgreement)
+	}
+	if a.Status != AgreementProvisional && a.Status != AgreementFinal {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidRateAgreement, a.Status)
+	}
+	if !IsKnownRateType(a.RateType) {
+		return fmt.Errorf("%w: unknown F&A rate type %q", ErrInvalidRateAgreement, a.RateType)
+	}
+	if a.SubawardCap.IsNegative() || a.EquipmentCap.IsNegative() {
+		return fmt.Errorf("%w: F&A caps cannot be negative", ErrInvalidRateAgreement)
+	}
+	if len(a.Rates) == 0 {
+		return fmt.Errorf("%w: at least one negotiated rate is required", ErrInvalidRateAgreement)
+	}
+
+	one := decimal.NewFromInt(1)
+	for i, r := range a.Rates {
+		if !r.ActivityType.IsValid() {
+			return fmt.Errorf("%w: unknown activity type %q", ErrInvalidRateAgreement, r.ActivityType)
+		}
+		if r.OnCampusRate.IsNegative() || r.OnCampusRate.GreaterThan(one) ||
+			r.OffCampusRate.IsNegative() || r.OffCampusRate.GreaterThan(one) {
+			return fmt.Errorf("%w: %s rates must be between 0 and 100%%", ErrInvalidRateAgreement, r.ActivityType)
+		}
+		if err := validateRange(r.EffectiveFrom, r.EffectiveTo); err != nil {
+			return fmt.Errorf("%w: %s rate %v", ErrInvalidRateAgreement, r.ActivityType, err)
+		}
+		for _, other := range a.Rates[:i] {
+			if other.ActivityType == r.ActivityType && overlaps(r.EffectiveFrom, r.EffectiveTo, other.EffectiveFrom, other.EffectiveTo) {
+				return fmt.Errorf("%w: %s rates overlap", ErrInvalidRateAgreement, r.ActivityType)
+			}
+		}
+	}
+
+	for i, f := range a.FringeRates {
+		class := normalizeCategory(f.EmployeeClass)
+		if class == "" {
+			return fmt.Errorf("%w: fringe rates need an employee class", ErrInvalidRateAgreement)
+		}
+		if f.Rate.IsNegative() || f.Rate.GreaterThan(one) {
+			return fmt.Errorf("%w: %s fringe rate must be between 0 and 100%%", ErrInvalidRateAgreement, f.EmployeeClass)
+		}
+		if err := validateRange(f.EffectiveFrom, f.EffectiveTo); err != nil {
+			return fmt.Errorf("%w: %s fringe rate %v", ErrInvalidRateAgreement, f.EmployeeClass, err)
+		}
+		for _, other := range a.FringeRates[:i] {
+			if normalizeCategory(other.EmployeeClass) == class && overlaps(f.EffectiveFrom, f.EffectiveTo, other.EffectiveFrom, other.EffectiveTo) {
+				return fmt.Errorf("%w: %s fringe rates overlap", ErrInvalidRateAgreement, f.EmployeeClass)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRange checks that a date range starts and does not end before it
+// starts.
+func validateRange(from, to time.Time) error {
+	if from.IsZero() {
+		return errors.New("needs an effective date")
+	}
+	if !to.IsZero() && civilDate(to).Before(civilDate(from)) {
+		return errors.New("ends before it takes effect")
+	}
+	return nil
+}
+
+// ratesFor returns the rates of an activity type by effective date.
+func (a *RateAgreement) ratesFor(activity ActivityType) []NegotiatedRate {
+	rates := make([]NegotiatedRate, 0, len(a.Rates))
+	for _, r := range a.Rates {
+		if r.ActivityType == activity {
+			rates = append(rates, r)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].EffectiveFrom.Before(rates[j].EffectiveFrom) })
+	return rates
+}
+
+// CoversDate reports whether the agreement has a rate for an activity type
+// in effect on a date.
+func (a *RateAgreement) CoversDate(activity ActivityType, date time.Time) bool {
+	for _, r := range a.ratesFor(activity.orDefault()) {
+		if covers(r.EffectiveFrom, r.EffectiveTo, date) {
+			return true
+		}
+	}
+	return false
+}
+
+// FARateFor returns the F&A rate of an activity type from start through end.
+// The rate in effect on start is the base rate; rates taking effect later
+// become effective rates. Like CoversDate, a rate is in effect only through
+// its end date, so every day from start through end, or through the last
+// rate for an open end, needs a rate in effect.
+func (a *RateAgreement) FARateFor(activity ActivityType, isOnCampus bool, start, end time.Time) (FARate, error) {
+	activity = activity.orDefault()
+	rates := a.ratesFor(activity)
+	noRate := func(date time.Time) error {
+		return fmt.Errorf("%w: %s has no %s rate on %s", ErrNoNegotiatedRate, a.Name, activity, date.Format("2006-01-02"))
+	}
+
+	current := -1
+	for i, r := range rates {
+		if covers(r.EffectiveFrom, r.EffectiveTo, start) {
+			current = i
+		}
+	}
+	if current < 0 {
+		return FARate{}, noRate(start)
+	}
+
+	// A rate ending before end must be followed by one the next day
+	for i := current; i < len(rates); i++ {
+		to := rates[i].EffectiveTo
+		if to.IsZero() || (!end.IsZero() && !civilDate(to).Before(civilDate(end))) {
+			break
+		}
+		next := civilDate(to).AddDate(0, 0, 1)
+		if i+1 < len(rates) && !civilDate(rates[i+1].EffectiveFrom).After(next) {
+			continue
+		}
+		if end.IsZero() && i+1 == len(rates) {
+			break
+		}
+		return FARate{}, noRate(next)
+	}
+
+	fa := FARate{
+		RateType:      a.RateType,
+		OnCampusRate:  rates[current].OnCampusRate,
+		OffCampusRate: rates[current].OffCampusRate,
+		IsOnCampus:    isOnCampus,
+		EquipmentCap:  a.EquipmentCap,
+		SubawardCap:   a.SubawardCap,
+		ExcludedItems: append([]string(nil), a.ExcludedItems...),
+	}
+	for _, r := range rates[current+1:] {
+		if !end.IsZero() && civilDate(r.EffectiveFrom).After(civilDate(end)) {
+			break
+		}
+		fa.EffectiveRates = append(fa.EffectiveRates, EffectiveRate{
+			EffectiveDate: civilDate(r.EffectiveFrom),
+			OnCampusRate:  r.OnCampusRate,
+			OffCampusRate: r.OffCampusRate,
+		})
+	}
+	return fa, nil
+}
+
+// FringeRateOn returns the fringe rate of an employee class on a date.
+func (a *RateAgreement) FringeRateOn(employeeClass string, date time.Time) (decimal.Decimal, bool) {
+	class := normalizeCategory(employeeClass)
+	for _, f := range a.FringeRates {
+		if normalizeCategory(f.EmployeeClass) == class && covers(f.EffectiveFrom, f.EffectiveTo, date) {
+			return f.Rate, true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// SelectAgreement returns the agreement to use for an activity type on a
+// date: among those with a rate in effect then, final agreements are
+// preferred over provisional ones, then the latest agreement. It returns
+// nil if none covers the date.
+func SelectAgreement(agreements []*RateAgreement, activity ActivityType, date time.Time) *RateAgreement {
+	var best *RateAgreement
+	for _, a := range agreements {
+		if !a.CoversDate(activity, date) {
+			continue
+		}
+		if best == nil || agreementRank(a).after(agreementRank(best)) {
+			best = a
+		}
+	}
+	return best
+}
+
+// rank orders agreements for SelectAgreement.
+type rank struct {
+	final bool
+	date  time.Time
+}
+
+// agreementRank returns the rank of an agreement; undated agreements rank
+// by when they were entered.
+func agreementRank(a *RateAgreement) rank {
+	date := a.CreatedAt
+	if a.AgreementDate != nil {
+		date = *a.AgreementDate
+	}
+	return rank{final: a.Status == AgreementFinal, date: date}
+}
+
+// after reports whether r ranks above other.
+func (r rank) after(other rank) bool {
+	if r.final != other.final {
+		return r.final
+	}
+	return r.date.After(other.date)
+}
+
+// DateRange returns the first day of the budget's first period and the last
+// day of its last period. A budget without periods covers the day it was
+// created.
+func (b *Budget) DateRange() (time.Time, time.Time) {
+	if len(b.Periods) == 0 {
+		return b.CreatedAt, b.CreatedAt
+	}
+	start, end := b.Periods[0].StartDate, b.Periods[0].EndDate
+	for _, p := range b.Periods[1:] {
+		if p.StartDate.Before(start) {
+			start = p.StartDate
+		}
+		if p.EndDate.After(end) {
+			end = p.EndDate
+		}
+	}
+	return start, end
+}
+
+// ApplyAgreement sets the budget's F&A rate from an agreement for the
+// budget's dates and activity type, and the fringe rates of personnel with
+// an employee class the agreement rates on their period's start. The budget
+// records the agreement; costs are updated by Recalculate.
+func (c *Calculator) ApplyAgreement(b *Budget, a *RateAgreement) error {
+	start, end := b.DateRange()
+	fa, err := a.FARateFor(b.ActivityType, b.FARate.IsOnCampus, start, end)
+	if err != nil {
+		return err
+	}
+
+	for i := range b.Periods {
+		period := &b.Periods[i]
+		for j := range period.Personnel {
+			p := &period.Personnel[j]
+			if p.EmployeeClass == "" {
+				continue
+			}
+			if rate, ok := a.FringeRateOn(p.EmployeeClass, period.StartDate); ok {
+				p.FringeRate = rate
+			}
+		}
+	}
+
+	c.FARate = fa
+	b.FARate = fa
+	b.ActivityType = b.ActivityType.orDefault()
+	b.RateAgreementID = &a.ID
+	return nil
+}
+
+// RateAgreementRepository persists a tenant's rate agreements.
+type RateAgreementRepository interface {
+	// SaveAgreement stores an agreement with its rates.
+	SaveAgreement(ctx context.Context, a *RateAgreement) error
+
+	// FindAgreement retrieves an agreement by ID, or nil.
+	FindAgreement(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*RateAgreement, error)
+
+	// ListAgreements retrieves the tenant's agreements, latest first.
+	ListAgreements(ctx context.Context, tenantID common.TenantID) ([]*RateAgreement, error)
+
+	// DeleteAgreement removes an agreement no budget uses.
+	DeleteAgreement(ctx context.Context, tenantID common.TenantID, id uuid.UUID) error
+}
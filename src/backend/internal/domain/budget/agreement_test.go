@@ -0,0 +1,49 @@
+package budget
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFARateForRespectsEffectiveTo(t *testing.T) {
+	fy26 := NegotiatedRate{ActivityType: ActivityResearch, OnCampusRate: dec("0.5"), EffectiveFrom: day(2025, time.July, 1), EffectiveTo: day(2026, time.June, 30)}
+	fy27 := NegotiatedRate{ActivityType: ActivityResearch, OnCampusRate: dec("0.55"), EffectiveFrom: day(2026, time.July, 1)}
+
+	tests := []struct {
+		name       string
+		rates      []NegotiatedRate
+		start, end time.Time
+		covered    bool // whether CoversDate holds on start
+		wantErr    bool
+		effective  int
+	}{
+		{name: "within a closed rate", rates: []NegotiatedRate{fy26}, start: day(2025, time.September, 1), end: day(2026, time.June, 30), covered: true},
+		{name: "starts after the rate ended", rates: []NegotiatedRate{fy26}, start: day(2026, time.September, 1), end: day(2027, time.August, 31), wantErr: true},
+		{name: "rate ends mid-period without a successor", rates: []NegotiatedRate{fy26}, start: day(2026, time.January, 1), end: day(2026, time.December, 31), covered: true, wantErr: true},
+		{name: "successor takes over the next day", rates: []NegotiatedRate{fy26, fy27}, start: day(2026, time.January, 1), end: day(2026, time.December, 31), covered: true, effective: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &RateAgreement{Name: "FY26", RateType: RateTypeMTDC, Rates: tt.rates}
+			if covered := a.CoversDate(ActivityResearch, tt.start); covered != tt.covered {
+				t.Errorf("CoversDate(%s) = %v, want %v", tt.start.Format("2006-01-02"), covered, tt.covered)
+			}
+
+			fa, err := a.FARateFor(ActivityResearch, true, tt.start, tt.end)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoNegotiatedRate) {
+					t.Fatalf("expected ErrNoNegotiatedRate, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(fa.EffectiveRates) != tt.effective {
+				t.Errorf("got %d effective rates, want %d", len(fa.EffectiveRates), tt.effective)
+			}
+		})
+	}
+}
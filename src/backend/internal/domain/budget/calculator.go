@@ -2,6 +2,7 @@
 package budget
 
 import (
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
@@ -147,6 +148,12 @@ func (c *Calculator) ValidateBudget(budget *Budget) []BudgetValidationError {
 		})
 	}
 
+	// Check modular budgets stay under the ceiling
+	errors = append(errors, c.validateModular(budget)...)
+
+	// Check cost share meets the sponsor's required match
+	errors = append(errors, c.validateCostShare(budget)...)
+
 	return errors
 }
 
@@ -189,6 +196,7 @@ func (c *Calculator) ProjectBudget(template BudgetPeriod, years int, inflationRa
 			adjusted := e
 			if year > 1 {
 				// Equipment typically only in year 1
+				adjusted.Quantity = 0
 				adjusted.TotalCost = decimal.Zero
 			}
 			period.Equipment = append(period.Equipment, adjusted)
@@ -233,6 +241,73 @@ func (c *Calculator) ProjectBudget(template BudgetPeriod, years int, inflationRa
 	return periods
 }
 
+// Recalculate recomputes the derived costs of every line item of a budget
+// from the item's inputs. Supplies, contractual and other costs are entered
+// as totals and kept.
+func (c *Calculator) Recalculate(budget *Budget) {
+	for i := range budget.Periods {
+		period := &budget.Periods[i]
+
+		for j := range period.Personnel {
+			p := &period.Personnel[j]
+			result := c.CalculatePersonnelCost(p.BaseSalary, p.EffortPercent, p.FringeRate, PersonnelMonths{
+				Calendar: p.CalendarMonths,
+				Academic: p.AcademicMonths,
+				Summer:   p.SummerMonths,
+			})
+			p.RequestedSalary = result.RequestedSalary
+			p.FringeBenefits = result.FringeBenefits
+			p.TotalCost = result.TotalCost
+		}
+
+		for j := range period.Equipment {
+			e := &period.Equipment[j]
+			e.TotalCost = c.CalculateEquipmentCost(e.Quantity, e.UnitCost)
+		}
+
+		for j := range period.Travel {
+			t := &period.Travel[j]
+			t.TotalCost = c.CalculateTravelCost(t.Travelers, t.TripCount, t.CostPerTrip)
+		}
+
+		for j := range period.Subawards {
+			s := &period.Subawards[j]
+			s.TotalCost = s.DirectCosts.Add(s.IndirectCosts)
+		}
+	}
+}
+
+// PeriodTotals contains the calculated totals of a budget period.
+type PeriodTotals struct {
+	PeriodID      uuid.UUID       `json:"period_id"`
+	PeriodNumber  int             `json:"period_number"`
+	DirectCosts   decimal.Decimal `json:"direct_costs"`
+	FABase        decimal.Decimal `json:"fa_base"`
+	FARate        decimal.Decimal `json:"fa_rate"` // prorated over rate changes
+	IndirectCosts decimal.Decimal `json:"indirect_costs"`
+	Total         decimal.Decimal `json:"total"`
+}
+
+// CalculatePeriodTotals calculates the direct, base and indirect costs of
+// every period of a budget.
+func (c *Calculator) CalculatePeriodTotals(budget *Budget) []PeriodTotals {
+	totals := make([]PeriodTotals, len(budget.Periods))
+	for i, fa := range c.CalculateFA(budget.Periods) {
+		period := &budget.Periods[i]
+		direct := period.TotalDirectCosts()
+		totals[i] = PeriodTotals{
+			PeriodID:      period.ID,
+			PeriodNumber:  period.PeriodNumber,
+			DirectCosts:   direct,
+			FABase:        fa.Base,
+			FARate:        fa.Rate.Round(6),
+			IndirectCosts: fa.IndirectCosts,
+			Total:         direct.Add(fa.IndirectCosts),
+		}
+	}
+	return totals
+}
+
 // CostSharingCalculator calculates cost sharing requirements.
 type CostSharingCalculator struct{}
 
@@ -0,0 +1,297 @@
+// Package budget provides cost sharing of budget line items.
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrMatchShortfall is returned when a budget's cost share falls short of the
+// sponsor's required match.
+var ErrMatchShortfall = errors.New("cost share does not meet the sponsor's required match")
+
+// CostShareType is the kind of commitment a cost share is.
+type CostShareType string
+
+const (
+	CostShareMandatory          CostShareType = "mandatory"           // required by the sponsor
+	CostShareVoluntaryCommitted CostShareType = "voluntary_committed" // offered beyond the requirement
+	CostShareThirdPartyInKind   CostShareType = "third_party_in_kind" // contributed by a third party
+)
+
+// IsValid reports whether the cost share type is known.
+func (t CostShareType) IsValid() bool {
+	switch t {
+	case CostShareMandatory, CostShareVoluntaryCommitted, CostShareThirdPartyInKind:
+		return true
+	}
+	return false
+}
+
+// FundingSourceType is where cost share is funded from.
+type FundingSourceType string
+
+const (
+	FundingDepartmentAccount FundingSourceType = "department_account"
+	FundingThirdParty        FundingSourceType = "third_party"
+)
+
+// IsValid reports whether the funding source type is known.
+func (t FundingSourceType) IsValid() bool {
+	return t == FundingDepartmentAccount || t == FundingThirdParty
+}
+
+// CostShare is a commitment to fund part of a line item's costs outside the
+// sponsor's request. The line item's total cost is what the sponsor is asked
+// for; cost share comes on top of it.
+type CostShare struct {
+	Type       CostShareType     `json:"type"`
+	SourceType FundingSourceType `json:"source_type"`
+	Source     string            `json:"source"` // department account number or third party name
+	Amount     decimal.Decimal   `json:"amount"`
+}
+
+// Validate checks the type, source and amount of the cost share. In-kind
+// contributions of a third party cannot come from a department account.
+func (cs CostShare) Validate() error {
+	if !cs.Type.IsValid() {
+		return fmt.Errorf("%w: unknown cost share type %q", ErrInvalidLineItem, cs.Type)
+	}
+	if !cs.SourceType.IsValid() {
+		return fmt.Errorf("%w: unknown cost share source type %q", ErrInvalidLineItem, cs.SourceType)
+	}
+	if cs.Type == CostShareThirdPartyInKind && cs.SourceType != FundingThirdParty {
+		return fmt.Errorf("%w: third-party in-kind cost share must come from a third party", ErrInvalidLineItem)
+	}
+	if strings.TrimSpace(cs.Source) == "" {
+		return fmt.Errorf("%w: cost share needs a funding source", ErrInvalidLineItem)
+	}
+	if !cs.Amount.IsPositive() {
+		return fmt.Errorf("%w: cost share from %s must be positive", ErrInvalidLineItem, cs.Source)
+	}
+	return nil
+}
+
+// copyCostShares returns a copy of cost shares that shares no memory with them.
+func copyCostShares(shares []CostShare) []CostShare {
+	return append([]CostShare(nil), shares...)
+}
+
+// CostShares returns the cost share of the line item.
+func (li LineItem) CostShares() []CostShare {
+	switch li.Type {
+	case CostTypePersonnel:
+		return li.Personnel.CostShare
+	case CostTypeEquipment:
+		return li.Equipment.CostShare
+	case CostTypeTravel:
+		return li.Travel.CostShare
+	case CostTypeSupplies:
+		return li.Supplies.CostShare
+	case CostTypeContractual:
+		return li.Contractual.CostShare
+	case CostTypeOther:
+		return li.Other.CostShare
+	case CostTypeSubaward:
+		return li.Subaward.CostShare
+	}
+	return nil
+}
+
+// CostShareTotals are cost share amounts by type and funding source.
+type CostShareTotals struct {
+	Mandatory          decimal.Decimal `json:"mandatory"`
+	VoluntaryCommitted decimal.Decimal `json:"voluntary_committed"`
+	ThirdPartyInKind   decimal.Decimal `json:"third_party_in_kind"`
+	DepartmentAccounts decimal.Decimal `json:"department_accounts"`
+	ThirdParties       decimal.Decimal `json:"third_parties"`
+	Total              decimal.Decimal `json:"total"`
+	FAForgone          decimal.Decimal `json:"fa_forgone"` // F&A not recovered on department-funded cost share
+}
+
+// add counts a cost share in the totals.
+func (t *CostShareTotals) add(cs CostShare) {
+	switch cs.Type {
+	case CostShareMandatory:
+		t.Mandatory = t.Mandatory.Add(cs.Amount)
+	case CostShareVoluntaryCommitted:
+		t.VoluntaryCommitted = t.VoluntaryCommitted.Add(cs.Amount)
+	case CostShareThirdPartyInKind:
+		t.ThirdPartyInKind = t.ThirdPartyInKind.Add(cs.Amount)
+	}
+	if cs.SourceType == FundingDepartmentAccount {
+		t.DepartmentAccounts = t.DepartmentAccounts.Add(cs.Amount)
+	} else {
+		t.ThirdParties = t.ThirdParties.Add(cs.Amount)
+	}
+	t.Total = t.Total.Add(cs.Amount)
+}
+
+// plus returns the sum of two totals.
+func (t CostShareTotals) plus(o CostShareTotals) CostShareTotals {
+	return CostShareTotals{
+		Mandatory:          t.Mandatory.Add(o.Mandatory),
+		VoluntaryCommitted: t.VoluntaryCommitted.Add(o.VoluntaryCommitted),
+		ThirdPartyInKind:   t.ThirdPartyInKind.Add(o.ThirdPartyInKind),
+		DepartmentAccounts: t.DepartmentAccounts.Add(o.DepartmentAccounts),
+		ThirdParties:       t.ThirdParties.Add(o.ThirdParties),
+		Total:              t.Total.Add(o.Total),
+		FAForgone:          t.FAForgone.Add(o.FAForgone),
+	}
+}
+
+// PeriodCostShare is the cost share of a budget period.
+type PeriodCostShare struct {
+	PeriodID     uuid.UUID `json:"period_id"`
+	PeriodNumber int       `json:"period_number"`
+	CostShareTotals
+}
+
+// SourceCostShare is the cost share committed from one funding source.
+type SourceCostShare struct {
+	SourceType FundingSourceType `json:"source_type"`
+	Source     string            `json:"source"`
+	Amount     decimal.Decimal   `json:"amount"`
+}
+
+// CostShareBreakdown is the cost share of a budget by period and funding
+// source, measured against the sponsor's required match. Mandatory and
+// third-party in-kind cost share count toward the match; voluntary committed
+// cost share does not.
+type CostShareBreakdown struct {
+	CostShareTotals
+	Periods              []PeriodCostShare `json:"periods"`
+	Sources              []SourceCostShare `json:"sources"`
+	RequiredMatchPercent decimal.Decimal   `json:"required_match_percent"`
+	RequiredMatch        decimal.Decimal   `json:"required_match"`
+	CountedMatch         decimal.Decimal   `json:"counted_match"`
+	Shortfall            decimal.Decimal   `json:"shortfall"`
+}
+
+// SetRequiredMatch sets the sponsor's required match, in percent of the
+// sponsor's request.
+func (b *Budget) SetRequiredMatch(percent decimal.Decimal) error {
+	if percent.IsNegative() {
+		return fmt.Errorf("%w: required match cannot be negative", ErrInvalidBudget)
+	}
+	b.RequiredMatchPercent = percent
+	return nil
+}
+
+// CheckMatch fails if the budget's mandatory and third-party in-kind cost
+// share fall short of the sponsor's required match.
+func (b *Budget) CheckMatch() error {
+	cs := NewCalculator(b.FARate).CalculateCostShare(b)
+	if cs.Shortfall.IsPositive() {
+		return fmt.Errorf("%w: $%s of $%s committed, $%s short",
+			ErrMatchShortfall, cs.CountedMatch.StringFixed(2), cs.RequiredMatch.StringFixed(2), cs.Shortfall.StringFixed(2))
+	}
+	return nil
+}
+
+// CalculateCostShare totals the cost share of every period by type and
+// funding source with the F&A forgone on it, and compares the match with
+// the sponsor's requirement on the budget's grand total.
+func (c *Calculator) CalculateCostShare(budget *Budget) CostShareBreakdown {
+	result := CostShareBreakdown{
+		Periods:              make([]PeriodCostShare, len(budget.Periods)),
+		Sources:              make([]SourceCostShare, 0),
+		RequiredMatchPercent: budget.RequiredMatchPercent,
+	}
+
+	bySource := make(map[SourceCostShare]int)
+	for i := range budget.Periods {
+		period := &budget.Periods[i]
+		var totals CostShareTotals
+		for _, item := range period.LineItems() {
+			for _, cs := range item.CostShares() {
+				totals.add(cs)
+
+				key := SourceCostShare{SourceType: cs.SourceType, Source: strings.TrimSpace(cs.Source)}
+				j, ok := bySource[key]
+				if !ok {
+					j = len(result.Sources)
+					bySource[key] = j
+					result.Sources = append(result.Sources, key)
+				}
+				result.Sources[j].Amount = result.Sources[j].Amount.Add(cs.Amount)
+			}
+		}
+		rate := c.FARate.ProratedRate(period.StartDate, period.EndDate)
+		totals.FAForgone = c.costShareBase(period).Mul(rate).Round(2)
+
+		result.Periods[i] = PeriodCostShare{
+			PeriodID:        period.ID,
+			PeriodNumber:    period.PeriodNumber,
+			CostShareTotals: totals,
+		}
+		result.CostShareTotals = result.CostShareTotals.plus(totals)
+	}
+
+	result.RequiredMatch = budget.GrandTotal().Mul(budget.RequiredMatchPercent).Div(decimal.NewFromInt(100)).Round(2)
+	result.CountedMatch = result.Mandatory.Add(result.ThirdPartyInKind)
+	if result.CountedMatch.LessThan(result.RequiredMatch) {
+		result.Shortfall = result.RequiredMatch.Sub(result.CountedMatch)
+	}
+	return result
+}
+
+// costShareBase returns the department-funded cost share of a period that
+// would be in its F&A base. Third parties bear their own indirect costs, and
+// subaward cost share is the subrecipient's, so neither is in the base.
+func (c *Calculator) costShareBase(period *BudgetPeriod) decimal.Decimal {
+	rateType := normalizeRateType(c.FARate.RateType)
+	base := decimal.Zero
+	for _, item := range period.LineItems() {
+		if item.Type == CostTypeSubaward {
+			continue
+		}
+		amount := decimal.Zero
+		for _, cs := range item.CostShares() {
+			if cs.SourceType == FundingDepartmentAccount {
+				amount = amount.Add(cs.Amount)
+			}
+		}
+		if amount.IsZero() {
+			continue
+		}
+
+		switch rateType {
+		case RateTypeTDC:
+		case RateTypeSW:
+			if item.Type != CostTypePersonnel {
+				continue
+			}
+			// Only the salary share of personnel cost share is in the base
+			if p := item.Personnel; p.TotalCost.IsPositive() {
+				amount = amount.Mul(p.RequestedSalary).Div(p.TotalCost)
+			}
+		default:
+			if c.excludesFromMTDC(item) {
+				continue
+			}
+		}
+		base = base.Add(amount)
+	}
+	return base
+}
+
+// validateCostShare reports a cost share short of the sponsor's required match.
+func (c *Calculator) validateCostShare(budget *Budget) []BudgetValidationError {
+	if !budget.RequiredMatchPercent.IsPositive() {
+		return nil
+	}
+	cs := c.CalculateCostShare(budget)
+	if !cs.Shortfall.IsPositive() {
+		return nil
+	}
+	return []BudgetValidationError{{
+		Code: "MATCH_SHORTFALL",
+		Message: fmt.Sprintf("Mandatory and third-party in-kind cost share of $%s is $%s short of the required %s%% match",
+			cs.CountedMatch.StringFixed(2), cs.Shortfall.StringFixed(2), cs.RequiredMatchPercent.String()),
+	}}
+}
@@ -16,6 +16,10 @@ type Budget struct {
 	ProposalID   uuid.UUID      `json:"proposal_id"`
 	Periods      []BudgetPeriod `json:"periods"`
 	FARate       FARate         `json:"fa_rate"`
+	RateAgreementID *uuid.UUID  `json:"rate_agreement_id,omitempty"` // agreement FARate was looked up from
+	ActivityType ActivityType   `json:"activity_type,omitempty"`
+	IsModular    bool           `json:"is_modular"` // NIH modular budget, see CalculateModular
+	RequiredMatchPercent decimal.Decimal `json:"required_match_percent"` // sponsor's required cost share, in percent of the request
 	Currency     string         `json:"currency"` // ISO 4217
 	Status       BudgetStatus   `json:"status"`
 	SubmittedAt  *time.Time     `json:"submitted_at,omitempty"`
@@ -57,6 +61,7 @@ type PersonnelCost struct {
 	PersonID        *uuid.UUID      `json:"person_id,omitempty"`
 	Name            string          `json:"name"`
 	Role            string          `json:"role"`
+	EmployeeClass   string          `json:"employee_class,omitempty"` // selects the agreement's fringe rate
 	BaseSalary      decimal.Decimal `json:"base_salary"`
 	FringeRate      decimal.Decimal `json:"fringe_rate"`
 	CalendarMonths  decimal.Decimal `json:"calendar_months"`
@@ -67,6 +72,7 @@ type PersonnelCost struct {
 	FringeBenefits  decimal.Decimal `json:"fringe_benefits"`
 	TotalCost       decimal.Decimal `json:"total_cost"`
 	IsPIOrCoPI      bool            `json:"is_pi_or_copi"`
+	CostShare       []CostShare     `json:"cost_share,omitempty"`
 }
 
 // EquipmentCost represents equipment purchases.
@@ -77,6 +83,7 @@ type EquipmentCost struct {
 	UnitCost     decimal.Decimal `json:"unit_cost"`
 	TotalCost    decimal.Decimal `json:"total_cost"`
 	Justification string         `json:"justification"`
+	CostShare    []CostShare     `json:"cost_share,omitempty"`
 }
 
 // TravelCost represents travel expenses.
@@ -89,6 +96,7 @@ type TravelCost struct {
 	TripCount   int             `json:"trip_count"`
 	CostPerTrip decimal.Decimal `json:"cost_per_trip"`
 	TotalCost   decimal.Decimal `json:"total_cost"`
+	CostShare   []CostShare     `json:"cost_share,omitempty"`
 }
 
 // SupplyCost represents supplies and materials.
@@ -97,6 +105,7 @@ type SupplyCost struct {
 	Category    string          `json:"category"`
 	Description string          `json:"description"`
 	TotalCost   decimal.Decimal `json:"total_cost"`
+	CostShare   []CostShare     `json:"cost_share,omitempty"`
 }
 
 // ContractualCost represents contractual services.
@@ -105,6 +114,7 @@ type ContractualCost struct {
 	Vendor      string          `json:"vendor"`
 	Description string          `json:"description"`
 	TotalCost   decimal.Decimal `json:"total_cost"`
+	CostShare   []CostShare     `json:"cost_share,omitempty"`
 }
 
 // OtherCost represents miscellaneous direct costs.
@@ -113,6 +123,7 @@ type OtherCost struct {
 	Category    string          `json:"category"`
 	Description string          `json:"description"`
 	TotalCost   decimal.Decimal `json:"total_cost"`
+	CostShare   []CostShare     `json:"cost_share,omitempty"`
 }
 
 // SubawardCost represents subaward costs.
@@ -124,6 +135,7 @@ type SubawardCost struct {
 	IndirectCosts    decimal.Decimal `json:"indirect_costs"`
 	TotalCost        decimal.Decimal `json:"total_cost"`
 	FirstYearDirect  decimal.Decimal `json:"first_year_direct"` // First $25k subject to F&A
+	CostShare        []CostShare     `json:"cost_share,omitempty"` // the subrecipient's
 }
 
 // FARate represents F&A (Facilities & Administrative) rate structure.
@@ -135,6 +147,7 @@ type FARate struct {
 	EquipmentCap    decimal.Decimal `json:"equipment_cap"`
 	SubawardCap     decimal.Decimal `json:"subaward_cap"` // Typically $25,000
 	ExcludedItems   []string        `json:"excluded_items"`
+	EffectiveRates  []EffectiveRate `json:"effective_rates,omitempty"` // negotiated rate changes
 }
 
 // NewBudget creates a new budget for a proposal.
@@ -169,6 +182,61 @@ func (b *Budget) AddPeriod(period BudgetPeriod) {
 	b.Periods = append(b.Periods, period)
 }
 
+// CopyFor returns a draft copy of the budget for another proposal. Periods
+// and line items get new IDs; review status is not carried over.
+func (b *Budget) CopyFor(userID, proposalID uuid.UUID) *Budget {
+	c := NewBudget(b.TenantID, userID, proposalID, b.Currency)
+	c.FARate = b.FARate
+	c.FARate.ExcludedItems = append([]string(nil), b.FARate.ExcludedItems...)
+	c.FARate.EffectiveRates = append([]EffectiveRate(nil), b.FARate.EffectiveRates...)
+	c.RateAgreementID = b.RateAgreementID
+	c.ActivityType = b.ActivityType
+	c.IsModular = b.IsModular
+	c.RequiredMatchPercent = b.RequiredMatchPercent
+	c.Notes = b.Notes
+
+	for _, period := range b.Periods {
+		period.Personnel = append([]PersonnelCost(nil), period.Personnel...)
+		for i := range period.Personnel {
+			period.Personnel[i].ID = uuid.New()
+			period.Personnel[i].CostShare = copyCostShares(period.Personnel[i].CostShare)
+		}
+		period.Equipment = append([]EquipmentCost(nil), period.Equipment...)
+		for i := range period.Equipment {
+			period.Equipment[i].ID = uuid.New()
+			period.Equipment[i].CostShare = copyCostShares(period.Equipment[i].CostShare)
+		}
+		period.Travel = append([]TravelCost(nil), period.Travel...)
+		for i := range period.Travel {
+			period.Travel[i].ID = uuid.New()
+			period.Travel[i].CostShare = copyCostShares(period.Travel[i].CostShare)
+		}
+		period.Supplies = append([]SupplyCost(nil), period.Supplies...)
+		for i := range period.Supplies {
+			period.Supplies[i].ID = uuid.New()
+			period.Supplies[i].CostShare = copyCostShares(period.Supplies[i].CostShare)
+		}
+		period.Contractual = append([]ContractualCost(nil), period.Contractual...)
+		for i := range period.Contractual {
+			period.Contractual[i].ID = uuid.New()
+			period.Contractual[i].CostShare = copyCostShares(period.Contractual[i].CostShare)
+		}
+		period.Other = append([]OtherCost(nil), period.Other...)
+		for i := range period.Other {
+			period.Other[i].ID = uuid.New()
+			period.Other[i].CostShare = copyCostShares(period.Other[i].CostShare)
+		}
+		period.Subawards = append([]SubawardCost(nil), period.Subawards...)
+		for i := range period.Subawards {
+			period.Subawards[i].ID = uuid.New()
+			period.Subawards[i].CostShare = copyCostShares(period.Subawards[i].CostShare)
+		}
+		c.AddPeriod(period)
+	}
+
+	return c
+}
+
 // TotalDirectCosts calculates total direct costs across all periods.
 func (b *Budget) TotalDirectCosts() decimal.Decimal {
 	total := decimal.Zero
@@ -181,8 +249,8 @@ func (b *Budget) TotalDirectCosts() decimal.Decimal {
 // TotalIndirectCosts calculates total indirect (F&A) costs across all periods.
 func (b *Budget) TotalIndirectCosts() decimal.Decimal {
 	total := decimal.Zero
-	for _, period := range b.Periods {
-		total = total.Add(period.IndirectCosts(b.FARate))
+	for _, fa := range NewCalculator(b.FARate).CalculateFA(b.Periods) {
+		total = total.Add(fa.IndirectCosts)
 	}
 	return total
 }
@@ -234,38 +302,17 @@ func (bp *BudgetPeriod) TotalDirectCosts() decimal.Decimal {
 	return total
 }
 
-// MTDCBase calculates the Modified Total Direct Cost base for F&A.
+// MTDCBase calculates the Modified Total Direct Cost base for F&A of the
+// period taken alone. Calculator.CalculateFA caps subawards over all periods.
 func (bp *BudgetPeriod) MTDCBase(faRate FARate) decimal.Decimal {
-	total := bp.TotalDirectCosts()
-
-	// Exclude equipment (typically over $5000)
-	for _, e := range bp.Equipment {
-		total = total.Sub(e.TotalCost)
-	}
-
-	// Exclude subaward amounts over cap (typically $25,000)
-	for _, s := range bp.Subawards {
-		if s.DirectCosts.GreaterThan(faRate.SubawardCap) {
-			excess := s.DirectCosts.Sub(faRate.SubawardCap)
-			total = total.Sub(excess)
-		}
-	}
-
-	return total
+	faRate.RateType = RateTypeMTDC
+	return NewCalculator(faRate).faBase(bp, make(map[string]decimal.Decimal))
 }
 
-// IndirectCosts calculates indirect costs for a period.
+// IndirectCosts calculates indirect costs for a period taken alone, on the
+// base of the rate type and prorated over rate changes.
 func (bp *BudgetPeriod) IndirectCosts(faRate FARate) decimal.Decimal {
-	base := bp.MTDCBase(faRate)
-
-	var rate decimal.Decimal
-	if faRate.IsOnCampus {
-		rate = faRate.OnCampusRate
-	} else {
-		rate = faRate.OffCampusRate
-	}
-
-	return base.Mul(rate).Round(2)
+	return NewCalculator(faRate).CalculateFA([]BudgetPeriod{*bp})[0].IndirectCosts
 }
 
 // BudgetSummary provides a summary view of the budget.
@@ -281,6 +328,9 @@ type BudgetSummary struct {
 	TotalIndirectCosts decimal.Decimal `json:"total_indirect_costs"`
 	GrandTotal        decimal.Decimal `json:"grand_total"`
 	PeriodCount       int             `json:"period_count"`
+	CostSharing       CostShareTotals `json:"cost_sharing"`
+	RequiredMatch     decimal.Decimal `json:"required_match"`
+	MatchShortfall    decimal.Decimal `json:"match_shortfall"`
 }
 
 // Summary returns a budget summary.
@@ -317,5 +367,10 @@ func (b *Budget) Summary() BudgetSummary {
 	summary.TotalIndirectCosts = b.TotalIndirectCosts()
 	summary.GrandTotal = b.GrandTotal()
 
+	costShare := NewCalculator(b.FARate).CalculateCostShare(b)
+	summary.CostSharing = costShare.CostShareTotals
+	summary.RequiredMatch = costShare.RequiredMatch
+	summary.MatchShortfall = costShare.Shortfall
+
 	return summary
 }
@@ -0,0 +1,262 @@
+// Package budget provides the F&A rate engine.
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// F&A base types of a rate.
+const (
+	RateTypeMTDC = "MTDC" // modified total direct costs
+	RateTypeTDC  = "TDC"  // total direct costs
+	RateTypeSW   = "S&W"  // salaries and wages
+)
+
+// Cost categories a rate commonly leaves out of the MTDC base. Cost types
+// such as "travel" may be excluded by name as well.
+const (
+	ExclusionEquipment          = "equipment"
+	ExclusionSubawardExcess     = "subaward_excess" // subaward costs over SubawardCap
+	ExclusionTuition            = "tuition"
+	ExclusionPatientCare        = "patient_care"
+	ExclusionParticipantSupport = "participant_support"
+	ExclusionRental             = "rental"
+)
+
+// EffectiveRate is a negotiated rate that applies from its effective date
+// until the next effective rate.
+type EffectiveRate struct {
+	EffectiveDate time.Time       `json:"effective_date"`
+	OnCampusRate  decimal.Decimal `json:"on_campus_rate"`
+	OffCampusRate decimal.Decimal `json:"off_campus_rate"`
+}
+
+// IsKnownRateType reports whether the rate type is MTDC, TDC or S&W.
+func IsKnownRateType(rateType string) bool {
+	switch normalizeRateType(rateType) {
+	case RateTypeMTDC, RateTypeTDC, RateTypeSW:
+		return true
+	}
+	return false
+}
+
+// normalizeRateType maps spellings of a rate type to its constant. Empty
+// rate types are MTDC.
+func normalizeRateType(rateType string) string {
+	switch strings.ToUpper(strings.TrimSpace(rateType)) {
+	case "", RateTypeMTDC:
+		return RateTypeMTDC
+	case RateTypeTDC:
+		return RateTypeTDC
+	case RateTypeSW, "SW", "SALARIES":
+		return RateTypeSW
+	}
+	return rateType
+}
+
+// normalizeCategory makes cost categories comparable: "Patient Care" and
+// "patient_care" are the same category.
+func normalizeCategory(category string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(category)), " ", "_")
+}
+
+// Validate checks the rate type and that every rate is between 0 and 100%.
+func (r FARate) Validate() error {
+	if !IsKnownRateType(r.RateType) {
+		return fmt.Errorf("%w: unknown F&A rate type %q", ErrInvalidBudget, r.RateType)
+	}
+
+	one := decimal.NewFromInt(1)
+	rates := []decimal.Decimal{r.OnCampusRate, r.OffCampusRate}
+	seen := make(map[time.Time]bool, len(r.EffectiveRates))
+	for _, er := range r.EffectiveRates {
+		day := civilDate(er.EffectiveDate)
+		if er.EffectiveDate.IsZero() || seen[day] {
+			return fmt.Errorf("%w: effective F&A rates need distinct effective dates", ErrInvalidBudget)
+		}
+		seen[day] = true
+		rates = append(rates, er.OnCampusRate, er.OffCampusRate)
+	}
+	for _, rate := range rates {
+		if rate.IsNegative() || rate.GreaterThan(one) {
+			return fmt.Errorf("%w: F&A rates must be between 0 and 100%%", ErrInvalidBudget)
+		}
+	}
+	if r.SubawardCap.IsNegative() || r.EquipmentCap.IsNegative() {
+		return fmt.Errorf("%w: F&A caps cannot be negative", ErrInvalidBudget)
+	}
+	return nil
+}
+
+// Excludes reports whether the MTDC base leaves out a cost category.
+func (r FARate) Excludes(category string) bool {
+	category = normalizeCategory(category)
+	if category == "" {
+		return false
+	}
+	for _, excluded := range r.ExcludedItems {
+		if normalizeCategory(excluded) == category {
+			return true
+		}
+	}
+	return false
+}
+
+// RateOn returns the rate for the budget's location that applies on a date:
+// the latest effective rate on or before the date, else the base rate.
+func (r FARate) RateOn(date time.Time) decimal.Decimal {
+	rate := r.OffCampusRate
+	if r.IsOnCampus {
+		rate = r.OnCampusRate
+	}
+
+	day := civilDate(date)
+	var latest *EffectiveRate
+	for i := range r.EffectiveRates {
+		er := &r.EffectiveRates[i]
+		if civilDate(er.EffectiveDate).After(day) {
+			continue
+		}
+		if latest == nil || er.EffectiveDate.After(latest.EffectiveDate) {
+			latest = er
+		}
+	}
+	if latest != nil {
+		rate = latest.OffCampusRate
+		if r.IsOnCampus {
+			rate = latest.OnCampusRate
+		}
+	}
+	return rate
+}
+
+// ProratedRate returns the rate over the days from start through end,
+// weighting each rate by the days it applies.
+func (r FARate) ProratedRate(start, end time.Time) decimal.Decimal {
+	start, end = civilDate(start), civilDate(end)
+	if start.IsZero() || !end.After(start) {
+		return r.RateOn(start)
+	}
+	stop := end.AddDate(0, 0, 1)
+
+	changes := make([]time.Time, 0, len(r.EffectiveRates))
+	for _, er := range r.EffectiveRates {
+		day := civilDate(er.EffectiveDate)
+		if day.After(start) && day.Before(stop) {
+			changes = append(changes, day)
+		}
+	}
+	if len(changes) == 0 {
+		return r.RateOn(start)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Before(changes[j]) })
+
+	weighted := decimal.Zero
+	from := start
+	for _, to := range append(changes, stop) {
+		weighted = weighted.Add(r.RateOn(from).Mul(decimal.NewFromInt(daysBetween(from, to))))
+		from = to
+	}
+	return weighted.Div(decimal.NewFromInt(daysBetween(start, stop)))
+}
+
+// civilDate truncates a time to its calendar date in UTC.
+func civilDate(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween counts the days from one calendar date to a later one.
+func daysBetween(from, to time.Time) int64 {
+	return int64(to.Sub(from).Hours()/24 + 0.5)
+}
+
+// PeriodFA is the F&A calculation of a budget period.
+type PeriodFA struct {
+	Base          decimal.Decimal `json:"base"`
+	Excluded      decimal.Decimal `json:"excluded"` // direct costs left out of the base
+	Rate          decimal.Decimal `json:"rate"`     // prorated over the period
+	IndirectCosts decimal.Decimal `json:"indirect_costs"`
+}
+
+// CalculateFA computes the F&A base, rate and indirect costs of budget
+// periods given in order. The MTDC subaward cap applies once to each
+// subrecipient over all the periods.
+func (c *Calculator) CalculateFA(periods []BudgetPeriod) []PeriodFA {
+	results := make([]PeriodFA, len(periods))
+	capUsed := make(map[string]decimal.Decimal)
+	for i := range periods {
+		period := &periods[i]
+		base := c.faBase(period, capUsed)
+		rate := c.FARate.ProratedRate(period.StartDate, period.EndDate)
+		results[i] = PeriodFA{
+			Base:          base,
+			Excluded:      period.TotalDirectCosts().Sub(base),
+			Rate:          rate,
+			IndirectCosts: base.Mul(rate).Round(2),
+		}
+	}
+	return results
+}
+
+// faBase computes the F&A base of a period by the rate type. capUsed holds
+// the subaward costs already in the MTDC base by subrecipient key.
+func (c *Calculator) faBase(period *BudgetPeriod, capUsed map[string]decimal.Decimal) decimal.Decimal {
+	switch normalizeRateType(c.FARate.RateType) {
+	case RateTypeTDC:
+		return period.TotalDirectCosts()
+	case RateTypeSW:
+		base := decimal.Zero
+		for _, p := range period.Personnel {
+			base = base.Add(p.RequestedSalary)
+		}
+		return base
+	}
+
+	base := decimal.Zero
+	for _, item := range period.LineItems() {
+		if c.excludesFromMTDC(item) {
+			continue
+		}
+		cost := item.TotalCost()
+		if item.Type == CostTypeSubaward && c.FARate.Excludes(ExclusionSubawardExcess) {
+			// Only the first SubawardCap of each subaward is in the base
+			key := item.Subaward.SubrecipientKey()
+			remaining := c.FARate.SubawardCap.Sub(capUsed[key])
+			if remaining.IsNegative() {
+				remaining = decimal.Zero
+			}
+			cost = decimal.Min(cost, remaining)
+			capUsed[key] = capUsed[key].Add(cost)
+		}
+		base = base.Add(cost)
+	}
+	return base
+}
+
+// excludesFromMTDC reports whether a line item is left out of the MTDC base
+// by its cost type or category. Equipment is excluded from its unit cost of
+// EquipmentCap on.
+func (c *Calculator) excludesFromMTDC(item LineItem) bool {
+	switch item.Type {
+	case CostTypeEquipment:
+		return c.FARate.Excludes(ExclusionEquipment) && item.Equipment.UnitCost.GreaterThanOrEqual(c.FARate.EquipmentCap)
+	case CostTypeSupplies:
+		if c.FARate.Excludes(item.Supplies.Category) {
+			return true
+		}
+	case CostTypeOther:
+		if c.FARate.Excludes(item.Other.Category) {
+			return true
+		}
+	}
+	return c.FARate.Excludes(string(item.Type))
+}
@@ -0,0 +1,110 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCalculateFA(t *testing.T) {
+	mtdc := FARate{
+		RateType:      RateTypeMTDC,
+		OnCampusRate:  dec("0.5"),
+		IsOnCampus:    true,
+		EquipmentCap:  dec("5000"),
+		SubawardCap:   dec("25000"),
+		ExcludedItems: []string{ExclusionEquipment, ExclusionSubawardExcess},
+	}
+	rateChange := mtdc
+	rateChange.EffectiveRates = []EffectiveRate{{EffectiveDate: day(2027, time.July, 1), OnCampusRate: dec("0.6")}}
+
+	year := func(y int) BudgetPeriod {
+		return BudgetPeriod{StartDate: day(y, time.January, 1), EndDate: day(y, time.December, 31)}
+	}
+	withSubawards := func(p BudgetPeriod, subawards ...SubawardCost) BudgetPeriod {
+		p.Subawards = subawards
+		return p
+	}
+	withEquipment := func(p BudgetPeriod, equipment ...EquipmentCost) BudgetPeriod {
+		p.Equipment = equipment
+		return p
+	}
+	withSupplies := func(p BudgetPeriod, total string) BudgetPeriod {
+		p.Supplies = []SupplyCost{{Description: "Reagents", TotalCost: dec(total)}}
+		return p
+	}
+
+	tests := []struct {
+		name     string
+		rate     FARate
+		periods  []BudgetPeriod
+		base     []string
+		indirect []string
+	}{
+		{
+			name: "subaward crosses the cap in a later period",
+			rate: mtdc,
+			periods: []BudgetPeriod{
+				withSubawards(year(2026),
+					SubawardCost{Organization: "Acme University", TotalCost: dec("20000")},
+					SubawardCost{Organization: "Beta Institute", TotalCost: dec("30000")}),
+				withSubawards(year(2027), SubawardCost{Organization: " acme  UNIVERSITY", TotalCost: dec("15000")}),
+				withSubawards(year(2028), SubawardCost{Organization: "Acme University", TotalCost: dec("10000")}),
+			},
+			base:     []string{"45000", "5000", "0"},
+			indirect: []string{"22500", "2500", "0"},
+		},
+		{
+			name: "equipment at the cap is excluded and below it included",
+			rate: mtdc,
+			periods: []BudgetPeriod{
+				withEquipment(year(2026),
+					EquipmentCost{Description: "Microscope", Quantity: 1, UnitCost: dec("5000"), TotalCost: dec("5000")},
+					EquipmentCost{Description: "Centrifuge", Quantity: 2, UnitCost: dec("4999.99"), TotalCost: dec("9999.98")}),
+			},
+			base:     []string{"9999.98"},
+			indirect: []string{"4999.99"},
+		},
+		{
+			name:    "rate change mid-period is prorated by day",
+			rate:    rateChange,
+			periods: []BudgetPeriod{withSupplies(year(2026), "10000"), withSupplies(year(2027), "10000")},
+			base:    []string{"10000", "10000"},
+			// 181 days at 50% and 184 days at 60%
+			indirect: []string{"5000", "5504.11"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := NewCalculator(tt.rate).CalculateFA(tt.periods)
+			if len(results) != len(tt.periods) {
+				t.Fatalf("got %d results for %d periods", len(results), len(tt.periods))
+			}
+			for i, r := range results {
+				if !r.Base.Equal(dec(tt.base[i])) {
+					t.Errorf("period %d: base %s, want %s", i+1, r.Base, tt.base[i])
+				}
+				if !r.IndirectCosts.Equal(dec(tt.indirect[i])) {
+					t.Errorf("period %d: indirect costs %s, want %s", i+1, r.IndirectCosts, tt.indirect[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSubawardNeedsOrganization(t *testing.T) {
+	item := LineItem{Type: CostTypeSubaward, Subaward: &SubawardCost{Organization: "  ", DirectCosts: dec("1000")}}
+	if err := item.Validate(); err == nil {
+		t.Error("expected a subaward without an organization to be rejected")
+	}
+}
@@ -0,0 +1,466 @@
+// Package budget provides editing of budget periods and line items.
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrBudgetNotFound is returned when a budget does not exist.
+var ErrBudgetNotFound = errors.New("budget not found")
+
+// ErrBudgetExists is returned when a proposal already has a budget.
+var ErrBudgetExists = errors.New("proposal already has a budget")
+
+// ErrBudgetNotEditable is returned when a budget under review or approved is
+// changed.
+var ErrBudgetNotEditable = errors.New("budget cannot be edited in its current status")
+
+// ErrPeriodNotFound is returned when a budget period does not exist.
+var ErrPeriodNotFound = errors.New("budget period not found")
+
+// ErrLineItemNotFound is returned when a line item does not exist.
+var ErrLineItemNotFound = errors.New("budget line item not found")
+
+// ErrInvalidBudget is returned when a budget or period is malformed.
+var ErrInvalidBudget = errors.New("invalid budget")
+
+// ErrInvalidLineItem is returned when a line item is malformed.
+var ErrInvalidLineItem = errors.New("invalid budget line item")
+
+// maxDescriptionLength bounds line item descriptions.
+const maxDescriptionLength = 500
+
+// CostType is the cost category of a line item.
+type CostType string
+
+const (
+	CostTypePersonnel   CostType = "personnel"
+	CostTypeEquipment   CostType = "equipment"
+	CostTypeTravel      CostType = "travel"
+	CostTypeSupplies    CostType = "supplies"
+	CostTypeContractual CostType = "contractual"
+	CostTypeOther       CostType = "other"
+	CostTypeSubaward    CostType = "subaward"
+)
+
+// CostTypes returns every cost type in budget order.
+func CostTypes() []CostType {
+	return []CostType{
+		CostTypePersonnel, CostTypeEquipment, CostTypeTravel, CostTypeSupplies,
+		CostTypeContractual, CostTypeOther, CostTypeSubaward,
+	}
+}
+
+// IsValid reports whether the cost type is known.
+func (t CostType) IsValid() bool {
+	for _, known := range CostTypes() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// LineItem is a line item of any cost type. Only the field of its type is set.
+type LineItem struct {
+	Type        CostType         `json:"type"`
+	Personnel   *PersonnelCost   `json:"personnel,omitempty"`
+	Equipment   *EquipmentCost   `json:"equipment,omitempty"`
+	Travel      *TravelCost      `json:"travel,omitempty"`
+	Supplies    *SupplyCost      `json:"supplies,omitempty"`
+	Contractual *ContractualCost `json:"contractual,omitempty"`
+	Other       *OtherCost       `json:"other,omitempty"`
+	Subaward    *SubawardCost    `json:"subaward,omitempty"`
+}
+
+// ID returns the ID of the line item.
+func (li LineItem) ID() uuid.UUID {
+	switch li.Type {
+	case CostTypePersonnel:
+		return li.Personnel.ID
+	case CostTypeEquipment:
+		return li.Equipment.ID
+	case CostTypeTravel:
+		return li.Travel.ID
+	case CostTypeSupplies:
+		return li.Supplies.ID
+	case CostTypeContractual:
+		return li.Contractual.ID
+	case CostTypeOther:
+		return li.Other.ID
+	case CostTypeSubaward:
+		return li.Subaward.ID
+	}
+	return uuid.Nil
+}
+
+// TotalCost returns the total cost of the line item.
+func (li LineItem) TotalCost() decimal.Decimal {
+	switch li.Type {
+	case CostTypePersonnel:
+		return li.Personnel.TotalCost
+	case CostTypeEquipment:
+		return li.Equipment.TotalCost
+	case CostTypeTravel:
+		return li.Travel.TotalCost
+	case CostTypeSupplies:
+		return li.Supplies.TotalCost
+	case CostTypeContractual:
+		return li.Contractual.TotalCost
+	case CostTypeOther:
+		return li.Other.TotalCost
+	case CostTypeSubaward:
+		return li.Subaward.TotalCost
+	}
+	return decimal.Zero
+}
+
+// Description returns a one-line description of the line item.
+func (li LineItem) Description() string {
+	switch li.Type {
+	case CostTypePersonnel:
+		return li.Personnel.Name
+	case CostTypeEquipment:
+		return li.Equipment.Description
+	case CostTypeTravel:
+		return li.Travel.Purpose
+	case CostTypeSupplies:
+		return li.Supplies.Description
+	case CostTypeContractual:
+		if li.Contractual.Description == "" {
+			return li.Contractual.Vendor
+		}
+		return li.Contractual.Description
+	case CostTypeOther:
+		return li.Other.Description
+	case CostTypeSubaward:
+		return li.Subaward.Organization
+	}
+	return ""
+}
+
+// SubrecipientKey identifies the subrecipient of a subaward across periods.
+// Organization names differing only in case and spacing are the same
+// subrecipient.
+func (s SubawardCost) SubrecipientKey() string {
+	return strings.Join(strings.Fields(strings.ToLower(s.Organization)), " ")
+}
+
+// withID returns a copy of the line item with the given ID. The copy shares
+// no memory with the original.
+func (li LineItem) withID(id uuid.UUID) LineItem {
+	c := LineItem{Type: li.Type}
+	switch li.Type {
+	case CostTypePersonnel:
+		v := *li.Personnel
+		v.ID = id
+		v.CostShare = copyCostShares(v.CostShare)
+		c.Personnel = &v
+	case CostTypeEquipment:
+		v := *li.Equipment
+		v.ID = id
+		v.CostShare = copyCostShares(v.CostShare)
+		c.Equipment = &v
+	case CostTypeTravel:
+		v := *li.Travel
+		v.ID = id
+		v.CostShare = copyCostShares(v.CostShare)
+		c.Travel = &v
+	case CostTypeSupplies:
+		v := *li.Supplies
+		v.ID = id
+		v.CostShare = copyCostShares(v.CostShare)
+		c.Supplies = &v
+	case CostTypeContractual:
+		v := *li.Contractual
+		v.ID = id
+		v.CostShare = copyCostShares(v.CostShare)
+		c.Contractual = &v
+	case CostTypeOther:
+		v := *li.Other
+		v.ID = id
+		v.CostShare = copyCostShares(v.CostShare)
+		c.Other = &v
+	case CostTypeSubaward:
+		v := *li.Subaward
+		v.ID = id
+		v.CostShare = copyCostShares(v.CostShare)
+		c.Subaward = &v
+	}
+	return c
+}
+
+// Validate checks that exactly the field of the item's type is set and that
+// its amounts are in range. Derived totals are not checked; Calculator.Recalculate
+// sets them.
+func (li LineItem) Validate() error {
+	if !li.Type.IsValid() {
+		return fmt.Errorf("%w: unknown cost type %q", ErrInvalidLineItem, li.Type)
+	}
+
+	set := 0
+	for _, present := range []bool{
+		li.Personnel != nil, li.Equipment != nil, li.Travel != nil, li.Supplies != nil,
+		li.Contractual != nil, li.Other != nil, li.Subaward != nil,
+	} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 || li.isEmpty() {
+		return fmt.Errorf("%w: a %s item must set only its %s costs", ErrInvalidLineItem, li.Type, li.Type)
+	}
+
+	description := strings.TrimSpace(li.Description())
+	if description == "" {
+		return fmt.Errorf("%w: a %s item needs a description", ErrInvalidLineItem, li.Type)
+	}
+	if len(description) > maxDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", ErrInvalidLineItem, maxDescriptionLength)
+	}
+
+	for _, cs := range li.CostShares() {
+		if err := cs.Validate(); err != nil {
+			return err
+		}
+	}
+
+	switch li.Type {
+	case CostTypePersonnel:
+		p := li.Personnel
+		if p.BaseSalary.IsNegative() {
+			return fmt.Errorf("%w: base salary of %s cannot be negative", ErrInvalidLineItem, p.Name)
+		}
+		if p.FringeRate.IsNegative() || p.FringeRate.GreaterThan(decimal.NewFromInt(1)) {
+			return fmt.Errorf("%w: fringe rate of %s must be between 0 and 1", ErrInvalidLineItem, p.Name)
+		}
+		if p.EffortPercent.IsNegative() || p.EffortPercent.GreaterThan(decimal.NewFromInt(100)) {
+			return fmt.Errorf("%w: effort of %s must be between 0 and 100", ErrInvalidLineItem, p.Name)
+		}
+		months := p.CalendarMonths.Add(p.AcademicMonths).Add(p.SummerMonths)
+		if p.CalendarMonths.IsNegative() || p.AcademicMonths.IsNegative() || p.SummerMonths.IsNegative() ||
+			months.GreaterThan(decimal.NewFromInt(12)) {
+			return fmt.Errorf("%w: months of %s must add up to between 0 and 12", ErrInvalidLineItem, p.Name)
+		}
+	case CostTypeEquipment:
+		if li.Equipment.Quantity < 0 || li.Equipment.UnitCost.IsNegative() {
+			return fmt.Errorf("%w: equipment quantity and unit cost cannot be negative", ErrInvalidLineItem)
+		}
+	case CostTypeTravel:
+		if li.Travel.Travelers < 0 || li.Travel.TripCount < 0 || li.Travel.CostPerTrip.IsNegative() {
+			return fmt.Errorf("%w: travelers, trips and cost per trip cannot be negative", ErrInvalidLineItem)
+		}
+	case CostTypeSubaward:
+		s := li.Subaward
+		if s.SubrecipientKey() == "" {
+			return fmt.Errorf("%w: subaward needs a subrecipient organization", ErrInvalidLineItem)
+		}
+		if s.DirectCosts.IsNegative() || s.IndirectCosts.IsNegative() || s.FirstYearDirect.IsNegative() {
+			return fmt.Errorf("%w: subaward costs cannot be negative", ErrInvalidLineItem)
+		}
+	default:
+		if li.TotalCost().IsNegative() {
+			return fmt.Errorf("%w: total cost cannot be negative", ErrInvalidLineItem)
+		}
+	}
+	return nil
+}
+
+// isEmpty reports whether the field of the item's type is unset.
+func (li LineItem) isEmpty() bool {
+	switch li.Type {
+	case CostTypePersonnel:
+		return li.Personnel == nil
+	case CostTypeEquipment:
+		return li.Equipment == nil
+	case CostTypeTravel:
+		return li.Travel == nil
+	case CostTypeSupplies:
+		return li.Supplies == nil
+	case CostTypeContractual:
+		return li.Contractual == nil
+	case CostTypeOther:
+		return li.Other == nil
+	case CostTypeSubaward:
+		return li.Subaward == nil
+	}
+	return true
+}
+
+// LineItems returns the line items of a period in budget order. The items
+// point into the period.
+func (bp *BudgetPeriod) LineItems() []LineItem {
+	items := make([]LineItem, 0)
+	for i := range bp.Personnel {
+		items = append(items, LineItem{Type: CostTypePersonnel, Personnel: &bp.Personnel[i]})
+	}
+	for i := range bp.Equipment {
+		items = append(items, LineItem{Type: CostTypeEquipment, Equipment: &bp.Equipment[i]})
+	}
+	for i := range bp.Travel {
+		items = append(items, LineItem{Type: CostTypeTravel, Travel: &bp.Travel[i]})
+	}
+	for i := range bp.Supplies {
+		items = append(items, LineItem{Type: CostTypeSupplies, Supplies: &bp.Supplies[i]})
+	}
+	for i := range bp.Contractual {
+		items = append(items, LineItem{Type: CostTypeContractual, Contractual: &bp.Contractual[i]})
+	}
+	for i := range bp.Other {
+		items = append(items, LineItem{Type: CostTypeOther, Other: &bp.Other[i]})
+	}
+	for i := range bp.Subawards {
+		items = append(items, LineItem{Type: CostTypeSubaward, Subaward: &bp.Subawards[i]})
+	}
+	return items
+}
+
+// SetLineItems replaces the line items of a period.
+func (bp *BudgetPeriod) SetLineItems(items []LineItem) {
+	bp.Personnel, bp.Equipment, bp.Travel, bp.Supplies = []PersonnelCost{}, []EquipmentCost{}, []TravelCost{}, []SupplyCost{}
+	bp.Contractual, bp.Other, bp.Subawards = []ContractualCost{}, []OtherCost{}, []SubawardCost{}
+	for _, item := range items {
+		switch item.Type {
+		case CostTypePersonnel:
+			bp.Personnel = append(bp.Personnel, *item.Personnel)
+		case CostTypeEquipment:
+			bp.Equipment = append(bp.Equipment, *item.Equipment)
+		case CostTypeTravel:
+			bp.Travel = append(bp.Travel, *item.Travel)
+		case CostTypeSupplies:
+			bp.Supplies = append(bp.Supplies, *item.Supplies)
+		case CostTypeContractual:
+			bp.Contractual = append(bp.Contractual, *item.Contractual)
+		case CostTypeOther:
+			bp.Other = append(bp.Other, *item.Other)
+		case CostTypeSubaward:
+			bp.Subawards = append(bp.Subawards, *item.Subaward)
+		}
+	}
+}
+
+// FindLineItem returns a copy of a line item of the period.
+func (bp *BudgetPeriod) FindLineItem(id uuid.UUID) (LineItem, error) {
+	for _, item := range bp.LineItems() {
+		if item.ID() == id {
+			return item.withID(id), nil
+		}
+	}
+	return LineItem{}, ErrLineItemNotFound
+}
+
+// AddLineItem validates a line item and appends it to the period under a
+// new ID.
+func (bp *BudgetPeriod) AddLineItem(item LineItem) (LineItem, error) {
+	if err := item.Validate(); err != nil {
+		return LineItem{}, err
+	}
+
+	added := item.withID(uuid.New())
+	bp.SetLineItems(append(bp.LineItems(), added))
+	return added, nil
+}
+
+// UpdateLineItem replaces a line item, keeping its ID and position. The cost
+// type of a line item cannot change.
+func (bp *BudgetPeriod) UpdateLineItem(id uuid.UUID, item LineItem) (LineItem, error) {
+	if err := item.Validate(); err != nil {
+		return LineItem{}, err
+	}
+
+	items := bp.LineItems()
+	for i := range items {
+		if items[i].ID() != id {
+			continue
+		}
+		if items[i].Type != item.Type {
+			return LineItem{}, fmt.Errorf("%w: a %s item cannot become a %s item", ErrInvalidLineItem, items[i].Type, item.Type)
+		}
+		items[i] = item.withID(id)
+		bp.SetLineItems(items)
+		return items[i], nil
+	}
+	return LineItem{}, ErrLineItemNotFound
+}
+
+// RemoveLineItem removes a line item from the period.
+func (bp *BudgetPeriod) RemoveLineItem(id uuid.UUID) error {
+	items := bp.LineItems()
+	for i := range items {
+		if items[i].ID() == id {
+			bp.SetLineItems(append(items[:i:i], items[i+1:]...))
+			return nil
+		}
+	}
+	return ErrLineItemNotFound
+}
+
+// IsEditable reports whether the budget may be changed. Budgets submitted,
+// in review or approved are frozen.
+func (b *Budget) IsEditable() bool {
+	switch b.Status {
+	case BudgetStatusDraft, BudgetStatusRevision, BudgetStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// Period returns a period of the budget.
+func (b *Budget) Period(id uuid.UUID) (*BudgetPeriod, error) {
+	for i := range b.Periods {
+		if b.Periods[i].ID == id {
+			return &b.Periods[i], nil
+		}
+	}
+	return nil, ErrPeriodNotFound
+}
+
+// OpenPeriod appends an empty period. Zero dates continue the budget for a
+// year after its last period. Periods may not overlap the previous period.
+func (b *Budget) OpenPeriod(start, end time.Time) (*BudgetPeriod, error) {
+	var last *BudgetPeriod
+	if len(b.Periods) > 0 {
+		last = &b.Periods[len(b.Periods)-1]
+	}
+
+	if start.IsZero() {
+		if last == nil {
+			return nil, fmt.Errorf("%w: the first period needs a start date", ErrInvalidBudget)
+		}
+		start = last.EndDate.AddDate(0, 0, 1)
+	}
+	if end.IsZero() {
+		end = start.AddDate(1, 0, -1)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: period cannot end before it starts", ErrInvalidBudget)
+	}
+	if last != nil && !start.After(last.EndDate) {
+		return nil, fmt.Errorf("%w: period must start after period %d ends", ErrInvalidBudget, last.PeriodNumber)
+	}
+
+	b.AddPeriod(BudgetPeriod{StartDate: start, EndDate: end})
+	return &b.Periods[len(b.Periods)-1], nil
+}
+
+// RemovePeriod removes a period and renumbers the periods after it.
+func (b *Budget) RemovePeriod(id uuid.UUID) error {
+	for i := range b.Periods {
+		if b.Periods[i].ID != id {
+			continue
+		}
+		b.Periods = append(b.Periods[:i], b.Periods[i+1:]...)
+		for j := i; j < len(b.Periods); j++ {
+			b.Periods[j].PeriodNumber = j + 1
+		}
+		return nil
+	}
+	return ErrPeriodNotFound
+}
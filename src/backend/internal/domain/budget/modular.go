@@ -0,0 +1,197 @@
+// Package budget provides NIH modular budgets.
+package budget
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// NIH modular budget limits.
+var (
+	// ModuleSize is the increment modular direct costs are requested in.
+	ModuleSize = decimal.NewFromInt(25000)
+	// ModularAnnualCeiling bounds the direct costs less consortium F&A of a
+	// modular budget period.
+	ModularAnnualCeiling = decimal.NewFromInt(250000)
+)
+
+// Modular narrative triggers.
+const (
+	NarrativeVariableModules = "VARIABLE_MODULES" // module counts vary across periods
+	NarrativeModularVariance = "MODULAR_VARIANCE" // modules exceed the detailed costs by a module or more
+	NarrativeConsortium      = "CONSORTIUM"       // consortium costs are requested
+)
+
+// ModularPeriod is the modular request of a budget period.
+type ModularPeriod struct {
+	PeriodID            uuid.UUID       `json:"period_id"`
+	PeriodNumber        int             `json:"period_number"`
+	DetailedDirectCosts decimal.Decimal `json:"detailed_direct_costs"` // less consortium F&A
+	ConsortiumFA        decimal.Decimal `json:"consortium_fa"`
+	Modules             int             `json:"modules"`
+	ModularDirectCosts  decimal.Decimal `json:"modular_direct_costs"` // modules of ModuleSize
+	Variance            decimal.Decimal `json:"variance"`             // modular less detailed
+	TotalDirectCosts    decimal.Decimal `json:"total_direct_costs"`   // modular plus consortium F&A
+	OverCeiling         bool            `json:"over_ceiling"`
+}
+
+// ModularNarrative is an additional narrative justification NIH requires
+// of a modular budget.
+type ModularNarrative struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Periods []int  `json:"periods,omitempty"`
+}
+
+// ModularBudget is the modular request of a budget with the narratives it
+// needs.
+type ModularBudget struct {
+	Periods             []ModularPeriod    `json:"periods"`
+	TotalModules        int                `json:"total_modules"`
+	DetailedDirectCosts decimal.Decimal    `json:"detailed_direct_costs"`
+	ModularDirectCosts  decimal.Decimal    `json:"modular_direct_costs"`
+	Variance            decimal.Decimal    `json:"variance"`
+	VarianceFlagged     bool               `json:"variance_flagged"` // modules exceed the detailed costs by a module or more
+	Narratives          []ModularNarrative `json:"narratives"`
+}
+
+// ConsortiumFA returns the F&A costs of the period's subawards, which
+// modular budgets request outside the modules.
+func (bp *BudgetPeriod) ConsortiumFA() decimal.Decimal {
+	total := decimal.Zero
+	for _, s := range bp.Subawards {
+		total = total.Add(s.IndirectCosts)
+	}
+	return total
+}
+
+// modularBase returns the direct costs less consortium F&A of a period.
+func (bp *BudgetPeriod) modularBase() decimal.Decimal {
+	return bp.TotalDirectCosts().Sub(bp.ConsortiumFA())
+}
+
+// modulesFor returns the modules covering an amount, rounding up.
+func modulesFor(amount decimal.Decimal) int {
+	if !amount.IsPositive() {
+		return 0
+	}
+	return int(amount.Div(ModuleSize).Ceil().IntPart())
+}
+
+// SetModular turns the budget's modular mode on or off. A budget with a
+// period over ModularAnnualCeiling cannot be made modular.
+func (b *Budget) SetModular(modular bool) error {
+	if modular {
+		if err := b.checkModularCeiling(); err != nil {
+			return err
+		}
+	}
+	b.IsModular = modular
+	return nil
+}
+
+// CheckModular fails if the budget is modular and a period's direct costs
+// less consortium F&A exceed ModularAnnualCeiling.
+func (b *Budget) CheckModular() error {
+	if !b.IsModular {
+		return nil
+	}
+	return b.checkModularCeiling()
+}
+
+// checkModularCeiling fails on the first period over the modular ceiling.
+func (b *Budget) checkModularCeiling() error {
+	for _, period := range b.Periods {
+		if period.modularBase().GreaterThan(ModularAnnualCeiling) {
+			return fmt.Errorf("%w: period %d direct costs less consortium F&A exceed the modular ceiling of $%s",
+				ErrInvalidBudget, period.PeriodNumber, ModularAnnualCeiling.StringFixed(0))
+		}
+	}
+	return nil
+}
+
+// CalculateModular rounds each period's direct costs less consortium F&A up
+// to modules and compares them with the detailed costs. Module counts that
+// change from one period to the next, a variance of a module or more and
+// consortium costs each call for an additional narrative.
+func (c *Calculator) CalculateModular(budget *Budget) ModularBudget {
+	result := ModularBudget{
+		Periods:    make([]ModularPeriod, len(budget.Periods)),
+		Narratives: make([]ModularNarrative, 0),
+	}
+
+	var varying, consortium []int
+	for i := range budget.Periods {
+		period := &budget.Periods[i]
+		detailed := period.modularBase()
+		consortiumFA := period.ConsortiumFA()
+		modules := modulesFor(detailed)
+		modular := ModuleSize.Mul(decimal.NewFromInt(int64(modules)))
+
+		result.Periods[i] = ModularPeriod{
+			PeriodID:            period.ID,
+			PeriodNumber:        period.PeriodNumber,
+			DetailedDirectCosts: detailed,
+			ConsortiumFA:        consortiumFA,
+			Modules:             modules,
+			ModularDirectCosts:  modular,
+			Variance:            modular.Sub(detailed),
+			TotalDirectCosts:    modular.Add(consortiumFA),
+			OverCeiling:         detailed.GreaterThan(ModularAnnualCeiling),
+		}
+		result.TotalModules += modules
+		result.DetailedDirectCosts = result.DetailedDirectCosts.Add(detailed)
+		result.ModularDirectCosts = result.ModularDirectCosts.Add(modular)
+
+		if i > 0 && modules != result.Periods[i-1].Modules {
+			varying = append(varying, period.PeriodNumber)
+		}
+		if len(period.Subawards) > 0 {
+			consortium = append(consortium, period.PeriodNumber)
+		}
+	}
+	result.Variance = result.ModularDirectCosts.Sub(result.DetailedDirectCosts)
+	result.VarianceFlagged = result.Variance.GreaterThanOrEqual(ModuleSize)
+
+	if len(varying) > 0 {
+		result.Narratives = append(result.Narratives, ModularNarrative{
+			Code:    NarrativeVariableModules,
+			Message: "Explain the change in the number of modules requested from the previous period",
+			Periods: varying,
+		})
+	}
+	if result.VarianceFlagged {
+		result.Narratives = append(result.Narratives, ModularNarrative{
+			Code:    NarrativeModularVariance,
+			Message: fmt.Sprintf("Modules exceed the detailed direct costs by $%s", result.Variance.StringFixed(0)),
+		})
+	}
+	if len(consortium) > 0 {
+		result.Narratives = append(result.Narratives, ModularNarrative{
+			Code:    NarrativeConsortium,
+			Message: "Justify each consortium's total costs and its F&A",
+			Periods: consortium,
+		})
+	}
+	return result
+}
+
+// validateModular reports the periods of a modular budget over the ceiling.
+func (c *Calculator) validateModular(budget *Budget) []BudgetValidationError {
+	if !budget.IsModular {
+		return nil
+	}
+	var errs []BudgetValidationError
+	for i, period := range budget.Periods {
+		if period.modularBase().GreaterThan(ModularAnnualCeiling) {
+			errs = append(errs, BudgetValidationError{
+				Code:    "MODULAR_CEILING",
+				Message: fmt.Sprintf("Modular budgets are limited to $%s direct costs less consortium F&A per period", ModularAnnualCeiling.StringFixed(0)),
+				Period:  i + 1,
+			})
+		}
+	}
+	return errs
+}
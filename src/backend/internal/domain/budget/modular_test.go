@@ -0,0 +1,126 @@
+package budget
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCalculateModular(t *testing.T) {
+	period := func(number int, supplies string, subawards ...SubawardCost) BudgetPeriod {
+		return BudgetPeriod{
+			PeriodNumber: number,
+			Supplies:     []SupplyCost{{Description: "Reagents", TotalCost: dec(supplies)}},
+			Subawards:    subawards,
+		}
+	}
+	consortium := SubawardCost{Organization: "Beta Institute", DirectCosts: dec("40000"), IndirectCosts: dec("20000"), TotalCost: dec("60000")}
+
+	tests := []struct {
+		name       string
+		periods    []BudgetPeriod
+		modules    []int
+		over       []bool
+		total      []string // total direct costs per period
+		variance   string
+		flagged    bool
+		narratives []string
+	}{
+		{
+			name:       "exactly at the ceiling",
+			periods:    []BudgetPeriod{period(1, "250000")},
+			modules:    []int{10},
+			over:       []bool{false},
+			total:      []string{"250000"},
+			variance:   "0",
+			narratives: []string{},
+		},
+		{
+			name:       "a cent over a module rounds up",
+			periods:    []BudgetPeriod{period(1, "225000.01")},
+			modules:    []int{10},
+			over:       []bool{false},
+			total:      []string{"250000"},
+			variance:   "24999.99",
+			narratives: []string{},
+		},
+		{
+			name:       "a cent over the ceiling",
+			periods:    []BudgetPeriod{period(1, "250000.01")},
+			modules:    []int{11},
+			over:       []bool{true},
+			total:      []string{"275000"},
+			variance:   "24999.99",
+			narratives: []string{},
+		},
+		{
+			name:       "consortium F&A is requested outside the modules",
+			periods:    []BudgetPeriod{period(1, "190000", consortium)},
+			modules:    []int{10},
+			over:       []bool{false},
+			total:      []string{"270000"},
+			variance:   "20000",
+			narratives: []string{NarrativeConsortium},
+		},
+		{
+			name:       "module counts vary across periods",
+			periods:    []BudgetPeriod{period(1, "200000"), period(2, "150001"), period(3, "150000")},
+			modules:    []int{8, 7, 6},
+			over:       []bool{false, false, false},
+			total:      []string{"200000", "175000", "150000"},
+			variance:   "24999",
+			narratives: []string{NarrativeVariableModules},
+		},
+		{
+			name:       "variances add up to a module",
+			periods:    []BudgetPeriod{period(1, "200001"), period(2, "200001")},
+			modules:    []int{9, 9},
+			over:       []bool{false, false},
+			total:      []string{"225000", "225000"},
+			variance:   "49998",
+			flagged:    true,
+			narratives: []string{NarrativeModularVariance},
+		},
+		{
+			name:       "no direct costs",
+			periods:    []BudgetPeriod{period(1, "0")},
+			modules:    []int{0},
+			over:       []bool{false},
+			total:      []string{"0"},
+			variance:   "0",
+			narratives: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewCalculator(FARate{}).CalculateModular(&Budget{Periods: tt.periods})
+
+			var modules []int
+			var over []bool
+			for i, p := range result.Periods {
+				modules = append(modules, p.Modules)
+				over = append(over, p.OverCeiling)
+				if !p.TotalDirectCosts.Equal(dec(tt.total[i])) {
+					t.Errorf("period %d: total direct costs %s, want %s", i+1, p.TotalDirectCosts, tt.total[i])
+				}
+			}
+			if !reflect.DeepEqual(modules, tt.modules) {
+				t.Errorf("modules %v, want %v", modules, tt.modules)
+			}
+			if !reflect.DeepEqual(over, tt.over) {
+				t.Errorf("over ceiling %v, want %v", over, tt.over)
+			}
+			if !result.Variance.Equal(dec(tt.variance)) || result.VarianceFlagged != tt.flagged {
+				t.Errorf("variance %s flagged %v, want %s flagged %v", result.Variance, result.VarianceFlagged, tt.variance, tt.flagged)
+			}
+
+			codes := []string{}
+			for _, n := range result.Narratives {
+				codes = append(codes, n.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.narratives) {
+				t.Errorf("narratives %v, want %v", codes, tt.narratives)
+			}
+		})
+	}
+}
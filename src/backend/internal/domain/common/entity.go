@@ -81,9 +81,10 @@ func NewAuditInfo(action string, userID uuid.UUID, metadata map[string]interface
 
 // TenantContext provides tenant-aware context for operations.
 type TenantContext struct {
-	TenantID TenantID
-	UserID   uuid.UUID
-	Roles    []string
+	TenantID   TenantID
+	UserID     uuid.UUID
+	Roles      []string
+	Department string
 }
 
 // HasRole checks if the context has a specific role.
@@ -99,6 +99,29 @@ func NewProposalStateChangedEvent(aggregateID uuid.UUID, tenantID TenantID, vers
 	}
 }
 
+// ProposalSLABreachedEvent is emitted when a proposal stays in a state longer
+// than the state's SLA.
+type ProposalSLABreachedEvent struct {
+	BaseDomainEvent
+	State     string    `json:"state"`
+	Region    string    `json:"region,omitempty"`
+	SLAHours  int       `json:"sla_hours"`
+	EnteredAt time.Time `json:"entered_at"`
+	DueAt     time.Time `json:"due_at"`
+}
+
+// NewProposalSLABreachedEvent creates a new proposal SLA breached event.
+func NewProposalSLABreachedEvent(aggregateID uuid.UUID, tenantID TenantID, version int, state, region string, slaHours int, enteredAt, dueAt time.Time) ProposalSLABreachedEvent {
+	return ProposalSLABreachedEvent{
+		BaseDomainEvent: NewBaseDomainEvent("proposal.sla_breached", aggregateID, "Proposal", tenantID, version),
+		State:           state,
+		Region:          region,
+		SLAHours:        slaHours,
+		EnteredAt:       enteredAt,
+		DueAt:           dueAt,
+	}
+}
+
 // BudgetUpdatedEvent is emitted when a budget is updated.
 type BudgetUpdatedEvent struct {
 	BaseDomainEvent
@@ -119,6 +142,68 @@ func NewBudgetUpdatedEvent(aggregateID uuid.UUID, tenantID TenantID, version int
 	}
 }
 
+// AwardCreatedEvent is emitted when an award is opened for an awarded proposal.
+type AwardCreatedEvent struct {
+	BaseDomainEvent
+	ProposalID uuid.UUID `json:"proposal_id"`
+	SponsorID  uuid.UUID `json:"sponsor_id"`
+}
+
+// NewAwardCreatedEvent creates a new award created event.
+func NewAwardCreatedEvent(aggregateID uuid.UUID, tenantID TenantID, version int, proposalID, sponsorID uuid.UUID) AwardCreatedEvent {
+	return AwardCreatedEvent{
+		BaseDomainEvent: NewBaseDomainEvent("award.created", aggregateID, "Award", tenantID, version),
+		ProposalID:      proposalID,
+		SponsorID:       sponsorID,
+	}
+}
+
+// AwardSetupCompletedEvent is emitted when an award's setup is complete and
+// the proposal may be activated.
+type AwardSetupCompletedEvent struct {
+	BaseDomainEvent
+	ProposalID         uuid.UUID `json:"proposal_id"`
+	SponsorAwardNumber string    `json:"sponsor_award_number"`
+	TotalObligated     string    `json:"total_obligated"`
+	TotalAnticipated   string    `json:"total_anticipated"`
+}
+
+// NewAwardSetupCompletedEvent creates a new award setup completed event.
+func NewAwardSetupCompletedEvent(aggregateID uuid.UUID, tenantID TenantID, version int, proposalID uuid.UUID, sponsorAwardNumber, obligated, anticipated string) AwardSetupCompletedEvent {
+	return AwardSetupCompletedEvent{
+		BaseDomainEvent:    NewBaseDomainEvent("award.setup_completed", aggregateID, "Award", tenantID, version),
+		ProposalID:         proposalID,
+		SponsorAwardNumber: sponsorAwardNumber,
+		TotalObligated:     obligated,
+		TotalAnticipated:   anticipated,
+	}
+}
+
+// AwardModifiedEvent is emitted when a modification is recorded on an award.
+// The event type names the kind of modification, e.g. award.supplemented.
+type AwardModifiedEvent struct {
+	BaseDomainEvent
+	ModificationID     uuid.UUID  `json:"modification_id"`
+	ModificationNumber int        `json:"modification_number"`
+	PeriodNumber       int        `json:"period_number,omitempty"`
+	Amount             string     `json:"amount"`
+	NewEndDate         *time.Time `json:"new_end_date,omitempty"`
+	EffectiveDate      time.Time  `json:"effective_date"`
+}
+
+// NewAwardModifiedEvent creates a new award modified event of the given type.
+func NewAwardModifiedEvent(eventType string, aggregateID uuid.UUID, tenantID TenantID, version int, modificationID uuid.UUID, number, periodNumber int, amount string, newEndDate *time.Time, effectiveDate time.Time) AwardModifiedEvent {
+	return AwardModifiedEvent{
+		BaseDomainEvent:    NewBaseDomainEvent(eventType, aggregateID, "Award", tenantID, version),
+		ModificationID:     modificationID,
+		ModificationNumber: number,
+		PeriodNumber:       periodNumber,
+		Amount:             amount,
+		NewEndDate:         newEndDate,
+		EffectiveDate:      effectiveDate,
+	}
+}
+
 // EventStore defines the interface for storing domain events.
 type EventStore interface {
 	// Append appends events to the event store.
@@ -0,0 +1,383 @@
+// Package proposal provides multi-approver approval sets for the proposal workflow.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+)
+
+// GuardApprovalQuorum is the name of the guard that enforces approval sets.
+const GuardApprovalQuorum = "approval_quorum"
+
+// ErrApprovalPending is returned when a gated transition lacks the required approvals.
+var ErrApprovalPending = errors.New("required approvals are outstanding")
+
+// ErrNoApprovalSet is returned when votes are cast on a state without an approval set.
+var ErrNoApprovalSet = errors.New("no approval set for the current state")
+
+// ErrNotApprover is returned when the actor holds no open approver role.
+var ErrNotApprover = errors.New("actor is not an eligible approver")
+
+// ErrInvalidDecision is returned for an unknown approval decision.
+var ErrInvalidDecision = errors.New("invalid approval decision")
+
+// ApprovalDecision is an approver's vote.
+type ApprovalDecision string
+
+const (
+	DecisionApprove ApprovalDecision = "APPROVE"
+	DecisionReject  ApprovalDecision = "REJECT"
+	DecisionAbstain ApprovalDecision = "ABSTAIN"
+)
+
+// IsValid reports whether the decision is known.
+func (d ApprovalDecision) IsValid() bool {
+	switch d {
+	case DecisionApprove, DecisionReject, DecisionAbstain:
+		return true
+	}
+	return false
+}
+
+// ApprovalRule decides when an approval set is satisfied.
+type ApprovalRule string
+
+const (
+	// RuleAllOf requires every approver who does not abstain to approve.
+	RuleAllOf ApprovalRule = "ALL_OF"
+
+	// RuleAnyN requires Quorum approvals.
+	RuleAnyN ApprovalRule = "ANY_N"
+)
+
+// ApprovalStatus is the outcome of an approval set.
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "PENDING"
+	ApprovalApproved ApprovalStatus = "APPROVED"
+	ApprovalRejected ApprovalStatus = "REJECTED"
+)
+
+// ApprovalSet requires votes from a set of approvers before a transition out
+// of a state fires. Approvers are roles; a role listed several times takes
+// that many votes. Required roles must approve whatever the rule.
+type ApprovalSet struct {
+	State            ProposalState      `json:"state"`
+	Transition       ProposalTransition `json:"transition"`
+	RejectTransition ProposalTransition `json:"reject_transition,omitempty"`
+	Rule             ApprovalRule       `json:"rule"`
+	Quorum           int                `json:"quorum,omitempty"`
+	Approvers        []string           `json:"approvers"`
+	RequiredRoles    []string           `json:"required_roles,omitempty"`
+}
+
+// Approval is a vote recorded by an approver while a proposal is in a gated state.
+type Approval struct {
+	ID         uuid.UUID          `json:"id"`
+	TenantID   common.TenantID    `json:"tenant_id"`
+	ProposalID uuid.UUID          `json:"proposal_id"`
+	State      ProposalState      `json:"state"`
+	Transition ProposalTransition `json:"transition"`
+	ApproverID uuid.UUID          `json:"approver_id"`
+	OnBehalfOf *uuid.UUID         `json:"on_behalf_of,omitempty"`
+	Role       string             `json:"role"`
+	Decision   ApprovalDecision   `json:"decision"`
+	Comment    string             `json:"comment,omitempty"`
+	DecidedAt  time.Time          `json:"decided_at"`
+}
+
+// ApprovalTally summarizes the votes on an approval set.
+type ApprovalTally struct {
+	Set         ApprovalSet    `json:"set"`
+	Status      ApprovalStatus `json:"status"`
+	Approvals   int            `json:"approvals"`
+	Rejections  int            `json:"rejections"`
+	Abstentions int            `json:"abstentions"`
+	Outstanding []string       `json:"outstanding,omitempty"`
+	Votes       []Approval     `json:"votes"`
+}
+
+// ApprovalRepository persists approval votes.
+type ApprovalRepository interface {
+	// SaveApproval persists a vote.
+	SaveApproval(ctx context.Context, approval *Approval) error
+
+	// FindApprovals retrieves all votes of a proposal ordered by decision time.
+	FindApprovals(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) ([]Approval, error)
+}
+
+// approvalSetFromDefinition converts a workflow document approval set.
+func approvalSetFromDefinition(state string, d statemachine.ApprovalDefinition) (ApprovalSet, error) {
+	set := ApprovalSet{
+		State:            ProposalState(state),
+		Transition:       ProposalTransition(d.Transition),
+		RejectTransition: ProposalTransition(d.RejectTransition),
+		Rule:             ApprovalRule(strings.ToUpper(d.Rule)),
+		Quorum:           d.Quorum,
+		Approvers:        d.Approvers,
+		RequiredRoles:    d.RequiredRoles,
+	}
+	if err := set.Validate(); err != nil {
+		return set, fmt.Errorf("%w: approval set for %s/%s: %v", statemachine.ErrInvalidDefinition, state, d.Transition, err)
+	}
+	return set, nil
+}
+
+// Validate checks the rule and quorum of the approval set.
+func (s ApprovalSet) Validate() error {
+	switch s.Rule {
+	case RuleAllOf:
+	case RuleAnyN:
+		if s.Quorum < 1 || s.Quorum > len(s.seats()) {
+			return fmt.Errorf("quorum %d must be between 1 and %d", s.Quorum, len(s.seats()))
+		}
+	default:
+		return fmt.Errorf("unknown rule %q", s.Rule)
+	}
+	if len(s.seats()) == 0 {
+		return errors.New("no approvers")
+	}
+	return nil
+}
+
+// seats returns one entry per vote the set takes. Required roles not listed
+// as approvers take one seat each.
+func (s ApprovalSet) seats() []string {
+	seats := append([]string(nil), s.Approvers...)
+	for _, r := range s.RequiredRoles {
+		if !containsString(s.Approvers, r) {
+			seats = append(seats, r)
+		}
+	}
+	return seats
+}
+
+// current returns the latest vote of each approver on the set cast since the
+// given time, in decision order.
+func (s ApprovalSet) current(votes []Approval, since time.Time) []Approval {
+	latest := make(map[uuid.UUID]int)
+	var result []Approval
+	for _, v := range votes {
+		if v.State != s.State || v.Transition != s.Transition || v.DecidedAt.Before(since) {
+			continue
+		}
+		if i, ok := latest[v.ApproverID]; ok {
+			result[i] = v
+			continue
+		}
+		latest[v.ApproverID] = len(result)
+		result = append(result, v)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].DecidedAt.Before(result[j].DecidedAt) })
+	return result
+}
+
+// SeatFor returns the role an approver votes under: the first of the actor's
+// roles with a seat not taken by another approver.
+func (s ApprovalSet) SeatFor(actor uuid.UUID, roles []string, votes []Approval) (string, error) {
+	open := make(map[string]int)
+	for _, seat := range s.seats() {
+		open[seat]++
+	}
+	for _, v := range votes {
+		if v.ApproverID != actor {
+			open[v.Role]--
+		}
+	}
+	for _, r := range roles {
+		if open[r] > 0 {
+			return r, nil
+		}
+	}
+	return "", ErrNotApprover
+}
+
+// Evaluate tallies votes cast on the set and decides its status.
+func (s ApprovalSet) Evaluate(votes []Approval) ApprovalTally {
+	tally := ApprovalTally{Set: s, Status: ApprovalPending, Votes: votes}
+
+	open := make(map[string]int)
+	seats := s.seats()
+	for _, seat := range seats {
+		open[seat]++
+	}
+	approvedBy := make(map[string]bool)
+	rejectedBy := make(map[string]bool)
+	for _, v := range votes {
+		if open[v.Role] == 0 {
+			continue
+		}
+		open[v.Role]--
+		switch v.Decision {
+		case DecisionApprove:
+			tally.Approvals++
+			approvedBy[v.Role] = true
+		case DecisionReject:
+			tally.Rejections++
+			rejectedBy[v.Role] = true
+		case DecisionAbstain:
+			tally.Abstentions++
+		}
+	}
+	for _, seat := range seats {
+		if open[seat] > 0 {
+			tally.Outstanding = append(tally.Outstanding, seat)
+			open[seat]--
+		}
+	}
+
+	requiredMet := true
+	for _, r := range s.RequiredRoles {
+		if rejectedBy[r] && !approvedBy[r] {
+			tally.Status = ApprovalRejected
+			return tally
+		}
+		if !approvedBy[r] {
+			requiredMet = false
+		}
+	}
+
+	switch s.Rule {
+	case RuleAllOf:
+		if tally.Rejections > 0 {
+			tally.Status = ApprovalRejected
+		} else if tally.Approvals > 0 && tally.Approvals == len(seats)-tally.Abstentions && requiredMet {
+			tally.Status = ApprovalApproved
+		}
+	case RuleAnyN:
+		if len(seats)-tally.Rejections-tally.Abstentions < s.Quorum {
+			tally.Status = ApprovalRejected
+		} else if tally.Approvals >= s.Quorum && requiredMet {
+			tally.Status = ApprovalApproved
+		}
+	}
+
+	return tally
+}
+
+// Summary describes the tally for guard rejections and notifications.
+func (t ApprovalTally) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d approval(s), %d rejection(s), %d abstention(s)", t.Approvals, t.Rejections, t.Abstentions)
+	if t.Set.Rule == RuleAnyN {
+		fmt.Fprintf(&b, " of %d required", t.Set.Quorum)
+	}
+	if len(t.Outstanding) > 0 {
+		fmt.Fprintf(&b, "; awaiting %s", strings.Join(t.Outstanding, ", "))
+	}
+	return b.String()
+}
+
+// CurrentApprovals returns the latest vote of each approver on an approval
+// set cast during the proposal's current stay in the set's state.
+func (p *Proposal) CurrentApprovals(set ApprovalSet, votes []Approval) []Approval {
+	return set.current(votes, p.ActiveSince(set.State))
+}
+
+// ApprovalTally evaluates the current votes on an approval set.
+func (p *Proposal) ApprovalTally(set ApprovalSet, votes []Approval) ApprovalTally {
+	return set.Evaluate(p.CurrentApprovals(set, votes))
+}
+
+// CastApproval records an approver's vote on an approval set. The approver
+// votes under the first of their roles with an open seat; a later vote by the
+// same approver replaces the earlier one.
+func (p *Proposal) CastApproval(set ApprovalSet, votes []Approval, actor uuid.UUID, roles []string, decision ApprovalDecision, comment string) (*Approval, error) {
+	if !decision.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDecision, decision)
+	}
+
+	role, err := set.SeatFor(actor, roles, p.CurrentApprovals(set, votes))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Approval{
+		ID:         uuid.New(),
+		TenantID:   p.TenantID,
+		ProposalID: p.ID,
+		State:      set.State,
+		Transition: set.Transition,
+		ApproverID: actor,
+		Role:       role,
+		Decision:   decision,
+		Comment:    comment,
+		DecidedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// ActiveApprovalSets returns the approval sets of the proposal's active
+// state and of the active sub-states of unfinished regions.
+func (psm *ProposalStateMachine) ActiveApprovalSets(p *Proposal) []ApprovalSet {
+	var sets []ApprovalSet
+	for _, set := range psm.approvals {
+		if set.State == p.State {
+			sets = append(sets, set)
+			continue
+		}
+		for _, sub := range p.SubStates {
+			if sub == set.State && sub != p.State {
+				sets = append(sets, set)
+				break
+			}
+		}
+	}
+	return sets
+}
+
+// ApprovalSets returns every approval set of the workflow.
+func (psm *ProposalStateMachine) ApprovalSets() []ApprovalSet {
+	return psm.approvals
+}
+
+// AddApprovalSet declares an approval set. It is enforced once
+// RequireApprovals is called.
+func (psm *ProposalStateMachine) AddApprovalSet(set ApprovalSet) {
+	psm.approvals = append(psm.approvals, set)
+}
+
+// RequireApprovals gates each approval set's transition on its votes, which
+// are read from the repository when the transition is attempted.
+func (psm *ProposalStateMachine) RequireApprovals(repo ApprovalRepository) {
+	for _, set := range psm.approvals {
+		psm.AddStateGuard(set.State, set.Transition, GuardApprovalQuorum, ApprovalQuorumGuard(set, repo).Func())
+	}
+}
+
+// ApprovalQuorumGuard blocks a transition until its approval set is approved.
+func ApprovalQuorumGuard(set ApprovalSet, repo ApprovalRepository) ProposalGuard {
+	return func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error {
+		if payload.Proposal == nil {
+			return errors.New("transition payload has no proposal")
+		}
+
+		votes, err := repo.FindApprovals(ctx, payload.Proposal.TenantID, payload.Proposal.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load approvals: %w", err)
+		}
+		tally := payload.Proposal.ApprovalTally(set, votes)
+		if tally.Status != ApprovalApproved {
+			return fmt.Errorf("%w: %s", ErrApprovalPending, tally.Summary())
+		}
+		return nil
+	}
+}
+
+// containsString reports whether a slice contains a value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
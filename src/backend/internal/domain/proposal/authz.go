@@ -0,0 +1,79 @@
+// Package proposal provides policy attributes and authorization helpers.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// PolicyAttributes returns the attributes policies can match on.
+func (p *Proposal) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{
+		"type":              "proposal",
+		"id":                p.ID,
+		"state":             p.State,
+		"proposal_type":     p.Type,
+		"sub_states":        p.SubStates,
+		"department":        p.Department,
+		"pi_id":             p.PrincipalInvestigatorID,
+		"co_investigators":  p.CoInvestigators,
+		"sponsor_id":        p.SponsorID,
+		"created_by":        p.CreatedBy,
+		"workflow_version":  p.WorkflowVersion,
+		"is_latest_version": p.IsLatestVersion,
+	}
+}
+
+// PolicyAttributes returns the attributes policies can match on.
+func (d Delegation) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{
+		"type":         "delegation",
+		"id":           d.ID,
+		"delegator_id": d.DelegatorID,
+		"delegate_id":  d.DelegateID,
+		"role":         d.Role,
+		"department":   d.Department,
+		"created_by":   d.CreatedBy,
+	}
+}
+
+// Authorize checks an action on a proposal, which may be nil for actions
+// without a resource. Denials wrap both ErrUnauthorized and authz.ErrDenied.
+func Authorize(ctx context.Context, az authz.Authorizer, tenantCtx common.TenantContext, action string, p *Proposal) error {
+	req := authz.Request{Action: action}
+	if p != nil {
+		req.Resource = p.PolicyAttributes()
+	}
+	return authorize(ctx, az, tenantCtx, req)
+}
+
+// authorize checks a request, wrapping denials in ErrUnauthorized.
+func authorize(ctx context.Context, az authz.Authorizer, tenantCtx common.TenantContext, req authz.Request) error {
+	err := authz.Check(ctx, az, tenantCtx, req)
+	if errors.Is(err, authz.ErrDenied) {
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return err
+}
+
+// TransitionRequest builds the authorization request for firing a transition
+// from the proposal's current state.
+func TransitionRequest(p *Proposal, transition ProposalTransition, metadata StateMetadata, delegatedRoles []string) authz.Request {
+	required := metadata.RequiredRoles
+	if required == nil {
+		required = []string{}
+	}
+	return authz.Request{
+		Action:   authz.ActionProposalTransition,
+		Resource: p.PolicyAttributes(),
+		Context: map[string]interface{}{
+			"transition":     string(transition),
+			"required_roles": required,
+		},
+		DelegatedRoles: delegatedRoles,
+	}
+}
@@ -0,0 +1,532 @@
+// Package proposal provides the Award aggregate set up when a proposal is awarded.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/shopspring/decimal"
+)
+
+// GuardAwardSetupComplete is the workflow guard name of the award setup guard.
+const GuardAwardSetupComplete = "award_setup_complete"
+
+// ErrAwardNotFound is returned when a proposal has no award.
+var ErrAwardNotFound = errors.New("award not found")
+
+// ErrInvalidAward is returned when award setup data is malformed.
+var ErrInvalidAward = errors.New("invalid award")
+
+// ErrAwardSetupIncomplete is returned when an award is not fully set up.
+var ErrAwardSetupIncomplete = errors.New("award setup is incomplete")
+
+// ErrAwardSetupComplete is returned when setup data of a set-up award is
+// changed; later changes are recorded as modifications.
+var ErrAwardSetupComplete = errors.New("award setup is already complete - record a modification instead")
+
+// ErrInvalidModification is returned when an award modification cannot be applied.
+var ErrInvalidModification = errors.New("invalid award modification")
+
+// AwardPeriod is a budget period of an award with the amount the sponsor
+// has obligated so far and the amount it anticipates funding.
+type AwardPeriod struct {
+	PeriodNumber int             `json:"period_number"`
+	StartDate    time.Time       `json:"start_date"`
+	EndDate      time.Time       `json:"end_date"`
+	Anticipated  decimal.Decimal `json:"anticipated_amount"`
+	Obligated    decimal.Decimal `json:"obligated_amount"`
+}
+
+// AwardTerm is a term or condition of an award.
+type AwardTerm struct {
+	Code  string `json:"code,omitempty"` // e.g. the sponsor's clause number
+	Title string `json:"title"`
+	Text  string `json:"text,omitempty"`
+}
+
+// ReportType is the kind of report an award requires.
+type ReportType string
+
+const (
+	ReportFinancial ReportType = "financial"
+	ReportTechnical ReportType = "technical"
+	ReportInvention ReportType = "invention"
+	ReportProperty  ReportType = "property"
+	ReportOther     ReportType = "other"
+)
+
+// ReportFrequency is how often a report is due.
+type ReportFrequency string
+
+const (
+	ReportQuarterly  ReportFrequency = "quarterly"
+	ReportSemiannual ReportFrequency = "semiannual"
+	ReportAnnual     ReportFrequency = "annual"
+	ReportFinal      ReportFrequency = "final"
+)
+
+// ReportingRequirement is a report the sponsor requires, due a number of
+// days after the end of each reporting period.
+type ReportingRequirement struct {
+	Type         ReportType      `json:"type"`
+	Frequency    ReportFrequency `json:"frequency"`
+	DueDaysAfter int             `json:"due_days_after"`
+	Description  string          `json:"description,omitempty"`
+}
+
+// Validate checks the requirement's type, frequency and due days.
+func (r ReportingRequirement) Validate() error {
+	switch r.Type {
+	case ReportFinancial, ReportTechnical, ReportInvention, ReportProperty, ReportOther:
+	default:
+		return fmt.Errorf("%w: unknown report type %q", ErrInvalidAward, r.Type)
+	}
+	switch r.Frequency {
+	case ReportQuarterly, ReportSemiannual, ReportAnnual, ReportFinal:
+	default:
+		return fmt.Errorf("%w: unknown report frequency %q", ErrInvalidAward, r.Frequency)
+	}
+	if r.DueDaysAfter < 0 {
+		return fmt.Errorf("%w: due days must not be negative", ErrInvalidAward)
+	}
+	return nil
+}
+
+// ModificationType is the kind of an award modification.
+type ModificationType string
+
+const (
+	// ModificationSupplement adds funds to a budget period.
+	ModificationSupplement ModificationType = "SUPPLEMENT"
+	// ModificationNoCostExtension moves the end date without adding funds.
+	ModificationNoCostExtension ModificationType = "NO_COST_EXTENSION"
+	// ModificationDeobligation withdraws obligated funds from a budget period.
+	ModificationDeobligation ModificationType = "DEOBLIGATION"
+)
+
+// EventType returns the domain event type of the modification.
+func (t ModificationType) EventType() string {
+	switch t {
+	case ModificationSupplement:
+		return "award.supplemented"
+	case ModificationNoCostExtension:
+		return "award.no_cost_extended"
+	case ModificationDeobligation:
+		return "award.deobligated"
+	}
+	return "award.modified"
+}
+
+// AwardModification is a change to an award after setup, numbered in the
+// order it was recorded.
+type AwardModification struct {
+	ID               uuid.UUID        `json:"id"`
+	Number           int              `json:"number"`
+	Type             ModificationType `json:"type"`
+	PeriodNumber     int              `json:"period_number,omitempty"` // supplements and de-obligations
+	Amount           decimal.Decimal  `json:"amount"`
+	NewEndDate       *time.Time       `json:"new_end_date,omitempty"` // no-cost extensions
+	EffectiveDate    time.Time        `json:"effective_date"`
+	SponsorReference string           `json:"sponsor_reference,omitempty"`
+	Reason           string           `json:"reason,omitempty"`
+	RecordedBy       uuid.UUID        `json:"recorded_by"`
+	RecordedAt       time.Time        `json:"recorded_at"`
+}
+
+// Award is the aggregate root for a sponsor's award of a proposal. It is
+// opened when the proposal is awarded and must be set up before the
+// proposal is activated.
+type Award struct {
+	common.BaseEntity
+	common.AggregateRoot
+
+	ProposalID            uuid.UUID              `json:"proposal_id"`
+	SponsorID             uuid.UUID              `json:"sponsor_id"`
+	SponsorAwardNumber    string                 `json:"sponsor_award_number,omitempty"`
+	AwardDate             *time.Time             `json:"award_date,omitempty"`
+	ProjectPeriod         common.DateRange       `json:"project_period"`
+	Periods               []AwardPeriod          `json:"periods"`
+	Terms                 []AwardTerm            `json:"terms"`
+	ReportingRequirements []ReportingRequirement `json:"reporting_requirements"`
+	Modifications         []AwardModification    `json:"modifications"`
+	SetupCompletedAt      *time.Time             `json:"setup_completed_at,omitempty"`
+	SetupCompletedBy      *uuid.UUID             `json:"setup_completed_by,omitempty"`
+}
+
+// NewAward opens an award for a proposal. Budget periods are taken from the
+// proposal's budget if it has one, with each period's total anticipated and
+// nothing obligated yet.
+func NewAward(p *Proposal, b *budget.Budget, userID uuid.UUID) *Award {
+	a := &Award{
+		BaseEntity:            common.NewBaseEntity(p.TenantID, userID),
+		ProposalID:            p.ID,
+		SponsorID:             p.SponsorID,
+		ProjectPeriod:         p.ProjectPeriod,
+		Periods:               make([]AwardPeriod, 0),
+		Terms:                 make([]AwardTerm, 0),
+		ReportingRequirements: make([]ReportingRequirement, 0),
+		Modifications:         make([]AwardModification, 0),
+	}
+
+	if b != nil {
+		totals := budget.NewCalculator(b.FARate).CalculatePeriodTotals(b)
+		for i := range b.Periods {
+			bp := &b.Periods[i]
+			a.Periods = append(a.Periods, AwardPeriod{
+				PeriodNumber: bp.PeriodNumber,
+				StartDate:    bp.StartDate,
+				EndDate:      bp.EndDate,
+				Anticipated:  totals[i].Total.Round(2),
+				Obligated:    decimal.Zero,
+			})
+		}
+	}
+	if len(a.Periods) == 0 {
+		a.Periods = append(a.Periods, AwardPeriod{
+			PeriodNumber: 1,
+			StartDate:    p.ProjectPeriod.StartDate,
+			EndDate:      p.ProjectPeriod.EndDate,
+			Anticipated:  decimal.Zero,
+			Obligated:    decimal.Zero,
+		})
+	}
+
+	a.AddEvent(common.NewAwardCreatedEvent(a.ID, a.TenantID, a.Version, a.ProposalID, a.SponsorID))
+	return a
+}
+
+// TotalObligated returns the funds obligated across all periods.
+func (a *Award) TotalObligated() decimal.Decimal {
+	total := decimal.Zero
+	for _, p := range a.Periods {
+		total = total.Add(p.Obligated)
+	}
+	return total
+}
+
+// TotalAnticipated returns the funds anticipated across all periods.
+func (a *Award) TotalAnticipated() decimal.Decimal {
+	total := decimal.Zero
+	for _, p := range a.Periods {
+		total = total.Add(p.Anticipated)
+	}
+	return total
+}
+
+// IsSetupComplete reports whether the award has been set up.
+func (a *Award) IsSetupComplete() bool {
+	return a.SetupCompletedAt != nil
+}
+
+// SetupIssues returns what is missing before the award's setup can be
+// completed; it is empty when the award is ready.
+func (a *Award) SetupIssues() []string {
+	issues := make([]string, 0)
+	if strings.TrimSpace(a.SponsorAwardNumber) == "" {
+		issues = append(issues, "sponsor award number is missing")
+	}
+	if a.AwardDate == nil {
+		issues = append(issues, "award date is missing")
+	}
+	if a.ProjectPeriod.StartDate.IsZero() || a.ProjectPeriod.EndDate.IsZero() {
+		issues = append(issues, "award start and end dates are missing")
+	}
+	if len(a.Periods) == 0 {
+		issues = append(issues, "no budget periods")
+	} else if !a.Periods[0].Obligated.IsPositive() {
+		issues = append(issues, "no funds obligated for the first budget period")
+	}
+	if len(a.ReportingRequirements) == 0 {
+		issues = append(issues, "no reporting requirements")
+	}
+	return issues
+}
+
+// AwardSetup holds changes to an award's setup data. Nil fields are left
+// unchanged; slices replace the current values.
+type AwardSetup struct {
+	SponsorAwardNumber    *string                `json:"sponsor_award_number,omitempty"`
+	AwardDate             *time.Time             `json:"award_date,omitempty"`
+	ProjectPeriod         *common.DateRange      `json:"project_period,omitempty"`
+	Periods               []AwardPeriod          `json:"periods,omitempty"`
+	Terms                 []AwardTerm            `json:"terms,omitempty"`
+	ReportingRequirements []ReportingRequirement `json:"reporting_requirements,omitempty"`
+}
+
+// UpdateSetup changes the award's setup data. Once setup is complete,
+// changes are recorded as modifications instead.
+func (a *Award) UpdateSetup(userID uuid.UUID, setup AwardSetup) error {
+	if a.IsSetupComplete() {
+		return ErrAwardSetupComplete
+	}
+
+	period := a.ProjectPeriod
+	if setup.ProjectPeriod != nil {
+		period = *setup.ProjectPeriod
+		if period.EndDate.Before(period.StartDate) {
+			return fmt.Errorf("%w: end date is before start date", ErrInvalidAward)
+		}
+	}
+	periods := a.Periods
+	if setup.Periods != nil {
+		periods = setup.Periods
+	}
+	if err := validateAwardPeriods(periods, period); err != nil {
+		return err
+	}
+	for _, t := range setup.Terms {
+		if strings.TrimSpace(t.Title) == "" {
+			return fmt.Errorf("%w: terms need a title", ErrInvalidAward)
+		}
+	}
+	for _, r := range setup.ReportingRequirements {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if setup.SponsorAwardNumber != nil {
+		a.SponsorAwardNumber = strings.TrimSpace(*setup.SponsorAwardNumber)
+	}
+	if setup.AwardDate != nil {
+		a.AwardDate = setup.AwardDate
+	}
+	a.ProjectPeriod = period
+	a.Periods = periods
+	if setup.Terms != nil {
+		a.Terms = setup.Terms
+	}
+	if setup.ReportingRequirements != nil {
+		a.ReportingRequirements = setup.ReportingRequirements
+	}
+
+	a.Touch(userID)
+	return nil
+}
+
+// validateAwardPeriods checks that periods are numbered from 1, fall within
+// the project period and obligate no more than they anticipate.
+func validateAwardPeriods(periods []AwardPeriod, project common.DateRange) error {
+	for i, p := range periods {
+		if p.PeriodNumber != i+1 {
+			return fmt.Errorf("%w: budget periods must be numbered 1 to %d in order", ErrInvalidAward, len(periods))
+		}
+		if p.EndDate.Before(p.StartDate) {
+			return fmt.Errorf("%w: period %d ends before it starts", ErrInvalidAward, p.PeriodNumber)
+		}
+		if !project.StartDate.IsZero() && (p.StartDate.Before(project.StartDate) || p.EndDate.After(project.EndDate)) {
+			return fmt.Errorf("%w: period %d is outside the project period", ErrInvalidAward, p.PeriodNumber)
+		}
+		if p.Obligated.IsNegative() || p.Anticipated.IsNegative() {
+			return fmt.Errorf("%w: period %d has a negative amount", ErrInvalidAward, p.PeriodNumber)
+		}
+		if p.Obligated.GreaterThan(p.Anticipated) {
+			return fmt.Errorf("%w: period %d obligates more than it anticipates", ErrInvalidAward, p.PeriodNumber)
+		}
+	}
+	return nil
+}
+
+// CompleteSetup marks the award as set up so the proposal can be activated.
+func (a *Award) CompleteSetup(userID uuid.UUID) error {
+	if a.IsSetupComplete() {
+		return ErrAwardSetupComplete
+	}
+	if issues := a.SetupIssues(); len(issues) > 0 {
+		return fmt.Errorf("%w: %s", ErrAwardSetupIncomplete, strings.Join(issues, "; "))
+	}
+
+	now := time.Now().UTC()
+	a.SetupCompletedAt = &now
+	a.SetupCompletedBy = &userID
+	a.Touch(userID)
+
+	a.AddEvent(common.NewAwardSetupCompletedEvent(a.ID, a.TenantID, a.Version, a.ProposalID,
+		a.SponsorAwardNumber, a.TotalObligated().StringFixed(2), a.TotalAnticipated().StringFixed(2)))
+	return nil
+}
+
+// ModificationInput describes a modification to record.
+type ModificationInput struct {
+	Type             ModificationType `json:"type"`
+	PeriodNumber     int              `json:"period_number,omitempty"` // defaults to the last period
+	Amount           decimal.Decimal  `json:"amount"`
+	NewEndDate       *time.Time       `json:"new_end_date,omitempty"`
+	EffectiveDate    *time.Time       `json:"effective_date,omitempty"` // defaults to now
+	SponsorReference string           `json:"sponsor_reference,omitempty"`
+	Reason           string           `json:"reason,omitempty"`
+}
+
+// Modify records a modification and applies it. Supplements add funds to a
+// period, no-cost extensions move the end of the last period and the
+// project, and de-obligations withdraw obligated funds from a period.
+func (a *Award) Modify(userID uuid.UUID, input ModificationInput) (*AwardModification, error) {
+	if !a.IsSetupComplete() {
+		return nil, fmt.Errorf("%w: modifications are recorded after setup", ErrAwardSetupIncomplete)
+	}
+
+	m := AwardModification{
+		ID:               uuid.New(),
+		Number:           len(a.Modifications) + 1,
+		Type:             input.Type,
+		Amount:           input.Amount,
+		SponsorReference: input.SponsorReference,
+		Reason:           input.Reason,
+		RecordedBy:       userID,
+		RecordedAt:       time.Now().UTC(),
+	}
+	m.EffectiveDate = m.RecordedAt
+	if input.EffectiveDate != nil {
+		m.EffectiveDate = *input.EffectiveDate
+	}
+
+	switch input.Type {
+	case ModificationSupplement, ModificationDeobligation:
+		if !input.Amount.IsPositive() {
+			return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidModification)
+		}
+		if input.NewEndDate != nil {
+			return nil, fmt.Errorf("%w: only no-cost extensions change the end date", ErrInvalidModification)
+		}
+		m.PeriodNumber = input.PeriodNumber
+		if m.PeriodNumber == 0 {
+			m.PeriodNumber = len(a.Periods)
+		}
+		if m.PeriodNumber < 1 || m.PeriodNumber > len(a.Periods) {
+			return nil, fmt.Errorf("%w: no budget period %d", ErrInvalidModification, m.PeriodNumber)
+		}
+
+		p := &a.Periods[m.PeriodNumber-1]
+		if input.Type == ModificationSupplement {
+			p.Obligated = p.Obligated.Add(input.Amount)
+			p.Anticipated = p.Anticipated.Add(input.Amount)
+		} else {
+			if input.Amount.GreaterThan(p.Obligated) {
+				return nil, fmt.Errorf("%w: period %d has only %s obligated", ErrInvalidModification, p.PeriodNumber, p.Obligated.StringFixed(2))
+			}
+			p.Obligated = p.Obligated.Sub(input.Amount)
+			p.Anticipated = p.Anticipated.Sub(input.Amount)
+		}
+
+	case ModificationNoCostExtension:
+		if !input.Amount.IsZero() {
+			return nil, fmt.Errorf("%w: no-cost extensions do not change funding", ErrInvalidModification)
+		}
+		if input.NewEndDate == nil || !input.NewEndDate.After(a.ProjectPeriod.EndDate) {
+			return nil, fmt.Errorf("%w: new end date must be after the current end date", ErrInvalidModification)
+		}
+		m.NewEndDate = input.NewEndDate
+		a.ProjectPeriod.EndDate = *input.NewEndDate
+		if len(a.Periods) > 0 {
+			a.Periods[len(a.Periods)-1].EndDate = *input.NewEndDate
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidModification, input.Type)
+	}
+
+	a.Modifications = append(a.Modifications, m)
+	a.Touch(userID)
+
+	a.AddEvent(common.NewAwardModifiedEvent(m.Type.EventType(), a.ID, a.TenantID, a.Version,
+		m.ID, m.Number, m.PeriodNumber, m.Amount.StringFixed(2), m.NewEndDate, m.EffectiveDate))
+	return &m, nil
+}
+
+// PolicyAttributes returns the attributes policies can match on.
+func (a *Award) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{
+		"type":           "award",
+		"id":             a.ID,
+		"proposal_id":    a.ProposalID,
+		"setup_complete": a.IsSetupComplete(),
+	}
+}
+
+// AuthorizeAward checks an action on an award.
+func AuthorizeAward(ctx context.Context, az authz.Authorizer, tenantCtx common.TenantContext, action string, a *Award) error {
+	return authorize(ctx, az, tenantCtx, authz.Request{Action: action, Resource: a.PolicyAttributes()})
+}
+
+// AwardRepository persists awards.
+type AwardRepository interface {
+	// SaveAward persists an award with its new modifications and
+	// uncommitted events.
+	SaveAward(ctx context.Context, a *Award) error
+
+	// FindAward retrieves an award by ID.
+	FindAward(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*Award, error)
+
+	// FindAwardByProposal retrieves the award of a proposal, or nil if it
+	// has none.
+	FindAwardByProposal(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) (*Award, error)
+}
+
+// HasAward reports whether a proposal in the state has an award: it has
+// been awarded and has not yet moved on to closeout.
+func (s ProposalState) HasAward() bool {
+	return s == StateAwarded || s == StateActive
+}
+
+// OpenAward opens the award of an awarded or active proposal. It is
+// idempotent: it returns the existing award if the proposal already has
+// one, and nil if awards are not tracked or the proposal has no award.
+func OpenAward(ctx context.Context, repo AwardRepository, p *Proposal, b *budget.Budget, userID uuid.UUID) (*Award, error) {
+	if repo == nil || !p.State.HasAward() {
+		return nil, nil
+	}
+
+	existing, err := repo.FindAwardByProposal(ctx, p.TenantID, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load award: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	a := NewAward(p, b, userID)
+	if err := repo.SaveAward(ctx, a); err != nil {
+		return nil, fmt.Errorf("failed to save award: %w", err)
+	}
+	return a, nil
+}
+
+// AwardSetupCompleteGuard blocks a transition until the proposal's award
+// has been set up. An awarded proposal without an award has one opened, so
+// that it can be set up.
+func AwardSetupCompleteGuard(repo AwardRepository) ProposalGuard {
+	return func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error {
+		if payload.Proposal == nil {
+			return errors.New("transition payload has no proposal")
+		}
+
+		a, err := OpenAward(ctx, repo, payload.Proposal, nil, payload.Actor)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return fmt.Errorf("%w: no award has been recorded", ErrAwardSetupIncomplete)
+		}
+		if !a.IsSetupComplete() {
+			issues := a.SetupIssues()
+			if len(issues) == 0 {
+				issues = []string{"setup has not been marked complete"}
+			}
+			return fmt.Errorf("%w: %s", ErrAwardSetupIncomplete, strings.Join(issues, "; "))
+		}
+		return nil
+	}
+}
+
+// RequireAwardSetup prevents activating a proposal until its award has been
+// set up.
+func (psm *ProposalStateMachine) RequireAwardSetup(repo AwardRepository) {
+	psm.AddProposalGuard(TransitionActivate, GuardAwardSetupComplete, AwardSetupCompleteGuard(repo))
+}
@@ -0,0 +1,155 @@
+// Package proposal provides subscribable iCalendar feeds of proposal deadlines.
+package proposal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// ErrInvalidCalendarFeed is returned when a feed's scope or subject is invalid.
+var ErrInvalidCalendarFeed = errors.New("invalid calendar feed")
+
+// ErrCalendarFeedNotFound is returned when a feed does not exist or was revoked.
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// FeedScope selects the proposals a calendar feed covers.
+type FeedScope string
+
+const (
+	// FeedScopeUser covers proposals the user leads, co-leads or created.
+	FeedScopeUser FeedScope = "user"
+	// FeedScopeDepartment covers the proposals of a department.
+	FeedScopeDepartment FeedScope = "department"
+	// FeedScopeTenant covers every proposal of the tenant.
+	FeedScopeTenant FeedScope = "tenant"
+)
+
+// IsValid reports whether the scope is known.
+func (s FeedScope) IsValid() bool {
+	switch s {
+	case FeedScopeUser, FeedScopeDepartment, FeedScopeTenant:
+		return true
+	}
+	return false
+}
+
+// CalendarFeed is a subscription URL for the deadlines of a user, department
+// or tenant. Calendar apps cannot send credentials, so the feed is reached
+// through a secret token that is only stored as a hash; revoking the feed
+// disables the URL.
+type CalendarFeed struct {
+	ID         uuid.UUID       `json:"id"`
+	TenantID   common.TenantID `json:"tenant_id"`
+	Scope      FeedScope       `json:"scope"`
+	UserID     *uuid.UUID      `json:"user_id,omitempty"`    // user feeds
+	Department string          `json:"department,omitempty"` // department feeds
+	CreatedBy  uuid.UUID       `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+	RevokedAt  *time.Time      `json:"revoked_at,omitempty"`
+}
+
+// NewCalendarFeed creates a feed and the token of its URL. The token is
+// returned only here.
+func NewCalendarFeed(tenantID common.TenantID, createdBy uuid.UUID, scope FeedScope, userID *uuid.UUID, department string) (*CalendarFeed, string, error) {
+	feed := &CalendarFeed{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Scope:     scope,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	switch scope {
+	case FeedScopeUser:
+		if userID == nil || *userID == uuid.Nil {
+			return nil, "", fmt.Errorf("%w: user feeds need a user", ErrInvalidCalendarFeed)
+		}
+		feed.UserID = userID
+	case FeedScopeDepartment:
+		if department == "" {
+			return nil, "", fmt.Errorf("%w: department feeds need a department", ErrInvalidCalendarFeed)
+		}
+		feed.Department = department
+	case FeedScopeTenant:
+	default:
+		return nil, "", fmt.Errorf("%w: unknown scope %q", ErrInvalidCalendarFeed, scope)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	return feed, base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashFeedToken returns the stored form of a feed token.
+func HashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PolicyAttributes returns the attributes policies can match on.
+func (f CalendarFeed) PolicyAttributes() map[string]interface{} {
+	attrs := map[string]interface{}{
+		"type":       "calendar_feed",
+		"id":         f.ID,
+		"scope":      string(f.Scope),
+		"department": f.Department,
+		"created_by": f.CreatedBy,
+	}
+	if f.UserID != nil {
+		attrs["user_id"] = *f.UserID
+	}
+	return attrs
+}
+
+// Deadline kinds.
+const (
+	DeadlineSponsor  = "sponsor"
+	DeadlineInternal = "internal"
+)
+
+// DeadlineEntry is one deadline of a proposal in a calendar feed.
+type DeadlineEntry struct {
+	ProposalID     uuid.UUID     `json:"proposal_id"`
+	ProposalNumber string        `json:"proposal_number"`
+	Title          string        `json:"title"`
+	State          ProposalState `json:"state"`
+	Department     string        `json:"department,omitempty"`
+	Kind           string        `json:"kind"` // sponsor or internal
+	At             time.Time     `json:"at"`
+	TimeZone       string        `json:"time_zone,omitempty"` // zone the sponsor quotes its deadline in
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// CalendarFeedRepository persists calendar feeds and loads their deadlines.
+type CalendarFeedRepository interface {
+	// SaveFeed stores a new feed with the hash of its token.
+	SaveFeed(ctx context.Context, feed *CalendarFeed, tokenHash string) error
+
+	// FindFeedByToken retrieves an unrevoked feed by the hash of its token,
+	// in any tenant.
+	FindFeedByToken(ctx context.Context, tokenHash string) (*CalendarFeed, error)
+
+	// FindFeed retrieves a feed by ID.
+	FindFeed(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*CalendarFeed, error)
+
+	// ListFeeds retrieves the unrevoked feeds a user created.
+	ListFeeds(ctx context.Context, tenantID common.TenantID, createdBy uuid.UUID) ([]*CalendarFeed, error)
+
+	// RevokeFeed disables a feed.
+	RevokeFeed(ctx context.Context, tenantID common.TenantID, id uuid.UUID, at time.Time) error
+
+	// FindDeadlines retrieves the sponsor and internal deadlines between two
+	// times of the latest version of every proposal the feed covers.
+	FindDeadlines(ctx context.Context, feed *CalendarFeed, from, to time.Time) ([]DeadlineEntry, error)
+}
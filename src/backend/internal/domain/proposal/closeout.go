@@ -0,0 +1,385 @@
+// Package proposal provides closeout checklists that must be worked off
+// before a proposal can be closed.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// GuardCloseoutComplete is the workflow guard name of the closeout guard.
+const GuardCloseoutComplete = "closeout_complete"
+
+// DefaultCloseoutSponsorType is the sponsor type of the template used for
+// sponsors whose type has no template of its own.
+const DefaultCloseoutSponsorType = "default"
+
+// ErrCloseoutNotFound is returned when a proposal has no closeout checklist.
+var ErrCloseoutNotFound = errors.New("closeout checklist not found")
+
+// ErrCloseoutItemNotFound is returned when a checklist has no such item.
+var ErrCloseoutItemNotFound = errors.New("closeout item not found")
+
+// ErrCloseoutIncomplete is returned when a checklist still has pending items.
+var ErrCloseoutIncomplete = errors.New("closeout checklist is incomplete")
+
+// ErrInvalidCloseoutTemplate is returned when a closeout template is malformed.
+var ErrInvalidCloseoutTemplate = errors.New("invalid closeout template")
+
+// ErrInvalidCloseoutItem is returned when a closeout item cannot be resolved as requested.
+var ErrInvalidCloseoutItem = errors.New("invalid closeout item update")
+
+// CloseoutItemType is the kind of deliverable a closeout item tracks.
+type CloseoutItemType string
+
+const (
+	CloseoutFinancialReport    CloseoutItemType = "financial_report"
+	CloseoutTechnicalReport    CloseoutItemType = "technical_report"
+	CloseoutInventionReport    CloseoutItemType = "invention_report"
+	CloseoutEquipmentInventory CloseoutItemType = "equipment_inventory"
+	CloseoutSubaward           CloseoutItemType = "subaward_closeout"
+	CloseoutOther              CloseoutItemType = "other"
+)
+
+// IsValid reports whether the item type is known.
+func (t CloseoutItemType) IsValid() bool {
+	switch t {
+	case CloseoutFinancialReport, CloseoutTechnicalReport, CloseoutInventionReport,
+		CloseoutEquipmentInventory, CloseoutSubaward, CloseoutOther:
+		return true
+	}
+	return false
+}
+
+// CloseoutItemStatus is the state of a closeout item.
+type CloseoutItemStatus string
+
+const (
+	CloseoutItemPending  CloseoutItemStatus = "PENDING"
+	CloseoutItemComplete CloseoutItemStatus = "COMPLETE"
+	CloseoutItemWaived   CloseoutItemStatus = "WAIVED" // not required by the sponsor for this award
+)
+
+// closeoutSponsorTypes are the sponsor types templates can be defined for.
+var closeoutSponsorTypes = []string{
+	"federal", "state", "private", "foundation", "industry", "nonprofit", "internal", "other",
+	DefaultCloseoutSponsorType,
+}
+
+// CloseoutSponsorTypes returns the sponsor types templates can be defined for.
+func CloseoutSponsorTypes() []string {
+	return append([]string(nil), closeoutSponsorTypes...)
+}
+
+// CloseoutTemplateItem is an item a template adds to new checklists, due a
+// number of days after the project end date.
+type CloseoutTemplateItem struct {
+	Type            CloseoutItemType `json:"type"`
+	Title           string           `json:"title"`
+	Description     string           `json:"description,omitempty"`
+	DueDaysAfterEnd int              `json:"due_days_after_end"`
+}
+
+// CloseoutTemplate lists the closeout items required by sponsors of a type.
+type CloseoutTemplate struct {
+	SponsorType string                 `json:"sponsor_type"`
+	Items       []CloseoutTemplateItem `json:"items"`
+}
+
+// Validate checks the template's sponsor type and items.
+func (t CloseoutTemplate) Validate() error {
+	known := false
+	for _, st := range closeoutSponsorTypes {
+		if t.SponsorType == st {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("%w: unknown sponsor type %q", ErrInvalidCloseoutTemplate, t.SponsorType)
+	}
+	if len(t.Items) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidCloseoutTemplate)
+	}
+	for i, item := range t.Items {
+		if !item.Type.IsValid() {
+			return fmt.Errorf("%w: item %d has unknown type %q", ErrInvalidCloseoutTemplate, i+1, item.Type)
+		}
+		if strings.TrimSpace(item.Title) == "" {
+			return fmt.Errorf("%w: item %d has no title", ErrInvalidCloseoutTemplate, i+1)
+		}
+		if item.DueDaysAfterEnd < 0 || item.DueDaysAfterEnd > 730 {
+			return fmt.Errorf("%w: item %d must be due between 0 and 730 days after the end date", ErrInvalidCloseoutTemplate, i+1)
+		}
+	}
+	return nil
+}
+
+// DefaultCloseoutTemplate returns the built-in template for a sponsor type.
+// Federal awards follow the Uniform Guidance: final reports are due 120 days
+// after the end date, and subrecipients report earlier so the prime can.
+func DefaultCloseoutTemplate(sponsorType string) CloseoutTemplate {
+	financial := CloseoutTemplateItem{Type: CloseoutFinancialReport, Title: "Final financial report", DueDaysAfterEnd: 90}
+	technical := CloseoutTemplateItem{Type: CloseoutTechnicalReport, Title: "Final technical report", DueDaysAfterEnd: 90}
+
+	switch sponsorType {
+	case "federal":
+		return CloseoutTemplate{SponsorType: sponsorType, Items: []CloseoutTemplateItem{
+			{Type: CloseoutFinancialReport, Title: "Final Federal Financial Report (SF-425)", DueDaysAfterEnd: 120},
+			{Type: CloseoutTechnicalReport, Title: "Final research performance progress report", DueDaysAfterEnd: 120},
+			{Type: CloseoutInventionReport, Title: "Final invention statement and certification", DueDaysAfterEnd: 120},
+			{Type: CloseoutEquipmentInventory, Title: "Final equipment inventory", Description: "Equipment purchased with award funds and its disposition", DueDaysAfterEnd: 120},
+			{Type: CloseoutSubaward, Title: "Subaward closeouts", Description: "Final invoices, reports and releases from subrecipients", DueDaysAfterEnd: 60},
+		}}
+	case "industry":
+		return CloseoutTemplate{SponsorType: sponsorType, Items: []CloseoutTemplateItem{
+			{Type: CloseoutTechnicalReport, Title: "Final technical report", DueDaysAfterEnd: 60},
+			{Type: CloseoutInventionReport, Title: "Invention disclosure report", DueDaysAfterEnd: 60},
+			{Type: CloseoutFinancialReport, Title: "Final invoice", DueDaysAfterEnd: 60},
+		}}
+	case "internal":
+		technical.DueDaysAfterEnd = 60
+		return CloseoutTemplate{SponsorType: sponsorType, Items: []CloseoutTemplateItem{technical}}
+	case "state", "private", "foundation", "nonprofit", "other":
+		return CloseoutTemplate{SponsorType: sponsorType, Items: []CloseoutTemplateItem{financial, technical}}
+	}
+	return CloseoutTemplate{SponsorType: DefaultCloseoutSponsorType, Items: []CloseoutTemplateItem{financial, technical}}
+}
+
+// CloseoutItem is a deliverable owed to the sponsor at closeout.
+type CloseoutItem struct {
+	ID          uuid.UUID          `json:"id"`
+	Type        CloseoutItemType   `json:"type"`
+	Title       string             `json:"title"`
+	Description string             `json:"description,omitempty"`
+	DueDate     time.Time          `json:"due_date"`
+	Status      CloseoutItemStatus `json:"status"`
+	Note        string             `json:"note,omitempty"`
+	ResolvedAt  *time.Time         `json:"resolved_at,omitempty"`
+	ResolvedBy  *uuid.UUID         `json:"resolved_by,omitempty"`
+}
+
+// IsOutstanding reports whether the item still has to be done.
+func (i CloseoutItem) IsOutstanding() bool {
+	return i.Status == CloseoutItemPending
+}
+
+// IsOverdue reports whether the item is outstanding after its due date.
+func (i CloseoutItem) IsOverdue(now time.Time) bool {
+	return i.IsOutstanding() && !now.Before(i.DueDate.AddDate(0, 0, 1))
+}
+
+// CloseoutChecklist tracks the deliverables of a proposal in closeout. It is
+// generated from the template for the sponsor's type when closeout begins.
+type CloseoutChecklist struct {
+	common.BaseEntity
+	ProposalID     uuid.UUID      `json:"proposal_id"`
+	SponsorType    string         `json:"sponsor_type"`
+	ProjectEndDate time.Time      `json:"project_end_date"`
+	Items          []CloseoutItem `json:"items"`
+}
+
+// NewCloseoutChecklist generates a proposal's checklist from a template.
+// Due dates count from the project end date, or from today when the
+// proposal has none.
+func NewCloseoutChecklist(p *Proposal, tpl CloseoutTemplate, userID uuid.UUID) *CloseoutChecklist {
+	end := p.ProjectPeriod.EndDate
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+
+	c := &CloseoutChecklist{
+		BaseEntity:     common.NewBaseEntity(p.TenantID, userID),
+		ProposalID:     p.ID,
+		SponsorType:    tpl.SponsorType,
+		ProjectEndDate: end,
+		Items:          make([]CloseoutItem, 0, len(tpl.Items)),
+	}
+	for _, item := range tpl.Items {
+		c.Items = append(c.Items, CloseoutItem{
+			ID:          uuid.New(),
+			Type:        item.Type,
+			Title:       item.Title,
+			Description: item.Description,
+			DueDate:     end.AddDate(0, 0, item.DueDaysAfterEnd),
+			Status:      CloseoutItemPending,
+		})
+	}
+	sort.SliceStable(c.Items, func(i, j int) bool { return c.Items[i].DueDate.Before(c.Items[j].DueDate) })
+	return c
+}
+
+// Outstanding returns the items still to be done.
+func (c *CloseoutChecklist) Outstanding() []CloseoutItem {
+	items := make([]CloseoutItem, 0)
+	for _, item := range c.Items {
+		if item.IsOutstanding() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Overdue returns the outstanding items past their due date.
+func (c *CloseoutChecklist) Overdue(now time.Time) []CloseoutItem {
+	items := make([]CloseoutItem, 0)
+	for _, item := range c.Items {
+		if item.IsOverdue(now) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IsDone reports whether every item is complete or waived.
+func (c *CloseoutChecklist) IsDone() bool {
+	return len(c.Outstanding()) == 0
+}
+
+// ResolveItem sets the status of an item. Waiving an item requires a note
+// explaining why; setting it back to pending reopens it.
+func (c *CloseoutChecklist) ResolveItem(userID, itemID uuid.UUID, status CloseoutItemStatus, note string) (*CloseoutItem, error) {
+	note = strings.TrimSpace(note)
+	switch status {
+	case CloseoutItemPending, CloseoutItemComplete:
+	case CloseoutItemWaived:
+		if note == "" {
+			return nil, fmt.Errorf("%w: a note is required to waive an item", ErrInvalidCloseoutItem)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidCloseoutItem, status)
+	}
+
+	for i := range c.Items {
+		item := &c.Items[i]
+		if item.ID != itemID {
+			continue
+		}
+
+		item.Status = status
+		item.Note = note
+		if status == CloseoutItemPending {
+			item.ResolvedAt = nil
+			item.ResolvedBy = nil
+		} else {
+			now := time.Now().UTC()
+			item.ResolvedAt = &now
+			item.ResolvedBy = &userID
+		}
+		c.Touch(userID)
+		return item, nil
+	}
+	return nil, ErrCloseoutItemNotFound
+}
+
+// OverdueCloseoutItem is an overdue item with the proposal it belongs to.
+type OverdueCloseoutItem struct {
+	ProposalID     uuid.UUID    `json:"proposal_id"`
+	ProposalNumber string       `json:"proposal_number,omitempty"`
+	Title          string       `json:"title"`
+	Item           CloseoutItem `json:"item"`
+	DaysOverdue    int          `json:"days_overdue"`
+}
+
+// CloseoutRepository persists closeout checklists and templates.
+type CloseoutRepository interface {
+	// SaveChecklist stores a checklist and its items.
+	SaveChecklist(ctx context.Context, c *CloseoutChecklist) error
+
+	// FindChecklistByProposal retrieves a proposal's checklist, or nil.
+	FindChecklistByProposal(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) (*CloseoutChecklist, error)
+
+	// ListOverdueCloseoutItems retrieves outstanding items due before asOf.
+	ListOverdueCloseoutItems(ctx context.Context, tenantID common.TenantID, asOf time.Time) ([]OverdueCloseoutItem, error)
+
+	// FindCloseoutTemplate retrieves a tenant's template for a sponsor type, or nil.
+	FindCloseoutTemplate(ctx context.Context, tenantID common.TenantID, sponsorType string) (*CloseoutTemplate, error)
+
+	// ListCloseoutTemplates retrieves a tenant's templates.
+	ListCloseoutTemplates(ctx context.Context, tenantID common.TenantID) ([]CloseoutTemplate, error)
+
+	// SaveCloseoutTemplate stores a tenant's template for its sponsor type.
+	SaveCloseoutTemplate(ctx context.Context, tenantID common.TenantID, tpl CloseoutTemplate, userID uuid.UUID) error
+
+	// DeleteCloseoutTemplate removes a tenant's template for a sponsor type.
+	DeleteCloseoutTemplate(ctx context.Context, tenantID common.TenantID, sponsorType string) error
+}
+
+// OpenCloseout generates the closeout checklist of a proposal in closeout,
+// from the tenant's template for the sponsor type or the built-in one. It is
+// idempotent: it returns the existing checklist if there is one, and nil if
+// the proposal is not in closeout.
+func OpenCloseout(ctx context.Context, repo CloseoutRepository, p *Proposal, sponsorType string, userID uuid.UUID) (*CloseoutChecklist, error) {
+	if repo == nil || p.State != StateCloseout {
+		return nil, nil
+	}
+
+	existing, err := repo.FindChecklistByProposal(ctx, p.TenantID, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load closeout checklist: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	tpl, err := repo.FindCloseoutTemplate(ctx, p.TenantID, sponsorType)
+	if err == nil && tpl == nil {
+		tpl, err = repo.FindCloseoutTemplate(ctx, p.TenantID, DefaultCloseoutSponsorType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load closeout template: %w", err)
+	}
+	if tpl == nil {
+		builtin := DefaultCloseoutTemplate(sponsorType)
+		tpl = &builtin
+	}
+
+	c := NewCloseoutChecklist(p, *tpl, userID)
+	if err := repo.SaveChecklist(ctx, c); err != nil {
+		return nil, fmt.Errorf("failed to save closeout checklist: %w", err)
+	}
+	return c, nil
+}
+
+// CloseoutCompleteGuard blocks a transition until every item of the
+// proposal's closeout checklist is complete or waived. A proposal in
+// closeout without a checklist has the tenant's default one generated.
+func CloseoutCompleteGuard(repo CloseoutRepository) ProposalGuard {
+	return func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error {
+		if payload.Proposal == nil {
+			return errors.New("transition payload has no proposal")
+		}
+
+		c, err := OpenCloseout(ctx, repo, payload.Proposal, DefaultCloseoutSponsorType, payload.Actor)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return fmt.Errorf("%w: no closeout checklist has been generated", ErrCloseoutIncomplete)
+		}
+
+		outstanding := c.Outstanding()
+		if len(outstanding) == 0 {
+			return nil
+		}
+		titles := make([]string, len(outstanding))
+		for i, item := range outstanding {
+			titles[i] = fmt.Sprintf("%s (due %s)", item.Title, item.DueDate.Format("2006-01-02"))
+		}
+		return fmt.Errorf("%w: %s", ErrCloseoutIncomplete, strings.Join(titles, "; "))
+	}
+}
+
+// RequireCloseout prevents closing a proposal until its closeout checklist
+// is done.
+func (psm *ProposalStateMachine) RequireCloseout(repo CloseoutRepository) {
+	psm.AddProposalGuard(TransitionClose, GuardCloseoutComplete, CloseoutCompleteGuard(repo))
+}
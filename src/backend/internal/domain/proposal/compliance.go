@@ -0,0 +1,50 @@
+// Package proposal provides the compliance guard for the proposal workflow.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// GuardComplianceComplete is the workflow guard name of the compliance guard.
+const GuardComplianceComplete = "compliance_complete"
+
+// ErrComplianceIncomplete is returned when required compliance items are outstanding.
+var ErrComplianceIncomplete = errors.New("compliance requirements are incomplete")
+
+// ComplianceChecker reports outstanding compliance requirements of a proposal.
+type ComplianceChecker interface {
+	// IncompleteRequirements returns the codes of required compliance items
+	// that are not yet complete.
+	IncompleteRequirements(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) ([]string, error)
+}
+
+// ComplianceCompleteGuard blocks a transition while any required compliance
+// item of the proposal is incomplete.
+func ComplianceCompleteGuard(checker ComplianceChecker) ProposalGuard {
+	return func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error {
+		if payload.Proposal == nil {
+			return errors.New("transition payload has no proposal")
+		}
+
+		codes, err := checker.IncompleteRequirements(ctx, payload.Proposal.TenantID, payload.Proposal.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check compliance: %w", err)
+		}
+		if len(codes) > 0 {
+			return fmt.Errorf("%w: %s", ErrComplianceIncomplete, strings.Join(codes, ", "))
+		}
+		return nil
+	}
+}
+
+// RequireCompleteCompliance prevents advancing out of COMPLIANCE_REVIEW until
+// every required compliance item is complete. Other review states are not affected.
+func (psm *ProposalStateMachine) RequireCompleteCompliance(checker ComplianceChecker) {
+	psm.AddStateGuard(StateCompliance, TransitionAdvanceReview, GuardComplianceComplete, ComplianceCompleteGuard(checker).Func())
+}
@@ -0,0 +1,47 @@
+// Package proposal provides the cost share match guard for the proposal workflow.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// GuardCostShareMatch is the workflow guard name of the cost share match guard.
+const GuardCostShareMatch = "cost_share_match"
+
+// BudgetFinder loads the budget of a proposal.
+type BudgetFinder interface {
+	// FindByProposalID retrieves the current budget of a proposal, or nil.
+	FindByProposalID(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) (*budget.Budget, error)
+}
+
+// CostShareMatchGuard blocks a transition while the mandatory and
+// third-party in-kind cost share of the proposal's budget fall short of the
+// sponsor's required match. Proposals without a budget pass.
+func CostShareMatchGuard(budgets BudgetFinder) ProposalGuard {
+	return func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error {
+		if payload.Proposal == nil {
+			return errors.New("transition payload has no proposal")
+		}
+
+		b, err := budgets.FindByProposalID(ctx, payload.Proposal.TenantID, payload.Proposal.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load budget: %w", err)
+		}
+		if b == nil {
+			return nil
+		}
+		return b.CheckMatch()
+	}
+}
+
+// RequireCostShareMatch prevents advancing out of BUDGET_REVIEW until the
+// budget's cost share meets the sponsor's required match.
+func (psm *ProposalStateMachine) RequireCostShareMatch(budgets BudgetFinder) {
+	psm.AddStateGuard(StateBudgetReview, TransitionAdvanceReview, GuardCostShareMatch, CostShareMatchGuard(budgets).Func())
+}
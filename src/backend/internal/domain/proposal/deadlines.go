@@ -0,0 +1,231 @@
+// Package proposal provides internal deadline rules and holiday calendars.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// ErrInvalidDeadlineRule is returned when a deadline rule cannot be applied.
+var ErrInvalidDeadlineRule = errors.New("invalid deadline rule")
+
+// ErrInvalidTimeZone is returned when a time zone is not a known IANA zone.
+var ErrInvalidTimeZone = errors.New("invalid time zone")
+
+// ErrInvalidHoliday is returned when a holiday has no valid date or name.
+var ErrInvalidHoliday = errors.New("invalid holiday")
+
+// ErrHolidayNotFound is returned when a tenant has no holiday on a date.
+var ErrHolidayNotFound = errors.New("holiday not found")
+
+// ErrInvalidDeadline is returned when a deadline cannot be parsed.
+var ErrInvalidDeadline = errors.New("invalid deadline")
+
+// DefaultSponsorDeadlineTime is the time of day of sponsor deadlines given
+// as a date only; most sponsors close at 5 PM local time.
+const DefaultSponsorDeadlineTime = "17:00"
+
+// maxBusinessDaysBefore bounds how far ahead of the sponsor an internal
+// deadline may be set.
+const maxBusinessDaysBefore = 60
+
+// Layouts of dates, times of day and local deadlines.
+const (
+	dateLayout          = "2006-01-02"
+	timeOfDayLayout     = "15:04"
+	localDeadlineLayout = "2006-01-02T15:04"
+)
+
+// LoadTimeZone loads an IANA time zone; an empty name is UTC.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	return loc, nil
+}
+
+// ParseDeadline parses a deadline. RFC3339 values carry their own offset.
+// Local values, "2006-01-02T15:04" or a date that falls at defaultTime, are
+// read in the time zone, so a sponsor's "5 PM Eastern" can be entered as
+// quoted.
+func ParseDeadline(value, timeZone, defaultTime string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	loc, err := LoadTimeZone(timeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, err := time.ParseInLocation(localDeadlineLayout, value, loc); err == nil {
+		return t.UTC(), nil
+	}
+	if defaultTime == "" {
+		defaultTime = DefaultSponsorDeadlineTime
+	}
+	if t, err := time.ParseInLocation(localDeadlineLayout, value+"T"+defaultTime, loc); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %q is neither RFC3339, YYYY-MM-DDTHH:MM nor YYYY-MM-DD", ErrInvalidDeadline, value)
+}
+
+// Holiday is an institutional holiday on which business days are not counted.
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+// Validate checks the holiday's date and name.
+func (h Holiday) Validate() error {
+	if _, err := time.Parse(dateLayout, h.Date); err != nil {
+		return fmt.Errorf("%w: date %q must be YYYY-MM-DD", ErrInvalidHoliday, h.Date)
+	}
+	if strings.TrimSpace(h.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidHoliday)
+	}
+	return nil
+}
+
+// HolidayCalendar maps holiday dates (YYYY-MM-DD) to their names.
+type HolidayCalendar map[string]string
+
+// NewHolidayCalendar creates a calendar of the holidays.
+func NewHolidayCalendar(holidays []Holiday) HolidayCalendar {
+	c := make(HolidayCalendar, len(holidays))
+	for _, h := range holidays {
+		c[h.Date] = h.Name
+	}
+	return c
+}
+
+// IsBusinessDay reports whether the calendar date of day is a weekday that
+// is not a holiday.
+func (c HolidayCalendar) IsBusinessDay(day time.Time) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c[day.Format(dateLayout)]
+	return !holiday
+}
+
+// DeadlineRule derives a proposal's internal deadline from its sponsor
+// deadline, e.g. five business days before, skipping institutional holidays.
+type DeadlineRule struct {
+	BusinessDaysBefore int    `json:"business_days_before"`
+	InternalTime       string `json:"internal_time,omitempty"` // HH:MM; empty keeps the sponsor deadline's time of day
+	TimeZone           string `json:"time_zone"`               // institution's IANA zone, in which days are counted
+	SponsorTime        string `json:"sponsor_time,omitempty"`  // HH:MM of sponsor deadlines given as a date
+}
+
+// Validate checks that the rule can be applied.
+func (r DeadlineRule) Validate() error {
+	if r.BusinessDaysBefore < 0 || r.BusinessDaysBefore > maxBusinessDaysBefore {
+		return fmt.Errorf("%w: business days before must be between 0 and %d", ErrInvalidDeadlineRule, maxBusinessDaysBefore)
+	}
+	if _, err := LoadTimeZone(r.TimeZone); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDeadlineRule, err)
+	}
+	for _, t := range []string{r.InternalTime, r.SponsorTime} {
+		if _, err := time.Parse(timeOfDayLayout, t); t != "" && err != nil {
+			return fmt.Errorf("%w: time of day %q must be HH:MM", ErrInvalidDeadlineRule, t)
+		}
+	}
+	return nil
+}
+
+// SponsorTimeOfDay returns the time of day of sponsor deadlines given as a date.
+func (r DeadlineRule) SponsorTimeOfDay() string {
+	if r.SponsorTime == "" {
+		return DefaultSponsorDeadlineTime
+	}
+	return r.SponsorTime
+}
+
+// lookbackDays returns how many calendar days before a sponsor deadline
+// holidays may affect the rule.
+func (r DeadlineRule) lookbackDays() int {
+	// Two weekend days per five business days, plus a margin for holidays
+	return r.BusinessDaysBefore*7/5 + 21
+}
+
+// InternalDeadline counts back the rule's business days from the sponsor
+// deadline in the institution's time zone, skipping weekends and holidays.
+func (r DeadlineRule) InternalDeadline(sponsor time.Time, holidays HolidayCalendar) (time.Time, error) {
+	loc, err := LoadTimeZone(r.TimeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidDeadlineRule, err)
+	}
+
+	local := sponsor.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	for remaining := r.BusinessDaysBefore; remaining > 0; {
+		day = day.AddDate(0, 0, -1)
+		if holidays.IsBusinessDay(day) {
+			remaining--
+		}
+	}
+
+	hour, minute := local.Hour(), local.Minute()
+	if r.InternalTime != "" {
+		t, err := time.Parse(timeOfDayLayout, r.InternalTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: time of day %q must be HH:MM", ErrInvalidDeadlineRule, r.InternalTime)
+		}
+		hour, minute = t.Hour(), t.Minute()
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc).UTC(), nil
+}
+
+// DeadlineCalendarRepository persists tenant deadline rules and holidays.
+type DeadlineCalendarRepository interface {
+	// FindDeadlineRule retrieves a tenant's rule, or nil if internal
+	// deadlines are entered by hand.
+	FindDeadlineRule(ctx context.Context, tenantID common.TenantID) (*DeadlineRule, error)
+
+	// SaveDeadlineRule stores a tenant's rule.
+	SaveDeadlineRule(ctx context.Context, tenantID common.TenantID, rule DeadlineRule) error
+
+	// DeleteDeadlineRule removes a tenant's rule.
+	DeleteDeadlineRule(ctx context.Context, tenantID common.TenantID) error
+
+	// ListHolidays retrieves a tenant's holidays between two dates, inclusive.
+	ListHolidays(ctx context.Context, tenantID common.TenantID, from, to time.Time) ([]Holiday, error)
+
+	// SaveHoliday adds or renames a tenant holiday.
+	SaveHoliday(ctx context.Context, tenantID common.TenantID, holiday Holiday) error
+
+	// DeleteHoliday removes a tenant holiday.
+	DeleteHoliday(ctx context.Context, tenantID common.TenantID, date string) error
+}
+
+// DeriveInternalDeadline applies the tenant's deadline rule to a sponsor
+// deadline. It returns nil if the tenant has no rule.
+func DeriveInternalDeadline(ctx context.Context, repo DeadlineCalendarRepository, tenantID common.TenantID, sponsor time.Time) (*time.Time, error) {
+	rule, err := repo.FindDeadlineRule(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load deadline rule: %w", err)
+	}
+	if rule == nil {
+		return nil, nil
+	}
+
+	holidays, err := repo.ListHolidays(ctx, tenantID, sponsor.AddDate(0, 0, -rule.lookbackDays()), sponsor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load holidays: %w", err)
+	}
+
+	internal, err := rule.InternalDeadline(sponsor, NewHolidayCalendar(holidays))
+	if err != nil {
+		return nil, err
+	}
+	return &internal, nil
+}
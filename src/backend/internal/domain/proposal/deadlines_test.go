@@ -0,0 +1,143 @@
+package proposal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func utc(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestInternalDeadline(t *testing.T) {
+	thanksgiving := NewHolidayCalendar([]Holiday{
+		{Date: "2026-11-26", Name: "Thanksgiving"},
+		{Date: "2026-11-27", Name: "Day after Thanksgiving"},
+	})
+
+	tests := []struct {
+		name     string
+		rule     DeadlineRule
+		sponsor  time.Time
+		holidays HolidayCalendar
+		want     time.Time
+	}{
+		{
+			name:    "same day keeps the sponsor time",
+			rule:    DeadlineRule{TimeZone: "America/New_York"},
+			sponsor: utc("2026-10-16T21:00:00Z"),
+			want:    utc("2026-10-16T21:00:00Z"),
+		},
+		{
+			name:    "weekends are skipped",
+			rule:    DeadlineRule{BusinessDaysBefore: 2, TimeZone: "UTC"},
+			sponsor: utc("2026-10-20T17:00:00Z"), // Tuesday
+			want:    utc("2026-10-16T17:00:00Z"), // Friday
+		},
+		{
+			name:     "holidays are skipped",
+			rule:     DeadlineRule{BusinessDaysBefore: 3, InternalTime: "12:00", TimeZone: "America/New_York"},
+			sponsor:  utc("2026-11-30T22:00:00Z"), // Monday 5 PM EST
+			holidays: thanksgiving,
+			want:     utc("2026-11-23T17:00:00Z"), // Monday noon EST
+		},
+		{
+			name:    "local time of day is kept across the start of DST",
+			rule:    DeadlineRule{BusinessDaysBefore: 5, TimeZone: "America/New_York"},
+			sponsor: utc("2026-03-10T21:00:00Z"), // 5 PM EDT
+			want:    utc("2026-03-03T22:00:00Z"), // 5 PM EST
+		},
+		{
+			name:    "local time of day is kept across the end of DST",
+			rule:    DeadlineRule{BusinessDaysBefore: 1, InternalTime: "09:00", TimeZone: "Europe/London"},
+			sponsor: utc("2026-10-26T17:00:00Z"), // Monday 5 PM GMT
+			want:    utc("2026-10-23T08:00:00Z"), // Friday 9 AM BST
+		},
+		{
+			name:    "days are counted in the institution's zone",
+			rule:    DeadlineRule{BusinessDaysBefore: 1, TimeZone: "America/New_York"},
+			sponsor: utc("2026-06-02T02:00:00Z"), // Monday 10 PM EDT
+			want:    utc("2026-05-30T02:00:00Z"), // Friday 10 PM EDT
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.InternalDeadline(tt.sponsor, tt.holidays)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestDeadlineRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    DeadlineRule
+		wantErr bool
+	}{
+		{name: "valid", rule: DeadlineRule{BusinessDaysBefore: 5, InternalTime: "12:00", TimeZone: "America/Chicago", SponsorTime: "17:00"}},
+		{name: "empty zone is UTC", rule: DeadlineRule{BusinessDaysBefore: maxBusinessDaysBefore}},
+		{name: "negative days", rule: DeadlineRule{BusinessDaysBefore: -1}, wantErr: true},
+		{name: "too many days", rule: DeadlineRule{BusinessDaysBefore: maxBusinessDaysBefore + 1}, wantErr: true},
+		{name: "unknown zone", rule: DeadlineRule{TimeZone: "Mars/Olympus"}, wantErr: true},
+		{name: "server local zone", rule: DeadlineRule{TimeZone: "Local"}, wantErr: true},
+		{name: "bad internal time", rule: DeadlineRule{InternalTime: "25:00"}, wantErr: true},
+		{name: "bad sponsor time", rule: DeadlineRule{SponsorTime: "5pm"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr != errors.Is(err, ErrInvalidDeadlineRule) {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDeadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		timeZone    string
+		defaultTime string
+		want        time.Time
+		wantErr     error
+	}{
+		{name: "RFC3339 keeps its offset", value: "2026-03-10T17:00:00-04:00", timeZone: "Europe/London", want: utc("2026-03-10T21:00:00Z")},
+		{name: "local time in daylight time", value: "2026-03-10T17:00", timeZone: "America/New_York", want: utc("2026-03-10T21:00:00Z")},
+		{name: "local time in standard time", value: "2026-03-06T17:00", timeZone: "America/New_York", want: utc("2026-03-06T22:00:00Z")},
+		{name: "date falls at 5 PM by default", value: "2026-01-15", timeZone: "America/New_York", want: utc("2026-01-15T22:00:00Z")},
+		{name: "date falls at the given time", value: "2026-07-01", timeZone: "Europe/London", defaultTime: "09:00", want: utc("2026-07-01T08:00:00Z")},
+		{name: "unparseable", value: "next Friday", timeZone: "UTC", wantErr: ErrInvalidDeadline},
+		{name: "unknown zone", value: "2026-07-01", timeZone: "Nowhere/Town", wantErr: ErrInvalidTimeZone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDeadline(tt.value, tt.timeZone, tt.defaultTime)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package proposal provides role delegations and transition authorization.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// ErrInvalidDelegation is returned when a delegation is malformed.
+var ErrInvalidDelegation = errors.New("invalid delegation")
+
+// ErrDelegationNotFound is returned when a delegation does not exist.
+var ErrDelegationNotFound = errors.New("delegation not found")
+
+// Delegation lends one of a user's roles to another user for a period,
+// optionally limited to a department (e.g. while the delegator is out of office).
+type Delegation struct {
+	ID          uuid.UUID       `json:"id"`
+	TenantID    common.TenantID `json:"tenant_id"`
+	DelegatorID uuid.UUID       `json:"delegator_id"`
+	DelegateID  uuid.UUID       `json:"delegate_id"`
+	Role        string          `json:"role"`
+	Department  string          `json:"department,omitempty"` // empty for every department
+	StartsAt    time.Time       `json:"starts_at"`
+	EndsAt      time.Time       `json:"ends_at"`
+	Reason      string          `json:"reason,omitempty"`
+	CreatedBy   uuid.UUID       `json:"created_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+	RevokedAt   *time.Time      `json:"revoked_at,omitempty"`
+}
+
+// NewDelegation creates a delegation of a role from delegator to delegate.
+func NewDelegation(tenantID common.TenantID, createdBy, delegatorID, delegateID uuid.UUID, role, department string, startsAt, endsAt time.Time, reason string) (*Delegation, error) {
+	if role == "" {
+		return nil, fmt.Errorf("%w: role is required", ErrInvalidDelegation)
+	}
+	if delegatorID == uuid.Nil || delegateID == uuid.Nil {
+		return nil, fmt.Errorf("%w: delegator and delegate are required", ErrInvalidDelegation)
+	}
+	if delegatorID == delegateID {
+		return nil, fmt.Errorf("%w: a user cannot delegate to themselves", ErrInvalidDelegation)
+	}
+	if !endsAt.After(startsAt) {
+		return nil, fmt.Errorf("%w: end must be after start", ErrInvalidDelegation)
+	}
+
+	return &Delegation{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		DelegatorID: delegatorID,
+		DelegateID:  delegateID,
+		Role:        role,
+		Department:  department,
+		StartsAt:    startsAt.UTC(),
+		EndsAt:      endsAt.UTC(),
+		Reason:      reason,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// ActiveAt reports whether the delegation is in force at the given time.
+func (d Delegation) ActiveAt(t time.Time) bool {
+	if d.RevokedAt != nil && !t.Before(*d.RevokedAt) {
+		return false
+	}
+	return !t.Before(d.StartsAt) && t.Before(d.EndsAt)
+}
+
+// Covers reports whether the delegation grants a role for a department at the given time.
+func (d Delegation) Covers(role, department string, at time.Time) bool {
+	if d.Role != role || !d.ActiveAt(at) {
+		return false
+	}
+	return d.Department == "" || d.Department == department
+}
+
+// Revoke ends the delegation early.
+func (d *Delegation) Revoke(at time.Time) {
+	at = at.UTC()
+	d.RevokedAt = &at
+}
+
+// DelegationRepository persists delegations.
+type DelegationRepository interface {
+	// SaveDelegation persists a delegation (insert or update).
+	SaveDelegation(ctx context.Context, delegation *Delegation) error
+
+	// FindDelegation retrieves a delegation by ID.
+	FindDelegation(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*Delegation, error)
+
+	// FindActiveDelegations retrieves the delegations to a user in force at the given time.
+	FindActiveDelegations(ctx context.Context, tenantID common.TenantID, delegateID uuid.UUID, at time.Time) ([]Delegation, error)
+
+	// ListDelegations retrieves the delegations a user granted or received.
+	ListDelegations(ctx context.Context, tenantID common.TenantID, userID uuid.UUID) ([]Delegation, error)
+}
+
+// Authority describes on whose authority an actor performs an action.
+type Authority struct {
+	// Role is the role that permits the action; empty for the PI's own withdrawal.
+	Role string `json:"role,omitempty"`
+
+	// OnBehalfOf is the delegator when the role is held through a delegation.
+	OnBehalfOf *uuid.UUID `json:"on_behalf_of,omitempty"`
+
+	// DelegationID is the delegation that granted the role.
+	DelegationID *uuid.UUID `json:"delegation_id,omitempty"`
+}
+
+// AuthorizeTransition asks the policy engine whether the actor may perform a
+// transition from the proposal's current state. Roles delegated to the actor
+// for the proposal's department are offered to policies as delegated roles;
+// when only a delegated role satisfies the state, the delegator is reported
+// as the principal.
+func AuthorizeTransition(ctx context.Context, az authz.Authorizer, tenantCtx common.TenantContext, p *Proposal, transition ProposalTransition, metadata StateMetadata, delegations []Delegation, at time.Time) (Authority, error) {
+	delegated := DelegatedRoles(delegations, tenantCtx.UserID, p.Department, at)
+	if err := authorize(ctx, az, tenantCtx, TransitionRequest(p, transition, metadata, delegated)); err != nil {
+		return Authority{}, err
+	}
+
+	for _, role := range metadata.RequiredRoles {
+		if tenantCtx.HasRole(role) {
+			return Authority{Role: role}, nil
+		}
+	}
+	for _, role := range metadata.RequiredRoles {
+		if d := FindDelegation(delegations, tenantCtx.UserID, role, p.Department, at); d != nil {
+			return Authority{Role: role, OnBehalfOf: &d.DelegatorID, DelegationID: &d.ID}, nil
+		}
+	}
+
+	// Allowed by a policy other than a required role, e.g. the PI withdrawing
+	return Authority{}, nil
+}
+
+// DelegatedRoles returns the roles delegated to a user for a department at the given time.
+func DelegatedRoles(delegations []Delegation, delegateID uuid.UUID, department string, at time.Time) []string {
+	var roles []string
+	for _, d := range delegations {
+		if d.DelegateID == delegateID && d.Covers(d.Role, department, at) && !containsString(roles, d.Role) {
+			roles = append(roles, d.Role)
+		}
+	}
+	return roles
+}
+
+// FindDelegation returns the delegation granting a role to a user for a
+// department at the given time, or nil.
+func FindDelegation(delegations []Delegation, delegateID uuid.UUID, role, department string, at time.Time) *Delegation {
+	for i := range delegations {
+		if delegations[i].DelegateID == delegateID && delegations[i].Covers(role, department, at) {
+			return &delegations[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+// Package proposal provides field-level edit permissions per workflow state.
+package proposal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+)
+
+// ErrPriorApprovalRequired is returned when a change that needs the
+// sponsor's prior approval does not cite one.
+var ErrPriorApprovalRequired = errors.New("change requires a prior approval reference")
+
+// Field names a proposal field that can be changed through ProposalUpdates.
+type Field string
+
+const (
+	FieldTitle              Field = "title"
+	FieldShortTitle         Field = "short_title"
+	FieldAbstract           Field = "abstract"
+	FieldResearchArea       Field = "research_area"
+	FieldKeywords           Field = "keywords"
+	FieldSponsorDeadline    Field = "sponsor_deadline"
+	FieldInternalDeadline   Field = "internal_deadline"
+	FieldIRBRequired        Field = "irb_required"
+	FieldIACUCRequired      Field = "iacuc_required"
+	FieldIBCRequired        Field = "ibc_required"
+	FieldExportControl      Field = "export_control"
+	FieldConflictOfInterest Field = "conflict_of_interest"
+	FieldExternalID         Field = "external_id"
+	FieldKeyPersonnel       Field = "key_personnel"
+)
+
+// AllFields returns every editable field in display order.
+func AllFields() []Field {
+	return []Field{
+		FieldTitle, FieldShortTitle, FieldAbstract, FieldResearchArea, FieldKeywords,
+		FieldSponsorDeadline, FieldInternalDeadline,
+		FieldIRBRequired, FieldIACUCRequired, FieldIBCRequired, FieldExportControl, FieldConflictOfInterest,
+		FieldExternalID, FieldKeyPersonnel,
+	}
+}
+
+// IsValid reports whether the field is known.
+func (f Field) IsValid() bool {
+	for _, known := range AllFields() {
+		if f == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyRole matches every user in an edit rule.
+const AnyRole = "*"
+
+// EditRule lets holders of any of Roles change Fields. Changes granted only
+// by a PriorApproval rule must cite the sponsor's prior approval.
+type EditRule struct {
+	Roles         []string `json:"roles"`
+	Fields        []Field  `json:"fields"`
+	PriorApproval bool     `json:"prior_approval,omitempty"`
+}
+
+// matches reports whether the rule applies to a user with the given roles.
+func (r EditRule) matches(roles []string) bool {
+	for _, want := range r.Roles {
+		if want == AnyRole || containsString(roles, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// EditMatrix lists the edit rules of each state. States without rules are frozen.
+type EditMatrix map[ProposalState][]EditRule
+
+// DefaultEditMatrix returns the edit rules of the built-in workflow. Anyone
+// may edit a proposal being prepared or revised; afterwards OSP may correct
+// deadlines and the sponsor's ID, compliance may correct the compliance
+// flags, and after award key personnel changes require prior approval while
+// the scientific content stays frozen.
+func DefaultEditMatrix() EditMatrix {
+	everyone := []EditRule{{Roles: []string{AnyRole}, Fields: AllFields()}}
+	osp := EditRule{
+		Roles:  []string{"OSP_OFFICER", "OSP_DIRECTOR"},
+		Fields: []Field{FieldSponsorDeadline, FieldInternalDeadline, FieldExternalID},
+	}
+	sponsorID := EditRule{
+		Roles:  []string{"OSP_OFFICER", "OSP_DIRECTOR", "GRANTS_ADMIN"},
+		Fields: []Field{FieldExternalID},
+	}
+	keyPersonnel := EditRule{
+		Roles:         []string{"OSP_OFFICER", "GRANTS_ADMIN"},
+		Fields:        []Field{FieldKeyPersonnel},
+		PriorApproval: true,
+	}
+	compliance := EditRule{
+		Roles:  []string{"COMPLIANCE_OFFICER"},
+		Fields: []Field{FieldIRBRequired, FieldIACUCRequired, FieldIBCRequired, FieldExportControl, FieldConflictOfInterest},
+	}
+
+	return EditMatrix{
+		StateDraft:           everyone,
+		StateInProgress:      everyone,
+		StateRevisions:       everyone,
+		StateOSPReview:       {osp},
+		StateCompliance:      {osp, compliance},
+		StateBudgetReview:    {osp},
+		StatePendingApproval: {osp},
+		StateApproved:        {osp},
+		StateReadyToSubmit:   {osp},
+		StateSubmitted:       {sponsorID},
+		StateUnderReview:     {sponsorID},
+		StateNegotiation:     {sponsorID},
+		StateAwarded:         {sponsorID, keyPersonnel},
+		StateActive:          {sponsorID, keyPersonnel},
+	}
+}
+
+// editMatrixFromDefinition reads the edit rules of a workflow document. A
+// document that declares no rules at all predates them and gets the defaults.
+func editMatrixFromDefinition(def *statemachine.Definition) (EditMatrix, error) {
+	matrix := make(EditMatrix)
+	for _, s := range def.States {
+		for _, e := range s.Edits {
+			rule := EditRule{Roles: e.Roles, PriorApproval: e.PriorApproval}
+			for _, name := range e.Fields {
+				f := Field(name)
+				if !f.IsValid() {
+					return nil, fmt.Errorf("%w: edit rule of %s names unknown field %q", statemachine.ErrInvalidDefinition, s.Name, name)
+				}
+				rule.Fields = append(rule.Fields, f)
+			}
+			state := ProposalState(s.Name)
+			matrix[state] = append(matrix[state], rule)
+		}
+	}
+	if len(matrix) == 0 {
+		return DefaultEditMatrix(), nil
+	}
+	return matrix, nil
+}
+
+// editDefinitions converts the rules of a state for a workflow document.
+func (m EditMatrix) editDefinitions(state ProposalState) []statemachine.EditDefinition {
+	var defs []statemachine.EditDefinition
+	for _, r := range m[state] {
+		d := statemachine.EditDefinition{Roles: r.Roles, PriorApproval: r.PriorApproval}
+		for _, f := range r.Fields {
+			d.Fields = append(d.Fields, string(f))
+		}
+		defs = append(defs, d)
+	}
+	return defs
+}
+
+// FieldPermission is a field a user may change.
+type FieldPermission struct {
+	Field         Field `json:"field"`
+	PriorApproval bool  `json:"prior_approval,omitempty"`
+}
+
+// Permissions returns the fields users with the given roles may change in
+// any of the states, in display order. A field granted both with and without
+// prior approval does not need it.
+func (m EditMatrix) Permissions(states []ProposalState, roles []string) []FieldPermission {
+	granted := make(map[Field]bool) // field -> needs prior approval
+	for _, state := range states {
+		for _, r := range m[state] {
+			if !r.matches(roles) {
+				continue
+			}
+			for _, f := range r.Fields {
+				needs, seen := granted[f]
+				granted[f] = r.PriorApproval && (!seen || needs)
+			}
+		}
+	}
+
+	perms := make([]FieldPermission, 0, len(granted))
+	for _, f := range AllFields() {
+		if needs, ok := granted[f]; ok {
+			perms = append(perms, FieldPermission{Field: f, PriorApproval: needs})
+		}
+	}
+	return perms
+}
+
+// FieldEditError is returned when an update changes fields the user may not
+// change in the proposal's current state.
+type FieldEditError struct {
+	State  ProposalState `json:"state"`
+	Fields []Field       `json:"fields"`
+}
+
+// Error implements the error interface.
+func (e *FieldEditError) Error() string {
+	names := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		names[i] = string(f)
+	}
+	return fmt.Sprintf("%s: %s cannot be changed in %s", ErrProposalNotEditable, strings.Join(names, ", "), e.State)
+}
+
+// Unwrap lets errors.Is match ErrProposalNotEditable.
+func (e *FieldEditError) Unwrap() error {
+	return ErrProposalNotEditable
+}
+
+// EditableFields returns the fields users with the given roles may change in
+// the proposal's current state, including the states of active regions.
+func (p *Proposal) EditableFields(matrix EditMatrix, roles []string) []FieldPermission {
+	states := []ProposalState{p.State}
+	for _, sub := range p.SubStates {
+		states = append(states, sub)
+	}
+	return matrix.Permissions(states, roles)
+}
+
+// Fields returns the fields the updates change.
+func (u ProposalUpdates) Fields() []Field {
+	set := map[Field]bool{
+		FieldTitle:              u.Title != nil,
+		FieldShortTitle:         u.ShortTitle != nil,
+		FieldAbstract:           u.Abstract != nil,
+		FieldResearchArea:       u.ResearchArea != nil,
+		FieldKeywords:           u.Keywords != nil,
+		FieldSponsorDeadline:    u.SponsorDeadline != nil || u.DeadlineTimeZone != nil,
+		FieldInternalDeadline:   u.InternalDeadline != nil,
+		FieldIRBRequired:        u.IRBRequired != nil,
+		FieldIACUCRequired:      u.IACUCRequired != nil,
+		FieldIBCRequired:        u.IBCRequired != nil,
+		FieldExportControl:      u.ExportControl != nil,
+		FieldConflictOfInterest: u.ConflictOfInterest != nil,
+		FieldExternalID:         u.ExternalID != nil,
+		FieldKeyPersonnel:       u.KeyPersonnel != nil,
+	}
+
+	var fields []Field
+	for _, f := range AllFields() {
+		if set[f] {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// UpdateAs applies updates on behalf of a user with the given roles,
+// enforcing the edit matrix for the proposal's current state.
+func (p *Proposal) UpdateAs(matrix EditMatrix, userID uuid.UUID, roles []string, updates ProposalUpdates) error {
+	perms := p.EditableFields(matrix, roles)
+	if len(perms) == 0 {
+		return ErrProposalNotEditable
+	}
+
+	allowed := make(map[Field]FieldPermission, len(perms))
+	for _, perm := range perms {
+		allowed[perm.Field] = perm
+	}
+
+	var denied, needsApproval []Field
+	for _, f := range updates.Fields() {
+		perm, ok := allowed[f]
+		switch {
+		case !ok:
+			denied = append(denied, f)
+		case perm.PriorApproval && strings.TrimSpace(updates.PriorApproval) == "":
+			needsApproval = append(needsApproval, f)
+		}
+	}
+	if len(denied) > 0 {
+		return &FieldEditError{State: p.State, Fields: denied}
+	}
+	if len(needsApproval) > 0 {
+		return fmt.Errorf("%w: %v", ErrPriorApprovalRequired, needsApproval)
+	}
+	if updates.DeadlineTimeZone != nil {
+		if _, err := LoadTimeZone(*updates.DeadlineTimeZone); err != nil {
+			return err
+		}
+	}
+
+	p.applyUpdates(updates)
+	for _, section := range updates.Sections() {
+		p.touchSection(section)
+	}
+	p.Touch(userID)
+	return nil
+}
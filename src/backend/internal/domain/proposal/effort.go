@@ -0,0 +1,378 @@
+// Package proposal provides the cross-proposal key personnel effort ledger.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// GuardEffortWithinLimit is the workflow guard name of the effort guard.
+const GuardEffortWithinLimit = "effort_within_limit"
+
+// DefaultEffortCap is the total effort, in percent, a person may commit
+// across proposals and awards unless configured otherwise.
+const DefaultEffortCap = 100.0
+
+// maxEffortMonths bounds the length of an effort timeline.
+const maxEffortMonths = 120
+
+// ErrEffortOverCommitted is returned when a person's committed effort exceeds the cap.
+var ErrEffortOverCommitted = errors.New("key personnel effort exceeds the commitment limit")
+
+// ErrInvalidEffortWindow is returned when an effort timeline window is empty or too long.
+var ErrInvalidEffortWindow = errors.New("invalid effort window")
+
+// EffortStates returns the states whose commitments count against a person's
+// effort: proposals pending with the sponsor and awards.
+func EffortStates() []ProposalState {
+	return []ProposalState{StateSubmitted, StateUnderReview, StateNegotiation, StateAwarded, StateActive}
+}
+
+// Effort sources.
+const (
+	EffortFromBudget       = "budget"
+	EffortFromKeyPersonnel = "key_personnel"
+)
+
+// BudgetPersonnel is a personnel line of a budget period.
+type BudgetPersonnel struct {
+	Period common.DateRange     `json:"period"`
+	Cost   budget.PersonnelCost `json:"cost"`
+}
+
+// EffortSource is the effort data of one proposal: its key personnel and the
+// personnel lines of its budget periods.
+type EffortSource struct {
+	ProposalID      uuid.UUID
+	ProposalNumber  string
+	Title           string
+	State           ProposalState
+	ProjectPeriod   common.DateRange
+	KeyPersonnel    []KeyPerson
+	BudgetPersonnel []BudgetPersonnel
+}
+
+// PersonIDs returns the persons named in the source.
+func (s EffortSource) PersonIDs() []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	add := func(id uuid.UUID) {
+		if id != uuid.Nil && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, kp := range s.KeyPersonnel {
+		add(kp.PersonID)
+	}
+	for _, bp := range s.BudgetPersonnel {
+		if bp.Cost.PersonID != nil {
+			add(*bp.Cost.PersonID)
+		}
+	}
+	return ids
+}
+
+// Commitments returns the person's commitments on the proposal. Budget
+// personnel lines give effort per budget period and take precedence; without
+// them the key personnel effort applies to the whole project period.
+func (s EffortSource) Commitments(personID uuid.UUID) []EffortCommitment {
+	var commitments []EffortCommitment
+	for _, bp := range s.BudgetPersonnel {
+		if bp.Cost.PersonID == nil || *bp.Cost.PersonID != personID {
+			continue
+		}
+		commitments = append(commitments, s.commitment(bp.Cost.Role, PersonnelCostEffort(bp.Cost, bp.Period), bp.Period, EffortFromBudget))
+	}
+	if len(commitments) > 0 {
+		return commitments
+	}
+
+	for _, kp := range s.KeyPersonnel {
+		if kp.PersonID == personID {
+			commitments = append(commitments, s.commitment(kp.Role, KeyPersonEffort(kp), s.ProjectPeriod, EffortFromKeyPersonnel))
+		}
+	}
+	return commitments
+}
+
+// commitment builds a commitment on the source's proposal.
+func (s EffortSource) commitment(role string, effort float64, period common.DateRange, source string) EffortCommitment {
+	return EffortCommitment{
+		ProposalID:     s.ProposalID,
+		ProposalNumber: s.ProposalNumber,
+		Title:          s.Title,
+		State:          s.State,
+		Role:           role,
+		Effort:         effort,
+		Period:         period,
+		Source:         source,
+	}
+}
+
+// EffortCommitment is a person's effort on one proposal over a period.
+type EffortCommitment struct {
+	ProposalID     uuid.UUID        `json:"proposal_id"`
+	ProposalNumber string           `json:"proposal_number"`
+	Title          string           `json:"title"`
+	State          ProposalState    `json:"state"`
+	Role           string           `json:"role,omitempty"`
+	Effort         float64          `json:"effort"` // Percentage
+	Period         common.DateRange `json:"period"`
+	Source         string           `json:"source"` // budget or key_personnel
+}
+
+// KeyPersonEffort returns a key person's effort in percent. Without an
+// explicit effort, person months are converted at twelve months per year.
+func KeyPersonEffort(kp KeyPerson) float64 {
+	if kp.Effort > 0 {
+		return kp.Effort
+	}
+	return (kp.CalendarMonths + kp.AcademicMonths + kp.SummerMonths) / 12 * 100
+}
+
+// PersonnelCostEffort returns the effort of a budget personnel line in
+// percent. Without an explicit effort, its person months are spread over the
+// months of the budget period.
+func PersonnelCostEffort(cost budget.PersonnelCost, period common.DateRange) float64 {
+	if cost.EffortPercent.IsPositive() {
+		return cost.EffortPercent.InexactFloat64()
+	}
+	months := cost.CalendarMonths.Add(cost.AcademicMonths).Add(cost.SummerMonths).InexactFloat64()
+	span := (period.EndDate.Year()-period.StartDate.Year())*12 + int(period.EndDate.Month()) - int(period.StartDate.Month()) + 1
+	if span < 1 {
+		span = 1
+	}
+	return months / float64(span) * 100
+}
+
+// EffortPeriod is a person's total effort in one calendar month.
+type EffortPeriod struct {
+	Month         string             `json:"month"` // YYYY-MM
+	Effort        float64            `json:"effort"`
+	OverCommitted bool               `json:"over_committed"`
+	Commitments   []EffortCommitment `json:"commitments"`
+}
+
+// EffortTimeline is a person's monthly effort across proposals and awards.
+type EffortTimeline struct {
+	PersonID      uuid.UUID      `json:"person_id"`
+	Cap           float64        `json:"cap"`
+	PeakEffort    float64        `json:"peak_effort"`
+	OverCommitted bool           `json:"over_committed"`
+	Periods       []EffortPeriod `json:"periods"`
+}
+
+// NewEffortTimeline sums the commitments per calendar month of the window and
+// flags months above the cap.
+func NewEffortTimeline(personID uuid.UUID, cap float64, window common.DateRange, commitments []EffortCommitment) (*EffortTimeline, error) {
+	start := time.Date(window.StartDate.Year(), window.StartDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(window.EndDate.Year(), window.EndDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: ends before it starts", ErrInvalidEffortWindow)
+	}
+	if months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month()) + 1; months > maxEffortMonths {
+		return nil, fmt.Errorf("%w: %d months exceeds the limit of %d", ErrInvalidEffortWindow, months, maxEffortMonths)
+	}
+
+	t := &EffortTimeline{PersonID: personID, Cap: cap}
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		next := month.AddDate(0, 1, 0)
+		period := EffortPeriod{Month: month.Format("2006-01"), Commitments: []EffortCommitment{}}
+		for _, c := range commitments {
+			if c.Period.StartDate.Before(next) && !c.Period.EndDate.Before(month) {
+				period.Effort += c.Effort
+				period.Commitments = append(period.Commitments, c)
+			}
+		}
+		period.Effort = math.Round(period.Effort*100) / 100
+		period.OverCommitted = period.Effort > cap
+		if period.Effort > t.PeakEffort {
+			t.PeakEffort = period.Effort
+		}
+		t.OverCommitted = t.OverCommitted || period.OverCommitted
+		t.Periods = append(t.Periods, period)
+	}
+	return t, nil
+}
+
+// EffortOverage reports a person whose effort exceeds the cap.
+type EffortOverage struct {
+	PersonID   uuid.UUID `json:"person_id"`
+	PeakEffort float64   `json:"peak_effort"`
+	Months     []string  `json:"months"`
+}
+
+// EffortOverCommitmentError reports the persons whose effort a proposal
+// would push above the cap.
+type EffortOverCommitmentError struct {
+	Cap      float64         `json:"cap"`
+	Overages []EffortOverage `json:"overages"`
+}
+
+// Error implements the error interface.
+func (e *EffortOverCommitmentError) Error() string {
+	reasons := make([]string, len(e.Overages))
+	for i, o := range e.Overages {
+		reasons[i] = fmt.Sprintf("%s at %g%% from %s", o.PersonID, o.PeakEffort, o.Months[0])
+	}
+	return fmt.Sprintf("%s of %g%%: %s", ErrEffortOverCommitted, e.Cap, strings.Join(reasons, ", "))
+}
+
+// Unwrap allows errors.Is(err, ErrEffortOverCommitted).
+func (e *EffortOverCommitmentError) Unwrap() error {
+	return ErrEffortOverCommitted
+}
+
+// EffortQuery selects effort sources. A proposal matches when it is in one of
+// the states or listed in ProposalIDs and, if PersonIDs is set, names one of
+// the persons as key personnel or on a budget personnel line.
+type EffortQuery struct {
+	PersonIDs   []uuid.UUID
+	States      []ProposalState
+	ProposalIDs []uuid.UUID
+}
+
+// EffortRepository loads the effort data of proposals.
+type EffortRepository interface {
+	// FindEffortSources retrieves the effort data of the latest version of
+	// every matching proposal.
+	FindEffortSources(ctx context.Context, tenantID common.TenantID, query EffortQuery) ([]EffortSource, error)
+}
+
+// EffortLedger sums each person's committed effort across awards and pending
+// proposals.
+type EffortLedger struct {
+	repo EffortRepository
+	cap  float64
+}
+
+// NewEffortLedger creates a ledger that flags effort above cap percent. A
+// non-positive cap uses DefaultEffortCap.
+func NewEffortLedger(repo EffortRepository, cap float64) *EffortLedger {
+	if cap <= 0 {
+		cap = DefaultEffortCap
+	}
+	return &EffortLedger{repo: repo, cap: cap}
+}
+
+// Cap returns the effort limit in percent.
+func (l *EffortLedger) Cap() float64 {
+	return l.cap
+}
+
+// Timeline returns a person's monthly effort over the window.
+func (l *EffortLedger) Timeline(ctx context.Context, tenantID common.TenantID, personID uuid.UUID, window common.DateRange) (*EffortTimeline, error) {
+	sources, err := l.repo.FindEffortSources(ctx, tenantID, EffortQuery{
+		PersonIDs: []uuid.UUID{personID},
+		States:    EffortStates(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load effort commitments: %w", err)
+	}
+
+	var commitments []EffortCommitment
+	for _, s := range sources {
+		commitments = append(commitments, s.Commitments(personID)...)
+	}
+	return NewEffortTimeline(personID, l.cap, window, commitments)
+}
+
+// Check adds the proposal's commitments to those of its persons on awards and
+// pending proposals, and reports every person above the cap during the
+// proposal's project period.
+func (l *EffortLedger) Check(ctx context.Context, p *Proposal) error {
+	own, err := l.repo.FindEffortSources(ctx, p.TenantID, EffortQuery{ProposalIDs: []uuid.UUID{p.ID}})
+	if err != nil {
+		return fmt.Errorf("failed to load effort commitments: %w", err)
+	}
+	if len(own) == 0 {
+		return nil
+	}
+	personIDs := own[0].PersonIDs()
+	if len(personIDs) == 0 {
+		return nil
+	}
+
+	others, err := l.repo.FindEffortSources(ctx, p.TenantID, EffortQuery{PersonIDs: personIDs, States: EffortStates()})
+	if err != nil {
+		return fmt.Errorf("failed to load effort commitments: %w", err)
+	}
+
+	overCommitted := &EffortOverCommitmentError{Cap: l.cap}
+	for _, personID := range personIDs {
+		commitments := own[0].Commitments(personID)
+		for _, s := range others {
+			if s.ProposalID != p.ID {
+				commitments = append(commitments, s.Commitments(personID)...)
+			}
+		}
+
+		timeline, err := NewEffortTimeline(personID, l.cap, p.ProjectPeriod, commitments)
+		if errors.Is(err, ErrInvalidEffortWindow) {
+			// Check the first years of very long projects
+			window := common.DateRange{StartDate: p.ProjectPeriod.StartDate, EndDate: p.ProjectPeriod.StartDate.AddDate(0, maxEffortMonths-1, 0)}
+			timeline, err = NewEffortTimeline(personID, l.cap, window, commitments)
+		}
+		if err != nil {
+			return err
+		}
+		if !timeline.OverCommitted {
+			continue
+		}
+
+		overage := EffortOverage{PersonID: personID, PeakEffort: timeline.PeakEffort}
+		for _, period := range timeline.Periods {
+			if period.OverCommitted {
+				overage.Months = append(overage.Months, period.Month)
+			}
+		}
+		overCommitted.Overages = append(overCommitted.Overages, overage)
+	}
+
+	if len(overCommitted.Overages) > 0 {
+		sort.Slice(overCommitted.Overages, func(i, j int) bool {
+			return overCommitted.Overages[i].PersonID.String() < overCommitted.Overages[j].PersonID.String()
+		})
+		return overCommitted
+	}
+	return nil
+}
+
+// EffortWithinLimitGuard blocks a transition while the proposal would commit
+// any of its persons above the ledger's cap.
+func EffortWithinLimitGuard(ledger *EffortLedger) ProposalGuard {
+	return func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error {
+		if payload.Proposal == nil {
+			return errors.New("transition payload has no proposal")
+		}
+		return ledger.Check(ctx, payload.Proposal)
+	}
+}
+
+// RequireEffortWithinLimit prevents submitting a proposal to the sponsor while
+// it would over-commit any of its persons.
+func (psm *ProposalStateMachine) RequireEffortWithinLimit(ledger *EffortLedger) {
+	psm.AddProposalGuard(TransitionSubmitToSponsor, GuardEffortWithinLimit, EffortWithinLimitGuard(ledger))
+}
+
+// AuthorizeEffort checks access to a person's effort commitments.
+func AuthorizeEffort(ctx context.Context, az authz.Authorizer, tenantCtx common.TenantContext, personID uuid.UUID) error {
+	return authorize(ctx, az, tenantCtx, authz.Request{
+		Action: authz.ActionEffortRead,
+		Resource: map[string]interface{}{
+			"type":      "effort",
+			"person_id": personID,
+		},
+	})
+}
@@ -0,0 +1,102 @@
+package proposal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+func dateRange(from, to string) common.DateRange {
+	start, _ := time.Parse(dateLayout, from)
+	end, _ := time.Parse(dateLayout, to)
+	return common.DateRange{StartDate: start, EndDate: end}
+}
+
+func TestNewEffortTimeline(t *testing.T) {
+	award := EffortCommitment{ProposalNumber: "PSU-2026-00001", Effort: 60, Period: dateRange("2026-01-01", "2026-12-31")}
+	pending := EffortCommitment{ProposalNumber: "PSU-2027-00004", Effort: 33.335, Period: dateRange("2026-03-15", "2026-04-10")}
+	renewal := EffortCommitment{ProposalNumber: "PSU-2027-00009", Effort: 50, Period: dateRange("2026-04-30", "2027-03-31")}
+
+	tests := []struct {
+		name        string
+		window      common.DateRange
+		commitments []EffortCommitment
+		wantEffort  []float64
+		wantOver    []bool
+		wantPeak    float64
+		wantErr     bool
+	}{
+		{
+			name:        "partial months count in full",
+			window:      dateRange("2026-02-10", "2026-05-05"),
+			commitments: []EffortCommitment{award, pending},
+			wantEffort:  []float64{60, 93.34, 93.34, 60},
+			wantOver:    []bool{false, false, false, false},
+			wantPeak:    93.34,
+		},
+		{
+			name:        "months above the cap are flagged",
+			window:      dateRange("2026-03-01", "2026-05-31"),
+			commitments: []EffortCommitment{award, pending, renewal},
+			wantEffort:  []float64{93.34, 143.34, 110},
+			wantOver:    []bool{false, true, true},
+			wantPeak:    143.34,
+		},
+		{
+			name:       "no commitments",
+			window:     dateRange("2026-12-01", "2027-01-31"),
+			wantEffort: []float64{0, 0},
+			wantOver:   []bool{false, false},
+		},
+		{
+			name:    "window ends before it starts",
+			window:  dateRange("2026-05-01", "2026-04-30"),
+			wantErr: true,
+		},
+		{
+			name:    "window longer than the limit",
+			window:  dateRange("2026-01-01", "2036-01-01"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeline, err := NewEffortTimeline(uuid.New(), DefaultEffortCap, tt.window, tt.commitments)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidEffortWindow) {
+					t.Fatalf("expected ErrInvalidEffortWindow, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var effort []float64
+			var over []bool
+			anyOver := false
+			for _, p := range timeline.Periods {
+				effort = append(effort, p.Effort)
+				over = append(over, p.OverCommitted)
+				anyOver = anyOver || p.OverCommitted
+			}
+			if !reflect.DeepEqual(effort, tt.wantEffort) {
+				t.Errorf("monthly effort %v, want %v", effort, tt.wantEffort)
+			}
+			if !reflect.DeepEqual(over, tt.wantOver) {
+				t.Errorf("over-committed months %v, want %v", over, tt.wantOver)
+			}
+			if timeline.PeakEffort != tt.wantPeak {
+				t.Errorf("peak effort %g, want %g", timeline.PeakEffort, tt.wantPeak)
+			}
+			if timeline.OverCommitted != anyOver {
+				t.Errorf("timeline over-committed %v, but months say %v", timeline.OverCommitted, anyOver)
+			}
+		})
+	}
+}
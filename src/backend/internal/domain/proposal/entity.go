@@ -2,11 +2,14 @@
 package proposal
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
 	"github.com/pgvector/pgvector-go"
 )
 
@@ -29,7 +32,10 @@ type Proposal struct {
 	ShortTitle      string        `json:"short_title,omitempty"`
 	Abstract        string        `json:"abstract,omitempty"`
 	State           ProposalState `json:"state"`
-	ProposalNumber  string        `json:"proposal_number"` // System-generated
+	SubStates       map[string]ProposalState `json:"sub_states,omitempty"` // active sub-state per region of a composite state
+	Blocked         *statemachine.GuardRejection `json:"blocked,omitempty"` // why the last transition could not leave a completed composite state; not persisted
+	ProposalNumber  string        `json:"proposal_number"` // Assigned by the repository on first save
+	Type            ProposalType  `json:"proposal_type"`
 	ExternalID      string        `json:"external_id,omitempty"` // Sponsor's ID
 
 	// Personnel
@@ -42,6 +48,7 @@ type Proposal struct {
 	OpportunityID      *uuid.UUID `json:"opportunity_id,omitempty"`
 	SponsorDeadline    *time.Time `json:"sponsor_deadline,omitempty"`
 	InternalDeadline   *time.Time `json:"internal_deadline,omitempty"`
+	DeadlineTimeZone   string     `json:"deadline_time_zone,omitempty"` // IANA zone the sponsor quotes its deadline in
 
 	// Project Details
 	ProjectPeriod    common.DateRange `json:"project_period"`
@@ -63,10 +70,24 @@ type Proposal struct {
 	Embedding pgvector.Vector `json:"-"` // 1536-dimensional embedding
 
 	// State History
-	StateHistory []StateTransition `json:"state_history,omitempty"`
+	StateHistory    []StateTransition `json:"state_history,omitempty"`
+	WorkflowVersion int               `json:"workflow_version"`
+
+	// Versioning
+	ParentVersionID *uuid.UUID `json:"parent_version_id,omitempty"` // proposal this draft was restored from
+	IsLatestVersion bool       `json:"is_latest_version"`
+
+	// Lineage
+	PredecessorID *uuid.UUID `json:"predecessor_id,omitempty"` // proposal a resubmission, renewal or supplement follows
+
+	// Template
+	TemplateID *uuid.UUID `json:"template_id,omitempty"` // template the proposal was created from
 
 	// Attachments
 	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Concurrent Editing
+	SectionVersions map[Section]int `json:"section_versions,omitempty"` // change count per section
 }
 
 // KeyPerson represents a key personnel on the proposal.
@@ -85,8 +106,11 @@ type StateTransition struct {
 	ToState     ProposalState `json:"to_state"`
 	Transition  ProposalTransition `json:"transition"`
 	PerformedBy uuid.UUID    `json:"performed_by"`
+	OnBehalfOf  *uuid.UUID   `json:"on_behalf_of,omitempty"` // delegator when acting through a delegation
 	PerformedAt time.Time    `json:"performed_at"`
 	Comment     string       `json:"comment,omitempty"`
+	Region      string                   `json:"region,omitempty"`
+	SubStates   map[string]ProposalState `json:"sub_states,omitempty"`
 }
 
 // Attachment represents a file attachment.
@@ -115,6 +139,7 @@ func NewProposal(
 		BaseEntity:              common.NewBaseEntity(tenantID, userID),
 		Title:                   title,
 		State:                   StateDraft,
+		Type:                    TypeNew,
 		PrincipalInvestigatorID: piID,
 		SponsorID:               sponsorID,
 		Department:              department,
@@ -124,51 +149,84 @@ func NewProposal(
 		KeyPersonnel:            make([]KeyPerson, 0),
 		Keywords:                make([]string, 0),
 		Attachments:             make([]Attachment, 0),
+		WorkflowVersion:         DefaultWorkflowVersion,
+		IsLatestVersion:         true,
 	}
 
-	// Generate proposal number
-	p.ProposalNumber = generateProposalNumber(p.ID)
-
 	// Add creation event
 	p.AddEvent(common.NewProposalCreatedEvent(p.ID, tenantID, title, piID, sponsorID))
 
 	return p
 }
 
-// generateProposalNumber creates a unique proposal number.
-func generateProposalNumber(id uuid.UUID) string {
-	// Format: PROP-YYYY-XXXXXX (where X is first 6 chars of UUID)
-	year := time.Now().Year()
-	shortID := id.String()[:6]
-	return "PROP-" + string(rune(year)) + "-" + shortID
+// TransitionWith transitions the proposal using the given workflow state
+// machine. Guards and hooks receive a TransitionPayload; a guard rejection is
+// returned as a *statemachine.GuardRejection and leaves the proposal unchanged.
+func (p *Proposal) TransitionWith(ctx context.Context, sm *ProposalStateMachine, transition ProposalTransition, userID uuid.UUID, comment string) error {
+	return p.TransitionInRegion(ctx, sm, "", transition, userID, comment)
 }
 
-// TransitionTo transitions the proposal to a new state.
-func (p *Proposal) TransitionTo(transition ProposalTransition, userID uuid.UUID, comment string) error {
-	sm := NewProposalStateMachine()
+// TransitionInRegion transitions the proposal within a region of its current
+// composite state. An empty region fires the transition on the top-level
+// state, or in the only region that defines it; when several open regions
+// define it the region must be named.
+func (p *Proposal) TransitionInRegion(ctx context.Context, sm *ProposalStateMachine, region string, transition ProposalTransition, userID uuid.UUID, comment string) error {
+	return p.TransitionOnBehalf(ctx, sm, region, transition, userID, nil, comment)
+}
 
-	if !sm.CanTransition(p.State, transition) {
-		return ErrInvalidTransition
+// TransitionOnBehalf transitions the proposal like TransitionInRegion. When
+// the actor holds the required role through a delegation, onBehalfOf names
+// the delegator and is recorded alongside the actor.
+func (p *Proposal) TransitionOnBehalf(ctx context.Context, sm *ProposalStateMachine, region string, transition ProposalTransition, userID uuid.UUID, onBehalfOf *uuid.UUID, comment string) error {
+	payload := TransitionPayload{Actor: userID, OnBehalfOf: onBehalfOf, Proposal: p, Comment: comment}
+	cfg := p.Configuration()
+	if region == "" {
+		inferred, err := sm.ResolveRegion(cfg, transition)
+		if err != nil {
+			return err
+		}
+		region = inferred
 	}
 
-	nextState, err := sm.GetNextState(p.State, transition)
+	next, err := sm.FireIn(ctx, cfg, region, transition, payload)
 	if err != nil {
+		if errors.Is(err, statemachine.ErrInvalidTransition) || errors.Is(err, statemachine.ErrNoTransitions) {
+			var rejection *statemachine.GuardRejection
+			if errors.As(err, &rejection) {
+				return err
+			}
+			return ErrInvalidTransition
+		}
 		return err
 	}
 
+	// Region transitions are recorded between sub-states while the
+	// composite state remains active
+	fromState, toState := p.State, next.State
+	if region != "" {
+		fromState = cfg.Regions[region]
+		if next.State == p.State {
+			toState = next.Regions[region]
+		}
+	}
+
 	// Record the transition
 	stateChange := StateTransition{
-		FromState:   p.State,
-		ToState:     nextState,
+		FromState:   fromState,
+		ToState:     toState,
 		Transition:  transition,
 		PerformedBy: userID,
+		OnBehalfOf:  onBehalfOf,
 		PerformedAt: time.Now().UTC(),
 		Comment:     comment,
+		Region:      region,
+		SubStates:   next.Regions,
 	}
 	p.StateHistory = append(p.StateHistory, stateChange)
 
-	oldState := p.State
-	p.State = nextState
+	p.State = next.State
+	p.SubStates = next.Regions
+	p.Blocked = next.Blocked
 	p.Touch(userID)
 
 	// Add state change event
@@ -176,25 +234,33 @@ func (p *Proposal) TransitionTo(transition ProposalTransition, userID uuid.UUID,
 		p.ID,
 		p.TenantID,
 		p.Version,
-		oldState.String(),
-		nextState.String(),
+		fromState.String(),
+		toState.String(),
 		comment,
 	))
 
 	return nil
 }
 
+// Configuration returns the active state and region sub-states of the proposal.
+func (p *Proposal) Configuration() statemachine.Configuration[ProposalState] {
+	return statemachine.Configuration[ProposalState]{State: p.State, Regions: p.SubStates}
+}
+
 // CanEdit returns true if the proposal can be edited.
 func (p *Proposal) CanEdit() bool {
 	return p.State.CanEdit()
 }
 
 // Update updates proposal fields if the proposal is editable.
+// Only fields anyone may change in the current state are accepted; use
+// UpdateAs to apply role-specific edit permissions.
 func (p *Proposal) Update(userID uuid.UUID, updates ProposalUpdates) error {
-	if !p.CanEdit() {
-		return ErrProposalNotEditable
-	}
+	return p.UpdateAs(DefaultEditMatrix(), userID, nil, updates)
+}
 
+// applyUpdates copies the set fields of updates onto the proposal.
+func (p *Proposal) applyUpdates(updates ProposalUpdates) {
 	if updates.Title != nil {
 		p.Title = *updates.Title
 	}
@@ -216,6 +282,9 @@ func (p *Proposal) Update(userID uuid.UUID, updates ProposalUpdates) error {
 	if updates.InternalDeadline != nil {
 		p.InternalDeadline = updates.InternalDeadline
 	}
+	if updates.DeadlineTimeZone != nil {
+		p.DeadlineTimeZone = *updates.DeadlineTimeZone
+	}
 	if updates.IRBRequired != nil {
 		p.IRBRequired = *updates.IRBRequired
 	}
@@ -231,9 +300,12 @@ func (p *Proposal) Update(userID uuid.UUID, updates ProposalUpdates) error {
 	if updates.ConflictOfInterest != nil {
 		p.ConflictOfInterest = *updates.ConflictOfInterest
 	}
-
-	p.Touch(userID)
-	return nil
+	if updates.ExternalID != nil {
+		p.ExternalID = *updates.ExternalID
+	}
+	if updates.KeyPersonnel != nil {
+		p.KeyPersonnel = updates.KeyPersonnel
+	}
 }
 
 // ProposalUpdates contains optional updates to a proposal.
@@ -245,11 +317,17 @@ type ProposalUpdates struct {
 	Keywords           []string
 	SponsorDeadline    *time.Time
 	InternalDeadline   *time.Time
+	DeadlineTimeZone   *string
 	IRBRequired        *bool
 	IACUCRequired      *bool
 	IBCRequired        *bool
 	ExportControl      *bool
 	ConflictOfInterest *bool
+	ExternalID         *string
+	KeyPersonnel       []KeyPerson
+
+	// PriorApproval cites the sponsor's prior approval for changes that need it.
+	PriorApproval string
 }
 
 // AddCoInvestigator adds a co-investigator to the proposal.
@@ -266,6 +344,7 @@ func (p *Proposal) AddCoInvestigator(userID, coiID uuid.UUID) error {
 	}
 
 	p.CoInvestigators = append(p.CoInvestigators, coiID)
+	p.touchSection(SectionPersonnel)
 	p.Touch(userID)
 	return nil
 }
@@ -279,6 +358,7 @@ func (p *Proposal) RemoveCoInvestigator(userID, coiID uuid.UUID) error {
 	for i, coi := range p.CoInvestigators {
 		if coi == coiID {
 			p.CoInvestigators = append(p.CoInvestigators[:i], p.CoInvestigators[i+1:]...)
+			p.touchSection(SectionPersonnel)
 			p.Touch(userID)
 			return nil
 		}
@@ -293,6 +373,7 @@ func (p *Proposal) AddKeyPerson(userID uuid.UUID, keyPerson KeyPerson) error {
 	}
 
 	p.KeyPersonnel = append(p.KeyPersonnel, keyPerson)
+	p.touchSection(SectionPersonnel)
 	p.Touch(userID)
 	return nil
 }
@@ -308,6 +389,7 @@ func (p *Proposal) AddAttachment(userID uuid.UUID, attachment Attachment) error
 	attachment.UploadedAt = time.Now().UTC()
 
 	p.Attachments = append(p.Attachments, attachment)
+	p.touchSection(SectionAttachments)
 	p.Touch(userID)
 	return nil
 }
@@ -317,10 +399,33 @@ func (p *Proposal) SetEmbedding(embedding []float32) {
 	p.Embedding = pgvector.NewVector(embedding)
 }
 
-// GetAvailableTransitions returns the available state transitions.
-func (p *Proposal) GetAvailableTransitions() []ProposalTransition {
-	sm := NewProposalStateMachine()
-	return sm.GetAvailableTransitions(p.State)
+// AvailableTransitionsWith returns the transitions the actor may perform in
+// the given workflow, including those of active regions.
+func (p *Proposal) AvailableTransitionsWith(ctx context.Context, sm *ProposalStateMachine, actor uuid.UUID) []ProposalTransition {
+	available := p.AvailableRegionTransitionsWith(ctx, sm, actor)
+	regions := make([]string, 0, len(available))
+	for region := range available {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	var result []ProposalTransition
+	seen := make(map[ProposalTransition]bool)
+	for _, region := range regions {
+		for _, t := range available[region] {
+			if !seen[t] {
+				seen[t] = true
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
+
+// AvailableRegionTransitionsWith returns the transitions the actor may perform
+// keyed by region. Top-level transitions use the empty key.
+func (p *Proposal) AvailableRegionTransitionsWith(ctx context.Context, sm *ProposalStateMachine, actor uuid.UUID) map[string][]ProposalTransition {
+	return sm.AvailableTransitionsIn(ctx, p.Configuration(), TransitionPayload{Actor: actor, Proposal: p})
 }
 
 // IsOverdue returns true if the proposal is past its deadline.
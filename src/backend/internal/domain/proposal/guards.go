@@ -0,0 +1,84 @@
+// Package proposal provides typed guards and hooks for the proposal workflow.
+package proposal
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+)
+
+// TransitionPayload carries the context of a proposal transition to guards and hooks.
+type TransitionPayload struct {
+	Actor      uuid.UUID
+	OnBehalfOf *uuid.UUID
+	Proposal   *Proposal
+	Comment    string
+}
+
+// TransitionEvent is a transition of the proposal state machine.
+type TransitionEvent = statemachine.TransitionEvent[ProposalState, ProposalTransition]
+
+// ProposalGuard decides whether a proposal transition may proceed. A non-nil
+// error blocks the transition and is reported as the reason.
+type ProposalGuard func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error
+
+// ProposalHook runs when a proposal state is exited or entered.
+type ProposalHook func(ctx context.Context, from, to ProposalState, payload TransitionPayload) error
+
+// PayloadFrom extracts the proposal payload from a transition event.
+func PayloadFrom(event TransitionEvent) (TransitionPayload, bool) {
+	switch p := event.Payload.(type) {
+	case TransitionPayload:
+		return p, true
+	case *TransitionPayload:
+		if p != nil {
+			return *p, true
+		}
+	}
+	return TransitionPayload{}, false
+}
+
+// Func adapts the guard to the generic state machine.
+func (g ProposalGuard) Func() statemachine.GuardFunc[ProposalState, ProposalTransition] {
+	return func(ctx context.Context, event TransitionEvent) error {
+		payload, _ := PayloadFrom(event)
+		return g(ctx, event.From, event.To, payload)
+	}
+}
+
+// Func adapts the hook to the generic state machine.
+func (h ProposalHook) Func() statemachine.HookFunc[ProposalState, ProposalTransition] {
+	if h == nil {
+		return nil
+	}
+	return func(ctx context.Context, event TransitionEvent) error {
+		payload, _ := PayloadFrom(event)
+		return h(ctx, event.From, event.To, payload)
+	}
+}
+
+// AddProposalGuard adds a named guard for a transition.
+func (psm *ProposalStateMachine) AddProposalGuard(transition ProposalTransition, name string, guard ProposalGuard) {
+	psm.AddGuardFunc(transition, name, guard.Func())
+}
+
+// OnEnterState registers a named hook that runs when a state is entered. The
+// optional rollback undoes it if a later hook of the same transition fails.
+func (psm *ProposalStateMachine) OnEnterState(state ProposalState, name string, run, rollback ProposalHook) {
+	psm.AddEnterHook(state, statemachine.Hook[ProposalState, ProposalTransition]{
+		Name:     name,
+		Run:      run.Func(),
+		Rollback: rollback.Func(),
+	})
+}
+
+// OnExitState registers a named hook that runs when a state is exited. The
+// optional rollback undoes it if a later hook of the same transition fails.
+func (psm *ProposalStateMachine) OnExitState(state ProposalState, name string, run, rollback ProposalHook) {
+	psm.AddExitHook(state, statemachine.Hook[ProposalState, ProposalTransition]{
+		Name:     name,
+		Run:      run.Func(),
+		Rollback: rollback.Func(),
+	})
+}
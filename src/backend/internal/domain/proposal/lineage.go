@@ -0,0 +1,184 @@
+// Package proposal provides proposal types and predecessor lineage.
+package proposal
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidProposalType is returned for an unknown proposal type.
+var ErrInvalidProposalType = errors.New("invalid proposal type")
+
+// ErrPredecessorNotEligible is returned when a proposal cannot be the
+// predecessor of the requested type in its current state.
+var ErrPredecessorNotEligible = errors.New("proposal cannot be the predecessor of this proposal type")
+
+// ProposalType classifies a proposal relative to earlier submissions.
+type ProposalType string
+
+const (
+	TypeNew          ProposalType = "new"
+	TypeRenewal      ProposalType = "renewal"
+	TypeContinuation ProposalType = "continuation"
+	TypeSupplement   ProposalType = "supplement"
+	TypeResubmission ProposalType = "resubmission"
+	TypeTransfer     ProposalType = "transfer"
+	TypePreProposal  ProposalType = "pre_proposal"
+)
+
+// IsValid reports whether the type is known.
+func (t ProposalType) IsValid() bool {
+	switch t {
+	case TypeNew, TypeRenewal, TypeContinuation, TypeSupplement, TypeResubmission, TypeTransfer, TypePreProposal:
+		return true
+	}
+	return false
+}
+
+// RequiresPredecessor reports whether proposals of this type are derived
+// from an earlier proposal.
+func (t ProposalType) RequiresPredecessor() bool {
+	_, ok := predecessorStates[t]
+	return ok
+}
+
+// predecessorStates lists, per derived type, the states a predecessor must
+// be in: resubmissions follow unfunded proposals, the others follow awards.
+var predecessorStates = map[ProposalType][]ProposalState{
+	TypeResubmission: {StateRejected, StateDeclined, StateNotFunded},
+	TypeRenewal:      {StateAwarded, StateActive, StateCloseout, StateClosed},
+	TypeContinuation: {StateAwarded, StateActive},
+	TypeSupplement:   {StateAwarded, StateActive},
+}
+
+// Derive creates a new draft proposal of the given type that follows this
+// one. Title, abstract, personnel and keywords are carried over; the new
+// proposal starts its own lifecycle.
+func (p *Proposal) Derive(userID uuid.UUID, proposalType ProposalType) (*Proposal, error) {
+	states, ok := predecessorStates[proposalType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not derived from a predecessor", ErrInvalidProposalType, proposalType)
+	}
+
+	eligible := false
+	for _, s := range states {
+		if p.State == s {
+			eligible = true
+			break
+		}
+	}
+	if !eligible {
+		return nil, fmt.Errorf("%w: a %s cannot follow a proposal in %s", ErrPredecessorNotEligible, proposalType, p.State)
+	}
+
+	next := NewProposal(p.TenantID, userID, p.Title, p.PrincipalInvestigatorID, p.SponsorID, p.Department, p.ProjectPeriod)
+	next.Type = proposalType
+	next.PredecessorID = &p.ID
+	next.ShortTitle = p.ShortTitle
+	next.Abstract = p.Abstract
+	next.ResearchArea = p.ResearchArea
+	next.CoInvestigators = append(next.CoInvestigators, p.CoInvestigators...)
+	next.KeyPersonnel = append(next.KeyPersonnel, p.KeyPersonnel...)
+	next.Keywords = append(next.Keywords, p.Keywords...)
+
+	return next, nil
+}
+
+// LineageNode is a proposal in a predecessor chain.
+type LineageNode struct {
+	ID             uuid.UUID     `json:"id"`
+	ProposalNumber string        `json:"proposal_number"`
+	Title          string        `json:"title"`
+	Type           ProposalType  `json:"proposal_type"`
+	State          ProposalState `json:"state"`
+	PredecessorID  *uuid.UUID    `json:"predecessor_id,omitempty"`
+	Generation     int           `json:"generation"` // 0 for the original proposal
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// Lineage is the family of proposals linked to a proposal by predecessor
+// links. Chain runs from the original proposal to the requested one;
+// Successors lists every proposal derived from it, directly or indirectly.
+type Lineage struct {
+	ProposalID uuid.UUID     `json:"proposal_id"`
+	Chain      []LineageNode `json:"chain"`
+	Successors []LineageNode `json:"successors"`
+}
+
+// BuildLineage arranges related proposals into the lineage of the given
+// proposal. Proposals that are not linked to it are ignored.
+func BuildLineage(id uuid.UUID, related []*Proposal) (*Lineage, error) {
+	byID := make(map[uuid.UUID]*Proposal, len(related))
+	children := make(map[uuid.UUID][]*Proposal)
+	for _, p := range related {
+		byID[p.ID] = p
+		if p.PredecessorID != nil {
+			children[*p.PredecessorID] = append(children[*p.PredecessorID], p)
+		}
+	}
+	if byID[id] == nil {
+		return nil, ErrProposalNotFound
+	}
+
+	// Walk predecessors back to the original proposal
+	var ancestors []*Proposal
+	seen := make(map[uuid.UUID]bool)
+	for p := byID[id]; p != nil && !seen[p.ID]; {
+		seen[p.ID] = true
+		ancestors = append(ancestors, p)
+		if p.PredecessorID == nil {
+			break
+		}
+		p = byID[*p.PredecessorID]
+	}
+
+	lineage := &Lineage{
+		ProposalID: id,
+		Chain:      make([]LineageNode, 0, len(ancestors)),
+		Successors: make([]LineageNode, 0),
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		lineage.Chain = append(lineage.Chain, lineageNode(ancestors[i], len(ancestors)-1-i))
+	}
+
+	// Walk successors breadth first, oldest first within a generation
+	generation := len(ancestors) - 1
+	frontier := []uuid.UUID{id}
+	for len(frontier) > 0 {
+		generation++
+		var next []uuid.UUID
+		for _, parent := range frontier {
+			kids := children[parent]
+			sort.Slice(kids, func(i, j int) bool { return kids[i].CreatedAt.Before(kids[j].CreatedAt) })
+			for _, k := range kids {
+				if seen[k.ID] {
+					continue
+				}
+				seen[k.ID] = true
+				lineage.Successors = append(lineage.Successors, lineageNode(k, generation))
+				next = append(next, k.ID)
+			}
+		}
+		frontier = next
+	}
+
+	return lineage, nil
+}
+
+// lineageNode summarizes a proposal for its lineage.
+func lineageNode(p *Proposal, generation int) LineageNode {
+	return LineageNode{
+		ID:             p.ID,
+		ProposalNumber: p.ProposalNumber,
+		Title:          p.Title,
+		Type:           p.Type,
+		State:          p.State,
+		PredecessorID:  p.PredecessorID,
+		Generation:     generation,
+		CreatedAt:      p.CreatedAt,
+	}
+}
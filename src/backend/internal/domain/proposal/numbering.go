@@ -0,0 +1,148 @@
+// Package proposal provides configurable proposal numbering schemes.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// ErrInvalidNumberingScheme is returned when a numbering scheme cannot
+// produce unique proposal numbers.
+var ErrInvalidNumberingScheme = errors.New("invalid numbering scheme")
+
+// maxProposalNumberLength is the length of the proposal_number column.
+const maxProposalNumberLength = 50
+
+// defaultSequenceWidth pads {SEQ} when the token gives no width.
+const defaultSequenceWidth = 5
+
+// numberingToken matches tokens such as {TENANT}, {FY} and {SEQ:5}.
+var numberingToken = regexp.MustCompile(`\{([A-Z0-9]+)(?::(\d+))?\}`)
+
+// NumberingScheme formats proposal numbers for a tenant. The pattern may use
+//
+//	{TENANT}  the tenant code, e.g. PSU
+//	{FY}      the four-digit fiscal year, e.g. 2027
+//	{FY2}     the two-digit fiscal year, e.g. 27
+//	{YEAR}    the calendar year the number was assigned in
+//	{SEQ:n}   the sequence within the tenant and fiscal year, zero-padded to n digits
+//
+// Sequences restart every fiscal year, so the pattern must contain {SEQ} and
+// {FY} or {FY2}. Fiscal years are named after the calendar year they end in.
+type NumberingScheme struct {
+	Pattern              string     `json:"pattern"`
+	FiscalYearStartMonth time.Month `json:"fiscal_year_start_month"`
+	TenantCode           string     `json:"tenant_code,omitempty"` // empty uses the tenant's slug
+}
+
+// DefaultNumberingScheme returns the scheme used by tenants that have not
+// configured one, with a July fiscal year.
+func DefaultNumberingScheme() NumberingScheme {
+	return NumberingScheme{
+		Pattern:              "{TENANT}-{FY}-{SEQ:5}",
+		FiscalYearStartMonth: time.July,
+	}
+}
+
+// Validate checks that the scheme yields unique numbers that fit the column.
+func (s NumberingScheme) Validate() error {
+	if s.FiscalYearStartMonth < time.January || s.FiscalYearStartMonth > time.December {
+		return fmt.Errorf("%w: fiscal year start month %d must be between 1 and 12", ErrInvalidNumberingScheme, s.FiscalYearStartMonth)
+	}
+
+	seq, fiscalYear := 0, false
+	for _, m := range numberingToken.FindAllStringSubmatch(s.Pattern, -1) {
+		switch m[1] {
+		case "SEQ":
+			seq++
+			if m[2] != "" {
+				if width, _ := strconv.Atoi(m[2]); width < 1 || width > 12 {
+					return fmt.Errorf("%w: sequence width %s must be between 1 and 12", ErrInvalidNumberingScheme, m[2])
+				}
+			}
+		case "FY", "FY2":
+			fiscalYear = true
+		case "TENANT", "YEAR":
+		default:
+			return fmt.Errorf("%w: unknown token {%s}", ErrInvalidNumberingScheme, m[1])
+		}
+	}
+	if seq != 1 {
+		return fmt.Errorf("%w: pattern must contain {SEQ} exactly once", ErrInvalidNumberingScheme)
+	}
+	if !fiscalYear {
+		return fmt.Errorf("%w: pattern must contain {FY} or {FY2} because sequences restart every fiscal year", ErrInvalidNumberingScheme)
+	}
+	if strings.ContainsAny(numberingToken.ReplaceAllString(s.Pattern, ""), "{}") {
+		return fmt.Errorf("%w: malformed token in %q", ErrInvalidNumberingScheme, s.Pattern)
+	}
+
+	// Numbers must fit the column, allowing ten characters for a tenant slug
+	if example, err := s.Format("XXXXXXXXXX", time.Now(), 1); err != nil || len(example) > maxProposalNumberLength {
+		return fmt.Errorf("%w: numbers would exceed %d characters", ErrInvalidNumberingScheme, maxProposalNumberLength)
+	}
+	return nil
+}
+
+// FiscalYear returns the fiscal year a time falls in.
+func (s NumberingScheme) FiscalYear(at time.Time) int {
+	if s.FiscalYearStartMonth <= time.January || at.Month() < s.FiscalYearStartMonth {
+		return at.Year()
+	}
+	return at.Year() + 1
+}
+
+// Format renders the proposal number with the given sequence value.
+func (s NumberingScheme) Format(tenantCode string, at time.Time, seq int64) (string, error) {
+	if s.TenantCode != "" {
+		tenantCode = s.TenantCode
+	}
+	fiscalYear := s.FiscalYear(at)
+
+	var unknown string
+	number := numberingToken.ReplaceAllStringFunc(s.Pattern, func(token string) string {
+		m := numberingToken.FindStringSubmatch(token)
+		switch m[1] {
+		case "TENANT":
+			return strings.ToUpper(tenantCode)
+		case "FY":
+			return strconv.Itoa(fiscalYear)
+		case "FY2":
+			return fmt.Sprintf("%02d", fiscalYear%100)
+		case "YEAR":
+			return strconv.Itoa(at.Year())
+		case "SEQ":
+			width := defaultSequenceWidth
+			if m[2] != "" {
+				width, _ = strconv.Atoi(m[2])
+			}
+			return fmt.Sprintf("%0*d", width, seq)
+		}
+		unknown = m[1]
+		return token
+	})
+	if unknown != "" {
+		return "", fmt.Errorf("%w: unknown token {%s}", ErrInvalidNumberingScheme, unknown)
+	}
+	return number, nil
+}
+
+// NumberingRepository persists tenant numbering schemes. Proposal numbers
+// themselves are assigned by the proposal repository when a proposal is
+// first saved, from a gap-free counter per tenant and fiscal year that is
+// incremented in the same transaction as the insert.
+type NumberingRepository interface {
+	// FindNumberingScheme retrieves a tenant's scheme, or nil if the tenant
+	// uses the default.
+	FindNumberingScheme(ctx context.Context, tenantID common.TenantID) (*NumberingScheme, error)
+
+	// SaveNumberingScheme stores a tenant's scheme.
+	SaveNumberingScheme(ctx context.Context, tenantID common.TenantID, scheme NumberingScheme) error
+}
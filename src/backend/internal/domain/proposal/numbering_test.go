@@ -0,0 +1,71 @@
+package proposal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNumberingSchemeFiscalYear(t *testing.T) {
+	tests := []struct {
+		name       string
+		startMonth time.Month
+		at         time.Time
+		want       int
+	}{
+		{name: "last day before a July rollover", startMonth: time.July, at: time.Date(2026, time.June, 30, 23, 59, 59, 0, time.UTC), want: 2026},
+		{name: "first day of a July fiscal year", startMonth: time.July, at: time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), want: 2027},
+		{name: "December in a July fiscal year", startMonth: time.July, at: time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), want: 2027},
+		{name: "October fiscal year", startMonth: time.October, at: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), want: 2027},
+		{name: "calendar fiscal year", startMonth: time.January, at: time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC), want: 2026},
+		{name: "unset start month is the calendar year", at: time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC), want: 2026},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NumberingScheme{Pattern: "{FY}-{SEQ}", FiscalYearStartMonth: tt.startMonth}
+			if got := s.FiscalYear(tt.at); got != tt.want {
+				t.Errorf("FiscalYear(%s) = %d, want %d", tt.at.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberingSchemeFormat(t *testing.T) {
+	june30 := time.Date(2026, time.June, 30, 12, 0, 0, 0, time.UTC)
+	july1 := time.Date(2026, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		scheme  NumberingScheme
+		at      time.Time
+		seq     int64
+		want    string
+		wantErr bool
+	}{
+		{name: "default before the rollover", scheme: DefaultNumberingScheme(), at: june30, seq: 42, want: "PSU-2026-00042"},
+		{name: "default after the rollover", scheme: DefaultNumberingScheme(), at: july1, seq: 1, want: "PSU-2027-00001"},
+		{name: "configured tenant code", scheme: NumberingScheme{Pattern: "{TENANT}{FY2}-{SEQ:3}", FiscalYearStartMonth: time.July, TenantCode: "osp"}, at: july1, seq: 7, want: "OSP27-007"},
+		{name: "calendar year beside fiscal year", scheme: NumberingScheme{Pattern: "{YEAR}/{FY2}/{SEQ:4}", FiscalYearStartMonth: time.October}, at: time.Date(2026, time.November, 3, 0, 0, 0, 0, time.UTC), seq: 12, want: "2026/27/0012"},
+		{name: "sequence wider than its padding", scheme: NumberingScheme{Pattern: "{FY}-{SEQ:2}", FiscalYearStartMonth: time.July}, at: june30, seq: 1234, want: "2026-1234"},
+		{name: "unknown token", scheme: NumberingScheme{Pattern: "{FY}-{DEPT}-{SEQ}"}, at: june30, seq: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scheme.Format("psu", tt.at, tt.seq)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidNumberingScheme) {
+					t.Fatalf("expected ErrInvalidNumberingScheme, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,9 +10,14 @@ import (
 
 // Repository defines the interface for proposal persistence.
 type Repository interface {
-	// Save persists a proposal (insert or update).
+	// Save persists a proposal (insert or update). A proposal without a
+	// number is assigned the next number of its tenant's numbering scheme.
 	Save(ctx context.Context, proposal *Proposal) error
 
+	// SaveAll persists several proposals in one transaction; if any of
+	// them fails, none is saved.
+	SaveAll(ctx context.Context, proposals ...*Proposal) error
+
 	// FindByID retrieves a proposal by ID within a tenant.
 	FindByID(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*Proposal, error)
 
@@ -51,6 +56,10 @@ type Repository interface {
 
 	// GetStateHistory retrieves the state transition history for a proposal.
 	GetStateHistory(ctx context.Context, tenantID common.TenantID, id uuid.UUID) ([]StateTransition, error)
+
+	// FindLineage retrieves every proposal linked to the given one through
+	// predecessor links, including the proposal itself.
+	FindLineage(ctx context.Context, tenantID common.TenantID, id uuid.UUID) ([]*Proposal, error)
 }
 
 // ListFilter defines filtering and pagination options.
@@ -65,6 +74,7 @@ type ListFilter struct {
 
 	// Filters
 	States      []ProposalState `json:"states,omitempty"`
+	Types       []ProposalType  `json:"types,omitempty"`
 	Departments []string        `json:"departments,omitempty"`
 	PIIDs       []uuid.UUID     `json:"pi_ids,omitempty"`
 	SponsorIDs  []uuid.UUID     `json:"sponsor_ids,omitempty"`
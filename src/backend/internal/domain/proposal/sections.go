@@ -0,0 +1,210 @@
+// Package proposal provides section-level locks and versions for concurrent editing.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// ErrInvalidSection is returned for an unknown proposal section.
+var ErrInvalidSection = errors.New("invalid proposal section")
+
+// ErrSectionLocked is returned when another user holds the lock on a section.
+var ErrSectionLocked = errors.New("section is locked by another user")
+
+// ErrLockNotHeld is returned when renewing or releasing a lock the user does
+// not hold, including one that expired and was taken over.
+var ErrLockNotHeld = errors.New("section lock is not held")
+
+// DefaultLockTTL is how long a section lock lives without a heartbeat.
+const DefaultLockTTL = 2 * time.Minute
+
+// Section is a group of proposal fields that is locked and versioned as a unit.
+type Section string
+
+const (
+	SectionAbstract    Section = "abstract"
+	SectionSponsor     Section = "sponsor"
+	SectionPersonnel   Section = "personnel"
+	SectionCompliance  Section = "compliance"
+	SectionAttachments Section = "attachments"
+)
+
+// AllSections returns every section in display order.
+func AllSections() []Section {
+	return []Section{SectionAbstract, SectionSponsor, SectionPersonnel, SectionCompliance, SectionAttachments}
+}
+
+// IsValid reports whether the section is known.
+func (s Section) IsValid() bool {
+	for _, known := range AllSections() {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Section returns the section a field belongs to.
+func (f Field) Section() Section {
+	switch f {
+	case FieldSponsorDeadline, FieldInternalDeadline, FieldExternalID:
+		return SectionSponsor
+	case FieldKeyPersonnel:
+		return SectionPersonnel
+	case FieldIRBRequired, FieldIACUCRequired, FieldIBCRequired, FieldExportControl, FieldConflictOfInterest:
+		return SectionCompliance
+	default:
+		return SectionAbstract
+	}
+}
+
+// Sections returns the sections the updates change, in display order.
+func (u ProposalUpdates) Sections() []Section {
+	changed := make(map[Section]bool)
+	for _, f := range u.Fields() {
+		changed[f.Section()] = true
+	}
+
+	var sections []Section
+	for _, s := range AllSections() {
+		if changed[s] {
+			sections = append(sections, s)
+		}
+	}
+	return sections
+}
+
+// touchSection records a change to a section.
+func (p *Proposal) touchSection(section Section) {
+	if p.SectionVersions == nil {
+		p.SectionVersions = make(map[Section]int)
+	}
+	p.SectionVersions[section]++
+}
+
+// SectionConflictError is returned when sections were changed by someone
+// else since the versions the caller last saw.
+type SectionConflictError struct {
+	Sections []Section       `json:"sections"`
+	Current  map[Section]int `json:"current_versions"`
+}
+
+// Error implements the error interface.
+func (e *SectionConflictError) Error() string {
+	names := make([]string, len(e.Sections))
+	for i, s := range e.Sections {
+		names[i] = string(s)
+	}
+	return fmt.Sprintf("%s: %s changed", ErrVersionMismatch, strings.Join(names, ", "))
+}
+
+// Unwrap lets errors.Is match ErrVersionMismatch.
+func (e *SectionConflictError) Unwrap() error {
+	return ErrVersionMismatch
+}
+
+// CheckSectionVersions verifies that the sections the updates change are
+// still at the expected versions. Sections without an expected version are
+// not checked, so edits to other sections never conflict.
+func (p *Proposal) CheckSectionVersions(updates ProposalUpdates, expected map[Section]int) error {
+	var stale []Section
+	for _, s := range updates.Sections() {
+		want, ok := expected[s]
+		if ok && p.SectionVersions[s] != want {
+			stale = append(stale, s)
+		}
+	}
+	if len(stale) > 0 {
+		return &SectionConflictError{Sections: stale, Current: p.SectionVersions}
+	}
+	return nil
+}
+
+// SectionLock is a soft, expiring claim by one user on a proposal section.
+// Holders keep it alive with heartbeats; an expired lock may be taken over.
+type SectionLock struct {
+	TenantID    common.TenantID `json:"tenant_id"`
+	ProposalID  uuid.UUID       `json:"proposal_id"`
+	Section     Section         `json:"section"`
+	HolderID    uuid.UUID       `json:"holder_id"`
+	AcquiredAt  time.Time       `json:"acquired_at"`
+	HeartbeatAt time.Time       `json:"heartbeat_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
+
+// NewSectionLock creates a lock on a section held by a user.
+func NewSectionLock(tenantID common.TenantID, proposalID uuid.UUID, section Section, holderID uuid.UUID, at time.Time, ttl time.Duration) (*SectionLock, error) {
+	if !section.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSection, section)
+	}
+	return &SectionLock{
+		TenantID:    tenantID,
+		ProposalID:  proposalID,
+		Section:     section,
+		HolderID:    holderID,
+		AcquiredAt:  at,
+		HeartbeatAt: at,
+		ExpiresAt:   at.Add(ttl),
+	}, nil
+}
+
+// IsActive reports whether the lock has not expired at the given time.
+func (l SectionLock) IsActive(at time.Time) bool {
+	return at.Before(l.ExpiresAt)
+}
+
+// SectionLockedError is returned when a section is locked by another user.
+type SectionLockedError struct {
+	Lock SectionLock `json:"lock"`
+}
+
+// Error implements the error interface.
+func (e *SectionLockedError) Error() string {
+	return fmt.Sprintf("%s: %s is held by %s until %s", ErrSectionLocked, e.Lock.Section, e.Lock.HolderID, e.Lock.ExpiresAt.Format(time.RFC3339))
+}
+
+// Unwrap lets errors.Is match ErrSectionLocked.
+func (e *SectionLockedError) Unwrap() error {
+	return ErrSectionLocked
+}
+
+// SectionLockRepository persists section locks.
+type SectionLockRepository interface {
+	// AcquireLock stores the lock unless an active lock of another holder
+	// exists, and returns the lock that is in force afterwards. A holder
+	// acquiring its own lock again renews it.
+	AcquireLock(ctx context.Context, lock *SectionLock) (*SectionLock, error)
+
+	// RenewLock extends a lock held by the holder, returning nil if the
+	// holder no longer holds it.
+	RenewLock(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, section Section, holderID uuid.UUID, at, expiresAt time.Time) (*SectionLock, error)
+
+	// ReleaseLock removes a lock held by the holder and reports whether it did.
+	ReleaseLock(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, section Section, holderID uuid.UUID) (bool, error)
+
+	// FindLocks retrieves the locks on a proposal that are active at the given time.
+	FindLocks(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, at time.Time) ([]SectionLock, error)
+}
+
+// CheckSectionLocks fails if another user holds an active lock on a section
+// the updates change. Unlocked sections may be edited by anyone.
+func CheckSectionLocks(locks []SectionLock, userID uuid.UUID, updates ProposalUpdates, at time.Time) error {
+	changed := make(map[Section]bool)
+	for _, s := range updates.Sections() {
+		changed[s] = true
+	}
+
+	for _, l := range locks {
+		if changed[l.Section] && l.HolderID != userID && l.IsActive(at) {
+			return &SectionLockedError{Lock: l}
+		}
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
 	"github.com/huron-portland/grants-management/internal/domain/common"
 )
 
@@ -22,11 +23,20 @@ var ErrInvalidInput = errors.New("invalid input")
 
 // Service provides domain operations for proposals.
 type Service struct {
-	repo       Repository
-	eventStore common.EventStore
-	publisher  common.EventPublisher
+	repo        Repository
+	eventStore  common.EventStore
+	publisher   common.EventPublisher
+	delegations DelegationRepository
+	authorizer  authz.Authorizer
+	snapshots   SnapshotRepository
+	workflows   *WorkflowRegistry
+	locks       SectionLockRepository
 }
 
+// maxSectionSaveAttempts bounds how often a section update is reapplied when
+// a concurrent save to another section bumps the proposal version.
+const maxSectionSaveAttempts = 3
+
 // NewService creates a new proposal domain service.
 func NewService(repo Repository, eventStore common.EventStore, publisher common.EventPublisher) *Service {
 	return &Service{
@@ -36,6 +46,38 @@ func NewService(repo Repository, eventStore common.EventStore, publisher common.
 	}
 }
 
+// UseDelegations makes transition checks honor active role delegations.
+func (s *Service) UseDelegations(repo DelegationRepository) *Service {
+	s.delegations = repo
+	return s
+}
+
+// UseAuthorizer makes the service decide access with the given policy
+// engine instead of the built-in policies.
+func (s *Service) UseAuthorizer(az authz.Authorizer) *Service {
+	s.authorizer = az
+	return s
+}
+
+// UseSnapshots makes submissions capture an immutable snapshot of the proposal.
+func (s *Service) UseSnapshots(repo SnapshotRepository) *Service {
+	s.snapshots = repo
+	return s
+}
+
+// UseWorkflows makes updates enforce the edit rules of each proposal's
+// workflow version instead of the built-in rules.
+func (s *Service) UseWorkflows(registry *WorkflowRegistry) *Service {
+	s.workflows = registry
+	return s
+}
+
+// UseSectionLocks makes updates respect section locks held by other users.
+func (s *Service) UseSectionLocks(repo SectionLockRepository) *Service {
+	s.locks = repo
+	return s
+}
+
 // CreateProposalInput contains the input for creating a proposal.
 type CreateProposalInput struct {
 	Title            string
@@ -43,13 +85,17 @@ type CreateProposalInput struct {
 	Abstract         string
 	PIID             uuid.UUID
 	SponsorID        uuid.UUID
+	OpportunityID    *uuid.UUID
 	Department       string
 	ProjectStartDate time.Time
 	ProjectEndDate   time.Time
 	SponsorDeadline  *time.Time
 	InternalDeadline *time.Time
+	DeadlineTimeZone string
 	ResearchArea     string
 	Keywords         []string
+	WorkflowVersion  int
+	Type             ProposalType
 }
 
 // CreateProposal creates a new proposal.
@@ -67,6 +113,15 @@ func (s *Service) CreateProposal(ctx context.Context, tenantCtx common.TenantCon
 	if input.ProjectEndDate.Before(input.ProjectStartDate) {
 		return nil, fmt.Errorf("%w: project end date must be after start date", ErrInvalidInput)
 	}
+	if input.Type != "" && !input.Type.IsValid() {
+		return nil, fmt.Errorf("%w: %w: %s", ErrInvalidInput, ErrInvalidProposalType, input.Type)
+	}
+	if input.Type.RequiresPredecessor() {
+		return nil, fmt.Errorf("%w: a %s must be created from its predecessor", ErrInvalidInput, input.Type)
+	}
+	if _, err := LoadTimeZone(input.DeadlineTimeZone); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
 
 	// Create proposal
 	proposal := NewProposal(
@@ -85,10 +140,23 @@ func (s *Service) CreateProposal(ctx context.Context, tenantCtx common.TenantCon
 	// Set optional fields
 	proposal.ShortTitle = input.ShortTitle
 	proposal.Abstract = input.Abstract
+	proposal.OpportunityID = input.OpportunityID
 	proposal.SponsorDeadline = input.SponsorDeadline
 	proposal.InternalDeadline = input.InternalDeadline
+	proposal.DeadlineTimeZone = input.DeadlineTimeZone
 	proposal.ResearchArea = input.ResearchArea
 	proposal.Keywords = input.Keywords
+	if input.WorkflowVersion > 0 {
+		proposal.WorkflowVersion = input.WorkflowVersion
+	}
+	if input.Type != "" {
+		proposal.Type = input.Type
+	}
+
+	// Check authorization
+	if err := Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalCreate, proposal); err != nil {
+		return nil, err
+	}
 
 	// Persist
 	if err := s.repo.Save(ctx, proposal); err != nil {
@@ -141,8 +209,12 @@ func (s *Service) UpdateProposal(ctx context.Context, tenantCtx common.TenantCon
 		return nil, ErrVersionMismatch
 	}
 
-	// Apply updates
-	if err := proposal.Update(tenantCtx.UserID, updates); err != nil {
+	// Check authorization
+	if err := Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalUpdate, proposal); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyUpdates(ctx, tenantCtx, proposal, updates); err != nil {
 		return nil, err
 	}
 
@@ -154,6 +226,81 @@ func (s *Service) UpdateProposal(ctx context.Context, tenantCtx common.TenantCon
 	return proposal, nil
 }
 
+// UpdateSections updates a proposal, detecting conflicts per section instead
+// of for the whole proposal: only the sections the updates change must still
+// be at the expected versions, and a save that loses the race to an edit of
+// another section is reapplied to the fresh proposal.
+func (s *Service) UpdateSections(ctx context.Context, tenantCtx common.TenantContext, id uuid.UUID, updates ProposalUpdates, expected map[Section]int) (*Proposal, error) {
+	for attempt := 1; ; attempt++ {
+		proposal, err := s.GetProposal(ctx, tenantCtx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := proposal.CheckSectionVersions(updates, expected); err != nil {
+			return nil, err
+		}
+
+		if err := Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalUpdate, proposal); err != nil {
+			return nil, err
+		}
+
+		if err := s.applyUpdates(ctx, tenantCtx, proposal, updates); err != nil {
+			return nil, err
+		}
+
+		err = s.repo.Save(ctx, proposal)
+		if err == nil {
+			return proposal, nil
+		}
+		if !errors.Is(err, ErrVersionMismatch) || attempt == maxSectionSaveAttempts {
+			return nil, fmt.Errorf("failed to save proposal: %w", err)
+		}
+	}
+}
+
+// applyUpdates applies the updates the actor's roles may make in the current
+// state to sections no one else has locked.
+func (s *Service) applyUpdates(ctx context.Context, tenantCtx common.TenantContext, proposal *Proposal, updates ProposalUpdates) error {
+	if s.locks != nil {
+		now := time.Now().UTC()
+		locks, err := s.locks.FindLocks(ctx, tenantCtx.TenantID, proposal.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to load section locks: %w", err)
+		}
+		if err := CheckSectionLocks(locks, tenantCtx.UserID, updates, now); err != nil {
+			return err
+		}
+	}
+
+	matrix, err := s.EditMatrix(ctx, proposal)
+	if err != nil {
+		return err
+	}
+	return proposal.UpdateAs(matrix, tenantCtx.UserID, tenantCtx.Roles, updates)
+}
+
+// EditMatrix returns the edit rules of the proposal's workflow version.
+func (s *Service) EditMatrix(ctx context.Context, p *Proposal) (EditMatrix, error) {
+	if s.workflows == nil {
+		return DefaultEditMatrix(), nil
+	}
+	sm, err := s.workflows.StateMachine(ctx, p.TenantID, p.WorkflowVersion)
+	if err != nil {
+		return nil, err
+	}
+	return sm.EditMatrix(), nil
+}
+
+// stateMachine returns the state machine of the proposal's workflow version.
+// Transitions need a registry so that they run the guards of the version.
+func (s *Service) stateMachine(ctx context.Context, p *Proposal) (*ProposalStateMachine, error) {
+	if s.workflows == nil {
+		return nil, fmt.Errorf("%w: no workflow registry configured", ErrWorkflowVersionNotFound)
+	}
+	return s.workflows.StateMachine(ctx, p.TenantID, p.WorkflowVersion)
+}
+
 // TransitionProposalInput contains input for transitioning a proposal.
 type TransitionProposalInput struct {
 	ProposalID  uuid.UUID
@@ -175,12 +322,17 @@ func (s *Service) TransitionProposal(ctx context.Context, tenantCtx common.Tenan
 	}
 
 	// Validate authorization for transition
-	if !s.canPerformTransition(tenantCtx, proposal, input.Transition) {
-		return nil, ErrUnauthorized
+	authority, err := s.authorizeTransition(ctx, tenantCtx, proposal, input.Transition)
+	if err != nil {
+		return nil, err
 	}
 
-	// Perform transition
-	if err := proposal.TransitionTo(input.Transition, tenantCtx.UserID, input.Comment); err != nil {
+	// Perform transition under the proposal's workflow version
+	sm, err := s.stateMachine(ctx, proposal)
+	if err != nil {
+		return nil, err
+	}
+	if err := proposal.TransitionOnBehalf(ctx, sm, "", input.Transition, tenantCtx.UserID, authority.OnBehalfOf, input.Comment); err != nil {
 		return nil, err
 	}
 
@@ -189,6 +341,10 @@ func (s *Service) TransitionProposal(ctx context.Context, tenantCtx common.Tenan
 		return nil, fmt.Errorf("failed to save proposal: %w", err)
 	}
 
+	if _, err := CaptureSnapshot(ctx, s.snapshots, proposal, input.Transition, tenantCtx.UserID); err != nil {
+		return nil, err
+	}
+
 	// Handle events
 	if s.eventStore != nil {
 		events := proposal.GetUncommittedEvents()
@@ -207,24 +363,23 @@ func (s *Service) TransitionProposal(ctx context.Context, tenantCtx common.Tenan
 	return proposal, nil
 }
 
-// canPerformTransition checks if user can perform a transition.
-func (s *Service) canPerformTransition(tenantCtx common.TenantContext, proposal *Proposal, transition ProposalTransition) bool {
+// authorizeTransition checks if user can perform a transition, directly or
+// through an active delegation.
+func (s *Service) authorizeTransition(ctx context.Context, tenantCtx common.TenantContext, proposal *Proposal, transition ProposalTransition) (Authority, error) {
 	// Get metadata for current state
 	metadata := GetStateMetadata(proposal.State)
-
-	// Check if user has any required role
-	for _, requiredRole := range metadata.RequiredRoles {
-		if tenantCtx.HasRole(requiredRole) {
-			return true
+	now := time.Now().UTC()
+
+	var delegations []Delegation
+	if s.delegations != nil {
+		var err error
+		delegations, err = s.delegations.FindActiveDelegations(ctx, tenantCtx.TenantID, tenantCtx.UserID, now)
+		if err != nil {
+			return Authority{}, fmt.Errorf("failed to load delegations: %w", err)
 		}
 	}
 
-	// PI can always withdraw their own proposal
-	if transition == TransitionWithdraw && proposal.PrincipalInvestigatorID == tenantCtx.UserID {
-		return true
-	}
-
-	return false
+	return AuthorizeTransition(ctx, s.authorizer, tenantCtx, proposal, transition, metadata, delegations, now)
 }
 
 // ListProposals lists proposals with filtering.
@@ -274,8 +429,8 @@ func (s *Service) DeleteProposal(ctx context.Context, tenantCtx common.TenantCon
 	}
 
 	// Check authorization
-	if proposal.PrincipalInvestigatorID != tenantCtx.UserID && !tenantCtx.HasRole("ADMIN") {
-		return ErrUnauthorized
+	if err := Authorize(ctx, s.authorizer, tenantCtx, authz.ActionProposalDelete, proposal); err != nil {
+		return err
 	}
 
 	return s.repo.Delete(ctx, tenantCtx.TenantID, id)
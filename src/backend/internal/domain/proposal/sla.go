@@ -0,0 +1,80 @@
+// Package proposal provides SLA tracking for proposal states.
+package proposal
+
+import (
+	"sort"
+	"time"
+)
+
+// SLABreach describes an active state whose SLA has elapsed.
+type SLABreach struct {
+	State     ProposalState `json:"state"`
+	Region    string        `json:"region,omitempty"`
+	SLAHours  int           `json:"sla_hours"`
+	EnteredAt time.Time     `json:"entered_at"`
+	DueAt     time.Time     `json:"due_at"`
+}
+
+// ActiveSince returns when the proposal entered a state it has remained in
+// since, either as its top-level state or as the sub-state of a region.
+func (p *Proposal) ActiveSince(state ProposalState) time.Time {
+	if len(p.StateHistory) == 0 {
+		return p.CreatedAt
+	}
+
+	since := p.StateHistory[len(p.StateHistory)-1].PerformedAt
+	for i := len(p.StateHistory) - 1; i >= 0; i-- {
+		entry := p.StateHistory[i]
+		active := entry.ToState == state
+		for _, sub := range entry.SubStates {
+			if sub == state {
+				active = true
+			}
+		}
+		if !active {
+			break
+		}
+		since = entry.PerformedAt
+	}
+	return since
+}
+
+// SLABreaches returns the active states of the proposal whose SLA, as defined
+// by the workflow, has elapsed at the given time. Regions of a composite state
+// are checked individually.
+func (p *Proposal) SLABreaches(sm *ProposalStateMachine, now time.Time) []SLABreach {
+	var breaches []SLABreach
+
+	check := func(state ProposalState, region string) {
+		hours := sm.Metadata(state).SLAHours
+		if hours <= 0 {
+			return
+		}
+		entered := p.ActiveSince(state)
+		due := entered.Add(time.Duration(hours) * time.Hour)
+		if now.After(due) {
+			breaches = append(breaches, SLABreach{
+				State:     state,
+				Region:    region,
+				SLAHours:  hours,
+				EnteredAt: entered,
+				DueAt:     due,
+			})
+		}
+	}
+
+	check(p.State, "")
+
+	regions := make([]string, 0, len(p.SubStates))
+	for region := range p.SubStates {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	for _, region := range regions {
+		if sub := p.SubStates[region]; sub != p.State {
+			check(sub, region)
+		}
+	}
+
+	return breaches
+}
@@ -0,0 +1,365 @@
+// Package proposal provides immutable proposal snapshots and field-level diffs.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// ErrSnapshotNotFound is returned when a proposal version does not exist.
+var ErrSnapshotNotFound = errors.New("proposal version not found")
+
+// ErrNotLatestVersion is returned when restoring from a superseded proposal.
+var ErrNotLatestVersion = errors.New("proposal has been superseded by a newer version")
+
+// ErrNoPriorReview is returned when the actor has not reviewed the proposal.
+var ErrNoPriorReview = errors.New("no prior review of this proposal by the actor")
+
+// SnapshotTransitions are the transitions that capture a snapshot of the
+// proposal as submitted.
+var SnapshotTransitions = []ProposalTransition{
+	TransitionSubmitForReview,
+	TransitionSubmitToSponsor,
+}
+
+// CapturesSnapshot reports whether firing the transition captures a snapshot.
+func CapturesSnapshot(transition ProposalTransition) bool {
+	for _, t := range SnapshotTransitions {
+		if t == transition {
+			return true
+		}
+	}
+	return false
+}
+
+// SnapshotContent is the reviewable content of a proposal.
+type SnapshotContent struct {
+	Title                   string           `json:"title"`
+	ShortTitle              string           `json:"short_title,omitempty"`
+	Abstract                string           `json:"abstract,omitempty"`
+	PrincipalInvestigatorID uuid.UUID        `json:"principal_investigator_id"`
+	CoInvestigators         []uuid.UUID      `json:"co_investigators,omitempty"`
+	KeyPersonnel            []KeyPerson      `json:"key_personnel,omitempty"`
+	SponsorID               uuid.UUID        `json:"sponsor_id"`
+	OpportunityID           *uuid.UUID       `json:"opportunity_id,omitempty"`
+	SponsorDeadline         *time.Time       `json:"sponsor_deadline,omitempty"`
+	InternalDeadline        *time.Time       `json:"internal_deadline,omitempty"`
+	ProjectPeriod           common.DateRange `json:"project_period"`
+	Department              string           `json:"department"`
+	ResearchArea            string           `json:"research_area,omitempty"`
+	Keywords                []string         `json:"keywords,omitempty"`
+	IRBRequired             bool             `json:"irb_required"`
+	IACUCRequired           bool             `json:"iacuc_required"`
+	IBCRequired             bool             `json:"ibc_required"`
+	ExportControl           bool             `json:"export_control"`
+	ConflictOfInterest      bool             `json:"conflict_of_interest"`
+	Attachments             []Attachment     `json:"attachments,omitempty"`
+}
+
+// Content returns a copy of the proposal's reviewable content.
+func (p *Proposal) Content() SnapshotContent {
+	return SnapshotContent{
+		Title:                   p.Title,
+		ShortTitle:              p.ShortTitle,
+		Abstract:                p.Abstract,
+		PrincipalInvestigatorID: p.PrincipalInvestigatorID,
+		CoInvestigators:         append([]uuid.UUID(nil), p.CoInvestigators...),
+		KeyPersonnel:            append([]KeyPerson(nil), p.KeyPersonnel...),
+		SponsorID:               p.SponsorID,
+		OpportunityID:           p.OpportunityID,
+		SponsorDeadline:         p.SponsorDeadline,
+		InternalDeadline:        p.InternalDeadline,
+		ProjectPeriod:           p.ProjectPeriod,
+		Department:              p.Department,
+		ResearchArea:            p.ResearchArea,
+		Keywords:                append([]string(nil), p.Keywords...),
+		IRBRequired:             p.IRBRequired,
+		IACUCRequired:           p.IACUCRequired,
+		IBCRequired:             p.IBCRequired,
+		ExportControl:           p.ExportControl,
+		ConflictOfInterest:      p.ConflictOfInterest,
+		Attachments:             append([]Attachment(nil), p.Attachments...),
+	}
+}
+
+// Snapshot is an immutable copy of a proposal's content taken when it was
+// submitted for review or to the sponsor. Snapshots of a proposal are
+// numbered from 1 in the order they were taken.
+type Snapshot struct {
+	ID              uuid.UUID          `json:"id"`
+	TenantID        common.TenantID    `json:"tenant_id"`
+	ProposalID      uuid.UUID          `json:"proposal_id"`
+	Number          int                `json:"number"`
+	ProposalVersion int                `json:"proposal_version"`
+	Transition      ProposalTransition `json:"transition"`
+	State           ProposalState      `json:"state"`
+	Content         SnapshotContent    `json:"content"`
+	CreatedBy       uuid.UUID          `json:"created_by"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+// NewSnapshot captures the proposal's current content. The repository
+// assigns the snapshot number when it is saved.
+func NewSnapshot(p *Proposal, transition ProposalTransition, userID uuid.UUID) *Snapshot {
+	return &Snapshot{
+		ID:              uuid.New(),
+		TenantID:        p.TenantID,
+		ProposalID:      p.ID,
+		ProposalVersion: p.Version,
+		Transition:      transition,
+		State:           p.State,
+		Content:         p.Content(),
+		CreatedBy:       userID,
+		CreatedAt:       time.Now().UTC(),
+	}
+}
+
+// SnapshotRepository persists proposal snapshots.
+type SnapshotRepository interface {
+	// SaveSnapshot persists a snapshot and assigns its number.
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+
+	// FindSnapshots retrieves the snapshots of a proposal ordered by number.
+	FindSnapshots(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) ([]Snapshot, error)
+
+	// FindSnapshot retrieves a snapshot by number, or nil if none exists.
+	FindSnapshot(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, number int) (*Snapshot, error)
+}
+
+// CaptureSnapshot saves a snapshot of the proposal if the transition it just
+// fired is one that captures snapshots. A nil repository captures nothing.
+func CaptureSnapshot(ctx context.Context, repo SnapshotRepository, p *Proposal, transition ProposalTransition, userID uuid.UUID) (*Snapshot, error) {
+	if repo == nil || !CapturesSnapshot(transition) {
+		return nil, nil
+	}
+
+	snapshot := NewSnapshot(p, transition, userID)
+	if err := repo.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// LastReviewBy returns the most recent transition the user fired, directly
+// or on behalf of another user, while the proposal was out of the PI's hands.
+func (p *Proposal) LastReviewBy(userID uuid.UUID) *StateTransition {
+	for i := len(p.StateHistory) - 1; i >= 0; i-- {
+		t := p.StateHistory[i]
+		if t.FromState.CanEdit() {
+			continue
+		}
+		if t.PerformedBy == userID || (t.OnBehalfOf != nil && *t.OnBehalfOf == userID) {
+			return &p.StateHistory[i]
+		}
+	}
+	return nil
+}
+
+// SnapshotAt returns the latest snapshot taken at or before the given time.
+func SnapshotAt(snapshots []Snapshot, at time.Time) *Snapshot {
+	var found *Snapshot
+	for i := range snapshots {
+		if snapshots[i].CreatedAt.After(at) {
+			continue
+		}
+		if found == nil || snapshots[i].Number > found.Number {
+			found = &snapshots[i]
+		}
+	}
+	return found
+}
+
+// RestoreDraft creates a new draft proposal from a snapshot of this one. The
+// draft becomes the latest version and this proposal is marked superseded.
+func (p *Proposal) RestoreDraft(userID uuid.UUID, snapshot *Snapshot) (*Proposal, error) {
+	if snapshot == nil || snapshot.ProposalID != p.ID {
+		return nil, ErrSnapshotNotFound
+	}
+	if !p.IsLatestVersion {
+		return nil, ErrNotLatestVersion
+	}
+
+	c := snapshot.Content
+	draft := NewProposal(p.TenantID, userID, c.Title, c.PrincipalInvestigatorID, c.SponsorID, c.Department, c.ProjectPeriod)
+	draft.ShortTitle = c.ShortTitle
+	draft.Abstract = c.Abstract
+	draft.CoInvestigators = append(draft.CoInvestigators, c.CoInvestigators...)
+	draft.KeyPersonnel = append(draft.KeyPersonnel, c.KeyPersonnel...)
+	draft.OpportunityID = c.OpportunityID
+	draft.SponsorDeadline = c.SponsorDeadline
+	draft.InternalDeadline = c.InternalDeadline
+	draft.ResearchArea = c.ResearchArea
+	draft.Keywords = append(draft.Keywords, c.Keywords...)
+	draft.IRBRequired = c.IRBRequired
+	draft.IACUCRequired = c.IACUCRequired
+	draft.IBCRequired = c.IBCRequired
+	draft.ExportControl = c.ExportControl
+	draft.ConflictOfInterest = c.ConflictOfInterest
+	draft.Attachments = append(draft.Attachments, c.Attachments...)
+	draft.WorkflowVersion = p.WorkflowVersion
+	draft.ParentVersionID = &p.ID
+
+	p.IsLatestVersion = false
+	p.Touch(userID)
+
+	return draft, nil
+}
+
+// ChangeKind describes how a field changed between two versions.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// FieldChange is a single change between two versions. Changes to list
+// fields are reported per item, identified by Key.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Kind  ChangeKind  `json:"kind"`
+	Key   string      `json:"key,omitempty"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// SnapshotDiff lists the changes between two versions of a proposal. A
+// version number of 0 denotes the current working copy.
+type SnapshotDiff struct {
+	ProposalID  uuid.UUID     `json:"proposal_id"`
+	FromVersion int           `json:"from_version"`
+	ToVersion   int           `json:"to_version"`
+	Changes     []FieldChange `json:"changes"`
+}
+
+// HasChanges reports whether the versions differ.
+func (d *SnapshotDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// DiffContent returns the field-level changes from one version to another.
+func DiffContent(from, to SnapshotContent) []FieldChange {
+	changes := make([]FieldChange, 0)
+	scalar := func(field string, old, new interface{}) {
+		if !reflect.DeepEqual(old, new) {
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeModified, Old: old, New: new})
+		}
+	}
+
+	scalar("title", from.Title, to.Title)
+	scalar("short_title", from.ShortTitle, to.ShortTitle)
+	scalar("abstract", from.Abstract, to.Abstract)
+	scalar("principal_investigator_id", from.PrincipalInvestigatorID, to.PrincipalInvestigatorID)
+	changes = append(changes, diffSet("co_investigators", uuidStrings(from.CoInvestigators), uuidStrings(to.CoInvestigators))...)
+	changes = append(changes, diffKeyed("key_personnel", from.KeyPersonnel, to.KeyPersonnel, func(kp KeyPerson) string { return kp.PersonID.String() })...)
+	scalar("sponsor_id", from.SponsorID, to.SponsorID)
+	scalar("opportunity_id", from.OpportunityID, to.OpportunityID)
+	scalar("sponsor_deadline", timeValue(from.SponsorDeadline), timeValue(to.SponsorDeadline))
+	scalar("internal_deadline", timeValue(from.InternalDeadline), timeValue(to.InternalDeadline))
+	scalar("project_start_date", from.ProjectPeriod.StartDate.UTC(), to.ProjectPeriod.StartDate.UTC())
+	scalar("project_end_date", from.ProjectPeriod.EndDate.UTC(), to.ProjectPeriod.EndDate.UTC())
+	scalar("department", from.Department, to.Department)
+	scalar("research_area", from.ResearchArea, to.ResearchArea)
+	changes = append(changes, diffSet("keywords", from.Keywords, to.Keywords)...)
+	scalar("irb_required", from.IRBRequired, to.IRBRequired)
+	scalar("iacuc_required", from.IACUCRequired, to.IACUCRequired)
+	scalar("ibc_required", from.IBCRequired, to.IBCRequired)
+	scalar("export_control", from.ExportControl, to.ExportControl)
+	scalar("conflict_of_interest", from.ConflictOfInterest, to.ConflictOfInterest)
+	changes = append(changes, diffKeyed("attachments", from.Attachments, to.Attachments, func(a Attachment) string { return a.ID.String() })...)
+
+	return changes
+}
+
+// Diff returns the changes from this snapshot to another.
+func (s *Snapshot) Diff(to *Snapshot) *SnapshotDiff {
+	return &SnapshotDiff{
+		ProposalID:  s.ProposalID,
+		FromVersion: s.Number,
+		ToVersion:   to.Number,
+		Changes:     DiffContent(s.Content, to.Content),
+	}
+}
+
+// DiffWorkingCopy returns the changes from this snapshot to the proposal's
+// current content.
+func (s *Snapshot) DiffWorkingCopy(p *Proposal) *SnapshotDiff {
+	return &SnapshotDiff{
+		ProposalID:  s.ProposalID,
+		FromVersion: s.Number,
+		Changes:     DiffContent(s.Content, p.Content()),
+	}
+}
+
+// diffSet reports items added to and removed from an unordered list.
+func diffSet(field string, from, to []string) []FieldChange {
+	var changes []FieldChange
+	old := make(map[string]bool, len(from))
+	for _, v := range from {
+		old[v] = true
+	}
+	current := make(map[string]bool, len(to))
+	for _, v := range to {
+		current[v] = true
+		if !old[v] {
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeAdded, Key: v, New: v})
+		}
+	}
+	for _, v := range from {
+		if !current[v] {
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeRemoved, Key: v, Old: v})
+		}
+	}
+	return changes
+}
+
+// diffKeyed reports changes to a list whose items are identified by key.
+func diffKeyed[T any](field string, from, to []T, key func(T) string) []FieldChange {
+	var changes []FieldChange
+	old := make(map[string]T, len(from))
+	for _, item := range from {
+		old[key(item)] = item
+	}
+	current := make(map[string]bool, len(to))
+	for _, item := range to {
+		k := key(item)
+		current[k] = true
+		prev, ok := old[k]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeAdded, Key: k, New: item})
+		case !reflect.DeepEqual(prev, item):
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeModified, Key: k, Old: prev, New: item})
+		}
+	}
+	for _, item := range from {
+		if k := key(item); !current[k] {
+			changes = append(changes, FieldChange{Field: field, Kind: ChangeRemoved, Key: k, Old: item})
+		}
+	}
+	return changes
+}
+
+// uuidStrings converts IDs to strings for set comparison.
+func uuidStrings(ids []uuid.UUID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = id.String()
+	}
+	return result
+}
+
+// timeValue dereferences an optional time, normalized to UTC.
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
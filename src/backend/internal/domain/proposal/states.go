@@ -1,4 +1,5 @@
-// Package proposal defines the 21-state proposal state machine.
+// Package proposal defines the proposal state machine and the parallel
+// regions of its REVIEW state.
 package proposal
 
 import (
@@ -10,7 +11,8 @@ import (
 // ProposalState represents the current state of a proposal.
 type ProposalState string
 
-// All 21 proposal states in the grants management lifecycle.
+// All proposal states in the grants management lifecycle. COMPLIANCE_REVIEW
+// and BUDGET_REVIEW are only active as sub-states of the REVIEW regions.
 const (
 	// Initial States
 	StateDraft           ProposalState = "DRAFT"
@@ -20,6 +22,7 @@ const (
 	StateInternalReview  ProposalState = "INTERNAL_REVIEW"
 	StateDeptReview      ProposalState = "DEPT_REVIEW"
 	StateOSPReview       ProposalState = "OSP_REVIEW"
+	StateReview          ProposalState = "REVIEW"
 	StateCompliance      ProposalState = "COMPLIANCE_REVIEW"
 	StateBudgetReview    ProposalState = "BUDGET_REVIEW"
 
@@ -54,6 +57,19 @@ func (s ProposalState) String() string {
 	return string(s)
 }
 
+// AllStates returns every proposal state in lifecycle order.
+func AllStates() []ProposalState {
+	return []ProposalState{
+		StateDraft, StateInProgress,
+		StateInternalReview, StateDeptReview, StateOSPReview, StateReview, StateCompliance, StateBudgetReview,
+		StatePendingApproval, StateApproved, StateRejected, StateRevisions,
+		StateReadyToSubmit, StateSubmitted, StateUnderReview,
+		StateAwarded, StateNegotiation, StateDeclined, StateNotFunded,
+		StateActive, StateCloseout, StateClosed,
+		StateWithdrawn,
+	}
+}
+
 // IsTerminal returns true if this is a terminal state.
 func (s ProposalState) IsTerminal() bool {
 	switch s {
@@ -107,105 +123,208 @@ const (
 	TransitionReopen           ProposalTransition = "REOPEN"
 )
 
+// Regions of the REVIEW composite state.
+const (
+	RegionCompliance = "compliance"
+	RegionBudget     = "budget"
+)
+
+// reviewRegions are the parallel regions of the REVIEW superstate. Compliance
+// and budget review run concurrently; REVIEW exits once both are finished.
+var reviewRegions = []statemachine.Region[ProposalState]{
+	{Name: RegionCompliance, Initial: StateCompliance, States: []ProposalState{StateCompliance}},
+	{Name: RegionBudget, Initial: StateBudgetReview, States: []ProposalState{StateBudgetReview}},
+}
+
 // ProposalStateMachine defines the state machine for proposals.
 type ProposalStateMachine struct {
 	*statemachine.StateMachine[ProposalState, ProposalTransition]
+	version  int
+	states   []ProposalState
+	terminal  map[ProposalState]bool
+	metadata  map[ProposalState]StateMetadata
+	approvals []ApprovalSet
+	edits     EditMatrix
+}
+
+// transitionDef describes a single edge of the proposal workflow.
+type transitionDef struct {
+	from       ProposalState
+	transition ProposalTransition
+	to         ProposalState
+}
+
+// defaultTransitions defines all valid transitions of the built-in workflow.
+var defaultTransitions = []transitionDef{
+	// Draft -> In Progress
+	{StateDraft, TransitionStart, StateInProgress},
+
+	// In Progress -> Internal Review
+	{StateInProgress, TransitionSubmitForReview, StateInternalReview},
+
+	// Internal Review Flow
+	{StateInternalReview, TransitionAdvanceReview, StateDeptReview},
+	{StateInternalReview, TransitionRequestRevisions, StateRevisions},
+
+	// Department Review Flow
+	{StateDeptReview, TransitionAdvanceReview, StateOSPReview},
+	{StateDeptReview, TransitionRequestRevisions, StateRevisions},
+
+	// OSP Review Flow
+	{StateOSPReview, TransitionAdvanceReview, StateReview},
+	{StateOSPReview, TransitionRequestRevisions, StateRevisions},
+
+	// Parallel Review: exits once compliance and budget review both finish
+	{StateReview, TransitionAdvanceReview, StatePendingApproval},
+	{StateReview, TransitionRequestRevisions, StateRevisions},
+
+	// Compliance region: advancing to REVIEW completes the region
+	{StateCompliance, TransitionAdvanceReview, StateReview},
+	{StateCompliance, TransitionRequestRevisions, StateRevisions},
+
+	// Budget region: advancing to REVIEW completes the region
+	{StateBudgetReview, TransitionAdvanceReview, StateReview},
+	{StateBudgetReview, TransitionRequestRevisions, StateRevisions},
+
+	// Approval Flow
+	{StatePendingApproval, TransitionApprove, StateApproved},
+	{StatePendingApproval, TransitionReject, StateRejected},
+	{StatePendingApproval, TransitionRequestRevisions, StateRevisions},
+
+	// Revisions can go back to In Progress
+	{StateRevisions, TransitionSubmitForReview, StateInternalReview},
+
+	// Approved -> Ready to Submit
+	{StateApproved, TransitionSubmitToSponsor, StateReadyToSubmit},
+
+	// Ready to Submit -> Submitted
+	{StateReadyToSubmit, TransitionSubmitToSponsor, StateSubmitted},
+
+	// Submitted -> Under Review
+	{StateSubmitted, TransitionAdvanceReview, StateUnderReview},
+
+	// Under Review outcomes
+	{StateUnderReview, TransitionAward, StateAwarded},
+	{StateUnderReview, TransitionNegotiate, StateNegotiation},
+	{StateUnderReview, TransitionDecline, StateDeclined},
+	{StateUnderReview, TransitionNotFund, StateNotFunded},
+
+	// Negotiation outcomes
+	{StateNegotiation, TransitionAward, StateAwarded},
+	{StateNegotiation, TransitionDecline, StateDeclined},
+
+	// Awarded -> Active
+	{StateAwarded, TransitionActivate, StateActive},
+
+	// Active -> Closeout
+	{StateActive, TransitionCloseout, StateCloseout},
+
+	// Closeout -> Closed
+	{StateCloseout, TransitionClose, StateClosed},
+
+	// Withdrawal can happen from many states
+	{StateDraft, TransitionWithdraw, StateWithdrawn},
+	{StateInProgress, TransitionWithdraw, StateWithdrawn},
+	{StateInternalReview, TransitionWithdraw, StateWithdrawn},
+	{StateDeptReview, TransitionWithdraw, StateWithdrawn},
+	{StateOSPReview, TransitionWithdraw, StateWithdrawn},
+	{StateReview, TransitionWithdraw, StateWithdrawn},
+	{StateCompliance, TransitionWithdraw, StateWithdrawn},
+	{StateBudgetReview, TransitionWithdraw, StateWithdrawn},
+	{StatePendingApproval, TransitionWithdraw, StateWithdrawn},
+	{StateApproved, TransitionWithdraw, StateWithdrawn},
+	{StateRevisions, TransitionWithdraw, StateWithdrawn},
+	{StateReadyToSubmit, TransitionWithdraw, StateWithdrawn},
+	{StateSubmitted, TransitionWithdraw, StateWithdrawn},
+	{StateUnderReview, TransitionWithdraw, StateWithdrawn},
+	{StateNegotiation, TransitionWithdraw, StateWithdrawn},
+
+	// Rejected can be reopened or abandoned
+	{StateRejected, TransitionReopen, StateDraft},
+	{StateRejected, TransitionWithdraw, StateWithdrawn},
 }
 
 // NewProposalStateMachine creates a new proposal state machine.
 func NewProposalStateMachine() *ProposalStateMachine {
 	sm := statemachine.New[ProposalState, ProposalTransition](StateDraft)
 
-	// Define all valid transitions
-	transitions := []struct {
-		from       ProposalState
-		transition ProposalTransition
-		to         ProposalState
-	}{
-		// Draft -> In Progress
-		{StateDraft, TransitionStart, StateInProgress},
-
-		// In Progress -> Internal Review
-		{StateInProgress, TransitionSubmitForReview, StateInternalReview},
-
-		// Internal Review Flow
-		{StateInternalReview, TransitionAdvanceReview, StateDeptReview},
-		{StateInternalReview, TransitionRequestRevisions, StateRevisions},
-
-		// Department Review Flow
-		{StateDeptReview, TransitionAdvanceReview, StateOSPReview},
-		{StateDeptReview, TransitionRequestRevisions, StateRevisions},
-
-		// OSP Review Flow
-		{StateOSPReview, TransitionAdvanceReview, StateCompliance},
-		{StateOSPReview, TransitionRequestRevisions, StateRevisions},
-
-		// Compliance Review Flow
-		{StateCompliance, TransitionAdvanceReview, StateBudgetReview},
-		{StateCompliance, TransitionRequestRevisions, StateRevisions},
-
-		// Budget Review Flow
-		{StateBudgetReview, TransitionAdvanceReview, StatePendingApproval},
-		{StateBudgetReview, TransitionRequestRevisions, StateRevisions},
-
-		// Approval Flow
-		{StatePendingApproval, TransitionApprove, StateApproved},
-		{StatePendingApproval, TransitionReject, StateRejected},
-		{StatePendingApproval, TransitionRequestRevisions, StateRevisions},
-
-		// Revisions can go back to In Progress
-		{StateRevisions, TransitionSubmitForReview, StateInternalReview},
-
-		// Approved -> Ready to Submit
-		{StateApproved, TransitionSubmitToSponsor, StateReadyToSubmit},
-
-		// Ready to Submit -> Submitted
-		{StateReadyToSubmit, TransitionSubmitToSponsor, StateSubmitted},
-
-		// Submitted -> Under Review
-		{StateSubmitted, TransitionAdvanceReview, StateUnderReview},
-
-		// Under Review outcomes
-		{StateUnderReview, TransitionAward, StateAwarded},
-		{StateUnderReview, TransitionNegotiate, StateNegotiation},
-		{StateUnderReview, TransitionDecline, StateDeclined},
-		{StateUnderReview, TransitionNotFund, StateNotFunded},
-
-		// Negotiation outcomes
-		{StateNegotiation, TransitionAward, StateAwarded},
-		{StateNegotiation, TransitionDecline, StateDeclined},
-
-		// Awarded -> Active
-		{StateAwarded, TransitionActivate, StateActive},
-
-		// Active -> Closeout
-		{StateActive, TransitionCloseout, StateCloseout},
-
-		// Closeout -> Closed
-		{StateCloseout, TransitionClose, StateClosed},
-
-		// Withdrawal can happen from many states
-		{StateDraft, TransitionWithdraw, StateWithdrawn},
-		{StateInProgress, TransitionWithdraw, StateWithdrawn},
-		{StateInternalReview, TransitionWithdraw, StateWithdrawn},
-		{StateDeptReview, TransitionWithdraw, StateWithdrawn},
-		{StateOSPReview, TransitionWithdraw, StateWithdrawn},
-		{StateCompliance, TransitionWithdraw, StateWithdrawn},
-		{StateBudgetReview, TransitionWithdraw, StateWithdrawn},
-		{StatePendingApproval, TransitionWithdraw, StateWithdrawn},
-		{StateApproved, TransitionWithdraw, StateWithdrawn},
-		{StateRevisions, TransitionWithdraw, StateWithdrawn},
-		{StateReadyToSubmit, TransitionWithdraw, StateWithdrawn},
-
-		// Rejected can be reopened
-		{StateRejected, TransitionReopen, StateDraft},
+	for _, t := range defaultTransitions {
+		sm.AddTransition(t.from, t.transition, t.to)
 	}
+	sm.AddComposite(StateReview, TransitionAdvanceReview, reviewRegions...)
 
-	for _, t := range transitions {
-		sm.AddTransition(t.from, t.transition, t.to)
+	return &ProposalStateMachine{StateMachine: sm, version: DefaultWorkflowVersion}
+}
+
+// Version returns the workflow version the state machine was built from.
+func (psm *ProposalStateMachine) Version() int {
+	return psm.version
+}
+
+// Metadata returns the metadata for a state, preferring values from the
+// workflow definition over the built-in defaults.
+func (psm *ProposalStateMachine) Metadata(state ProposalState) StateMetadata {
+	if m, ok := psm.metadata[state]; ok {
+		return m
+	}
+	return GetStateMetadata(state)
+}
+
+// States returns the states of the workflow in lifecycle order.
+func (psm *ProposalStateMachine) States() []ProposalState {
+	if psm.states != nil {
+		return psm.states
+	}
+	return AllStates()
+}
+
+// EditMatrix returns which fields each role may change in each state.
+func (psm *ProposalStateMachine) EditMatrix() EditMatrix {
+	if psm.edits != nil {
+		return psm.edits
+	}
+	return DefaultEditMatrix()
+}
+
+// IsTerminal reports whether a state is terminal in this workflow.
+func (psm *ProposalStateMachine) IsTerminal(state ProposalState) bool {
+	if psm.terminal != nil {
+		return psm.terminal[state]
 	}
+	return state.IsTerminal()
+}
+
+// DiagramOptions returns the options used to render the workflow, annotating
+// states with their display name and SLA.
+func (psm *ProposalStateMachine) DiagramOptions() statemachine.DiagramOptions[ProposalState] {
+	return statemachine.DiagramOptions[ProposalState]{
+		Title:  fmt.Sprintf("%s v%d", WorkflowName, psm.version),
+		States: psm.States(),
+		Annotate: func(state ProposalState) statemachine.StateAnnotation {
+			m := psm.Metadata(state)
+			return statemachine.StateAnnotation{
+				Label:    m.DisplayName,
+				SLAHours: m.SLAHours,
+				Terminal: psm.IsTerminal(state),
+			}
+		},
+	}
+}
 
-	return &ProposalStateMachine{StateMachine: sm}
+// Analyze reports unreachable states, non-terminal dead ends, cycles that
+// never terminate and states without metadata.
+func (psm *ProposalStateMachine) Analyze() statemachine.AnalysisReport {
+	return psm.StateMachine.Analyze(statemachine.AnalysisOptions[ProposalState]{
+		States:     psm.States(),
+		IsTerminal: psm.IsTerminal,
+		HasMetadata: func(state ProposalState) bool {
+			if psm.metadata != nil {
+				_, ok := psm.metadata[state]
+				return ok
+			}
+			return HasStateMetadata(state)
+		},
+	})
 }
 
 // CanTransition checks if a transition is valid from the current state.
@@ -248,114 +367,201 @@ type StateMetadata struct {
 	SLAHours         int
 }
 
+// stateMetadata holds the built-in metadata of every proposal state.
+var stateMetadata = map[ProposalState]StateMetadata{
+	StateDraft: {
+		State:         StateDraft,
+		DisplayName:   "Draft",
+		Description:   "Proposal is being drafted",
+		RequiredRoles: []string{"PI", "PROPOSAL_CREATOR"},
+		SLAHours:      0,
+	},
+	StateInProgress: {
+		State:            StateInProgress,
+		DisplayName:      "In Progress",
+		Description:      "Proposal is actively being developed",
+		RequiredRoles:    []string{"PI", "PROPOSAL_CREATOR"},
+		NotificationList: []string{"PI"},
+		SLAHours:         0,
+	},
+	StateInternalReview: {
+		State:            StateInternalReview,
+		DisplayName:      "Internal Review",
+		Description:      "Proposal is under initial internal review",
+		RequiredRoles:    []string{"REVIEWER", "DEPT_ADMIN"},
+		NotificationList: []string{"PI", "DEPT_ADMIN"},
+		SLAHours:         48,
+	},
+	StateDeptReview: {
+		State:            StateDeptReview,
+		DisplayName:      "Department Review",
+		Description:      "Proposal is under department review",
+		RequiredRoles:    []string{"DEPT_HEAD", "DEPT_ADMIN"},
+		NotificationList: []string{"PI", "DEPT_HEAD"},
+		SLAHours:         72,
+	},
+	StateOSPReview: {
+		State:            StateOSPReview,
+		DisplayName:      "OSP Review",
+		Description:      "Proposal is under Office of Sponsored Programs review",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "DEPT_HEAD", "OSP_OFFICER"},
+		SLAHours:         96,
+	},
+	StateReview: {
+		State:            StateReview,
+		DisplayName:      "Review",
+		Description:      "Compliance and budget review are running in parallel",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER"},
+		SLAHours:         72,
+	},
+	StateCompliance: {
+		State:            StateCompliance,
+		DisplayName:      "Compliance Review",
+		Description:      "Proposal is under compliance review",
+		RequiredRoles:    []string{"COMPLIANCE_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER"},
+		SLAHours:         72,
+	},
+	StateBudgetReview: {
+		State:            StateBudgetReview,
+		DisplayName:      "Budget Review",
+		Description:      "Proposal budget is under review",
+		RequiredRoles:    []string{"BUDGET_OFFICER", "OSP_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER"},
+		SLAHours:         48,
+	},
+	StatePendingApproval: {
+		State:            StatePendingApproval,
+		DisplayName:      "Pending Approval",
+		Description:      "Proposal is pending final approval",
+		RequiredRoles:    []string{"OSP_DIRECTOR", "AUTHORIZED_SIGNATORY"},
+		NotificationList: []string{"PI", "OSP_DIRECTOR"},
+		SLAHours:         24,
+	},
+	StateApproved: {
+		State:            StateApproved,
+		DisplayName:      "Approved",
+		Description:      "Proposal has been approved for submission",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER"},
+		SLAHours:         0,
+	},
+	StateSubmitted: {
+		State:            StateSubmitted,
+		DisplayName:      "Submitted",
+		Description:      "Proposal has been submitted to sponsor",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER", "DEPT_HEAD"},
+		SLAHours:         0,
+	},
+	StateAwarded: {
+		State:            StateAwarded,
+		DisplayName:      "Awarded",
+		Description:      "Proposal has been awarded",
+		RequiredRoles:    []string{"OSP_OFFICER", "GRANTS_ADMIN"},
+		NotificationList: []string{"PI", "OSP_OFFICER", "DEPT_HEAD", "GRANTS_ADMIN"},
+		SLAHours:         0,
+	},
+	StateActive: {
+		State:            StateActive,
+		DisplayName:      "Active",
+		Description:      "Award is active and funds are being expended",
+		RequiredRoles:    []string{"PI", "GRANTS_ADMIN"},
+		NotificationList: []string{"PI", "GRANTS_ADMIN"},
+		SLAHours:         0,
+	},
+	StateClosed: {
+		State:         StateClosed,
+		DisplayName:   "Closed",
+		Description:   "Award has been closed out",
+		RequiredRoles: []string{"GRANTS_ADMIN"},
+		SLAHours:      0,
+	},
+	StateRejected: {
+		State:            StateRejected,
+		DisplayName:      "Rejected",
+		Description:      "Proposal was rejected during internal approval",
+		RequiredRoles:    []string{"PI", "PROPOSAL_CREATOR"},
+		NotificationList: []string{"PI", "DEPT_HEAD"},
+		SLAHours:         0,
+	},
+	StateRevisions: {
+		State:            StateRevisions,
+		DisplayName:      "Revisions Requested",
+		Description:      "Reviewers have requested revisions to the proposal",
+		RequiredRoles:    []string{"PI", "PROPOSAL_CREATOR"},
+		NotificationList: []string{"PI"},
+		SLAHours:         120,
+	},
+	StateReadyToSubmit: {
+		State:            StateReadyToSubmit,
+		DisplayName:      "Ready to Submit",
+		Description:      "Proposal is approved and queued for sponsor submission",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER"},
+		SLAHours:         24,
+	},
+	StateUnderReview: {
+		State:            StateUnderReview,
+		DisplayName:      "Under Sponsor Review",
+		Description:      "Proposal is being reviewed by the sponsor",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER"},
+		SLAHours:         0,
+	},
+	StateNegotiation: {
+		State:            StateNegotiation,
+		DisplayName:      "Negotiation",
+		Description:      "Award terms are being negotiated with the sponsor",
+		RequiredRoles:    []string{"OSP_OFFICER", "GRANTS_ADMIN"},
+		NotificationList: []string{"PI", "OSP_OFFICER", "GRANTS_ADMIN"},
+		SLAHours:         0,
+	},
+	StateDeclined: {
+		State:            StateDeclined,
+		DisplayName:      "Declined",
+		Description:      "Award was declined",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "DEPT_HEAD"},
+		SLAHours:         0,
+	},
+	StateNotFunded: {
+		State:            StateNotFunded,
+		DisplayName:      "Not Funded",
+		Description:      "Sponsor did not fund the proposal",
+		RequiredRoles:    []string{"OSP_OFFICER"},
+		NotificationList: []string{"PI", "DEPT_HEAD"},
+		SLAHours:         0,
+	},
+	StateCloseout: {
+		State:            StateCloseout,
+		DisplayName:      "Closeout",
+		Description:      "Award is being closed out",
+		RequiredRoles:    []string{"GRANTS_ADMIN"},
+		NotificationList: []string{"PI", "GRANTS_ADMIN"},
+		SLAHours:         0,
+	},
+	StateWithdrawn: {
+		State:            StateWithdrawn,
+		DisplayName:      "Withdrawn",
+		Description:      "Proposal was withdrawn",
+		RequiredRoles:    []string{"PI", "OSP_OFFICER"},
+		NotificationList: []string{"PI", "OSP_OFFICER"},
+		SLAHours:         0,
+	},
+}
+
+// HasStateMetadata reports whether a state has built-in metadata.
+func HasStateMetadata(state ProposalState) bool {
+	_, ok := stateMetadata[state]
+	return ok
+}
+
 // GetStateMetadata returns metadata for a proposal state.
 func GetStateMetadata(state ProposalState) StateMetadata {
-	metadata := map[ProposalState]StateMetadata{
-		StateDraft: {
-			State:         StateDraft,
-			DisplayName:   "Draft",
-			Description:   "Proposal is being drafted",
-			RequiredRoles: []string{"PI", "PROPOSAL_CREATOR"},
-			SLAHours:      0,
-		},
-		StateInProgress: {
-			State:            StateInProgress,
-			DisplayName:      "In Progress",
-			Description:      "Proposal is actively being developed",
-			RequiredRoles:    []string{"PI", "PROPOSAL_CREATOR"},
-			NotificationList: []string{"PI"},
-			SLAHours:         0,
-		},
-		StateInternalReview: {
-			State:            StateInternalReview,
-			DisplayName:      "Internal Review",
-			Description:      "Proposal is under initial internal review",
-			RequiredRoles:    []string{"REVIEWER", "DEPT_ADMIN"},
-			NotificationList: []string{"PI", "DEPT_ADMIN"},
-			SLAHours:         48,
-		},
-		StateDeptReview: {
-			State:            StateDeptReview,
-			DisplayName:      "Department Review",
-			Description:      "Proposal is under department review",
-			RequiredRoles:    []string{"DEPT_HEAD", "DEPT_ADMIN"},
-			NotificationList: []string{"PI", "DEPT_HEAD"},
-			SLAHours:         72,
-		},
-		StateOSPReview: {
-			State:            StateOSPReview,
-			DisplayName:      "OSP Review",
-			Description:      "Proposal is under Office of Sponsored Programs review",
-			RequiredRoles:    []string{"OSP_OFFICER"},
-			NotificationList: []string{"PI", "DEPT_HEAD", "OSP_OFFICER"},
-			SLAHours:         96,
-		},
-		StateCompliance: {
-			State:            StateCompliance,
-			DisplayName:      "Compliance Review",
-			Description:      "Proposal is under compliance review",
-			RequiredRoles:    []string{"COMPLIANCE_OFFICER"},
-			NotificationList: []string{"PI", "OSP_OFFICER"},
-			SLAHours:         72,
-		},
-		StateBudgetReview: {
-			State:            StateBudgetReview,
-			DisplayName:      "Budget Review",
-			Description:      "Proposal budget is under review",
-			RequiredRoles:    []string{"BUDGET_OFFICER", "OSP_OFFICER"},
-			NotificationList: []string{"PI", "OSP_OFFICER"},
-			SLAHours:         48,
-		},
-		StatePendingApproval: {
-			State:            StatePendingApproval,
-			DisplayName:      "Pending Approval",
-			Description:      "Proposal is pending final approval",
-			RequiredRoles:    []string{"OSP_DIRECTOR", "AUTHORIZED_SIGNATORY"},
-			NotificationList: []string{"PI", "OSP_DIRECTOR"},
-			SLAHours:         24,
-		},
-		StateApproved: {
-			State:            StateApproved,
-			DisplayName:      "Approved",
-			Description:      "Proposal has been approved for submission",
-			RequiredRoles:    []string{"OSP_OFFICER"},
-			NotificationList: []string{"PI", "OSP_OFFICER"},
-			SLAHours:         0,
-		},
-		StateSubmitted: {
-			State:            StateSubmitted,
-			DisplayName:      "Submitted",
-			Description:      "Proposal has been submitted to sponsor",
-			RequiredRoles:    []string{"OSP_OFFICER"},
-			NotificationList: []string{"PI", "OSP_OFFICER", "DEPT_HEAD"},
-			SLAHours:         0,
-		},
-		StateAwarded: {
-			State:            StateAwarded,
-			DisplayName:      "Awarded",
-			Description:      "Proposal has been awarded",
-			RequiredRoles:    []string{"OSP_OFFICER", "GRANTS_ADMIN"},
-			NotificationList: []string{"PI", "OSP_OFFICER", "DEPT_HEAD", "GRANTS_ADMIN"},
-			SLAHours:         0,
-		},
-		StateActive: {
-			State:            StateActive,
-			DisplayName:      "Active",
-			Description:      "Award is active and funds are being expended",
-			RequiredRoles:    []string{"PI", "GRANTS_ADMIN"},
-			NotificationList: []string{"PI", "GRANTS_ADMIN"},
-			SLAHours:         0,
-		},
-		StateClosed: {
-			State:         StateClosed,
-			DisplayName:   "Closed",
-			Description:   "Award has been closed out",
-			RequiredRoles: []string{"GRANTS_ADMIN"},
-			SLAHours:      0,
-		},
-	}
-
-	if m, ok := metadata[state]; ok {
+	if m, ok := stateMetadata[state]; ok {
 		return m
 	}
 
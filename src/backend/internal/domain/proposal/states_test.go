@@ -0,0 +1,69 @@
+package proposal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+)
+
+func TestProposalStateMachineAnalysis(t *testing.T) {
+	report := NewProposalStateMachine().Analyze()
+	for _, problem := range report.Problems() {
+		t.Error(problem)
+	}
+}
+
+func TestDefaultWorkflowDefinitionAnalysis(t *testing.T) {
+	sm, err := NewProposalStateMachineFromDefinition(DefaultWorkflowDefinition(), nil)
+	if err != nil {
+		t.Fatalf("failed to build default workflow: %v", err)
+	}
+	for _, problem := range sm.Analyze().Problems() {
+		t.Error(problem)
+	}
+}
+
+func TestTransitionRegionIgnoresGuards(t *testing.T) {
+	sm := NewProposalStateMachine()
+	sm.AddStateGuard(StateBudgetReview, TransitionAdvanceReview, "blocked", func(ctx context.Context, event TransitionEvent) error {
+		return errors.New("budget is not ready")
+	})
+
+	p := &Proposal{State: StateReview, SubStates: sm.Enter(StateReview).Regions}
+	err := p.TransitionWith(context.Background(), sm, TransitionAdvanceReview, uuid.New(), "")
+	if !errors.Is(err, statemachine.ErrAmbiguousRegion) {
+		t.Fatalf("expected an ambiguous region with both regions open, got %v", err)
+	}
+
+	p.SubStates[RegionCompliance] = StateReview
+	err = p.TransitionWith(context.Background(), sm, TransitionAdvanceReview, uuid.New(), "")
+	var rejection *statemachine.GuardRejection
+	if !errors.As(err, &rejection) {
+		t.Fatalf("expected the budget region's guard rejection, got %v", err)
+	}
+	if p.SubStates[RegionBudget] != StateBudgetReview {
+		t.Errorf("budget region moved to %s", p.SubStates[RegionBudget])
+	}
+}
+
+func TestBlockedCompletionKeepsRegionProgress(t *testing.T) {
+	sm := NewProposalStateMachine()
+	sm.AddStateGuard(StateReview, TransitionAdvanceReview, "blocked", func(ctx context.Context, event TransitionEvent) error {
+		return errors.New("review is not signed off")
+	})
+
+	p := &Proposal{State: StateReview, SubStates: sm.Enter(StateReview).Regions}
+	p.SubStates[RegionCompliance] = StateReview
+	if err := p.TransitionInRegion(context.Background(), sm, RegionBudget, TransitionAdvanceReview, uuid.New(), ""); err != nil {
+		t.Fatalf("region transition failed: %v", err)
+	}
+	if p.State != StateReview || p.SubStates[RegionBudget] != StateReview {
+		t.Fatalf("expected REVIEW with the budget region complete, got %s %v", p.State, p.SubStates)
+	}
+	if p.Blocked == nil || p.Blocked.Transition != string(TransitionAdvanceReview) {
+		t.Fatalf("expected the completion rejection, got %v", p.Blocked)
+	}
+}
@@ -0,0 +1,314 @@
+// Package proposal provides proposal templates for programs a tenant
+// submits to every cycle.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/shopspring/decimal"
+)
+
+// ErrTemplateNotFound is returned when a proposal template does not exist.
+var ErrTemplateNotFound = errors.New("proposal template not found")
+
+// ErrInvalidTemplate is returned when a proposal template is malformed or
+// does not fit the proposal being created from it.
+var ErrInvalidTemplate = errors.New("invalid proposal template")
+
+// ErrTemplateNameTaken is returned when another template has the same name.
+var ErrTemplateNameTaken = errors.New("a proposal template with this name already exists")
+
+// maxTemplateBudgetYears bounds the budget periods a template may project.
+const maxTemplateBudgetYears = 10
+
+// TemplateRole is a personnel role new proposals are staffed with. Roles
+// naming a person become key personnel; the others are left to be filled.
+type TemplateRole struct {
+	Role           string     `json:"role"`
+	PersonID       *uuid.UUID `json:"person_id,omitempty"`
+	Effort         float64    `json:"effort"` // Percentage
+	CalendarMonths float64    `json:"calendar_months,omitempty"`
+	AcademicMonths float64    `json:"academic_months,omitempty"`
+	SummerMonths   float64    `json:"summer_months,omitempty"`
+}
+
+// ComplianceFlags are the compliance reviews a proposal requires.
+type ComplianceFlags struct {
+	IRBRequired   bool `json:"irb_required"`
+	IACUCRequired bool `json:"iacuc_required"`
+	IBCRequired   bool `json:"ibc_required"`
+	ExportControl bool `json:"export_control"`
+}
+
+// BudgetSkeleton is a first-year budget period that is projected over the
+// project period with Calculator.ProjectBudget.
+type BudgetSkeleton struct {
+	Period         budget.BudgetPeriod `json:"period"`          // dates are taken from the project period
+	Years          int                 `json:"years,omitempty"` // 0 covers the project period
+	InflationRate  decimal.Decimal     `json:"inflation_rate"`
+	SalaryIncrease decimal.Decimal     `json:"salary_increase"`
+	FARate         *budget.FARate      `json:"fa_rate,omitempty"` // nil uses the default rate
+	Currency       string              `json:"currency,omitempty"`
+}
+
+// Validate checks the skeleton's years and rates.
+func (s BudgetSkeleton) Validate() error {
+	if s.Years < 0 || s.Years > maxTemplateBudgetYears {
+		return fmt.Errorf("%w: budget years must be between 0 and %d", ErrInvalidTemplate, maxTemplateBudgetYears)
+	}
+	if s.InflationRate.IsNegative() || s.SalaryIncrease.IsNegative() {
+		return fmt.Errorf("%w: budget escalation rates cannot be negative", ErrInvalidTemplate)
+	}
+	return nil
+}
+
+// Build projects the skeleton over a proposal's project period into a new
+// draft budget. The last period ends with the project.
+func (s BudgetSkeleton) Build(p *Proposal, userID uuid.UUID) *budget.Budget {
+	start := p.ProjectPeriod.StartDate
+	end := p.ProjectPeriod.EndDate
+
+	years := s.Years
+	if years == 0 {
+		years = 1
+		for years < maxTemplateBudgetYears && !end.IsZero() && !start.AddDate(years, 0, 0).After(end) {
+			years++
+		}
+	}
+
+	faRate := budget.DefaultFARate()
+	if s.FARate != nil {
+		faRate = *s.FARate
+	}
+	currency := s.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	first := s.Period
+	first.StartDate = start
+	first.EndDate = start.AddDate(1, 0, -1)
+	projected := &budget.Budget{
+		BaseEntity: common.BaseEntity{TenantID: p.TenantID},
+		Periods:    budget.NewCalculator(faRate).ProjectBudget(first, years, s.InflationRate, s.SalaryIncrease),
+		FARate:     faRate,
+		Currency:   currency,
+	}
+	if last := len(projected.Periods) - 1; last >= 0 && !end.IsZero() && projected.Periods[last].EndDate.After(end) {
+		projected.Periods[last].EndDate = end
+	}
+
+	// Copying gives every period and line item its own ID
+	return projected.CopyFor(userID, p.ID)
+}
+
+// ProposalTemplate holds the defaults of a program a tenant submits to
+// repeatedly. Templates may be limited to a department and an opportunity.
+type ProposalTemplate struct {
+	common.BaseEntity
+	Name                string          `json:"name"`
+	Description         string          `json:"description,omitempty"`
+	Department          string          `json:"department,omitempty"`     // empty: any department
+	OpportunityID       *uuid.UUID      `json:"opportunity_id,omitempty"` // nil: any opportunity
+	ProposalType        ProposalType    `json:"proposal_type,omitempty"`
+	ResearchArea        string          `json:"research_area,omitempty"`
+	Keywords            []string        `json:"keywords,omitempty"`
+	Personnel           []TemplateRole  `json:"personnel,omitempty"`
+	Compliance          ComplianceFlags `json:"compliance"`
+	Budget              *BudgetSkeleton `json:"budget,omitempty"`
+	RequiredAttachments []string        `json:"required_attachments,omitempty"` // attachment categories
+}
+
+// NewProposalTemplate creates a template.
+func NewProposalTemplate(tenantID common.TenantID, userID uuid.UUID, name string) *ProposalTemplate {
+	return &ProposalTemplate{
+		BaseEntity: common.NewBaseEntity(tenantID, userID),
+		Name:       name,
+	}
+}
+
+// Validate checks the template's name, type, personnel, budget and
+// attachment categories.
+func (t *ProposalTemplate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	if len(t.Name) > 255 {
+		return fmt.Errorf("%w: name must be at most 255 characters", ErrInvalidTemplate)
+	}
+	if t.ProposalType != "" {
+		if !t.ProposalType.IsValid() {
+			return fmt.Errorf("%w: %w: %s", ErrInvalidTemplate, ErrInvalidProposalType, t.ProposalType)
+		}
+		if t.ProposalType.RequiresPredecessor() {
+			return fmt.Errorf("%w: a %s must be created from its predecessor", ErrInvalidTemplate, t.ProposalType)
+		}
+	}
+	for i, role := range t.Personnel {
+		if strings.TrimSpace(role.Role) == "" {
+			return fmt.Errorf("%w: personnel role %d has no name", ErrInvalidTemplate, i+1)
+		}
+		if role.Effort < 0 || role.Effort > 100 {
+			return fmt.Errorf("%w: effort of %s must be between 0 and 100", ErrInvalidTemplate, role.Role)
+		}
+	}
+	if t.Budget != nil {
+		if err := t.Budget.Validate(); err != nil {
+			return err
+		}
+	}
+	seen := make(map[string]bool, len(t.RequiredAttachments))
+	for _, category := range t.RequiredAttachments {
+		if strings.TrimSpace(category) == "" {
+			return fmt.Errorf("%w: attachment categories cannot be empty", ErrInvalidTemplate)
+		}
+		if seen[category] {
+			return fmt.Errorf("%w: attachment category %q is listed twice", ErrInvalidTemplate, category)
+		}
+		seen[category] = true
+	}
+	return nil
+}
+
+// Defaults fills what the create input leaves open from the template and
+// merges its keywords. Creating a proposal for another opportunity or
+// department than the template is limited to fails.
+func (t *ProposalTemplate) Defaults(input *CreateProposalInput) error {
+	if t.OpportunityID != nil {
+		if input.OpportunityID != nil && *input.OpportunityID != *t.OpportunityID {
+			return fmt.Errorf("%w: template %q is for another opportunity", ErrInvalidTemplate, t.Name)
+		}
+		opportunityID := *t.OpportunityID
+		input.OpportunityID = &opportunityID
+	}
+	if t.Department != "" {
+		if input.Department != "" && input.Department != t.Department {
+			return fmt.Errorf("%w: template %q is for department %s", ErrInvalidTemplate, t.Name, t.Department)
+		}
+		input.Department = t.Department
+	}
+	if input.Type == "" {
+		input.Type = t.ProposalType
+	}
+	if input.ResearchArea == "" {
+		input.ResearchArea = t.ResearchArea
+	}
+
+	keywords := make([]string, 0, len(input.Keywords)+len(t.Keywords))
+	seen := make(map[string]bool)
+	for _, kw := range append(append([]string(nil), input.Keywords...), t.Keywords...) {
+		key := strings.ToLower(strings.TrimSpace(kw))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keywords = append(keywords, kw)
+	}
+	input.Keywords = keywords
+	return nil
+}
+
+// Apply staffs a newly created proposal and sets its compliance flags from
+// the template. It returns the roles no person was named for.
+func (t *ProposalTemplate) Apply(userID uuid.UUID, p *Proposal) ([]TemplateRole, error) {
+	p.TemplateID = &t.ID
+
+	flags := ProposalUpdates{}
+	if t.Compliance.IRBRequired {
+		flags.IRBRequired = &t.Compliance.IRBRequired
+	}
+	if t.Compliance.IACUCRequired {
+		flags.IACUCRequired = &t.Compliance.IACUCRequired
+	}
+	if t.Compliance.IBCRequired {
+		flags.IBCRequired = &t.Compliance.IBCRequired
+	}
+	if t.Compliance.ExportControl {
+		flags.ExportControl = &t.Compliance.ExportControl
+	}
+	if err := p.Update(userID, flags); err != nil {
+		return nil, err
+	}
+
+	open := make([]TemplateRole, 0)
+	for _, role := range t.Personnel {
+		if role.PersonID == nil {
+			open = append(open, role)
+			continue
+		}
+		err := p.AddKeyPerson(userID, KeyPerson{
+			PersonID:       *role.PersonID,
+			Role:           role.Role,
+			Effort:         role.Effort,
+			CalendarMonths: role.CalendarMonths,
+			AcademicMonths: role.AcademicMonths,
+			SummerMonths:   role.SummerMonths,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return open, nil
+}
+
+// MissingAttachments returns the required attachment categories a proposal
+// has no attachment in.
+func (t *ProposalTemplate) MissingAttachments(p *Proposal) []string {
+	present := make(map[string]bool, len(p.Attachments))
+	for _, a := range p.Attachments {
+		present[a.Category] = true
+	}
+
+	missing := make([]string, 0)
+	for _, category := range t.RequiredAttachments {
+		if !present[category] {
+			missing = append(missing, category)
+		}
+	}
+	return missing
+}
+
+// PolicyAttributes returns the attributes policies can match on.
+func (t *ProposalTemplate) PolicyAttributes() map[string]interface{} {
+	return map[string]interface{}{
+		"type":           "proposal_template",
+		"id":             t.ID,
+		"department":     t.Department,
+		"opportunity_id": t.OpportunityID,
+		"created_by":     t.CreatedBy,
+	}
+}
+
+// AuthorizeTemplate checks an action on a proposal template.
+func AuthorizeTemplate(ctx context.Context, az authz.Authorizer, tenantCtx common.TenantContext, action string, t *ProposalTemplate) error {
+	return authorize(ctx, az, tenantCtx, authz.Request{Action: action, Resource: t.PolicyAttributes()})
+}
+
+// TemplateFilter limits the templates listed.
+type TemplateFilter struct {
+	Department    string     // templates for the department or any department
+	OpportunityID *uuid.UUID // templates for the opportunity or any opportunity
+}
+
+// ProposalTemplateRepository persists proposal templates.
+type ProposalTemplateRepository interface {
+	// SaveTemplate stores a template.
+	SaveTemplate(ctx context.Context, t *ProposalTemplate) error
+
+	// FindTemplate retrieves a template by ID, or nil.
+	FindTemplate(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*ProposalTemplate, error)
+
+	// ListTemplates retrieves the templates matching a filter by name.
+	ListTemplates(ctx context.Context, tenantID common.TenantID, filter TemplateFilter) ([]*ProposalTemplate, error)
+
+	// DeleteTemplate removes a template; proposals created from it keep
+	// their data.
+	DeleteTemplate(ctx context.Context, tenantID common.TenantID, id uuid.UUID) error
+}
@@ -0,0 +1,632 @@
+// Package proposal provides versioned, per-tenant workflow definitions.
+package proposal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+)
+
+// WorkflowName is the name of the proposal workflow in workflow documents.
+const WorkflowName = "proposal"
+
+// DefaultWorkflowVersion is the version of the built-in proposal workflow.
+const DefaultWorkflowVersion = 1
+
+// ErrWorkflowVersionNotFound is returned when a workflow version is not registered.
+var ErrWorkflowVersionNotFound = errors.New("workflow version not found")
+
+// DefaultWorkflowDefinition returns the built-in proposal workflow as a
+// declarative document, suitable as a starting point for tenant workflows.
+// It names no guards; the copy held by a WorkflowRegistry lists the
+// mandatory guards in use.
+func DefaultWorkflowDefinition() *statemachine.Definition {
+	def := &statemachine.Definition{
+		Name:         WorkflowName,
+		Version:      DefaultWorkflowVersion,
+		Description:  "Standard proposal lifecycle",
+		InitialState: string(StateDraft),
+	}
+
+	edits := DefaultEditMatrix()
+	for _, state := range AllStates() {
+		m := GetStateMetadata(state)
+		sd := statemachine.StateDefinition{
+			Name:             string(state),
+			DisplayName:      m.DisplayName,
+			Description:      m.Description,
+			Terminal:         state.IsTerminal(),
+			RequiredRoles:    m.RequiredRoles,
+			NotificationList: m.NotificationList,
+			SLAHours:         m.SLAHours,
+			Edits:            edits.editDefinitions(state),
+		}
+		if state == StateReview {
+			sd.Completion = string(TransitionAdvanceReview)
+			for _, r := range reviewRegions {
+				rd := statemachine.RegionDefinition{Name: r.Name, Initial: string(r.Initial)}
+				for _, rs := range r.States {
+					rd.States = append(rd.States, string(rs))
+				}
+				sd.Regions = append(sd.Regions, rd)
+			}
+		}
+		def.States = append(def.States, sd)
+	}
+
+	for _, t := range defaultTransitions {
+		def.Transitions = append(def.Transitions, statemachine.TransitionDefinition{
+			From:       string(t.from),
+			Transition: string(t.transition),
+			To:         string(t.to),
+		})
+	}
+
+	return def
+}
+
+// NewProposalStateMachineFromDefinition builds a proposal state machine from a
+// workflow document, resolving guard names against the given registry.
+func NewProposalStateMachineFromDefinition(def *statemachine.Definition, guards statemachine.GuardRegistry[ProposalState, ProposalTransition]) (*ProposalStateMachine, error) {
+	if def.Name != WorkflowName {
+		return nil, fmt.Errorf("%w: expected workflow %q, got %q", statemachine.ErrInvalidDefinition, WorkflowName, def.Name)
+	}
+
+	sm, err := statemachine.Build[ProposalState, ProposalTransition](def, guards)
+	if err != nil {
+		return nil, err
+	}
+
+	var approvals []ApprovalSet
+	states := make([]ProposalState, 0, len(def.States))
+	terminal := make(map[ProposalState]bool)
+	metadata := make(map[ProposalState]StateMetadata, len(def.States))
+	for _, s := range def.States {
+		state := ProposalState(s.Name)
+		states = append(states, state)
+		terminal[state] = s.Terminal
+		displayName := s.DisplayName
+		if displayName == "" {
+			displayName = s.Name
+		}
+		metadata[state] = StateMetadata{
+			State:            state,
+			DisplayName:      displayName,
+			Description:      s.Description,
+			RequiredRoles:    s.RequiredRoles,
+			NotificationList: s.NotificationList,
+			SLAHours:         s.SLAHours,
+		}
+		for _, a := range s.Approvals {
+			set, err := approvalSetFromDefinition(s.Name, a)
+			if err != nil {
+				return nil, err
+			}
+			approvals = append(approvals, set)
+		}
+	}
+
+	edits, err := editMatrixFromDefinition(def)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProposalStateMachine{
+		StateMachine: sm,
+		version:      def.Version,
+		states:       states,
+		terminal:     terminal,
+		metadata:     metadata,
+		approvals:    approvals,
+		edits:        edits,
+	}, nil
+}
+
+// WorkflowStore persists the workflow documents and version pins of each
+// tenant.
+type WorkflowStore interface {
+	// SaveDefinition persists a tenant's workflow document.
+	SaveDefinition(ctx context.Context, tenantID common.TenantID, def *statemachine.Definition) error
+
+	// FindDefinition retrieves a version of a tenant's workflow. It returns
+	// nil if the tenant has no such version.
+	FindDefinition(ctx context.Context, tenantID common.TenantID, name string, version int) (*statemachine.Definition, error)
+
+	// ListDefinitions retrieves all versions of a tenant's workflow.
+	ListDefinitions(ctx context.Context, tenantID common.TenantID, name string) ([]*statemachine.Definition, error)
+
+	// ListAllDefinitions retrieves all versions of a workflow for every tenant.
+	ListAllDefinitions(ctx context.Context, name string) (map[common.TenantID][]*statemachine.Definition, error)
+
+	// SavePin pins a tenant to a workflow version.
+	SavePin(ctx context.Context, tenantID common.TenantID, name string, version int) error
+
+	// FindPin retrieves the version of a workflow a tenant is pinned to. It
+	// returns 0 if the tenant is not pinned.
+	FindPin(ctx context.Context, tenantID common.TenantID, name string) (int, error)
+}
+
+// WorkflowRegistry holds the proposal workflow versions known to the process
+// and the version each tenant is pinned to. Versions are numbered per tenant;
+// the built-in version is shared by all of them. With a store, versions
+// published by other processes are loaded on first use and pins are read
+// from the store.
+type WorkflowRegistry struct {
+	mu             sync.RWMutex
+	guards         statemachine.GuardRegistry[ProposalState, ProposalTransition]
+	definitions    map[workflowKey]*statemachine.Definition
+	machines       map[workflowKey]*ProposalStateMachine
+	pins           map[common.TenantID]int
+	defaultVersion int
+	approvalRepo   ApprovalRepository
+	mandatory      []mandatoryGuard
+	store          WorkflowStore
+}
+
+// workflowKey identifies a tenant's workflow version. The built-in version
+// has no tenant.
+type workflowKey struct {
+	tenantID common.TenantID
+	version  int
+}
+
+// builtinKey identifies the built-in workflow version.
+var builtinKey = workflowKey{version: DefaultWorkflowVersion}
+
+// mandatoryGuard is a guard every workflow version must name on a
+// transition. An empty from state requires it on the transition from every
+// state that defines it.
+type mandatoryGuard struct {
+	name       string
+	from       ProposalState
+	transition ProposalTransition
+}
+
+// appliesTo reports whether the guard is required on a document transition.
+func (m mandatoryGuard) appliesTo(t statemachine.TransitionDefinition) bool {
+	return t.Transition == string(m.transition) && (m.from == "" || t.From == string(m.from))
+}
+
+// hasGuard reports whether a document transition names a guard.
+func hasGuard(t statemachine.TransitionDefinition, name string) bool {
+	for _, g := range t.Guards {
+		if g == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWorkflowRegistry creates a registry containing the built-in workflow.
+func NewWorkflowRegistry() *WorkflowRegistry {
+	r := &WorkflowRegistry{
+		guards:         make(statemachine.GuardRegistry[ProposalState, ProposalTransition]),
+		definitions:    make(map[workflowKey]*statemachine.Definition),
+		machines:       make(map[workflowKey]*ProposalStateMachine),
+		pins:           make(map[common.TenantID]int),
+		defaultVersion: DefaultWorkflowVersion,
+	}
+	r.definitions[builtinKey] = DefaultWorkflowDefinition()
+	r.machines[builtinKey] = NewProposalStateMachine()
+	return r
+}
+
+// RegisterGuard makes a named guard available to workflow documents.
+func (r *WorkflowRegistry) RegisterGuard(name string, guard ProposalGuard) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.guards[name] = guard.Func()
+}
+
+// requireGuard registers a guard that every workflow document registered
+// afterwards must name, and lists it on the matching transitions of the
+// built-in workflow document so that copies of it start out compliant.
+func (r *WorkflowRegistry) requireGuard(m mandatoryGuard, guard ProposalGuard) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.guards[m.name] = guard.Func()
+	r.mandatory = append(r.mandatory, m)
+
+	def := r.definitions[builtinKey]
+	for i, t := range def.Transitions {
+		if m.appliesTo(t) && !hasGuard(t, m.name) {
+			def.Transitions[i].Guards = append(def.Transitions[i].Guards, m.name)
+		}
+	}
+}
+
+// checkMandatoryGuards fails if a workflow document drops a mandatory guard
+// from a transition it is required on.
+func (r *WorkflowRegistry) checkMandatoryGuards(def *statemachine.Definition) error {
+	for _, m := range r.mandatory {
+		for _, t := range def.Transitions {
+			if m.appliesTo(t) && !hasGuard(t, m.name) {
+				return fmt.Errorf("%w: transition %s from %s must name guard %q",
+					statemachine.ErrInvalidDefinition, t.Transition, t.From, m.name)
+			}
+		}
+	}
+	return nil
+}
+
+// withMandatoryGuards returns a workflow document that names every mandatory
+// guard on the transitions it is required on. The document is copied only if
+// a guard is missing.
+func (r *WorkflowRegistry) withMandatoryGuards(def *statemachine.Definition) *statemachine.Definition {
+	if r.checkMandatoryGuards(def) == nil {
+		return def
+	}
+
+	copied := *def
+	copied.Transitions = make([]statemachine.TransitionDefinition, len(def.Transitions))
+	for i, t := range def.Transitions {
+		t.Guards = append([]string(nil), t.Guards...)
+		for _, m := range r.mandatory {
+			if m.appliesTo(t) && !hasGuard(t, m.name) {
+				t.Guards = append(t.Guards, m.name)
+			}
+		}
+		copied.Transitions[i] = t
+	}
+	return &copied
+}
+
+// UseComplianceChecker makes the compliance guard mandatory for workflow
+// documents and applies it to the built-in workflow. It must be called
+// before Load.
+func (r *WorkflowRegistry) UseComplianceChecker(checker ComplianceChecker) {
+	r.requireGuard(mandatoryGuard{GuardComplianceComplete, StateCompliance, TransitionAdvanceReview}, ComplianceCompleteGuard(checker))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.machines[builtinKey].RequireCompleteCompliance(checker)
+}
+
+// UseEffortLedger makes the effort guard mandatory for workflow documents
+// and applies it to the built-in workflow. It must be called before Load.
+func (r *WorkflowRegistry) UseEffortLedger(ledger *EffortLedger) {
+	r.requireGuard(mandatoryGuard{GuardEffortWithinLimit, "", TransitionSubmitToSponsor}, EffortWithinLimitGuard(ledger))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.machines[builtinKey].RequireEffortWithinLimit(ledger)
+}
+
+// UseAwardRepository makes the award setup guard mandatory for workflow
+// documents and applies it to the built-in workflow. It must be called
+// before Load.
+func (r *WorkflowRegistry) UseAwardRepository(repo AwardRepository) {
+	r.requireGuard(mandatoryGuard{GuardAwardSetupComplete, "", TransitionActivate}, AwardSetupCompleteGuard(repo))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.machines[builtinKey].RequireAwardSetup(repo)
+}
+
+// UseCloseoutRepository makes the closeout checklist guard mandatory for
+// workflow documents and applies it to the built-in workflow. It must be
+// called before Load.
+func (r *WorkflowRegistry) UseCloseoutRepository(repo CloseoutRepository) {
+	r.requireGuard(mandatoryGuard{GuardCloseoutComplete, "", TransitionClose}, CloseoutCompleteGuard(repo))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.machines[builtinKey].RequireCloseout(repo)
+}
+
+// UseBudgetRepository makes the cost share match guard mandatory for
+// workflow documents and applies it to the built-in workflow. It must be
+// called before Load.
+func (r *WorkflowRegistry) UseBudgetRepository(budgets BudgetFinder) {
+	r.requireGuard(mandatoryGuard{GuardCostShareMatch, StateBudgetReview, TransitionAdvanceReview}, CostShareMatchGuard(budgets))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.machines[builtinKey].RequireCostShareMatch(budgets)
+}
+
+// UseApprovalRepository enforces the approval sets of every workflow version
+// using votes from the repository. It must be called before Load.
+func (r *WorkflowRegistry) UseApprovalRepository(repo ApprovalRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.approvalRepo = repo
+	for _, sm := range r.machines {
+		sm.RequireApprovals(repo)
+	}
+}
+
+// key returns the key of a tenant's workflow version. Version 0 denotes
+// proposals created before versioning and maps to the default.
+func (r *WorkflowRegistry) key(tenantID common.TenantID, version int) workflowKey {
+	if version == 0 {
+		version = r.defaultVersion
+	}
+	if version == DefaultWorkflowVersion {
+		return builtinKey
+	}
+	return workflowKey{tenantID: tenantID, version: version}
+}
+
+// prepare validates a tenant's workflow document and builds its state
+// machine. The document must name every mandatory guard on the transitions
+// it is required on.
+func (r *WorkflowRegistry) prepare(tenantID common.TenantID, def *statemachine.Definition) (workflowKey, *ProposalStateMachine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	k := r.key(tenantID, def.Version)
+	if k == builtinKey {
+		return k, nil, fmt.Errorf("%w: version %d is reserved for the built-in workflow", statemachine.ErrInvalidDefinition, def.Version)
+	}
+	if _, exists := r.definitions[k]; exists {
+		return k, nil, fmt.Errorf("%w: version %d is already registered", statemachine.ErrInvalidDefinition, def.Version)
+	}
+	if err := r.checkMandatoryGuards(def); err != nil {
+		return k, nil, err
+	}
+
+	sm, err := NewProposalStateMachineFromDefinition(def, r.guards)
+	if err != nil {
+		return k, nil, err
+	}
+	if r.approvalRepo != nil {
+		sm.RequireApprovals(r.approvalRepo)
+	}
+	return k, sm, nil
+}
+
+// add registers a prepared workflow version unless it is already
+// registered, and reports whether it did.
+func (r *WorkflowRegistry) add(k workflowKey, def *statemachine.Definition, sm *ProposalStateMachine) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.definitions[k]; exists {
+		return false
+	}
+	r.definitions[k] = def
+	r.machines[k] = sm
+	return true
+}
+
+// Register validates a tenant's workflow document and adds it to the
+// registry without storing it.
+func (r *WorkflowRegistry) Register(tenantID common.TenantID, def *statemachine.Definition) error {
+	k, sm, err := r.prepare(tenantID, def)
+	if err != nil {
+		return err
+	}
+	if !r.add(k, def, sm) {
+		return fmt.Errorf("%w: version %d is already registered", statemachine.ErrInvalidDefinition, def.Version)
+	}
+	return nil
+}
+
+// Publish validates a tenant's workflow document, stores it and adds it to
+// the registry. A document that fails to store is not registered.
+func (r *WorkflowRegistry) Publish(ctx context.Context, tenantID common.TenantID, def *statemachine.Definition) error {
+	k, sm, err := r.prepare(tenantID, def)
+	if err != nil {
+		return err
+	}
+
+	if store := r.workflowStore(); store != nil {
+		if err := store.SaveDefinition(ctx, tenantID, def); err != nil {
+			return fmt.Errorf("failed to store workflow definition: %w", err)
+		}
+	}
+
+	if !r.add(k, def, sm) {
+		return fmt.Errorf("%w: version %d is already registered", statemachine.ErrInvalidDefinition, def.Version)
+	}
+	return nil
+}
+
+// workflowStore returns the store the registry resolves versions and pins
+// from, if any.
+func (r *WorkflowRegistry) workflowStore() WorkflowStore {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store
+}
+
+// resolve returns the key of a tenant's workflow version, loading the
+// version from the store if this process has not registered it yet.
+func (r *WorkflowRegistry) resolve(ctx context.Context, tenantID common.TenantID, version int) (workflowKey, error) {
+	r.mu.RLock()
+	k := r.key(tenantID, version)
+	_, ok := r.definitions[k]
+	store := r.store
+	r.mu.RUnlock()
+
+	if ok {
+		return k, nil
+	}
+	if store == nil {
+		return k, ErrWorkflowVersionNotFound
+	}
+
+	def, err := store.FindDefinition(ctx, tenantID, WorkflowName, k.version)
+	if err != nil {
+		return k, fmt.Errorf("failed to find workflow definition: %w", err)
+	}
+	if def == nil {
+		return k, ErrWorkflowVersionNotFound
+	}
+	if err := r.load(tenantID, def); err != nil {
+		return k, err
+	}
+	return k, nil
+}
+
+// load registers a stored workflow document unless it is already registered.
+// Documents stored before a guard became mandatory get the guard added
+// rather than being rejected.
+func (r *WorkflowRegistry) load(tenantID common.TenantID, def *statemachine.Definition) error {
+	r.mu.RLock()
+	_, exists := r.definitions[r.key(tenantID, def.Version)]
+	def = r.withMandatoryGuards(def)
+	r.mu.RUnlock()
+	if exists {
+		return nil
+	}
+
+	k, sm, err := r.prepare(tenantID, def)
+	if err != nil {
+		return fmt.Errorf("failed to register workflow version %d of tenant %s: %w", def.Version, tenantID, err)
+	}
+	r.add(k, def, sm)
+	return nil
+}
+
+// Definition returns the workflow document for a tenant's version.
+func (r *WorkflowRegistry) Definition(ctx context.Context, tenantID common.TenantID, version int) (*statemachine.Definition, error) {
+	k, err := r.resolve(ctx, tenantID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.definitions[k], nil
+}
+
+// Versions returns the workflow versions available to a tenant in ascending
+// order, including the built-in version.
+func (r *WorkflowRegistry) Versions(ctx context.Context, tenantID common.TenantID) ([]int, error) {
+	if store := r.workflowStore(); store != nil {
+		defs, err := store.ListDefinitions(ctx, tenantID, WorkflowName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow definitions: %w", err)
+		}
+		for _, def := range defs {
+			if err := r.load(tenantID, def); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var versions []int
+	for k := range r.definitions {
+		if k == builtinKey || k.tenantID == tenantID {
+			versions = append(versions, k.version)
+		}
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// Machines returns the state machines of all workflow versions registered
+// in this process.
+func (r *WorkflowRegistry) Machines() []*ProposalStateMachine {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	machines := make([]*ProposalStateMachine, 0, len(r.machines))
+	for _, sm := range r.machines {
+		machines = append(machines, sm)
+	}
+	return machines
+}
+
+// Pin pins a tenant to one of its workflow versions, storing the pin before
+// applying it.
+func (r *WorkflowRegistry) Pin(ctx context.Context, tenantID common.TenantID, version int) error {
+	k, err := r.resolve(ctx, tenantID, version)
+	if err != nil {
+		return err
+	}
+
+	if store := r.workflowStore(); store != nil {
+		if err := store.SavePin(ctx, tenantID, WorkflowName, k.version); err != nil {
+			return fmt.Errorf("failed to store workflow pin: %w", err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pins[tenantID] = k.version
+	return nil
+}
+
+// PinnedVersion returns the workflow version new proposals of a tenant use.
+// With a store, the pin is read from the store so that pins made by other
+// processes apply at once.
+func (r *WorkflowRegistry) PinnedVersion(ctx context.Context, tenantID common.TenantID) (int, error) {
+	if store := r.workflowStore(); store != nil {
+		version, err := store.FindPin(ctx, tenantID, WorkflowName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find workflow pin: %w", err)
+		}
+		if version == 0 {
+			return r.defaultVersion, nil
+		}
+		return version, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if v, ok := r.pins[tenantID]; ok {
+		return v, nil
+	}
+	return r.defaultVersion, nil
+}
+
+// StateMachine returns the state machine for a tenant's workflow version.
+// Version 0 denotes proposals created before versioning and maps to the
+// default.
+func (r *WorkflowRegistry) StateMachine(ctx context.Context, tenantID common.TenantID, version int) (*ProposalStateMachine, error) {
+	k, err := r.resolve(ctx, tenantID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.machines[k], nil
+}
+
+// Load makes the registry resolve versions and pins from the store and
+// registers all stored workflow versions.
+func (r *WorkflowRegistry) Load(ctx context.Context, store WorkflowStore) error {
+	r.mu.Lock()
+	r.store = store
+	r.mu.Unlock()
+
+	return r.Refresh(ctx)
+}
+
+// Refresh registers the stored workflow versions this process has not
+// registered yet.
+func (r *WorkflowRegistry) Refresh(ctx context.Context) error {
+	store := r.workflowStore()
+	if store == nil {
+		return nil
+	}
+
+	defs, err := store.ListAllDefinitions(ctx, WorkflowName)
+	if err != nil {
+		return fmt.Errorf("failed to list workflow definitions: %w", err)
+	}
+	for tenantID, tenantDefs := range defs {
+		for _, def := range tenantDefs {
+			if err := r.load(tenantID, def); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
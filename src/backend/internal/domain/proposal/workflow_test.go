@@ -0,0 +1,200 @@
+package proposal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+)
+
+// fakeWorkflowStore keeps workflow documents and pins in memory, standing in
+// for the store shared by all processes.
+type fakeWorkflowStore struct {
+	defs    map[common.TenantID][]*statemachine.Definition
+	pins    map[common.TenantID]int
+	saveErr error
+}
+
+func newFakeWorkflowStore() *fakeWorkflowStore {
+	return &fakeWorkflowStore{
+		defs: make(map[common.TenantID][]*statemachine.Definition),
+		pins: make(map[common.TenantID]int),
+	}
+}
+
+func (f *fakeWorkflowStore) SaveDefinition(ctx context.Context, tenantID common.TenantID, def *statemachine.Definition) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.defs[tenantID] = append(f.defs[tenantID], def)
+	return nil
+}
+
+func (f *fakeWorkflowStore) FindDefinition(ctx context.Context, tenantID common.TenantID, name string, version int) (*statemachine.Definition, error) {
+	for _, def := range f.defs[tenantID] {
+		if def.Version == version {
+			return def, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeWorkflowStore) ListDefinitions(ctx context.Context, tenantID common.TenantID, name string) ([]*statemachine.Definition, error) {
+	return f.defs[tenantID], nil
+}
+
+func (f *fakeWorkflowStore) ListAllDefinitions(ctx context.Context, name string) (map[common.TenantID][]*statemachine.Definition, error) {
+	return f.defs, nil
+}
+
+func (f *fakeWorkflowStore) SavePin(ctx context.Context, tenantID common.TenantID, name string, version int) error {
+	f.pins[tenantID] = version
+	return nil
+}
+
+func (f *fakeWorkflowStore) FindPin(ctx context.Context, tenantID common.TenantID, name string) (int, error) {
+	return f.pins[tenantID], nil
+}
+
+func TestRegisterRequiresMandatoryGuards(t *testing.T) {
+	r := NewWorkflowRegistry()
+	tenantID := common.TenantID(uuid.New())
+	r.UseAwardRepository(nil)
+
+	def := DefaultWorkflowDefinition()
+	def.Version = 2
+	err := r.Register(tenantID, def)
+	if !errors.Is(err, statemachine.ErrInvalidDefinition) {
+		t.Fatalf("expected a document without %s to be rejected, got %v", GuardAwardSetupComplete, err)
+	}
+
+	served, err := r.Definition(context.Background(), tenantID, DefaultWorkflowVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied := *served
+	copied.Version = 3
+	if err := r.Register(tenantID, &copied); err != nil {
+		t.Fatalf("expected a copy of the served default to register, got %v", err)
+	}
+}
+
+func TestRegisterRequiresCostShareMatch(t *testing.T) {
+	r := NewWorkflowRegistry()
+	tenantID := common.TenantID(uuid.New())
+	r.UseBudgetRepository(nil)
+
+	served, err := r.Definition(context.Background(), tenantID, DefaultWorkflowVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := *served
+	def.Version = 2
+	def.Transitions = nil
+	for _, tr := range served.Transitions {
+		if tr.From == string(StateBudgetReview) && tr.Transition == string(TransitionAdvanceReview) {
+			if !hasGuard(tr, GuardCostShareMatch) {
+				t.Fatalf("served default does not list %s on %s", GuardCostShareMatch, tr.Transition)
+			}
+			tr.Guards = nil
+		}
+		def.Transitions = append(def.Transitions, tr)
+	}
+	if err := r.Register(tenantID, &def); !errors.Is(err, statemachine.ErrInvalidDefinition) {
+		t.Fatalf("expected a document without %s to be rejected, got %v", GuardCostShareMatch, err)
+	}
+}
+
+func TestWorkflowVersionsAreScopedPerTenant(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeWorkflowStore()
+	tenantA := common.TenantID(uuid.New())
+	tenantB := common.TenantID(uuid.New())
+
+	publisher := NewWorkflowRegistry()
+	if err := publisher.Load(ctx, store); err != nil {
+		t.Fatal(err)
+	}
+
+	store.saveErr = errors.New("database unavailable")
+	def := DefaultWorkflowDefinition()
+	def.Version = 2
+	if err := publisher.Publish(ctx, tenantA, def); err == nil {
+		t.Fatal("expected the publish to fail with the store")
+	}
+	if _, err := publisher.StateMachine(ctx, tenantA, 2); !errors.Is(err, ErrWorkflowVersionNotFound) {
+		t.Fatalf("expected a version that failed to store to be unregistered, got %v", err)
+	}
+
+	store.saveErr = nil
+	if err := publisher.Publish(ctx, tenantA, def); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.Pin(ctx, tenantA, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.Pin(ctx, tenantB, 2); !errors.Is(err, ErrWorkflowVersionNotFound) {
+		t.Fatalf("expected another tenant's version to be hidden, got %v", err)
+	}
+
+	other := *def
+	other.Description = "Tenant B lifecycle"
+	if err := publisher.Publish(ctx, tenantB, &other); err != nil {
+		t.Fatalf("expected tenant B to reuse version 2, got %v", err)
+	}
+
+	// A second process resolves versions and pins from the store
+	replica := NewWorkflowRegistry()
+	replica.store = store
+	if _, err := replica.StateMachine(ctx, tenantA, 2); err != nil {
+		t.Fatalf("expected the replica to load the stored version, got %v", err)
+	}
+	if v, err := replica.PinnedVersion(ctx, tenantA); err != nil || v != 2 {
+		t.Errorf("PinnedVersion(A) = %d, %v, want 2", v, err)
+	}
+	if v, err := replica.PinnedVersion(ctx, tenantB); err != nil || v != DefaultWorkflowVersion {
+		t.Errorf("PinnedVersion(B) = %d, %v, want %d", v, err, DefaultWorkflowVersion)
+	}
+	versions, err := replica.Versions(ctx, tenantB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0] != DefaultWorkflowVersion || versions[1] != 2 {
+		t.Errorf("Versions(B) = %v, want [1 2]", versions)
+	}
+}
+
+func TestLoadAddsMandatoryGuardsToStoredDocuments(t *testing.T) {
+	ctx := context.Background()
+	tenantID := common.TenantID(uuid.New())
+
+	// Stored before the award setup guard became mandatory
+	legacy := DefaultWorkflowDefinition()
+	legacy.Version = 2
+	store := newFakeWorkflowStore()
+	store.defs[tenantID] = []*statemachine.Definition{legacy}
+
+	r := NewWorkflowRegistry()
+	r.UseAwardRepository(nil)
+	if err := r.Load(ctx, store); err != nil {
+		t.Fatalf("expected a legacy document to load, got %v", err)
+	}
+
+	def, err := r.Definition(ctx, tenantID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tr := range def.Transitions {
+		if tr.Transition == string(TransitionActivate) && !hasGuard(tr, GuardAwardSetupComplete) {
+			t.Errorf("transition %s from %s does not name %s", tr.Transition, tr.From, GuardAwardSetupComplete)
+		}
+	}
+	for _, tr := range legacy.Transitions {
+		if hasGuard(tr, GuardAwardSetupComplete) {
+			t.Fatal("expected the stored document to be left unchanged")
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package postgres provides PostgreSQL advisory locks for leader election.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdvisoryLock is a session-level PostgreSQL advisory lock. The lock is held
+// on a dedicated connection, so it is released automatically if the process
+// dies or the connection drops.
+type AdvisoryLock struct {
+	pool *Pool
+	key  int64
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn
+}
+
+// NewAdvisoryLock creates an advisory lock keyed by name.
+func NewAdvisoryLock(pool *Pool, name string) *AdvisoryLock {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return &AdvisoryLock{pool: pool, key: int64(h.Sum64())}
+}
+
+// TryAcquire takes the lock without blocking and reports whether it is held.
+// Calling it while holding the lock verifies the connection is still alive.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.Ping(ctx); err == nil {
+			return true, nil
+		}
+		// The session may still hold the lock; close it so the lock is
+		// freed rather than returned to the pool
+		destroyLockConn(ctx, l.conn)
+		l.conn = nil
+	}
+
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked); err != nil {
+		destroyLockConn(ctx, conn)
+		return false, fmt.Errorf("failed to acquire advisory lock: %w", err)
+	}
+	if !locked {
+		conn.Release()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release unlocks the lock if it is held.
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	conn := l.conn
+	l.conn = nil
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		destroyLockConn(ctx, conn)
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	conn.Release()
+	return nil
+}
+
+// destroyLockConn closes a connection that may hold the lock and removes it
+// from the pool. Closing the session releases its advisory locks.
+func destroyLockConn(ctx context.Context, conn *pgxpool.Conn) {
+	_ = conn.Conn().Close(ctx)
+	conn.Release()
+}
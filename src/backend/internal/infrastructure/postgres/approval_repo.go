@@ -0,0 +1,92 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// ApprovalRepository implements the proposal.ApprovalRepository interface.
+type ApprovalRepository struct {
+	pool *Pool
+}
+
+// NewApprovalRepository creates a new approval repository.
+func NewApprovalRepository(pool *Pool) *ApprovalRepository {
+	return &ApprovalRepository{pool: pool}
+}
+
+// SaveApproval persists an approval vote.
+func (r *ApprovalRepository) SaveApproval(ctx context.Context, a *proposal.Approval) error {
+	query := `
+		INSERT INTO proposal_approvals (
+			id, tenant_id, proposal_id, state, transition, approver_id,
+			on_behalf_of, role, decision, comment, decided_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		a.ID,
+		uuid.UUID(a.TenantID),
+		a.ProposalID,
+		a.State.String(),
+		string(a.Transition),
+		a.ApproverID,
+		a.OnBehalfOf,
+		a.Role,
+		string(a.Decision),
+		a.Comment,
+		a.DecidedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save approval: %w", err)
+	}
+
+	return nil
+}
+
+// FindApprovals retrieves all votes of a proposal ordered by decision time.
+func (r *ApprovalRepository) FindApprovals(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) ([]proposal.Approval, error) {
+	query := `
+		SELECT id, tenant_id, proposal_id, state, transition, approver_id,
+			on_behalf_of, role, decision, COALESCE(comment, ''), decided_at
+		FROM proposal_approvals
+		WHERE tenant_id = $1 AND proposal_id = $2
+		ORDER BY decided_at ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), proposalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := make([]proposal.Approval, 0)
+	for rows.Next() {
+		var a proposal.Approval
+		var tenant uuid.UUID
+		if err := rows.Scan(
+			&a.ID,
+			&tenant,
+			&a.ProposalID,
+			&a.State,
+			&a.Transition,
+			&a.ApproverID,
+			&a.OnBehalfOf,
+			&a.Role,
+			&a.Decision,
+			&a.Comment,
+			&a.DecidedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan approval: %w", err)
+		}
+		a.TenantID = common.TenantID(tenant)
+		approvals = append(approvals, a)
+	}
+
+	return approvals, rows.Err()
+}
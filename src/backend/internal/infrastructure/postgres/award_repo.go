@@ -0,0 +1,222 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+)
+
+// AwardRepository implements the proposal.AwardRepository interface.
+type AwardRepository struct {
+	pool *Pool
+}
+
+// NewAwardRepository creates a new award repository.
+func NewAwardRepository(pool *Pool) *AwardRepository {
+	return &AwardRepository{pool: pool}
+}
+
+// SaveAward persists an award, its new modifications and its uncommitted
+// events in one transaction. Saving an award that changed since it was
+// loaded fails with proposal.ErrVersionMismatch.
+func (r *AwardRepository) SaveAward(ctx context.Context, a *proposal.Award) error {
+	periodsJSON, err := json.Marshal(a.Periods)
+	if err != nil {
+		return fmt.Errorf("failed to marshal budget periods: %w", err)
+	}
+	termsJSON, err := json.Marshal(a.Terms)
+	if err != nil {
+		return fmt.Errorf("failed to marshal terms: %w", err)
+	}
+	reportingJSON, err := json.Marshal(a.ReportingRequirements)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reporting requirements: %w", err)
+	}
+
+	query := `
+		INSERT INTO awards (
+			id, tenant_id, proposal_id, sponsor_id, sponsor_award_number, award_date,
+			start_date, end_date, budget_periods, terms, reporting_requirements,
+			total_obligated, total_anticipated, setup_completed_at, setup_completed_by,
+			created_at, updated_at, created_by, updated_by, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		ON CONFLICT (id) DO UPDATE SET
+			sponsor_award_number = EXCLUDED.sponsor_award_number,
+			award_date = EXCLUDED.award_date,
+			start_date = EXCLUDED.start_date,
+			end_date = EXCLUDED.end_date,
+			budget_periods = EXCLUDED.budget_periods,
+			terms = EXCLUDED.terms,
+			reporting_requirements = EXCLUDED.reporting_requirements,
+			total_obligated = EXCLUDED.total_obligated,
+			total_anticipated = EXCLUDED.total_anticipated,
+			setup_completed_at = EXCLUDED.setup_completed_at,
+			setup_completed_by = EXCLUDED.setup_completed_by,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			version = EXCLUDED.version
+		WHERE awards.version < EXCLUDED.version
+	`
+
+	return r.pool.WithTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query,
+			a.ID,
+			uuid.UUID(a.TenantID),
+			a.ProposalID,
+			a.SponsorID,
+			a.SponsorAwardNumber,
+			a.AwardDate,
+			nullDate(a.ProjectPeriod.StartDate),
+			nullDate(a.ProjectPeriod.EndDate),
+			periodsJSON,
+			termsJSON,
+			reportingJSON,
+			a.TotalObligated(),
+			a.TotalAnticipated(),
+			a.SetupCompletedAt,
+			a.SetupCompletedBy,
+			a.CreatedAt,
+			a.UpdatedAt,
+			a.CreatedBy,
+			a.UpdatedBy,
+			a.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save award: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return proposal.ErrVersionMismatch
+		}
+
+		for _, m := range a.Modifications {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO award_modifications (
+					id, tenant_id, award_id, number, modification_type, period_number, amount,
+					new_end_date, effective_date, sponsor_reference, reason, recorded_by, recorded_at
+				) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), $12, $13)
+				ON CONFLICT (id) DO NOTHING`,
+				m.ID, uuid.UUID(a.TenantID), a.ID, m.Number, string(m.Type), m.PeriodNumber, m.Amount,
+				m.NewEndDate, m.EffectiveDate, m.SponsorReference, m.Reason, m.RecordedBy, m.RecordedAt)
+			if err != nil {
+				return fmt.Errorf("failed to save award modification: %w", err)
+			}
+		}
+
+		for _, event := range a.GetUncommittedEvents() {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s event: %w", event.EventType(), err)
+			}
+			_, err = tx.Exec(ctx, `
+				INSERT INTO domain_events (id, tenant_id, aggregate_type, aggregate_id, event_type, event_data, version, occurred_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				event.EventID(), uuid.UUID(event.TenantID()), event.AggregateType(), event.AggregateID(),
+				event.EventType(), data, event.Version(), event.OccurredAt())
+			if err != nil {
+				return fmt.Errorf("failed to append %s event: %w", event.EventType(), err)
+			}
+		}
+
+		a.ClearUncommittedEvents()
+		return nil
+	})
+}
+
+// awardColumns are the columns scanned by scanAward.
+const awardColumns = `id, tenant_id, proposal_id, sponsor_id, sponsor_award_number, award_date,
+	start_date, end_date, budget_periods, terms, reporting_requirements,
+	setup_completed_at, setup_completed_by, created_at, updated_at, created_by, updated_by, version`
+
+// FindAward retrieves an award by ID.
+func (r *AwardRepository) FindAward(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*proposal.Award, error) {
+	query := `SELECT ` + awardColumns + ` FROM awards WHERE id = $1 AND tenant_id = $2`
+	return r.findAward(ctx, tenantID, query, id)
+}
+
+// FindAwardByProposal retrieves the award of a proposal.
+func (r *AwardRepository) FindAwardByProposal(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) (*proposal.Award, error) {
+	query := `SELECT ` + awardColumns + ` FROM awards WHERE proposal_id = $1 AND tenant_id = $2`
+	return r.findAward(ctx, tenantID, query, proposalID)
+}
+
+// findAward loads the award matched by a query and its modifications.
+func (r *AwardRepository) findAward(ctx context.Context, tenantID common.TenantID, query string, id uuid.UUID) (*proposal.Award, error) {
+	a, err := scanAward(r.pool.QueryRow(ctx, query, id, uuid.UUID(tenantID)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load award: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, number, modification_type, COALESCE(period_number, 0), amount, new_end_date,
+			effective_date, COALESCE(sponsor_reference, ''), COALESCE(reason, ''), recorded_by, recorded_at
+		FROM award_modifications
+		WHERE award_id = $1 AND tenant_id = $2
+		ORDER BY number`, a.ID, uuid.UUID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query award modifications: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m proposal.AwardModification
+		var modType string
+		if err := rows.Scan(&m.ID, &m.Number, &modType, &m.PeriodNumber, &m.Amount, &m.NewEndDate,
+			&m.EffectiveDate, &m.SponsorReference, &m.Reason, &m.RecordedBy, &m.RecordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan award modification: %w", err)
+		}
+		m.Type = proposal.ModificationType(modType)
+		a.Modifications = append(a.Modifications, m)
+	}
+
+	return a, rows.Err()
+}
+
+// scanAward scans a row of awardColumns.
+func scanAward(row pgx.Row) (*proposal.Award, error) {
+	var a proposal.Award
+	var tenantUUID uuid.UUID
+	var startDate, endDate *time.Time
+	var periodsJSON, termsJSON, reportingJSON []byte
+	if err := row.Scan(&a.ID, &tenantUUID, &a.ProposalID, &a.SponsorID, &a.SponsorAwardNumber, &a.AwardDate,
+		&startDate, &endDate, &periodsJSON, &termsJSON, &reportingJSON,
+		&a.SetupCompletedAt, &a.SetupCompletedBy, &a.CreatedAt, &a.UpdatedAt, &a.CreatedBy, &a.UpdatedBy, &a.Version); err != nil {
+		return nil, err
+	}
+	a.TenantID = common.TenantID(tenantUUID)
+	if startDate != nil {
+		a.ProjectPeriod.StartDate = *startDate
+	}
+	if endDate != nil {
+		a.ProjectPeriod.EndDate = *endDate
+	}
+	if err := json.Unmarshal(periodsJSON, &a.Periods); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal budget periods: %w", err)
+	}
+	if err := json.Unmarshal(termsJSON, &a.Terms); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal terms: %w", err)
+	}
+	if err := json.Unmarshal(reportingJSON, &a.ReportingRequirements); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reporting requirements: %w", err)
+	}
+	a.Modifications = make([]proposal.AwardModification, 0)
+	return &a, nil
+}
+
+// nullDate returns nil for the zero time so it is stored as NULL.
+func nullDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
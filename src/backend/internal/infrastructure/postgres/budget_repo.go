@@ -0,0 +1,647 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// budgetCategoryIDs are the system budget categories of 004_budgets.sql line
+// items of each cost type are filed under.
+var budgetCategoryIDs = map[budget.CostType]uuid.UUID{
+	budget.CostTypePersonnel:   uuid.MustParse("a0000001-0001-0001-0001-000000000001"),
+	budget.CostTypeEquipment:   uuid.MustParse("a0000001-0001-0001-0001-000000000003"),
+	budget.CostTypeTravel:      uuid.MustParse("a0000001-0001-0001-0001-000000000004"),
+	budget.CostTypeSupplies:    uuid.MustParse("a0000001-0001-0001-0001-000000000006"),
+	budget.CostTypeContractual: uuid.MustParse("a0000001-0001-0001-0001-000000000007"),
+	budget.CostTypeOther:       uuid.MustParse("a0000001-0001-0001-0001-000000000009"),
+	budget.CostTypeSubaward:    uuid.MustParse("a0000001-0001-0001-0001-000000000007"),
+}
+
+// BudgetRepository implements the ports.BudgetRepository interface.
+type BudgetRepository struct {
+	pool *Pool
+}
+
+// NewBudgetRepository creates a new budget repository.
+func NewBudgetRepository(pool *Pool) *BudgetRepository {
+	return &BudgetRepository{pool: pool}
+}
+
+// Save persists a budget with its periods, line items and subawards in one
+// transaction, together with the totals the budget calculator derives.
+// Saving a budget that changed since it was loaded fails with
+// proposal.ErrVersionMismatch.
+func (r *BudgetRepository) Save(ctx context.Context, b *budget.Budget) error {
+	faRateJSON, err := json.Marshal(b.FARate)
+	if err != nil {
+		return fmt.Errorf("failed to marshal F&A rate: %w", err)
+	}
+	exclusionsJSON, err := json.Marshal(b.FARate.ExcludedItems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal MTDC exclusions: %w", err)
+	}
+
+	calc := budget.NewCalculator(b.FARate)
+	totals := calc.CalculatePeriodTotals(b)
+	costShare := calc.CalculateCostShare(b)
+	direct, indirect := decimal.Zero, decimal.Zero
+	for _, t := range totals {
+		direct = direct.Add(t.DirectCosts)
+		indirect = indirect.Add(t.IndirectCosts)
+	}
+
+	query := `
+		INSERT INTO proposal_budgets (
+			id, proposal_id, tenant_id, currency, total_direct_costs, total_indirect_costs, total_budget,
+			indirect_cost_rate, indirect_cost_base, mtdc_exclusions, status, fa_rate, notes,
+			submitted_at, approved_at, approved_by, created_at, updated_at, created_by, updated_by, version,
+			rate_agreement_id, activity_type, is_modular, modular_increment, total_cost_sharing, required_match_percent
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''),
+			$14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
+		ON CONFLICT (id) DO UPDATE SET
+			currency = EXCLUDED.currency,
+			total_direct_costs = EXCLUDED.total_direct_costs,
+			total_indirect_costs = EXCLUDED.total_indirect_costs,
+			total_budget = EXCLUDED.total_budget,
+			indirect_cost_rate = EXCLUDED.indirect_cost_rate,
+			indirect_cost_base = EXCLUDED.indirect_cost_base,
+			mtdc_exclusions = EXCLUDED.mtdc_exclusions,
+			status = EXCLUDED.status,
+			fa_rate = EXCLUDED.fa_rate,
+			notes = EXCLUDED.notes,
+			submitted_at = EXCLUDED.submitted_at,
+			approved_at = EXCLUDED.approved_at,
+			approved_by = EXCLUDED.approved_by,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			version = EXCLUDED.version,
+			rate_agreement_id = EXCLUDED.rate_agreement_id,
+			activity_type = EXCLUDED.activity_type,
+			is_modular = EXCLUDED.is_modular,
+			modular_increment = EXCLUDED.modular_increment,
+			total_cost_sharing = EXCLUDED.total_cost_sharing,
+			required_match_percent = EXCLUDED.required_match_percent
+		WHERE proposal_budgets.version < EXCLUDED.version
+	`
+
+	return r.pool.WithTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query,
+			b.ID, b.ProposalID, uuid.UUID(b.TenantID), b.Currency, direct, indirect, direct.Add(indirect),
+			appliedFARate(b.FARate), indirectCostBase(b.FARate.RateType), exclusionsJSON,
+			strings.ToLower(string(b.Status)), faRateJSON, b.Notes,
+			b.SubmittedAt, b.ApprovedAt, b.ApprovedBy, b.CreatedAt, b.UpdatedAt, b.CreatedBy, b.UpdatedBy, b.Version,
+			b.RateAgreementID, string(activityType(b.ActivityType)), b.IsModular, modularIncrement(b),
+			costShare.Total, b.RequiredMatchPercent)
+		if err != nil {
+			return fmt.Errorf("failed to save budget: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return proposal.ErrVersionMismatch
+		}
+
+		// Periods are replaced in order so renumbered periods never collide
+		periodIDs := make([]uuid.UUID, len(b.Periods))
+		for i, period := range b.Periods {
+			periodIDs[i] = period.ID
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM budget_periods WHERE budget_id = $1 AND NOT (id = ANY($2))`, b.ID, periodIDs); err != nil {
+			return fmt.Errorf("failed to remove budget periods: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM budget_line_items
+			WHERE period_id IN (SELECT id FROM budget_periods WHERE budget_id = $1)`, b.ID); err != nil {
+			return fmt.Errorf("failed to clear budget line items: %w", err)
+		}
+
+		for i := range b.Periods {
+			period := &b.Periods[i]
+			_, err := tx.Exec(ctx, `
+				INSERT INTO budget_periods (
+					id, budget_id, period_number, name, start_date, end_date, direct_costs, indirect_costs, cost_sharing, total
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				ON CONFLICT (id) DO UPDATE SET
+					period_number = EXCLUDED.period_number,
+					name = EXCLUDED.name,
+					start_date = EXCLUDED.start_date,
+					end_date = EXCLUDED.end_date,
+					direct_costs = EXCLUDED.direct_costs,
+					indirect_costs = EXCLUDED.indirect_costs,
+					cost_sharing = EXCLUDED.cost_sharing,
+					total = EXCLUDED.total`,
+				period.ID, b.ID, period.PeriodNumber, fmt.Sprintf("Period %d", period.PeriodNumber),
+				period.StartDate, period.EndDate, totals[i].DirectCosts, totals[i].IndirectCosts, costShare.Periods[i].Total, totals[i].Total)
+			if err != nil {
+				return fmt.Errorf("failed to save budget period %d: %w", period.PeriodNumber, err)
+			}
+
+			for j, item := range period.LineItems() {
+				if err := saveLineItem(ctx, tx, period.ID, j, item); err != nil {
+					return err
+				}
+			}
+		}
+
+		return saveSubawards(ctx, tx, b)
+	})
+}
+
+// saveLineItem inserts a line item. The generic columns are filled for
+// reporting; details keeps the fields of the item's cost type and its cost
+// share.
+func saveLineItem(ctx context.Context, tx pgx.Tx, periodID uuid.UUID, position int, item budget.LineItem) error {
+	var details interface{}
+	quantity, unitCost := decimal.NewFromInt(1), item.TotalCost()
+	var justification, subawardOrg, subawardPI string
+	var personID *uuid.UUID
+	var personMonths, baseSalary, fringeRate, fringeAmount *decimal.Decimal
+	excludedFromIDC := false
+
+	switch item.Type {
+	case budget.CostTypePersonnel:
+		p := item.Personnel
+		details = p
+		months := p.CalendarMonths.Add(p.AcademicMonths).Add(p.SummerMonths)
+		personID, personMonths, baseSalary, fringeRate, fringeAmount = p.PersonID, &months, &p.BaseSalary, &p.FringeRate, &p.FringeBenefits
+	case budget.CostTypeEquipment:
+		details = item.Equipment
+		quantity, unitCost = decimal.NewFromInt(int64(item.Equipment.Quantity)), item.Equipment.UnitCost
+		justification = item.Equipment.Justification
+		excludedFromIDC = true
+	case budget.CostTypeTravel:
+		details = item.Travel
+	case budget.CostTypeSupplies:
+		details = item.Supplies
+	case budget.CostTypeContractual:
+		details = item.Contractual
+	case budget.CostTypeOther:
+		details = item.Other
+	case budget.CostTypeSubaward:
+		details = item.Subaward
+		subawardOrg, subawardPI = item.Subaward.Organization, item.Subaward.PIName
+	}
+
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal line item: %w", err)
+	}
+	costShareAmount, costShareType, costShareSource := lineItemCostShare(item.CostShares())
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO budget_line_items (
+			id, period_id, category_id, cost_type, description, quantity, unit_cost, justification,
+			person_id, person_months, base_salary, fringe_rate, fringe_amount,
+			subaward_organization, subaward_pi, sort_order, is_excluded_from_idc, details,
+			sponsor_amount, cost_share_amount, cost_share_type, cost_share_source
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11, $12, $13,
+			NULLIF($14, ''), NULLIF($15, ''), $16, $17, $18, $19, $20, NULLIF($21, ''), NULLIF($22, ''))`,
+		item.ID(), periodID, budgetCategoryIDs[item.Type], string(item.Type), item.Description(), quantity, unitCost, justification,
+		personID, personMonths, baseSalary, fringeRate, fringeAmount,
+		subawardOrg, subawardPI, position, excludedFromIDC, detailsJSON,
+		item.TotalCost(), costShareAmount, costShareType, costShareSource)
+	if err != nil {
+		return fmt.Errorf("failed to save budget line item: %w", err)
+	}
+	return nil
+}
+
+// lineItemCostShare sums the cost share of a line item for the generic
+// columns. The type and source are only filled when every entry shares them.
+func lineItemCostShare(shares []budget.CostShare) (decimal.Decimal, string, string) {
+	amount := decimal.Zero
+	var shareType, source string
+	for i, cs := range shares {
+		amount = amount.Add(cs.Amount)
+		if i == 0 {
+			shareType, source = string(cs.Type), cs.Source
+			continue
+		}
+		if string(cs.Type) != shareType {
+			shareType = ""
+		}
+		if cs.Source != source {
+			source = ""
+		}
+	}
+	return amount, shareType, source
+}
+
+// subawardTotal sums the subaward line items of one subrecipient.
+type subawardTotal struct {
+	key          string
+	organization string
+	piName       string
+	total        decimal.Decimal
+	firstYear    decimal.Decimal
+	start, end   time.Time
+}
+
+// subrecipientKeySQL is budget.SubawardCost.SubrecipientKey of a subawards row.
+const subrecipientKeySQL = `lower(regexp_replace(btrim(organization_name), '\s+', ' ', 'g'))`
+
+// saveSubawards keeps one subawards row per subrecipient in step with the
+// subaward line items. Rows of subrecipients still in the budget keep their
+// review status and documents.
+func saveSubawards(ctx context.Context, tx pgx.Tx, b *budget.Budget) error {
+	var subawards []*subawardTotal
+	bySubrecipient := make(map[string]*subawardTotal)
+	for _, period := range b.Periods {
+		for _, s := range period.Subawards {
+			key := s.SubrecipientKey()
+			t, ok := bySubrecipient[key]
+			if !ok {
+				t = &subawardTotal{key: key, organization: strings.TrimSpace(s.Organization), start: period.StartDate}
+				bySubrecipient[key] = t
+				subawards = append(subawards, t)
+			}
+			if s.PIName != "" {
+				t.piName = s.PIName
+			}
+			t.total = t.total.Add(s.TotalCost)
+			if period.PeriodNumber == 1 {
+				t.firstYear = t.firstYear.Add(s.TotalCost)
+			}
+			t.end = period.EndDate
+		}
+	}
+
+	keys := make([]string, len(subawards))
+	for i, t := range subawards {
+		keys[i] = t.key
+		result, err := tx.Exec(ctx, `
+			UPDATE subawards SET
+				organization_name = $8, pi_name = $3, total_amount = $4, first_year_amount = $5,
+				performance_period_start = $6, performance_period_end = $7
+			WHERE budget_id = $1 AND `+subrecipientKeySQL+` = $2`,
+			b.ID, t.key, t.piName, t.total, t.firstYear, t.start, t.end, t.organization)
+		if err != nil {
+			return fmt.Errorf("failed to update subaward: %w", err)
+		}
+		if result.RowsAffected() > 0 {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO subawards (
+				budget_id, organization_name, pi_name, total_amount, first_year_amount,
+				performance_period_start, performance_period_end
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			b.ID, t.organization, t.piName, t.total, t.firstYear, t.start, t.end); err != nil {
+			return fmt.Errorf("failed to save subaward: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM subawards WHERE budget_id = $1 AND NOT (`+subrecipientKeySQL+` = ANY($2))`, b.ID, keys); err != nil {
+		return fmt.Errorf("failed to remove subawards: %w", err)
+	}
+	return nil
+}
+
+// budgetColumns are the columns scanned by scanBudget.
+const budgetColumns = `id, proposal_id, tenant_id, COALESCE(currency, 'USD'), COALESCE(status, 'draft'), fa_rate,
+	COALESCE(notes, ''), submitted_at, approved_at, approved_by, created_at, updated_at, created_by, updated_by, version,
+	rate_agreement_id, activity_type, COALESCE(is_modular, FALSE), COALESCE(required_match_percent, 0)`
+
+// FindByID retrieves a budget by ID. Archived budgets are not found.
+func (r *BudgetRepository) FindByID(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*budget.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM proposal_budgets WHERE id = $1 AND tenant_id = $2 AND status <> 'archived'`
+	return r.findOne(ctx, query, id, uuid.UUID(tenantID))
+}
+
+// FindByProposalID retrieves the current budget of a proposal.
+func (r *BudgetRepository) FindByProposalID(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) (*budget.Budget, error) {
+	query := `
+		SELECT ` + budgetColumns + `
+		FROM proposal_budgets
+		WHERE proposal_id = $1 AND tenant_id = $2 AND status <> 'archived'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	return r.findOne(ctx, query, proposalID, uuid.UUID(tenantID))
+}
+
+// findOne loads the budget a query selects, or nil.
+func (r *BudgetRepository) findOne(ctx context.Context, query string, args ...interface{}) (*budget.Budget, error) {
+	b, err := scanBudget(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load budget: %w", err)
+	}
+
+	if err := r.loadPeriods(ctx, []*budget.Budget{b}); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Delete archives a budget. Its periods and line items are kept.
+func (r *BudgetRepository) Delete(ctx context.Context, tenantID common.TenantID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE proposal_budgets SET status = 'archived', version = version + 1
+		WHERE id = $1 AND tenant_id = $2 AND status <> 'archived'`, id, uuid.UUID(tenantID))
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return budget.ErrBudgetNotFound
+	}
+	return nil
+}
+
+// List retrieves budgets with filtering and pagination, newest first.
+// Archived budgets are only listed when asked for by status.
+func (r *BudgetRepository) List(ctx context.Context, tenantID common.TenantID, filter ports.BudgetListFilter) ([]*budget.Budget, int64, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{uuid.UUID(tenantID)}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = strings.ToLower(string(s))
+		}
+		args = append(args, statuses)
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	} else {
+		conditions = append(conditions, "status <> 'archived'")
+	}
+
+	if len(filter.ProposalIDs) > 0 {
+		args = append(args, filter.ProposalIDs)
+		conditions = append(conditions, fmt.Sprintf("proposal_id = ANY($%d)", len(args)))
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int64
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM proposal_budgets WHERE "+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count budgets: %w", err)
+	}
+
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	limit := filter.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`SELECT %s FROM proposal_budgets WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		budgetColumns, whereClause, len(args)-1, len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query budgets: %w", err)
+	}
+	defer rows.Close()
+
+	budgets := make([]*budget.Budget, 0)
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan budget: %w", err)
+		}
+		budgets = append(budgets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadPeriods(ctx, budgets); err != nil {
+		return nil, 0, err
+	}
+	return budgets, total, nil
+}
+
+// loadPeriods attaches the periods and line items of budgets.
+func (r *BudgetRepository) loadPeriods(ctx context.Context, budgets []*budget.Budget) error {
+	if len(budgets) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(budgets))
+	byID := make(map[uuid.UUID]*budget.Budget, len(budgets))
+	for i, b := range budgets {
+		ids[i] = b.ID
+		byID[b.ID] = b
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, budget_id, period_number, start_date, end_date
+		FROM budget_periods
+		WHERE budget_id = ANY($1)
+		ORDER BY budget_id, period_number`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to query budget periods: %w", err)
+	}
+	defer rows.Close()
+
+	type periodRef struct {
+		budget *budget.Budget
+		index  int
+	}
+	periods := make(map[uuid.UUID]periodRef)
+	for rows.Next() {
+		var period budget.BudgetPeriod
+		var budgetID uuid.UUID
+		if err := rows.Scan(&period.ID, &budgetID, &period.PeriodNumber, &period.StartDate, &period.EndDate); err != nil {
+			return fmt.Errorf("failed to scan budget period: %w", err)
+		}
+		b := byID[budgetID]
+		periods[period.ID] = periodRef{budget: b, index: len(b.Periods)}
+		b.Periods = append(b.Periods, period)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	itemRows, err := r.pool.Query(ctx, `
+		SELECT li.period_id, li.id, li.cost_type, li.details, li.description, COALESCE(li.total_cost, 0)
+		FROM budget_line_items li
+		JOIN budget_periods bp ON bp.id = li.period_id
+		WHERE bp.budget_id = ANY($1)
+		ORDER BY li.period_id, li.sort_order`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to query budget line items: %w", err)
+	}
+	defer itemRows.Close()
+
+	items := make(map[uuid.UUID][]budget.LineItem)
+	for itemRows.Next() {
+		var periodID, id uuid.UUID
+		var costType *string
+		var detailsJSON []byte
+		var description string
+		var totalCost decimal.Decimal
+		if err := itemRows.Scan(&periodID, &id, &costType, &detailsJSON, &description, &totalCost); err != nil {
+			return fmt.Errorf("failed to scan budget line item: %w", err)
+		}
+		item, err := decodeLineItem(id, costType, detailsJSON, description, totalCost)
+		if err != nil {
+			return err
+		}
+		items[periodID] = append(items[periodID], item)
+	}
+	if err := itemRows.Err(); err != nil {
+		return err
+	}
+
+	for periodID, ref := range periods {
+		ref.budget.Periods[ref.index].SetLineItems(items[periodID])
+	}
+	return nil
+}
+
+// decodeLineItem rebuilds a line item from its details. Rows written before
+// line items kept their cost type become other costs.
+func decodeLineItem(id uuid.UUID, costType *string, detailsJSON []byte, description string, totalCost decimal.Decimal) (budget.LineItem, error) {
+	if costType == nil || detailsJSON == nil {
+		return budget.LineItem{
+			Type:  budget.CostTypeOther,
+			Other: &budget.OtherCost{ID: id, Description: description, TotalCost: totalCost},
+		}, nil
+	}
+
+	item := budget.LineItem{Type: budget.CostType(*costType)}
+	var target interface{}
+	switch item.Type {
+	case budget.CostTypePersonnel:
+		item.Personnel = &budget.PersonnelCost{}
+		target = item.Personnel
+	case budget.CostTypeEquipment:
+		item.Equipment = &budget.EquipmentCost{}
+		target = item.Equipment
+	case budget.CostTypeTravel:
+		item.Travel = &budget.TravelCost{}
+		target = item.Travel
+	case budget.CostTypeSupplies:
+		item.Supplies = &budget.SupplyCost{}
+		target = item.Supplies
+	case budget.CostTypeContractual:
+		item.Contractual = &budget.ContractualCost{}
+		target = item.Contractual
+	case budget.CostTypeOther:
+		item.Other = &budget.OtherCost{}
+		target = item.Other
+	case budget.CostTypeSubaward:
+		item.Subaward = &budget.SubawardCost{}
+		target = item.Subaward
+	default:
+		return budget.LineItem{}, fmt.Errorf("unknown budget cost type %q", *costType)
+	}
+	if err := json.Unmarshal(detailsJSON, target); err != nil {
+		return budget.LineItem{}, fmt.Errorf("failed to unmarshal %s line item: %w", *costType, err)
+	}
+
+	// The row ID is authoritative
+	switch item.Type {
+	case budget.CostTypePersonnel:
+		item.Personnel.ID = id
+	case budget.CostTypeEquipment:
+		item.Equipment.ID = id
+	case budget.CostTypeTravel:
+		item.Travel.ID = id
+	case budget.CostTypeSupplies:
+		item.Supplies.ID = id
+	case budget.CostTypeContractual:
+		item.Contractual.ID = id
+	case budget.CostTypeOther:
+		item.Other.ID = id
+	case budget.CostTypeSubaward:
+		item.Subaward.ID = id
+	}
+	return item, nil
+}
+
+// scanBudget scans a row of budgetColumns.
+func scanBudget(row pgx.Row) (*budget.Budget, error) {
+	var b budget.Budget
+	var tenantUUID uuid.UUID
+	var status, activity string
+	var faRateJSON []byte
+	var createdBy, updatedBy *uuid.UUID
+	if err := row.Scan(&b.ID, &b.ProposalID, &tenantUUID, &b.Currency, &status, &faRateJSON,
+		&b.Notes, &b.SubmittedAt, &b.ApprovedAt, &b.ApprovedBy, &b.CreatedAt, &b.UpdatedAt,
+		&createdBy, &updatedBy, &b.Version, &b.RateAgreementID, &activity, &b.IsModular,
+		&b.RequiredMatchPercent); err != nil {
+		return nil, err
+	}
+	b.TenantID = common.TenantID(tenantUUID)
+	b.Status = budgetStatusFromColumn(status)
+	b.ActivityType = budget.ActivityType(activity)
+	if createdBy != nil {
+		b.CreatedBy = *createdBy
+	}
+	if updatedBy != nil {
+		b.UpdatedBy = *updatedBy
+	}
+
+	b.FARate = budget.DefaultFARate()
+	if faRateJSON != nil {
+		if err := json.Unmarshal(faRateJSON, &b.FARate); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal F&A rate: %w", err)
+		}
+	}
+	b.Periods = make([]budget.BudgetPeriod, 0)
+	return &b, nil
+}
+
+// budgetStatusFromColumn maps a stored status to a budget status. Budgets
+// pending review before the review statuses were stored are in review.
+func budgetStatusFromColumn(status string) budget.BudgetStatus {
+	if status == "pending_review" {
+		return budget.BudgetStatusInReview
+	}
+	return budget.BudgetStatus(strings.ToUpper(status))
+}
+
+// appliedFARate returns the F&A rate that applies to the budget's location.
+func appliedFARate(rate budget.FARate) decimal.Decimal {
+	if rate.IsOnCampus {
+		return rate.OnCampusRate
+	}
+	return rate.OffCampusRate
+}
+
+// indirectCostBase maps an F&A rate type to the indirect_cost_base column.
+func indirectCostBase(rateType string) string {
+	switch strings.ToUpper(rateType) {
+	case "MTDC":
+		return "mtdc"
+	case "TDC":
+		return "tdc"
+	case "S&W", "SW", "SALARIES":
+		return "salaries"
+	default:
+		return "custom"
+	}
+}
+
+// activityType stores budgets without an activity type as research budgets.
+func activityType(t budget.ActivityType) budget.ActivityType {
+	if t == "" {
+		return budget.ActivityResearch
+	}
+	return t
+}
+
+// modularIncrement returns the module size stored with modular budgets.
+func modularIncrement(b *budget.Budget) *decimal.Decimal {
+	if !b.IsModular {
+		return nil
+	}
+	return &budget.ModuleSize
+}
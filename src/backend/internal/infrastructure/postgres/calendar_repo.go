@@ -0,0 +1,272 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+)
+
+// CalendarRepository implements the proposal.DeadlineCalendarRepository and
+// proposal.CalendarFeedRepository interfaces.
+type CalendarRepository struct {
+	pool *Pool
+}
+
+// NewCalendarRepository creates a new deadline calendar repository.
+func NewCalendarRepository(pool *Pool) *CalendarRepository {
+	return &CalendarRepository{pool: pool}
+}
+
+// FindDeadlineRule retrieves a tenant's internal deadline rule.
+func (r *CalendarRepository) FindDeadlineRule(ctx context.Context, tenantID common.TenantID) (*proposal.DeadlineRule, error) {
+	query := `
+		SELECT business_days_before, COALESCE(internal_time, ''), time_zone, sponsor_time
+		FROM deadline_rules
+		WHERE tenant_id = $1
+	`
+
+	var rule proposal.DeadlineRule
+	err := r.pool.QueryRow(ctx, query, uuid.UUID(tenantID)).Scan(
+		&rule.BusinessDaysBefore, &rule.InternalTime, &rule.TimeZone, &rule.SponsorTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load deadline rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// SaveDeadlineRule stores a tenant's internal deadline rule.
+func (r *CalendarRepository) SaveDeadlineRule(ctx context.Context, tenantID common.TenantID, rule proposal.DeadlineRule) error {
+	query := `
+		INSERT INTO deadline_rules (tenant_id, business_days_before, internal_time, time_zone, sponsor_time, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, NOW())
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			business_days_before = EXCLUDED.business_days_before,
+			internal_time = EXCLUDED.internal_time,
+			time_zone = EXCLUDED.time_zone,
+			sponsor_time = EXCLUDED.sponsor_time,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), rule.BusinessDaysBefore, rule.InternalTime, rule.TimeZone, rule.SponsorTimeOfDay())
+	if err != nil {
+		return fmt.Errorf("failed to save deadline rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteDeadlineRule removes a tenant's internal deadline rule.
+func (r *CalendarRepository) DeleteDeadlineRule(ctx context.Context, tenantID common.TenantID) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM deadline_rules WHERE tenant_id = $1`, uuid.UUID(tenantID))
+	if err != nil {
+		return fmt.Errorf("failed to delete deadline rule: %w", err)
+	}
+	return nil
+}
+
+// ListHolidays retrieves a tenant's holidays between two dates, inclusive.
+func (r *CalendarRepository) ListHolidays(ctx context.Context, tenantID common.TenantID, from, to time.Time) ([]proposal.Holiday, error) {
+	query := `
+		SELECT to_char(holiday_date, 'YYYY-MM-DD'), name
+		FROM holidays
+		WHERE tenant_id = $1 AND holiday_date BETWEEN $2::date AND $3::date
+		ORDER BY holiday_date
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := make([]proposal.Holiday, 0)
+	for rows.Next() {
+		var h proposal.Holiday
+		if err := rows.Scan(&h.Date, &h.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		holidays = append(holidays, h)
+	}
+
+	return holidays, rows.Err()
+}
+
+// SaveHoliday adds or renames a tenant holiday.
+func (r *CalendarRepository) SaveHoliday(ctx context.Context, tenantID common.TenantID, holiday proposal.Holiday) error {
+	query := `
+		INSERT INTO holidays (tenant_id, holiday_date, name)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (tenant_id, holiday_date) DO UPDATE SET name = EXCLUDED.name
+	`
+
+	if _, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), holiday.Date, holiday.Name); err != nil {
+		return fmt.Errorf("failed to save holiday: %w", err)
+	}
+	return nil
+}
+
+// DeleteHoliday removes a tenant holiday.
+func (r *CalendarRepository) DeleteHoliday(ctx context.Context, tenantID common.TenantID, date string) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM holidays WHERE tenant_id = $1 AND holiday_date = $2::date`, uuid.UUID(tenantID), date)
+	if err != nil {
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return proposal.ErrHolidayNotFound
+	}
+	return nil
+}
+
+// SaveFeed stores a new calendar feed with the hash of its token.
+func (r *CalendarRepository) SaveFeed(ctx context.Context, feed *proposal.CalendarFeed, tokenHash string) error {
+	query := `
+		INSERT INTO calendar_feeds (id, tenant_id, scope, user_id, department, token_hash, created_by, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+	`
+
+	_, err := r.pool.Exec(ctx, query, feed.ID, uuid.UUID(feed.TenantID), string(feed.Scope), feed.UserID,
+		feed.Department, tokenHash, feed.CreatedBy, feed.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+	return nil
+}
+
+// feedColumns are the columns scanned by scanFeed.
+const feedColumns = `id, tenant_id, scope, user_id, COALESCE(department, ''), created_by, created_at, revoked_at`
+
+// FindFeedByToken retrieves an unrevoked calendar feed by the hash of its token.
+func (r *CalendarRepository) FindFeedByToken(ctx context.Context, tokenHash string) (*proposal.CalendarFeed, error) {
+	query := `SELECT ` + feedColumns + ` FROM calendar_feeds WHERE token_hash = $1 AND revoked_at IS NULL`
+
+	feed, err := scanFeed(r.pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// FindFeed retrieves a calendar feed by ID.
+func (r *CalendarRepository) FindFeed(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*proposal.CalendarFeed, error) {
+	query := `SELECT ` + feedColumns + ` FROM calendar_feeds WHERE id = $1 AND tenant_id = $2`
+
+	feed, err := scanFeed(r.pool.QueryRow(ctx, query, id, uuid.UUID(tenantID)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load calendar feed: %w", err)
+	}
+	return feed, nil
+}
+
+// ListFeeds retrieves the unrevoked calendar feeds a user created.
+func (r *CalendarRepository) ListFeeds(ctx context.Context, tenantID common.TenantID, createdBy uuid.UUID) ([]*proposal.CalendarFeed, error) {
+	query := `SELECT ` + feedColumns + `
+		FROM calendar_feeds
+		WHERE tenant_id = $1 AND created_by = $2 AND revoked_at IS NULL
+		ORDER BY created_at`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar feeds: %w", err)
+	}
+	defer rows.Close()
+
+	feeds := make([]*proposal.CalendarFeed, 0)
+	for rows.Next() {
+		feed, err := scanFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	return feeds, rows.Err()
+}
+
+// RevokeFeed disables a calendar feed.
+func (r *CalendarRepository) RevokeFeed(ctx context.Context, tenantID common.TenantID, id uuid.UUID, at time.Time) error {
+	query := `UPDATE calendar_feeds SET revoked_at = $3 WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL`
+
+	result, err := r.pool.Exec(ctx, query, id, uuid.UUID(tenantID), at)
+	if err != nil {
+		return fmt.Errorf("failed to revoke calendar feed: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return proposal.ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// scanFeed scans a row of feedColumns.
+func scanFeed(row pgx.Row) (*proposal.CalendarFeed, error) {
+	var feed proposal.CalendarFeed
+	var tenantUUID uuid.UUID
+	var scope string
+	if err := row.Scan(&feed.ID, &tenantUUID, &scope, &feed.UserID, &feed.Department,
+		&feed.CreatedBy, &feed.CreatedAt, &feed.RevokedAt); err != nil {
+		return nil, err
+	}
+	feed.TenantID = common.TenantID(tenantUUID)
+	feed.Scope = proposal.FeedScope(scope)
+	return &feed, nil
+}
+
+// FindDeadlines retrieves the sponsor and internal deadlines between two
+// times of the latest version of every proposal the feed covers. Withdrawn
+// proposals are left out.
+func (r *CalendarRepository) FindDeadlines(ctx context.Context, feed *proposal.CalendarFeed, from, to time.Time) ([]proposal.DeadlineEntry, error) {
+	query := `
+		SELECT p.id, p.proposal_number, p.title, p.state, COALESCE(p.department, ''),
+			d.kind, d.at, p.deadline_time_zone, p.updated_at
+		FROM proposals p
+		CROSS JOIN LATERAL (VALUES ('sponsor', p.sponsor_deadline), ('internal', p.internal_deadline)) AS d(kind, at)
+		WHERE p.tenant_id = $1 AND p.deleted_at IS NULL AND p.is_latest_version
+			AND p.state <> 'WITHDRAWN'
+			AND d.at IS NOT NULL AND d.at BETWEEN $2 AND $3
+			AND CASE $4
+				WHEN 'user' THEN p.principal_investigator_id = $5
+					OR p.created_by = $5
+					OR p.co_investigators @> to_jsonb(ARRAY[$5::text])
+				WHEN 'department' THEN p.department = $6
+				ELSE TRUE
+			END
+		ORDER BY d.at, p.id
+	`
+
+	var userID uuid.UUID
+	if feed.UserID != nil {
+		userID = *feed.UserID
+	}
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(feed.TenantID), from, to, string(feed.Scope), userID, feed.Department)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deadlines: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]proposal.DeadlineEntry, 0)
+	for rows.Next() {
+		var e proposal.DeadlineEntry
+		if err := rows.Scan(&e.ProposalID, &e.ProposalNumber, &e.Title, &e.State, &e.Department,
+			&e.Kind, &e.At, &e.TimeZone, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deadline: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
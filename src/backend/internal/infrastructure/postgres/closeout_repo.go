@@ -0,0 +1,259 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+)
+
+// CloseoutRepository implements the proposal.CloseoutRepository interface.
+type CloseoutRepository struct {
+	pool *Pool
+}
+
+// NewCloseoutRepository creates a new closeout repository.
+func NewCloseoutRepository(pool *Pool) *CloseoutRepository {
+	return &CloseoutRepository{pool: pool}
+}
+
+// SaveChecklist persists a checklist and its items in one transaction.
+// Saving a checklist that changed since it was loaded fails with
+// proposal.ErrVersionMismatch.
+func (r *CloseoutRepository) SaveChecklist(ctx context.Context, c *proposal.CloseoutChecklist) error {
+	query := `
+		INSERT INTO closeout_checklists (
+			id, tenant_id, proposal_id, sponsor_type, project_end_date,
+			created_at, updated_at, created_by, updated_by, version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			version = EXCLUDED.version
+		WHERE closeout_checklists.version < EXCLUDED.version
+	`
+
+	return r.pool.WithTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query,
+			c.ID, uuid.UUID(c.TenantID), c.ProposalID, c.SponsorType, c.ProjectEndDate,
+			c.CreatedAt, c.UpdatedAt, c.CreatedBy, c.UpdatedBy, c.Version)
+		if err != nil {
+			return fmt.Errorf("failed to save closeout checklist: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return proposal.ErrVersionMismatch
+		}
+
+		for i, item := range c.Items {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO closeout_items (
+					id, tenant_id, checklist_id, position, item_type, title, description,
+					due_date, status, note, resolved_at, resolved_by
+				) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, NULLIF($10, ''), $11, $12)
+				ON CONFLICT (id) DO UPDATE SET
+					status = EXCLUDED.status,
+					note = EXCLUDED.note,
+					resolved_at = EXCLUDED.resolved_at,
+					resolved_by = EXCLUDED.resolved_by`,
+				item.ID, uuid.UUID(c.TenantID), c.ID, i, string(item.Type), item.Title, item.Description,
+				item.DueDate, string(item.Status), item.Note, item.ResolvedAt, item.ResolvedBy)
+			if err != nil {
+				return fmt.Errorf("failed to save closeout item: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// FindChecklistByProposal retrieves the closeout checklist of a proposal.
+func (r *CloseoutRepository) FindChecklistByProposal(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) (*proposal.CloseoutChecklist, error) {
+	query := `
+		SELECT id, tenant_id, proposal_id, sponsor_type, project_end_date,
+			created_at, updated_at, created_by, updated_by, version
+		FROM closeout_checklists
+		WHERE proposal_id = $1 AND tenant_id = $2
+	`
+
+	var c proposal.CloseoutChecklist
+	var tenantUUID uuid.UUID
+	err := r.pool.QueryRow(ctx, query, proposalID, uuid.UUID(tenantID)).Scan(
+		&c.ID, &tenantUUID, &c.ProposalID, &c.SponsorType, &c.ProjectEndDate,
+		&c.CreatedAt, &c.UpdatedAt, &c.CreatedBy, &c.UpdatedBy, &c.Version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load closeout checklist: %w", err)
+	}
+	c.TenantID = common.TenantID(tenantUUID)
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+closeoutItemColumns+`
+		FROM closeout_items
+		WHERE checklist_id = $1 AND tenant_id = $2
+		ORDER BY position`, c.ID, uuid.UUID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closeout items: %w", err)
+	}
+	defer rows.Close()
+
+	c.Items = make([]proposal.CloseoutItem, 0)
+	for rows.Next() {
+		item, err := scanCloseoutItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan closeout item: %w", err)
+		}
+		c.Items = append(c.Items, item)
+	}
+
+	return &c, rows.Err()
+}
+
+// ListOverdueCloseoutItems retrieves pending items due before asOf, most
+// overdue first.
+func (r *CloseoutRepository) ListOverdueCloseoutItems(ctx context.Context, tenantID common.TenantID, asOf time.Time) ([]proposal.OverdueCloseoutItem, error) {
+	query := `
+		SELECT p.id, COALESCE(p.proposal_number, ''), p.title, ` + prefixedCloseoutItemColumns + `
+		FROM closeout_items i
+		JOIN closeout_checklists c ON c.id = i.checklist_id
+		JOIN proposals p ON p.id = c.proposal_id
+		WHERE i.tenant_id = $1
+			AND i.status = 'PENDING'
+			AND i.due_date < $2::date
+			AND p.deleted_at IS NULL
+		ORDER BY i.due_date ASC, p.proposal_number
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overdue closeout items: %w", err)
+	}
+	defer rows.Close()
+
+	overdue := make([]proposal.OverdueCloseoutItem, 0)
+	for rows.Next() {
+		var o proposal.OverdueCloseoutItem
+		var item proposal.CloseoutItem
+		var itemType, status string
+		if err := rows.Scan(&o.ProposalID, &o.ProposalNumber, &o.Title,
+			&item.ID, &itemType, &item.Title, &item.Description, &item.DueDate,
+			&status, &item.Note, &item.ResolvedAt, &item.ResolvedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan overdue closeout item: %w", err)
+		}
+		item.Type = proposal.CloseoutItemType(itemType)
+		item.Status = proposal.CloseoutItemStatus(status)
+		o.Item = item
+		o.DaysOverdue = int(asOf.Sub(item.DueDate).Hours() / 24)
+		overdue = append(overdue, o)
+	}
+
+	return overdue, rows.Err()
+}
+
+// closeoutItemColumns are the columns scanned by scanCloseoutItem.
+const closeoutItemColumns = `id, item_type, title, COALESCE(description, ''), due_date,
+	status, COALESCE(note, ''), resolved_at, resolved_by`
+
+// prefixedCloseoutItemColumns are closeoutItemColumns of closeout_items aliased as i.
+const prefixedCloseoutItemColumns = `i.id, i.item_type, i.title, COALESCE(i.description, ''), i.due_date,
+	i.status, COALESCE(i.note, ''), i.resolved_at, i.resolved_by`
+
+// scanCloseoutItem scans a row of closeoutItemColumns.
+func scanCloseoutItem(row pgx.Row) (proposal.CloseoutItem, error) {
+	var item proposal.CloseoutItem
+	var itemType, status string
+	if err := row.Scan(&item.ID, &itemType, &item.Title, &item.Description, &item.DueDate,
+		&status, &item.Note, &item.ResolvedAt, &item.ResolvedBy); err != nil {
+		return item, err
+	}
+	item.Type = proposal.CloseoutItemType(itemType)
+	item.Status = proposal.CloseoutItemStatus(status)
+	return item, nil
+}
+
+// FindCloseoutTemplate retrieves a tenant's template for a sponsor type.
+func (r *CloseoutRepository) FindCloseoutTemplate(ctx context.Context, tenantID common.TenantID, sponsorType string) (*proposal.CloseoutTemplate, error) {
+	query := `SELECT items FROM closeout_templates WHERE tenant_id = $1 AND sponsor_type = $2`
+
+	var itemsJSON []byte
+	err := r.pool.QueryRow(ctx, query, uuid.UUID(tenantID), sponsorType).Scan(&itemsJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load closeout template: %w", err)
+	}
+
+	tpl := proposal.CloseoutTemplate{SponsorType: sponsorType}
+	if err := json.Unmarshal(itemsJSON, &tpl.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal closeout template: %w", err)
+	}
+	return &tpl, nil
+}
+
+// ListCloseoutTemplates retrieves a tenant's templates ordered by sponsor type.
+func (r *CloseoutRepository) ListCloseoutTemplates(ctx context.Context, tenantID common.TenantID) ([]proposal.CloseoutTemplate, error) {
+	query := `SELECT sponsor_type, items FROM closeout_templates WHERE tenant_id = $1 ORDER BY sponsor_type`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closeout templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]proposal.CloseoutTemplate, 0)
+	for rows.Next() {
+		var tpl proposal.CloseoutTemplate
+		var itemsJSON []byte
+		if err := rows.Scan(&tpl.SponsorType, &itemsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan closeout template: %w", err)
+		}
+		if err := json.Unmarshal(itemsJSON, &tpl.Items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal closeout template: %w", err)
+		}
+		templates = append(templates, tpl)
+	}
+
+	return templates, rows.Err()
+}
+
+// SaveCloseoutTemplate stores a tenant's template, replacing the one for
+// the same sponsor type. Existing checklists are not changed.
+func (r *CloseoutRepository) SaveCloseoutTemplate(ctx context.Context, tenantID common.TenantID, tpl proposal.CloseoutTemplate, userID uuid.UUID) error {
+	itemsJSON, err := json.Marshal(tpl.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal closeout template: %w", err)
+	}
+
+	query := `
+		INSERT INTO closeout_templates (tenant_id, sponsor_type, items, updated_at, updated_by)
+		VALUES ($1, $2, $3, NOW(), $4)
+		ON CONFLICT (tenant_id, sponsor_type) DO UPDATE SET
+			items = EXCLUDED.items,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by
+	`
+
+	if _, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), tpl.SponsorType, itemsJSON, userID); err != nil {
+		return fmt.Errorf("failed to save closeout template: %w", err)
+	}
+	return nil
+}
+
+// DeleteCloseoutTemplate removes a tenant's template for a sponsor type so
+// the built-in template applies again.
+func (r *CloseoutRepository) DeleteCloseoutTemplate(ctx context.Context, tenantID common.TenantID, sponsorType string) error {
+	query := `DELETE FROM closeout_templates WHERE tenant_id = $1 AND sponsor_type = $2`
+
+	if _, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), sponsorType); err != nil {
+		return fmt.Errorf("failed to delete closeout template: %w", err)
+	}
+	return nil
+}
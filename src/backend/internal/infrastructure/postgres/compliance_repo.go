@@ -0,0 +1,51 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// ComplianceRepository implements the proposal.ComplianceChecker interface.
+type ComplianceRepository struct {
+	pool *Pool
+}
+
+// NewComplianceRepository creates a new compliance repository.
+func NewComplianceRepository(pool *Pool) *ComplianceRepository {
+	return &ComplianceRepository{pool: pool}
+}
+
+// IncompleteRequirements returns the codes of required compliance items of a
+// proposal that are not yet complete.
+func (r *ComplianceRepository) IncompleteRequirements(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT requirement_code
+		FROM proposal_compliance
+		WHERE tenant_id = $1 AND proposal_id = $2
+			AND COALESCE(is_required, TRUE)
+			AND NOT COALESCE(is_complete, FALSE)
+			AND status <> 'not_applicable'
+		ORDER BY requirement_code
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), proposalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proposal compliance: %w", err)
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("failed to scan proposal compliance: %w", err)
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, rows.Err()
+}
@@ -0,0 +1,150 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+)
+
+// DelegationRepository implements the proposal.DelegationRepository interface.
+type DelegationRepository struct {
+	pool *Pool
+}
+
+// NewDelegationRepository creates a new delegation repository.
+func NewDelegationRepository(pool *Pool) *DelegationRepository {
+	return &DelegationRepository{pool: pool}
+}
+
+// delegationColumns lists the columns read by delegation queries.
+const delegationColumns = `
+	id, tenant_id, delegator_id, delegate_id, role, COALESCE(department, ''),
+	starts_at, ends_at, COALESCE(reason, ''), created_by, created_at, revoked_at
+`
+
+// SaveDelegation persists a delegation (insert or update).
+func (r *DelegationRepository) SaveDelegation(ctx context.Context, d *proposal.Delegation) error {
+	query := `
+		INSERT INTO delegations (
+			id, tenant_id, delegator_id, delegate_id, role, department,
+			starts_at, ends_at, reason, created_by, created_at, revoked_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (id) DO UPDATE SET
+			ends_at = EXCLUDED.ends_at,
+			reason = EXCLUDED.reason,
+			revoked_at = EXCLUDED.revoked_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		d.ID,
+		uuid.UUID(d.TenantID),
+		d.DelegatorID,
+		d.DelegateID,
+		d.Role,
+		d.Department,
+		d.StartsAt,
+		d.EndsAt,
+		d.Reason,
+		d.CreatedBy,
+		d.CreatedAt,
+		d.RevokedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save delegation: %w", err)
+	}
+
+	return nil
+}
+
+// FindDelegation retrieves a delegation by ID.
+func (r *DelegationRepository) FindDelegation(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*proposal.Delegation, error) {
+	query := `SELECT ` + delegationColumns + ` FROM delegations WHERE tenant_id = $1 AND id = $2`
+
+	d, err := scanDelegation(r.pool.QueryRow(ctx, query, uuid.UUID(tenantID), id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// FindActiveDelegations retrieves the delegations to a user in force at the given time.
+func (r *DelegationRepository) FindActiveDelegations(ctx context.Context, tenantID common.TenantID, delegateID uuid.UUID, at time.Time) ([]proposal.Delegation, error) {
+	query := `SELECT ` + delegationColumns + `
+		FROM delegations
+		WHERE tenant_id = $1 AND delegate_id = $2
+			AND starts_at <= $3 AND ends_at > $3
+			AND (revoked_at IS NULL OR revoked_at > $3)
+		ORDER BY starts_at ASC
+	`
+
+	return r.queryDelegations(ctx, query, uuid.UUID(tenantID), delegateID, at)
+}
+
+// ListDelegations retrieves the delegations a user granted or received.
+func (r *DelegationRepository) ListDelegations(ctx context.Context, tenantID common.TenantID, userID uuid.UUID) ([]proposal.Delegation, error) {
+	query := `SELECT ` + delegationColumns + `
+		FROM delegations
+		WHERE tenant_id = $1 AND (delegator_id = $2 OR delegate_id = $2)
+		ORDER BY starts_at DESC
+	`
+
+	return r.queryDelegations(ctx, query, uuid.UUID(tenantID), userID)
+}
+
+// queryDelegations runs a delegation query and scans every row.
+func (r *DelegationRepository) queryDelegations(ctx context.Context, query string, args ...interface{}) ([]proposal.Delegation, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delegations: %w", err)
+	}
+	defer rows.Close()
+
+	delegations := make([]proposal.Delegation, 0)
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, *d)
+	}
+
+	return delegations, rows.Err()
+}
+
+// scanDelegation scans a delegation row.
+func scanDelegation(row pgx.Row) (*proposal.Delegation, error) {
+	var d proposal.Delegation
+	var tenantID uuid.UUID
+	err := row.Scan(
+		&d.ID,
+		&tenantID,
+		&d.DelegatorID,
+		&d.DelegateID,
+		&d.Role,
+		&d.Department,
+		&d.StartsAt,
+		&d.EndsAt,
+		&d.Reason,
+		&d.CreatedBy,
+		&d.CreatedAt,
+		&d.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan delegation: %w", err)
+	}
+	d.TenantID = common.TenantID(tenantID)
+	return &d, nil
+}
@@ -0,0 +1,139 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/shopspring/decimal"
+)
+
+// EffortRepository implements the proposal.EffortRepository interface.
+type EffortRepository struct {
+	pool *Pool
+}
+
+// NewEffortRepository creates a new effort repository.
+func NewEffortRepository(pool *Pool) *EffortRepository {
+	return &EffortRepository{pool: pool}
+}
+
+// FindEffortSources retrieves the key personnel and budget personnel lines of
+// the latest version of every matching proposal.
+func (r *EffortRepository) FindEffortSources(ctx context.Context, tenantID common.TenantID, q proposal.EffortQuery) ([]proposal.EffortSource, error) {
+	query := `
+		SELECT p.id, p.proposal_number, p.title, p.state,
+			p.project_start_date, p.project_end_date, p.key_personnel
+		FROM proposals p
+		WHERE p.tenant_id = $1 AND p.deleted_at IS NULL AND p.is_latest_version
+			AND (p.state = ANY($2) OR p.id = ANY($3))
+			AND (cardinality($4::uuid[]) = 0
+				OR EXISTS (
+					SELECT 1 FROM jsonb_array_elements(p.key_personnel) kp
+					WHERE (kp->>'person_id')::uuid = ANY($4))
+				OR EXISTS (
+					SELECT 1
+					FROM proposal_budgets b
+					JOIN budget_periods bp ON bp.budget_id = b.id
+					JOIN budget_line_items li ON li.period_id = bp.id
+					WHERE b.proposal_id = p.id AND b.status <> 'archived' AND li.person_id = ANY($4)))
+		ORDER BY p.project_start_date, p.id
+	`
+
+	states := make([]string, len(q.States))
+	for i, s := range q.States {
+		states[i] = s.String()
+	}
+	proposalIDs := q.ProposalIDs
+	if proposalIDs == nil {
+		proposalIDs = []uuid.UUID{}
+	}
+	personIDs := q.PersonIDs
+	if personIDs == nil {
+		personIDs = []uuid.UUID{}
+	}
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), states, proposalIDs, personIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query effort sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []proposal.EffortSource
+	index := make(map[uuid.UUID]int)
+	var ids []uuid.UUID
+	for rows.Next() {
+		var s proposal.EffortSource
+		var keyPersonnelJSON []byte
+		if err := rows.Scan(&s.ProposalID, &s.ProposalNumber, &s.Title, &s.State,
+			&s.ProjectPeriod.StartDate, &s.ProjectPeriod.EndDate, &keyPersonnelJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan effort source: %w", err)
+		}
+		if err := json.Unmarshal(keyPersonnelJSON, &s.KeyPersonnel); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal key personnel: %w", err)
+		}
+		index[s.ProposalID] = len(sources)
+		ids = append(ids, s.ProposalID)
+		sources = append(sources, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	if err := r.loadBudgetPersonnel(ctx, tenantID, ids, sources, index); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// loadBudgetPersonnel attaches the personnel lines of the proposals' budgets.
+func (r *EffortRepository) loadBudgetPersonnel(ctx context.Context, tenantID common.TenantID, ids []uuid.UUID, sources []proposal.EffortSource, index map[uuid.UUID]int) error {
+	query := `
+		SELECT b.proposal_id, bp.start_date, bp.end_date,
+			li.id, li.person_id, li.description, COALESCE(li.person_months, 0),
+			COALESCE(li.base_salary, 0), COALESCE(li.fringe_rate, 0)
+		FROM proposal_budgets b
+		JOIN budget_periods bp ON bp.budget_id = b.id
+		JOIN budget_line_items li ON li.period_id = bp.id
+		WHERE b.tenant_id = $1 AND b.proposal_id = ANY($2) AND b.status <> 'archived'
+			AND li.person_id IS NOT NULL
+		ORDER BY b.proposal_id, bp.period_number, li.sort_order
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), ids)
+	if err != nil {
+		return fmt.Errorf("failed to query budget personnel: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var proposalID uuid.UUID
+		var start, end time.Time
+		var cost budget.PersonnelCost
+		var personMonths, baseSalary, fringeRate decimal.Decimal
+		if err := rows.Scan(&proposalID, &start, &end,
+			&cost.ID, &cost.PersonID, &cost.Name, &personMonths, &baseSalary, &fringeRate); err != nil {
+			return fmt.Errorf("failed to scan budget personnel: %w", err)
+		}
+		cost.CalendarMonths = personMonths
+		cost.BaseSalary = baseSalary
+		cost.FringeRate = fringeRate
+
+		i := index[proposalID]
+		sources[i].BudgetPersonnel = append(sources[i].BudgetPersonnel, proposal.BudgetPersonnel{
+			Period: common.DateRange{StartDate: start, EndDate: end},
+			Cost:   cost,
+		})
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,79 @@
+// Package postgres provides a LISTEN/NOTIFY relay for section lock events.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// lockEventChannel is the notification channel section lock events are
+// broadcast on.
+const lockEventChannel = "section_lock_events"
+
+// lockRelayRetryDelay is how long Listen waits before reconnecting after
+// the listening connection fails.
+const lockRelayRetryDelay = 5 * time.Second
+
+// LockEventRelay broadcasts section lock events to every process connected
+// to the database. Notifications sent while a listener is reconnecting are
+// lost.
+type LockEventRelay struct {
+	pool *Pool
+}
+
+// NewLockEventRelay creates a relay on the pool's database.
+func NewLockEventRelay(pool *Pool) *LockEventRelay {
+	return &LockEventRelay{pool: pool}
+}
+
+// Send broadcasts an encoded event.
+func (r *LockEventRelay) Send(ctx context.Context, payload []byte) error {
+	if _, err := r.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, lockEventChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify lock event: %w", err)
+	}
+	return nil
+}
+
+// Listen passes every broadcast event to receive until ctx is cancelled,
+// reconnecting whenever the listening connection fails.
+func (r *LockEventRelay) Listen(ctx context.Context, receive func(payload []byte)) {
+	for {
+		err := r.listen(ctx, receive)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error().Err(err).Msg("Lock event listener failed, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(lockRelayRetryDelay):
+		}
+	}
+}
+
+// listen holds a dedicated connection on the channel until it fails. The
+// connection is taken out of the pool so it is never reused while listening.
+func (r *LockEventRelay) listen(ctx context.Context, receive func(payload []byte)) error {
+	pooled, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+lockEventChannel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		receive([]byte(notification.Payload))
+	}
+}
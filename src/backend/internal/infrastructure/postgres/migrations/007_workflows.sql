@@ -0,0 +1,61 @@
+-- Migration: 007_workflows.sql
+-- Description: Per-tenant versioned workflow definitions and version pins
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Workflow Definitions
+-- Declarative workflow documents (states, transitions, guards, SLA metadata).
+-- Versions are numbered per tenant; version 1 is the built-in workflow and is
+-- never stored.
+-- ============================================================================
+CREATE TABLE workflow_definitions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    version INT NOT NULL,
+    description TEXT,
+    document JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT unique_workflow_version UNIQUE (tenant_id, name, version),
+    CONSTRAINT valid_workflow_version CHECK (version > 1)
+);
+
+CREATE INDEX idx_workflow_definitions_name ON workflow_definitions(name, version);
+
+ALTER TABLE workflow_definitions ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_workflow_definitions ON workflow_definitions
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Tenant Workflow Pins
+-- The workflow version new records of a tenant are created with
+-- ============================================================================
+CREATE TABLE tenant_workflow_pins (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    workflow_name VARCHAR(100) NOT NULL,
+    version INT NOT NULL,
+    pinned_at TIMESTAMPTZ DEFAULT NOW(),
+
+    PRIMARY KEY (tenant_id, workflow_name),
+    CONSTRAINT valid_pinned_version CHECK (version > 0)
+);
+
+ALTER TABLE tenant_workflow_pins ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_tenant_workflow_pins ON tenant_workflow_pins
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- Proposals remember the workflow version they were created under
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS workflow_version INT NOT NULL DEFAULT 1;
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE workflow_definitions IS 'Versioned declarative workflow documents of each tenant';
+COMMENT ON TABLE tenant_workflow_pins IS 'Workflow version each tenant uses for new records';
+COMMENT ON COLUMN proposals.workflow_version IS 'Workflow version the proposal follows';
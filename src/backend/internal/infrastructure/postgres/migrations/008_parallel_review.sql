@@ -0,0 +1,43 @@
+-- Migration: 008_parallel_review.sql
+-- Description: Composite workflow states with parallel review regions
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Sub-States
+-- Active sub-state per region while a proposal is in a composite state
+-- (e.g. REVIEW with concurrent compliance and budget regions)
+-- ============================================================================
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS sub_states JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_proposals_sub_states ON proposals USING GIN (sub_states);
+
+-- ============================================================================
+-- Top-Level States
+-- COMPLIANCE_REVIEW and BUDGET_REVIEW become sub-states of REVIEW. Compliance
+-- review used to come first, so a proposal in budget review has finished it.
+-- ============================================================================
+ALTER TABLE proposals DROP CONSTRAINT IF EXISTS valid_state;
+
+UPDATE proposals
+SET state = 'REVIEW',
+    sub_states = jsonb_build_object('compliance', 'COMPLIANCE_REVIEW', 'budget', 'BUDGET_REVIEW')
+WHERE state = 'COMPLIANCE_REVIEW';
+
+UPDATE proposals
+SET state = 'REVIEW',
+    sub_states = jsonb_build_object('compliance', 'REVIEW', 'budget', 'BUDGET_REVIEW')
+WHERE state = 'BUDGET_REVIEW';
+
+ALTER TABLE proposals ADD CONSTRAINT valid_state CHECK (state IN (
+    'DRAFT', 'IN_PROGRESS', 'INTERNAL_REVIEW', 'DEPT_REVIEW', 'OSP_REVIEW',
+    'REVIEW', 'PENDING_APPROVAL', 'APPROVED',
+    'REJECTED', 'REVISIONS_REQUESTED', 'READY_TO_SUBMIT', 'SUBMITTED',
+    'UNDER_SPONSOR_REVIEW', 'AWARDED', 'NEGOTIATION', 'DECLINED', 'NOT_FUNDED',
+    'ACTIVE', 'CLOSEOUT', 'CLOSED', 'WITHDRAWN'
+));
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON COLUMN proposals.sub_states IS 'Active sub-state of each region of a composite workflow state';
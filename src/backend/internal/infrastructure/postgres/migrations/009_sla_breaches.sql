@@ -0,0 +1,40 @@
+-- Migration: 009_sla_breaches.sql
+-- Description: SLA breach log for the escalation scheduler
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- SLA Breaches
+-- One row per proposal stay in a state that exceeded the state's SLA; the
+-- unique key makes escalation idempotent across scans and replicas
+-- ============================================================================
+CREATE TABLE sla_breaches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    proposal_id UUID NOT NULL REFERENCES proposals(id) ON DELETE CASCADE,
+    state VARCHAR(50) NOT NULL,
+    region VARCHAR(50) NOT NULL DEFAULT '',
+    sla_hours INT NOT NULL,
+    entered_at TIMESTAMPTZ NOT NULL,
+    due_at TIMESTAMPTZ NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    detected_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT unique_sla_breach UNIQUE (proposal_id, state, region, entered_at),
+    CONSTRAINT valid_sla_hours CHECK (sla_hours > 0)
+);
+
+CREATE INDEX idx_sla_breaches_tenant ON sla_breaches(tenant_id, detected_at DESC);
+
+ALTER TABLE sla_breaches ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_sla_breaches ON sla_breaches
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE sla_breaches IS 'Proposal SLA breaches detected by the escalation scheduler';
+COMMENT ON COLUMN sla_breaches.region IS 'Region of a composite state, empty for top-level states';
+COMMENT ON COLUMN sla_breaches.action IS 'Escalation taken: notify or the automatic transition fired';
@@ -0,0 +1,40 @@
+-- Migration: 010_proposal_approvals.sql
+-- Description: Approver votes for multi-approver workflow transitions
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Approvals
+-- One row per vote on an approval set; the latest vote of an approver during
+-- a stay in the gated state counts
+-- ============================================================================
+CREATE TABLE proposal_approvals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    proposal_id UUID NOT NULL REFERENCES proposals(id) ON DELETE CASCADE,
+    state VARCHAR(50) NOT NULL,
+    transition VARCHAR(50) NOT NULL,
+    approver_id UUID NOT NULL,
+    role VARCHAR(100) NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    comment TEXT,
+    decided_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_approval_decision CHECK (decision IN ('APPROVE', 'REJECT', 'ABSTAIN'))
+);
+
+CREATE INDEX idx_proposal_approvals_proposal ON proposal_approvals(tenant_id, proposal_id, decided_at);
+CREATE INDEX idx_proposal_approvals_approver ON proposal_approvals(tenant_id, approver_id);
+
+ALTER TABLE proposal_approvals ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_proposal_approvals ON proposal_approvals
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE proposal_approvals IS 'Approver votes on workflow approval sets';
+COMMENT ON COLUMN proposal_approvals.role IS 'Approver seat the vote was cast under';
+COMMENT ON COLUMN proposal_approvals.transition IS 'Transition gated by the approval set';
@@ -0,0 +1,46 @@
+-- Migration: 011_delegations.sql
+-- Description: Time-bounded role delegations and proxy approvals
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Delegations
+-- A user lends one of their roles to another user for a period, optionally
+-- limited to one department (e.g. a department head out of office)
+-- ============================================================================
+CREATE TABLE delegations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    delegator_id UUID NOT NULL,
+    delegate_id UUID NOT NULL,
+    role VARCHAR(100) NOT NULL,
+    department VARCHAR(255),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason TEXT,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+
+    CONSTRAINT valid_delegation_period CHECK (ends_at > starts_at),
+    CONSTRAINT no_self_delegation CHECK (delegator_id <> delegate_id)
+);
+
+CREATE INDEX idx_delegations_delegate ON delegations(tenant_id, delegate_id, starts_at, ends_at);
+CREATE INDEX idx_delegations_delegator ON delegations(tenant_id, delegator_id);
+
+ALTER TABLE delegations ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_delegations ON delegations
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- Votes cast through a delegation record the delegator as principal
+ALTER TABLE proposal_approvals ADD COLUMN IF NOT EXISTS on_behalf_of UUID;
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE delegations IS 'Time-bounded role delegations between users';
+COMMENT ON COLUMN delegations.department IS 'Department the delegation is limited to, NULL for all departments';
+COMMENT ON COLUMN proposal_approvals.on_behalf_of IS 'Delegator when the vote was cast through a delegation';
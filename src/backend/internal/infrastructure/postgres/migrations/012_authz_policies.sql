@@ -0,0 +1,37 @@
+-- Migration: 012_authz_policies.sql
+-- Description: Per-tenant attribute-based authorization policies
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Authorization Policies
+-- Tenant policies are layered over the built-in policies: a row with the ID
+-- of a built-in policy replaces it, a disabled row switches it off
+-- ============================================================================
+CREATE TABLE authz_policies (
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    policy_id VARCHAR(100) NOT NULL,
+    description TEXT,
+    effect VARCHAR(10) NOT NULL,
+    actions TEXT[] NOT NULL,
+    condition TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by UUID NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (tenant_id, policy_id),
+    CONSTRAINT valid_policy_effect CHECK (effect IN ('allow', 'deny'))
+);
+
+ALTER TABLE authz_policies ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_authz_policies ON authz_policies
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE authz_policies IS 'Tenant authorization policies evaluated by the in-process policy engine';
+COMMENT ON COLUMN authz_policies.actions IS 'Action names, "prefix.*" patterns or "*"';
+COMMENT ON COLUMN authz_policies.condition IS 'Policy expression over subject, resource and context attributes; empty always holds';
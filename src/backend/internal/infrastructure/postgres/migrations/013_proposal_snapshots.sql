@@ -0,0 +1,48 @@
+-- Migration: 013_proposal_snapshots.sql
+-- Description: Immutable proposal snapshots and version lineage
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Snapshots
+-- Content of a proposal captured on each submission for review and to the
+-- sponsor; rows are never updated
+-- ============================================================================
+CREATE TABLE proposal_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    proposal_id UUID NOT NULL REFERENCES proposals(id) ON DELETE CASCADE,
+    number INT NOT NULL,
+    proposal_version INT NOT NULL,
+    transition VARCHAR(50) NOT NULL,
+    state VARCHAR(50) NOT NULL,
+    content JSONB NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_proposal_snapshot_number UNIQUE (proposal_id, number),
+    CONSTRAINT valid_snapshot_number CHECK (number > 0)
+);
+
+CREATE INDEX idx_proposal_snapshots_proposal ON proposal_snapshots(tenant_id, proposal_id, number);
+
+ALTER TABLE proposal_snapshots ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_proposal_snapshots ON proposal_snapshots
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Version Lineage
+-- Restored drafts point at the proposal they were restored from
+-- ============================================================================
+UPDATE proposals SET is_latest_version = TRUE WHERE is_latest_version IS NULL;
+ALTER TABLE proposals ALTER COLUMN is_latest_version SET NOT NULL;
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE proposal_snapshots IS 'Immutable proposal content captured on submission';
+COMMENT ON COLUMN proposal_snapshots.number IS 'Version number of the snapshot within its proposal, from 1';
+COMMENT ON COLUMN proposal_snapshots.transition IS 'Transition that captured the snapshot';
+COMMENT ON COLUMN proposals.parent_version_id IS 'Proposal a restored draft was created from';
@@ -0,0 +1,21 @@
+-- Migration: 014_proposal_lineage.sql
+-- Description: Predecessor links for resubmissions, renewals and supplements
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Lineage
+-- A resubmission, renewal, continuation or supplement points at the proposal
+-- it follows; proposal_type records the kind of link
+-- ============================================================================
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS predecessor_id UUID REFERENCES proposals(id);
+ALTER TABLE proposals ALTER COLUMN proposal_type SET DEFAULT 'new';
+
+CREATE INDEX IF NOT EXISTS idx_proposals_predecessor ON proposals(tenant_id, predecessor_id)
+    WHERE predecessor_id IS NOT NULL;
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON COLUMN proposals.predecessor_id IS 'Proposal this resubmission, renewal, continuation or supplement follows';
+COMMENT ON COLUMN proposals.proposal_type IS 'Relation to earlier submissions: new, renewal, continuation, supplement, resubmission, transfer or pre_proposal';
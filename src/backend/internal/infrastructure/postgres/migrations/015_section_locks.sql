@@ -0,0 +1,47 @@
+-- Migration: 015_section_locks.sql
+-- Description: Section-level soft locks and versions for concurrent proposal editing
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Section Locks
+-- Expiring claims on a proposal section, kept alive by heartbeats; an expired
+-- lock may be taken over by another user
+-- ============================================================================
+CREATE TABLE proposal_section_locks (
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    proposal_id UUID NOT NULL REFERENCES proposals(id) ON DELETE CASCADE,
+    section VARCHAR(50) NOT NULL,
+    holder_id UUID NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (proposal_id, section),
+    CONSTRAINT valid_lock_section CHECK (section IN (
+        'abstract', 'sponsor', 'personnel', 'compliance', 'attachments'
+    )),
+    CONSTRAINT valid_lock_expiry CHECK (expires_at > heartbeat_at)
+);
+
+CREATE INDEX idx_proposal_section_locks_proposal ON proposal_section_locks(tenant_id, proposal_id, expires_at);
+
+ALTER TABLE proposal_section_locks ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_proposal_section_locks ON proposal_section_locks
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Section Versions
+-- Change count per section, so saves to one section do not conflict with
+-- edits to another
+-- ============================================================================
+ALTER TABLE proposals ADD COLUMN section_versions JSONB NOT NULL DEFAULT '{}';
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE proposal_section_locks IS 'Soft, expiring locks on proposal sections for concurrent editing';
+COMMENT ON COLUMN proposal_section_locks.expires_at IS 'Lock lapses unless renewed by a heartbeat before this time';
+COMMENT ON COLUMN proposals.section_versions IS 'Change count per proposal section, used to detect conflicting section edits';
@@ -0,0 +1,80 @@
+-- Migration: 016_proposal_numbering.sql
+-- Description: Configurable per-tenant proposal numbering with gap-free fiscal year sequences
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Numbering Schemes
+-- Tenants without a row use {TENANT}-{FY}-{SEQ:5} with a July fiscal year
+-- ============================================================================
+CREATE TABLE proposal_numbering_schemes (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id),
+    pattern VARCHAR(100) NOT NULL,
+    fiscal_year_start_month SMALLINT NOT NULL DEFAULT 7,
+    tenant_code VARCHAR(20),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_fiscal_year_start_month CHECK (fiscal_year_start_month BETWEEN 1 AND 12),
+    CONSTRAINT pattern_has_sequence CHECK (pattern LIKE '%{SEQ%')
+);
+
+ALTER TABLE proposal_numbering_schemes ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_proposal_numbering_schemes ON proposal_numbering_schemes
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Number Sequences
+-- One counter per tenant and fiscal year. Counters are incremented in the
+-- transaction that inserts the proposal, so they never skip a value
+-- ============================================================================
+CREATE TABLE proposal_number_sequences (
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    fiscal_year INT NOT NULL,
+    last_value BIGINT NOT NULL,
+
+    PRIMARY KEY (tenant_id, fiscal_year),
+    CONSTRAINT valid_last_value CHECK (last_value > 0)
+);
+
+ALTER TABLE proposal_number_sequences ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_proposal_number_sequences ON proposal_number_sequences
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- Returns the next sequence value; the counter row stays locked until the
+-- calling transaction ends
+CREATE OR REPLACE FUNCTION next_proposal_sequence(p_tenant_id UUID, p_fiscal_year INT)
+RETURNS BIGINT AS $$
+DECLARE
+    v_value BIGINT;
+BEGIN
+    INSERT INTO proposal_number_sequences (tenant_id, fiscal_year, last_value)
+    VALUES (p_tenant_id, p_fiscal_year, 1)
+    ON CONFLICT (tenant_id, fiscal_year) DO UPDATE
+        SET last_value = proposal_number_sequences.last_value + 1
+    RETURNING last_value INTO v_value;
+
+    RETURN v_value;
+END;
+$$ LANGUAGE plpgsql;
+
+-- The scan-based generator was never called and races under concurrency
+DROP FUNCTION IF EXISTS generate_proposal_number(UUID);
+
+-- ============================================================================
+-- Uniqueness
+-- UNIQUE (tenant_id, proposal_number) from 001_init covers lookups by number
+-- ============================================================================
+ALTER TABLE proposals ADD CONSTRAINT proposal_number_not_empty CHECK (proposal_number <> '');
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE proposal_numbering_schemes IS 'Per-tenant proposal number pattern and fiscal year';
+COMMENT ON COLUMN proposal_numbering_schemes.pattern IS 'Tokens: {TENANT}, {FY}, {FY2}, {YEAR}, {SEQ:n}';
+COMMENT ON COLUMN proposal_numbering_schemes.tenant_code IS 'Replaces the tenant slug in {TENANT}';
+COMMENT ON TABLE proposal_number_sequences IS 'Gap-free proposal number counters per tenant and fiscal year';
+COMMENT ON FUNCTION next_proposal_sequence(UUID, INT) IS 'Increments and returns the proposal counter of a tenant and fiscal year';
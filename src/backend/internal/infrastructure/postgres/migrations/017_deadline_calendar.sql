@@ -0,0 +1,91 @@
+-- Migration: 017_deadline_calendar.sql
+-- Description: Internal deadline rules, holiday calendars, time-zone-aware deadlines and iCal feeds
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Deadline Time Zones
+-- Deadlines are stored in UTC; the zone is the one the sponsor quotes them in
+-- ============================================================================
+ALTER TABLE proposals ADD COLUMN deadline_time_zone VARCHAR(64) NOT NULL DEFAULT '';
+
+-- ============================================================================
+-- Deadline Rules
+-- Tenants without a row enter internal deadlines by hand
+-- ============================================================================
+CREATE TABLE deadline_rules (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id),
+    business_days_before SMALLINT NOT NULL,
+    internal_time VARCHAR(5),
+    time_zone VARCHAR(64) NOT NULL DEFAULT '',
+    sponsor_time VARCHAR(5) NOT NULL DEFAULT '17:00',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_business_days_before CHECK (business_days_before BETWEEN 0 AND 60),
+    CONSTRAINT valid_internal_time CHECK (internal_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    CONSTRAINT valid_sponsor_time CHECK (sponsor_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$')
+);
+
+ALTER TABLE deadline_rules ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_deadline_rules ON deadline_rules
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Holidays
+-- ============================================================================
+CREATE TABLE holidays (
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    holiday_date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+
+    PRIMARY KEY (tenant_id, holiday_date)
+);
+
+ALTER TABLE holidays ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_holidays ON holidays
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Calendar Feeds
+-- Feeds are fetched by token without a tenant context, so lookups by
+-- token_hash run as the application role
+-- ============================================================================
+CREATE TABLE calendar_feeds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    scope VARCHAR(20) NOT NULL,
+    user_id UUID,
+    department VARCHAR(255),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+
+    CONSTRAINT valid_feed_scope CHECK (scope IN ('user', 'department', 'tenant')),
+    CONSTRAINT user_feed_has_user CHECK (scope <> 'user' OR user_id IS NOT NULL),
+    CONSTRAINT department_feed_has_department CHECK (scope <> 'department' OR department IS NOT NULL)
+);
+
+CREATE INDEX idx_calendar_feeds_created_by ON calendar_feeds(tenant_id, created_by) WHERE revoked_at IS NULL;
+
+ALTER TABLE calendar_feeds ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_calendar_feeds ON calendar_feeds
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON COLUMN proposals.deadline_time_zone IS 'IANA zone the sponsor deadline is quoted in; empty is UTC';
+COMMENT ON TABLE deadline_rules IS 'Per-tenant rule deriving internal deadlines from sponsor deadlines';
+COMMENT ON COLUMN deadline_rules.internal_time IS 'HH:MM of internal deadlines; NULL keeps the sponsor deadline time of day';
+COMMENT ON COLUMN deadline_rules.time_zone IS 'IANA zone in which business days are counted';
+COMMENT ON COLUMN deadline_rules.sponsor_time IS 'HH:MM of sponsor deadlines entered as a date';
+COMMENT ON TABLE holidays IS 'Institutional holidays skipped when counting business days';
+COMMENT ON TABLE calendar_feeds IS 'Subscribable iCal feeds of proposal deadlines';
+COMMENT ON COLUMN calendar_feeds.token_hash IS 'SHA-256 of the secret feed URL token';
@@ -0,0 +1,112 @@
+-- Migration: 018_awards.sql
+-- Description: Awards opened for awarded proposals, with setup data and modification history
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Awards
+-- One award per proposal. Budget periods, terms and reporting requirements
+-- are only read and written with the award
+-- ============================================================================
+CREATE TABLE awards (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    proposal_id UUID NOT NULL REFERENCES proposals(id),
+    sponsor_id UUID NOT NULL,
+    sponsor_award_number VARCHAR(100) NOT NULL DEFAULT '',
+    award_date DATE,
+    start_date DATE,
+    end_date DATE,
+    budget_periods JSONB NOT NULL DEFAULT '[]',
+    terms JSONB NOT NULL DEFAULT '[]',
+    reporting_requirements JSONB NOT NULL DEFAULT '[]',
+    total_obligated DECIMAL(15,2) NOT NULL DEFAULT 0,
+    total_anticipated DECIMAL(15,2) NOT NULL DEFAULT 0,
+    setup_completed_at TIMESTAMPTZ,
+    setup_completed_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    updated_by UUID NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+
+    CONSTRAINT unique_proposal_award UNIQUE (tenant_id, proposal_id),
+    CONSTRAINT valid_award_dates CHECK (end_date IS NULL OR start_date IS NULL OR end_date >= start_date),
+    CONSTRAINT valid_award_totals CHECK (total_obligated >= 0 AND total_obligated <= total_anticipated)
+);
+
+CREATE INDEX idx_awards_sponsor_award_number ON awards(tenant_id, sponsor_award_number) WHERE sponsor_award_number <> '';
+
+ALTER TABLE awards ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_awards ON awards
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Award Modifications
+-- Append-only history of supplements, no-cost extensions and de-obligations
+-- ============================================================================
+CREATE TABLE award_modifications (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    award_id UUID NOT NULL REFERENCES awards(id),
+    number INT NOT NULL,
+    modification_type VARCHAR(30) NOT NULL,
+    period_number INT,
+    amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    new_end_date DATE,
+    effective_date TIMESTAMPTZ NOT NULL,
+    sponsor_reference VARCHAR(100),
+    reason TEXT,
+    recorded_by UUID NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_award_modification_number UNIQUE (award_id, number),
+    CONSTRAINT valid_modification_type CHECK (modification_type IN ('SUPPLEMENT', 'NO_COST_EXTENSION', 'DEOBLIGATION')),
+    CONSTRAINT valid_modification_amount CHECK (amount >= 0)
+);
+
+ALTER TABLE award_modifications ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_award_modifications ON award_modifications
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE awards IS 'Sponsor awards of proposals; setup must be complete before activation';
+COMMENT ON COLUMN awards.budget_periods IS 'Per-period anticipated and obligated amounts';
+COMMENT ON COLUMN awards.total_obligated IS 'Sum of obligated amounts, kept for reporting';
+COMMENT ON TABLE award_modifications IS 'Supplements, no-cost extensions and de-obligations in the order recorded';
+
+-- ============================================================================
+-- Backfill
+-- Proposals awarded or active before awards were tracked get an award with a
+-- single period over the project period; active ones were already set up
+-- ============================================================================
+INSERT INTO awards (
+    tenant_id, proposal_id, sponsor_id, start_date, end_date, budget_periods,
+    setup_completed_at, setup_completed_by, created_by, updated_by
+)
+SELECT
+    p.tenant_id,
+    p.id,
+    COALESCE(p.sponsor_id, '00000000-0000-0000-0000-000000000000'),
+    p.project_start_date,
+    p.project_end_date,
+    jsonb_build_array(jsonb_build_object(
+        'period_number', 1,
+        'start_date', to_char(p.project_start_date, 'YYYY-MM-DD"T00:00:00Z"'),
+        'end_date', to_char(p.project_end_date, 'YYYY-MM-DD"T00:00:00Z"'),
+        'anticipated_amount', 0,
+        'obligated_amount', 0
+    )),
+    CASE WHEN p.state = 'ACTIVE' THEN NOW() END,
+    CASE WHEN p.state = 'ACTIVE' THEN p.created_by END,
+    p.created_by,
+    p.created_by
+FROM proposals p
+WHERE p.state IN ('AWARDED', 'ACTIVE')
+ON CONFLICT (tenant_id, proposal_id) DO NOTHING;
@@ -0,0 +1,110 @@
+-- Migration: 019_closeout.sql
+-- Description: Closeout checklists generated from per-sponsor-type templates
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Closeout Templates
+-- A tenant's closeout items per sponsor type; built-in templates apply to
+-- sponsor types without one
+-- ============================================================================
+CREATE TABLE closeout_templates (
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    sponsor_type VARCHAR(50) NOT NULL,
+    items JSONB NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_by UUID NOT NULL,
+
+    PRIMARY KEY (tenant_id, sponsor_type)
+);
+
+ALTER TABLE closeout_templates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_closeout_templates ON closeout_templates
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Closeout Checklists
+-- One checklist per proposal, generated when it enters closeout
+-- ============================================================================
+CREATE TABLE closeout_checklists (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    proposal_id UUID NOT NULL REFERENCES proposals(id),
+    sponsor_type VARCHAR(50) NOT NULL,
+    project_end_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    updated_by UUID NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+
+    CONSTRAINT unique_proposal_closeout UNIQUE (tenant_id, proposal_id)
+);
+
+ALTER TABLE closeout_checklists ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_closeout_checklists ON closeout_checklists
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Closeout Items
+-- ============================================================================
+CREATE TABLE closeout_items (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    checklist_id UUID NOT NULL REFERENCES closeout_checklists(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    item_type VARCHAR(30) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    due_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    note TEXT,
+    resolved_at TIMESTAMPTZ,
+    resolved_by UUID,
+
+    CONSTRAINT valid_closeout_item_type CHECK (item_type IN ('financial_report', 'technical_report', 'invention_report', 'equipment_inventory', 'subaward_closeout', 'other')),
+    CONSTRAINT valid_closeout_item_status CHECK (status IN ('PENDING', 'COMPLETE', 'WAIVED'))
+);
+
+CREATE INDEX idx_closeout_items_checklist ON closeout_items(checklist_id, position);
+CREATE INDEX idx_closeout_items_pending_due ON closeout_items(tenant_id, due_date) WHERE status = 'PENDING';
+
+ALTER TABLE closeout_items ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_closeout_items ON closeout_items
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE closeout_templates IS 'Closeout items required by sponsors of a type, due a number of days after the project end date';
+COMMENT ON TABLE closeout_checklists IS 'Closeout deliverables of a proposal; all items must be complete or waived before it is closed';
+COMMENT ON COLUMN closeout_items.due_date IS 'Sponsor due date, counted from closeout_checklists.project_end_date';
+
+-- ============================================================================
+-- Backfill
+-- Proposals already in closeout get the built-in default checklist
+-- ============================================================================
+WITH created AS (
+    INSERT INTO closeout_checklists (
+        id, tenant_id, proposal_id, sponsor_type, project_end_date, created_by, updated_by
+    )
+    SELECT uuid_generate_v4(), p.tenant_id, p.id, 'default',
+        COALESCE(p.project_end_date, CURRENT_DATE), p.created_by, p.created_by
+    FROM proposals p
+    WHERE p.state = 'CLOSEOUT'
+    ON CONFLICT (tenant_id, proposal_id) DO NOTHING
+    RETURNING id, tenant_id, project_end_date
+)
+INSERT INTO closeout_items (id, tenant_id, checklist_id, position, item_type, title, due_date)
+SELECT uuid_generate_v4(), c.tenant_id, c.id, i.position, i.item_type, i.title, c.project_end_date + 90
+FROM created c
+CROSS JOIN (VALUES
+    (0, 'financial_report', 'Final financial report'),
+    (1, 'technical_report', 'Final technical report')
+) AS i(position, item_type, title);
@@ -0,0 +1,56 @@
+-- Migration: 020_proposal_templates.sql
+-- Description: Named proposal templates for recurring program submissions
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Templates
+-- Defaults for proposals to a program, optionally limited to a department
+-- and an opportunity. Personnel, budget and attachment requirements are only
+-- read and written with the template
+-- ============================================================================
+CREATE TABLE proposal_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    department VARCHAR(255),
+    opportunity_id UUID,
+    proposal_type VARCHAR(30),
+    research_area VARCHAR(255),
+    keywords JSONB NOT NULL DEFAULT '[]',
+    personnel JSONB NOT NULL DEFAULT '[]',
+    compliance JSONB NOT NULL DEFAULT '{}',
+    budget_skeleton JSONB,
+    required_attachments JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    updated_by UUID NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+
+    CONSTRAINT unique_template_name UNIQUE (tenant_id, name)
+);
+
+CREATE INDEX idx_proposal_templates_opportunity ON proposal_templates(tenant_id, opportunity_id)
+    WHERE opportunity_id IS NOT NULL;
+
+ALTER TABLE proposal_templates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_proposal_templates ON proposal_templates
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Template Link
+-- ============================================================================
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS template_id UUID
+    REFERENCES proposal_templates(id) ON DELETE SET NULL;
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE proposal_templates IS 'Defaults for proposals to programs a tenant submits to every cycle';
+COMMENT ON COLUMN proposal_templates.budget_skeleton IS 'First-year budget period projected over the project period';
+COMMENT ON COLUMN proposal_templates.required_attachments IS 'Attachment categories proposals from the template must include';
+COMMENT ON COLUMN proposals.template_id IS 'Template the proposal was created from';
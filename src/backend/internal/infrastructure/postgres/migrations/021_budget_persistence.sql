@@ -0,0 +1,54 @@
+-- Migration: 021_budget_persistence.sql
+-- Description: Columns the budget repository needs to store the Budget aggregate
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Budgets
+-- The F&A rate structure, notes and optimistic locking of the aggregate.
+-- Statuses are the budget review statuses in lower case
+-- ============================================================================
+ALTER TABLE proposal_budgets
+    ADD COLUMN IF NOT EXISTS fa_rate JSONB,
+    ADD COLUMN IF NOT EXISTS notes TEXT,
+    ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS created_by UUID,
+    ADD COLUMN IF NOT EXISTS updated_by UUID,
+    ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE proposal_budgets DROP CONSTRAINT IF EXISTS valid_budget_status;
+ALTER TABLE proposal_budgets ADD CONSTRAINT valid_budget_status CHECK (status IN (
+    'draft', 'submitted', 'in_review', 'pending_review', 'approved', 'rejected',
+    'revision_requested', 'archived'
+));
+
+CREATE INDEX IF NOT EXISTS idx_proposal_budgets_status ON proposal_budgets(tenant_id, status);
+
+-- ============================================================================
+-- Budget Line Items
+-- Every line item keeps its cost type and the fields of that type; the
+-- generic columns are filled for reporting
+-- ============================================================================
+ALTER TABLE budget_line_items
+    ADD COLUMN IF NOT EXISTS cost_type VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS details JSONB;
+
+ALTER TABLE budget_line_items ADD CONSTRAINT valid_cost_type CHECK (cost_type IS NULL OR cost_type IN (
+    'personnel', 'equipment', 'travel', 'supplies', 'contractual', 'other', 'subaward'
+));
+
+-- ============================================================================
+-- Totals
+-- Totals are calculated by the application with the budget calculator, which
+-- applies the MTDC exclusions the trigger ignored
+-- ============================================================================
+DROP TRIGGER IF EXISTS trigger_recalculate_period_totals ON budget_line_items;
+DROP FUNCTION IF EXISTS recalculate_period_totals();
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON COLUMN proposal_budgets.fa_rate IS 'F&A rate structure the budget is calculated with';
+COMMENT ON COLUMN proposal_budgets.version IS 'Optimistic locking version of the budget aggregate';
+COMMENT ON COLUMN budget_line_items.cost_type IS 'Cost type of the line item: personnel, equipment, travel, supplies, contractual, other or subaward';
+COMMENT ON COLUMN budget_line_items.details IS 'Fields of the line item specific to its cost type';
@@ -0,0 +1,110 @@
+-- Migration: 022_rate_agreements.sql
+-- Description: Negotiated F&A and fringe rate agreements per tenant
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Rate Agreements
+-- A tenant's negotiated rate agreements with their cognizant agency. The F&A
+-- base, caps and exclusions apply to every rate of the agreement
+-- ============================================================================
+CREATE TABLE rate_agreements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    name VARCHAR(255) NOT NULL,
+    cognizant_agency VARCHAR(100),
+    agreement_date DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'provisional',
+    rate_type VARCHAR(10) NOT NULL DEFAULT 'MTDC',
+    subaward_cap DECIMAL(15,2) NOT NULL DEFAULT 25000,
+    equipment_cap DECIMAL(15,2) NOT NULL DEFAULT 0,
+    excluded_items JSONB NOT NULL DEFAULT '[]',
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by UUID NOT NULL,
+    updated_by UUID NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+
+    CONSTRAINT unique_rate_agreement_name UNIQUE (tenant_id, name),
+    CONSTRAINT valid_rate_agreement_status CHECK (status IN ('provisional', 'final')),
+    CONSTRAINT valid_rate_agreement_type CHECK (rate_type IN ('MTDC', 'TDC', 'S&W'))
+);
+
+ALTER TABLE rate_agreements ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_rate_agreements ON rate_agreements
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Negotiated F&A Rates
+-- On- and off-campus rates of an activity type over an inclusive date range;
+-- a NULL end date leaves the rate open-ended
+-- ============================================================================
+CREATE TABLE negotiated_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    agreement_id UUID NOT NULL REFERENCES rate_agreements(id) ON DELETE CASCADE,
+    activity_type VARCHAR(30) NOT NULL,
+    on_campus_rate DECIMAL(7,6) NOT NULL,
+    off_campus_rate DECIMAL(7,6) NOT NULL,
+    effective_from DATE NOT NULL,
+    effective_to DATE,
+
+    CONSTRAINT valid_negotiated_activity CHECK (activity_type IN ('research', 'instruction', 'other_sponsored')),
+    CONSTRAINT valid_negotiated_dates CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+CREATE INDEX idx_negotiated_rates_agreement ON negotiated_rates(agreement_id, activity_type, effective_from);
+
+ALTER TABLE negotiated_rates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_negotiated_rates ON negotiated_rates
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Fringe Rates
+-- Fringe benefit rates by employee class over an inclusive date range
+-- ============================================================================
+CREATE TABLE fringe_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id),
+    agreement_id UUID NOT NULL REFERENCES rate_agreements(id) ON DELETE CASCADE,
+    employee_class VARCHAR(50) NOT NULL,
+    rate DECIMAL(7,6) NOT NULL,
+    effective_from DATE NOT NULL,
+    effective_to DATE,
+
+    CONSTRAINT valid_fringe_dates CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+
+CREATE INDEX idx_fringe_rates_agreement ON fringe_rates(agreement_id, employee_class, effective_from);
+
+ALTER TABLE fringe_rates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation_fringe_rates ON fringe_rates
+    USING (tenant_id = current_tenant_id())
+    WITH CHECK (tenant_id = current_tenant_id());
+
+-- ============================================================================
+-- Budget Link
+-- Agreements budgets used cannot be deleted
+-- ============================================================================
+ALTER TABLE proposal_budgets
+    ADD COLUMN IF NOT EXISTS rate_agreement_id UUID REFERENCES rate_agreements(id),
+    ADD COLUMN IF NOT EXISTS activity_type VARCHAR(30) NOT NULL DEFAULT 'research';
+
+CREATE INDEX IF NOT EXISTS idx_proposal_budgets_rate_agreement ON proposal_budgets(rate_agreement_id)
+    WHERE rate_agreement_id IS NOT NULL;
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON TABLE rate_agreements IS 'Negotiated F&A and fringe rate agreements of a tenant';
+COMMENT ON COLUMN rate_agreements.status IS 'provisional rates are billed until final rates are negotiated';
+COMMENT ON TABLE negotiated_rates IS 'F&A rates by activity type and effective date range';
+COMMENT ON TABLE fringe_rates IS 'Fringe benefit rates by employee class and effective date range';
+COMMENT ON COLUMN proposal_budgets.rate_agreement_id IS 'Rate agreement the budget F&A and fringe rates were looked up from';
+COMMENT ON COLUMN proposal_budgets.activity_type IS 'Sponsored activity type selecting the negotiated rate';
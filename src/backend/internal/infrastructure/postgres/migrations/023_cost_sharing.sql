@@ -0,0 +1,36 @@
+-- Migration: 023_cost_sharing.sql
+-- Description: Cost share commitments of budget line items and the sponsor's required match
+-- Author: System
+-- Created: 2026-10-16
+
+-- ============================================================================
+-- Proposal Budgets
+-- The sponsor's required match, in percent of the sponsor's request
+-- ============================================================================
+ALTER TABLE proposal_budgets
+    ADD COLUMN IF NOT EXISTS required_match_percent DECIMAL(7,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE proposal_budgets ADD CONSTRAINT valid_required_match CHECK (required_match_percent >= 0);
+
+-- ============================================================================
+-- Budget Line Items
+-- Each line item keeps its cost share entries in details; the generic
+-- columns hold their sum, and the type and source when all entries share them
+-- ============================================================================
+ALTER TABLE budget_line_items DROP CONSTRAINT IF EXISTS valid_cost_share_type;
+ALTER TABLE budget_line_items ADD CONSTRAINT valid_cost_share_type CHECK (cost_share_type IS NULL OR cost_share_type IN (
+    'mandatory', 'voluntary_committed', 'third_party_in_kind',
+    'cash', 'in_kind', 'third_party', 'unrecovered_idc'
+));
+
+CREATE INDEX IF NOT EXISTS idx_budget_line_items_cost_share_source ON budget_line_items(cost_share_source)
+    WHERE cost_share_source IS NOT NULL;
+
+-- ============================================================================
+-- Comments
+-- ============================================================================
+COMMENT ON COLUMN proposal_budgets.required_match_percent IS 'Cost share the sponsor requires, in percent of the sponsor request';
+COMMENT ON COLUMN proposal_budgets.total_cost_sharing IS 'Committed cost share of all periods';
+COMMENT ON COLUMN budget_periods.cost_sharing IS 'Committed cost share of the period';
+COMMENT ON COLUMN budget_line_items.cost_share_type IS 'mandatory, voluntary_committed or third_party_in_kind; earlier rows use the legacy types';
+COMMENT ON COLUMN budget_line_items.cost_share_source IS 'Department account or third party funding the cost share';
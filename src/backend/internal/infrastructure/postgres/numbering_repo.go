@@ -0,0 +1,107 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+)
+
+// NumberingRepository implements the proposal.NumberingRepository interface.
+type NumberingRepository struct {
+	pool *Pool
+}
+
+// NewNumberingRepository creates a new numbering scheme repository.
+func NewNumberingRepository(pool *Pool) *NumberingRepository {
+	return &NumberingRepository{pool: pool}
+}
+
+// FindNumberingScheme retrieves a tenant's numbering scheme.
+func (r *NumberingRepository) FindNumberingScheme(ctx context.Context, tenantID common.TenantID) (*proposal.NumberingScheme, error) {
+	scheme, err := findNumberingScheme(ctx, r.pool, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load numbering scheme: %w", err)
+	}
+	return scheme, nil
+}
+
+// SaveNumberingScheme stores a tenant's numbering scheme.
+func (r *NumberingRepository) SaveNumberingScheme(ctx context.Context, tenantID common.TenantID, scheme proposal.NumberingScheme) error {
+	query := `
+		INSERT INTO proposal_numbering_schemes (tenant_id, pattern, fiscal_year_start_month, tenant_code, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NOW())
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			pattern = EXCLUDED.pattern,
+			fiscal_year_start_month = EXCLUDED.fiscal_year_start_month,
+			tenant_code = EXCLUDED.tenant_code,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), scheme.Pattern, int(scheme.FiscalYearStartMonth), scheme.TenantCode)
+	if err != nil {
+		return fmt.Errorf("failed to save numbering scheme: %w", err)
+	}
+	return nil
+}
+
+// querier is satisfied by both the pool and a transaction.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// findNumberingScheme loads a tenant's scheme, or nil if it has none.
+func findNumberingScheme(ctx context.Context, q querier, tenantID common.TenantID) (*proposal.NumberingScheme, error) {
+	query := `
+		SELECT pattern, fiscal_year_start_month, COALESCE(tenant_code, '')
+		FROM proposal_numbering_schemes
+		WHERE tenant_id = $1
+	`
+
+	var scheme proposal.NumberingScheme
+	var month int
+	err := q.QueryRow(ctx, query, uuid.UUID(tenantID)).Scan(&scheme.Pattern, &month, &scheme.TenantCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	scheme.FiscalYearStartMonth = time.Month(month)
+	return &scheme, nil
+}
+
+// nextProposalNumber draws the next value of the tenant's counter for the
+// fiscal year of at and formats it. The counter row stays locked until the
+// transaction ends, so concurrent inserts are numbered in commit order and a
+// rollback returns the value.
+func nextProposalNumber(ctx context.Context, tx pgx.Tx, tenantID common.TenantID, at time.Time) (string, error) {
+	scheme, err := findNumberingScheme(ctx, tx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load numbering scheme: %w", err)
+	}
+	if scheme == nil {
+		def := proposal.DefaultNumberingScheme()
+		scheme = &def
+	}
+
+	var tenantCode string
+	if scheme.TenantCode == "" {
+		if err := tx.QueryRow(ctx, `SELECT slug FROM tenants WHERE id = $1`, uuid.UUID(tenantID)).Scan(&tenantCode); err != nil {
+			return "", fmt.Errorf("failed to load tenant code: %w", err)
+		}
+	}
+
+	var seq int64
+	if err := tx.QueryRow(ctx, `SELECT next_proposal_sequence($1, $2)`, uuid.UUID(tenantID), scheme.FiscalYear(at)).Scan(&seq); err != nil {
+		return "", fmt.Errorf("failed to allocate proposal number: %w", err)
+	}
+
+	return scheme.Format(tenantCode, at, seq)
+}
@@ -0,0 +1,47 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+)
+
+// PersonRepository looks up persons of a tenant.
+type PersonRepository struct {
+	pool *Pool
+}
+
+// NewPersonRepository creates a new person repository.
+func NewPersonRepository(pool *Pool) *PersonRepository {
+	return &PersonRepository{pool: pool}
+}
+
+// UsersWithRoles returns the IDs of active persons holding any of the roles.
+func (r *PersonRepository) UsersWithRoles(ctx context.Context, tenantID common.TenantID, roles []string) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM persons
+		WHERE tenant_id = $1 AND is_active AND roles && $2
+		ORDER BY id
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), roles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query persons by role: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan person: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,111 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/policy"
+)
+
+// PolicyRepository implements the authz.PolicyRepository interface.
+type PolicyRepository struct {
+	pool *Pool
+}
+
+// NewPolicyRepository creates a new policy repository.
+func NewPolicyRepository(pool *Pool) *PolicyRepository {
+	return &PolicyRepository{pool: pool}
+}
+
+// ListPolicies retrieves the policies of a tenant.
+func (r *PolicyRepository) ListPolicies(ctx context.Context, tenantID common.TenantID) ([]authz.TenantPolicy, error) {
+	query := `
+		SELECT policy_id, COALESCE(description, ''), effect, actions, COALESCE(condition, ''),
+			enabled, updated_by, updated_at
+		FROM authz_policies
+		WHERE tenant_id = $1
+		ORDER BY policy_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]authz.TenantPolicy, 0)
+	for rows.Next() {
+		var p authz.TenantPolicy
+		var effect string
+		if err := rows.Scan(
+			&p.ID,
+			&p.Description,
+			&effect,
+			&p.Actions,
+			&p.Condition,
+			&p.Enabled,
+			&p.UpdatedBy,
+			&p.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan policy: %w", err)
+		}
+		p.Effect = policy.Effect(effect)
+		policies = append(policies, p)
+	}
+
+	return policies, rows.Err()
+}
+
+// SavePolicy persists a tenant policy (insert or update).
+func (r *PolicyRepository) SavePolicy(ctx context.Context, tenantID common.TenantID, p authz.TenantPolicy) error {
+	query := `
+		INSERT INTO authz_policies (
+			tenant_id, policy_id, description, effect, actions, condition,
+			enabled, updated_by, updated_at
+		) VALUES ($1, $2, NULLIF($3, ''), $4, $5, NULLIF($6, ''), $7, $8, $9)
+		ON CONFLICT (tenant_id, policy_id) DO UPDATE SET
+			description = EXCLUDED.description,
+			effect = EXCLUDED.effect,
+			actions = EXCLUDED.actions,
+			condition = EXCLUDED.condition,
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		uuid.UUID(tenantID),
+		p.ID,
+		p.Description,
+		string(p.Effect),
+		p.Actions,
+		p.Condition,
+		p.Enabled,
+		p.UpdatedBy,
+		p.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save policy: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePolicy removes a tenant policy.
+func (r *PolicyRepository) DeletePolicy(ctx context.Context, tenantID common.TenantID, id string) error {
+	query := `DELETE FROM authz_policies WHERE tenant_id = $1 AND policy_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return authz.ErrPolicyNotFound
+	}
+
+	return nil
+}
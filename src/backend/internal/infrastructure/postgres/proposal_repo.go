@@ -28,30 +28,103 @@ func NewProposalRepository(pool *Pool) *ProposalRepository {
 
 // Save persists a proposal (insert or update).
 func (r *ProposalRepository) Save(ctx context.Context, p *proposal.Proposal) error {
+	if p.ProposalNumber == "" {
+		return r.insertNumbered(ctx, p)
+	}
+
+	query, args, err := saveProposalQuery(p, p.ProposalNumber)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save proposal: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return proposal.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// SaveAll persists several proposals in one transaction. Proposals without
+// a number are numbered in the same transaction.
+func (r *ProposalRepository) SaveAll(ctx context.Context, proposals ...*proposal.Proposal) error {
+	numbered := make([]bool, len(proposals))
+	err := r.pool.WithTx(ctx, func(tx pgx.Tx) error {
+		for i, p := range proposals {
+			if p.ProposalNumber == "" {
+				if err := insertNumberedTx(ctx, tx, p); err != nil {
+					return err
+				}
+				numbered[i] = true
+				continue
+			}
+
+			query, args, err := saveProposalQuery(p, p.ProposalNumber)
+			if err != nil {
+				return err
+			}
+
+			result, err := tx.Exec(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to save proposal: %w", err)
+			}
+			if result.RowsAffected() == 0 {
+				return proposal.ErrVersionMismatch
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i, p := range proposals {
+			if numbered[i] {
+				p.ProposalNumber = "" // not committed
+			}
+		}
+	}
+	return err
+}
+
+// saveProposalQuery builds the upsert of a proposal and its arguments,
+// saving the proposal under the given number.
+func saveProposalQuery(p *proposal.Proposal, number string) (string, []interface{}, error) {
 	// Convert complex fields to JSON
 	coInvestigatorsJSON, err := json.Marshal(p.CoInvestigators)
 	if err != nil {
-		return fmt.Errorf("failed to marshal co-investigators: %w", err)
+		return "", nil, fmt.Errorf("failed to marshal co-investigators: %w", err)
 	}
 
 	keyPersonnelJSON, err := json.Marshal(p.KeyPersonnel)
 	if err != nil {
-		return fmt.Errorf("failed to marshal key personnel: %w", err)
+		return "", nil, fmt.Errorf("failed to marshal key personnel: %w", err)
 	}
 
 	keywordsJSON, err := json.Marshal(p.Keywords)
 	if err != nil {
-		return fmt.Errorf("failed to marshal keywords: %w", err)
+		return "", nil, fmt.Errorf("failed to marshal keywords: %w", err)
 	}
 
 	stateHistoryJSON, err := json.Marshal(p.StateHistory)
 	if err != nil {
-		return fmt.Errorf("failed to marshal state history: %w", err)
+		return "", nil, fmt.Errorf("failed to marshal state history: %w", err)
 	}
 
 	attachmentsJSON, err := json.Marshal(p.Attachments)
 	if err != nil {
-		return fmt.Errorf("failed to marshal attachments: %w", err)
+		return "", nil, fmt.Errorf("failed to marshal attachments: %w", err)
+	}
+
+	subStatesJSON, err := json.Marshal(p.SubStates)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal sub-states: %w", err)
+	}
+
+	sectionVersionsJSON, err := json.Marshal(p.SectionVersions)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal section versions: %w", err)
 	}
 
 	query := `
@@ -62,11 +135,13 @@ func (r *ProposalRepository) Save(ctx context.Context, p *proposal.Proposal) err
 			project_start_date, project_end_date, department, research_area, keywords,
 			budget_id, irb_required, iacuc_required, ibc_required, export_control,
 			conflict_of_interest, embedding, state_history, attachments,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
 			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29,
-			$30, $31, $32, $33, $34
+			$30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43
 		)
 		ON CONFLICT (id) DO UPDATE SET
 			title = EXCLUDED.title,
@@ -91,6 +166,14 @@ func (r *ProposalRepository) Save(ctx context.Context, p *proposal.Proposal) err
 			embedding = EXCLUDED.embedding,
 			state_history = EXCLUDED.state_history,
 			attachments = EXCLUDED.attachments,
+			workflow_version = EXCLUDED.workflow_version,
+			sub_states = EXCLUDED.sub_states,
+			is_latest_version = EXCLUDED.is_latest_version,
+			proposal_type = EXCLUDED.proposal_type,
+			predecessor_id = EXCLUDED.predecessor_id,
+			section_versions = EXCLUDED.section_versions,
+			deadline_time_zone = EXCLUDED.deadline_time_zone,
+			template_id = EXCLUDED.template_id,
 			updated_at = EXCLUDED.updated_at,
 			updated_by = EXCLUDED.updated_by,
 			version = proposals.version + 1
@@ -102,14 +185,14 @@ func (r *ProposalRepository) Save(ctx context.Context, p *proposal.Proposal) err
 		embeddingValue = p.Embedding
 	}
 
-	result, err := r.pool.Exec(ctx, query,
+	args := []interface{}{
 		p.ID,
 		uuid.UUID(p.TenantID),
 		p.Title,
 		p.ShortTitle,
 		p.Abstract,
 		p.State,
-		p.ProposalNumber,
+		number,
 		p.ExternalID,
 		p.PrincipalInvestigatorID,
 		coInvestigatorsJSON,
@@ -137,16 +220,54 @@ func (r *ProposalRepository) Save(ctx context.Context, p *proposal.Proposal) err
 		p.CreatedBy,
 		p.UpdatedBy,
 		p.Version,
-	)
+		p.WorkflowVersion,
+		subStatesJSON,
+		p.ParentVersionID,
+		p.IsLatestVersion,
+		string(p.Type),
+		p.PredecessorID,
+		sectionVersionsJSON,
+		p.DeadlineTimeZone,
+		p.TemplateID,
+	}
+
+	return query, args, nil
+}
 
+// insertNumbered inserts a new proposal under the next number of its
+// tenant's scheme. The counter is incremented in the same transaction, so a
+// failed insert leaves no gap in the sequence.
+func (r *ProposalRepository) insertNumbered(ctx context.Context, p *proposal.Proposal) error {
+	err := r.pool.WithTx(ctx, func(tx pgx.Tx) error {
+		return insertNumberedTx(ctx, tx, p)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save proposal: %w", err)
+		p.ProposalNumber = "" // not committed
 	}
+	return err
+}
 
+// insertNumberedTx numbers and inserts a new proposal within a transaction.
+func insertNumberedTx(ctx context.Context, tx pgx.Tx, p *proposal.Proposal) error {
+	number, err := nextProposalNumber(ctx, tx, p.TenantID, p.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := saveProposalQuery(p, number)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to save proposal: %w", err)
+	}
 	if result.RowsAffected() == 0 {
 		return proposal.ErrVersionMismatch
 	}
 
+	p.ProposalNumber = number
 	return nil
 }
 
@@ -159,7 +280,9 @@ func (r *ProposalRepository) FindByID(ctx context.Context, tenantID common.Tenan
 			project_start_date, project_end_date, department, research_area, keywords,
 			budget_id, irb_required, iacuc_required, ibc_required, export_control,
 			conflict_of_interest, embedding, state_history, attachments,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
 		FROM proposals
 		WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 	`
@@ -177,7 +300,9 @@ func (r *ProposalRepository) FindByNumber(ctx context.Context, tenantID common.T
 			project_start_date, project_end_date, department, research_area, keywords,
 			budget_id, irb_required, iacuc_required, ibc_required, export_control,
 			conflict_of_interest, embedding, state_history, attachments,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
 		FROM proposals
 		WHERE proposal_number = $1 AND tenant_id = $2 AND deleted_at IS NULL
 	`
@@ -190,7 +315,7 @@ func (r *ProposalRepository) FindByNumber(ctx context.Context, tenantID common.T
 func (r *ProposalRepository) scanProposal(row pgx.Row) (*proposal.Proposal, error) {
 	var p proposal.Proposal
 	var tenantUUID uuid.UUID
-	var coInvestigatorsJSON, keyPersonnelJSON, keywordsJSON, stateHistoryJSON, attachmentsJSON []byte
+	var coInvestigatorsJSON, keyPersonnelJSON, keywordsJSON, stateHistoryJSON, attachmentsJSON, subStatesJSON, sectionVersionsJSON []byte
 	var embedding pgvector.Vector
 	var opportunityID, budgetID *uuid.UUID
 	var sponsorDeadline, internalDeadline *time.Time
@@ -230,6 +355,15 @@ func (r *ProposalRepository) scanProposal(row pgx.Row) (*proposal.Proposal, erro
 		&p.CreatedBy,
 		&p.UpdatedBy,
 		&p.Version,
+		&p.WorkflowVersion,
+		&subStatesJSON,
+		&p.ParentVersionID,
+		&p.IsLatestVersion,
+		&p.Type,
+		&p.PredecessorID,
+		&sectionVersionsJSON,
+		&p.DeadlineTimeZone,
+		&p.TemplateID,
 	)
 
 	if err != nil {
@@ -262,6 +396,12 @@ func (r *ProposalRepository) scanProposal(row pgx.Row) (*proposal.Proposal, erro
 	if err := json.Unmarshal(attachmentsJSON, &p.Attachments); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal attachments: %w", err)
 	}
+	if err := json.Unmarshal(subStatesJSON, &p.SubStates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sub-states: %w", err)
+	}
+	if err := json.Unmarshal(sectionVersionsJSON, &p.SectionVersions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal section versions: %w", err)
+	}
 
 	return &p, nil
 }
@@ -313,6 +453,16 @@ func (r *ProposalRepository) List(ctx context.Context, tenantID common.TenantID,
 		conditions = append(conditions, fmt.Sprintf("state IN (%s)", strings.Join(placeholders, ", ")))
 	}
 
+	if len(filter.Types) > 0 {
+		placeholders := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			placeholders[i] = fmt.Sprintf("$%d", argNum)
+			args = append(args, string(t))
+			argNum++
+		}
+		conditions = append(conditions, fmt.Sprintf("proposal_type IN (%s)", strings.Join(placeholders, ", ")))
+	}
+
 	if len(filter.Departments) > 0 {
 		placeholders := make([]string, len(filter.Departments))
 		for i, d := range filter.Departments {
@@ -387,7 +537,9 @@ func (r *ProposalRepository) List(ctx context.Context, tenantID common.TenantID,
 			project_start_date, project_end_date, department, research_area, keywords,
 			budget_id, irb_required, iacuc_required, ibc_required, export_control,
 			conflict_of_interest, embedding, state_history, attachments,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
 		FROM proposals
 		WHERE %s
 		ORDER BY %s %s
@@ -418,7 +570,7 @@ func (r *ProposalRepository) List(ctx context.Context, tenantID common.TenantID,
 func (r *ProposalRepository) scanProposalFromRows(rows pgx.Rows) (*proposal.Proposal, error) {
 	var p proposal.Proposal
 	var tenantUUID uuid.UUID
-	var coInvestigatorsJSON, keyPersonnelJSON, keywordsJSON, stateHistoryJSON, attachmentsJSON []byte
+	var coInvestigatorsJSON, keyPersonnelJSON, keywordsJSON, stateHistoryJSON, attachmentsJSON, subStatesJSON, sectionVersionsJSON []byte
 	var embedding pgvector.Vector
 	var opportunityID, budgetID *uuid.UUID
 	var sponsorDeadline, internalDeadline *time.Time
@@ -458,6 +610,15 @@ func (r *ProposalRepository) scanProposalFromRows(rows pgx.Rows) (*proposal.Prop
 		&p.CreatedBy,
 		&p.UpdatedBy,
 		&p.Version,
+		&p.WorkflowVersion,
+		&subStatesJSON,
+		&p.ParentVersionID,
+		&p.IsLatestVersion,
+		&p.Type,
+		&p.PredecessorID,
+		&sectionVersionsJSON,
+		&p.DeadlineTimeZone,
+		&p.TemplateID,
 	)
 
 	if err != nil {
@@ -477,6 +638,8 @@ func (r *ProposalRepository) scanProposalFromRows(rows pgx.Rows) (*proposal.Prop
 	_ = json.Unmarshal(keywordsJSON, &p.Keywords)
 	_ = json.Unmarshal(stateHistoryJSON, &p.StateHistory)
 	_ = json.Unmarshal(attachmentsJSON, &p.Attachments)
+	_ = json.Unmarshal(subStatesJSON, &p.SubStates)
+	_ = json.Unmarshal(sectionVersionsJSON, &p.SectionVersions)
 
 	return &p, nil
 }
@@ -490,8 +653,9 @@ func (r *ProposalRepository) Search(ctx context.Context, tenantID common.TenantI
 			project_start_date, project_end_date, department, research_area, keywords,
 			budget_id, irb_required, iacuc_required, ibc_required, export_control,
 			conflict_of_interest, embedding, state_history, attachments,
-			created_at, updated_at, created_by, updated_by, version,
-			1 - (embedding <=> $1) AS similarity
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id, 1 - (embedding <=> $1) AS similarity
 		FROM proposals
 		WHERE tenant_id = $2
 			AND deleted_at IS NULL
@@ -512,7 +676,7 @@ func (r *ProposalRepository) Search(ctx context.Context, tenantID common.TenantI
 	for rows.Next() {
 		var p proposal.Proposal
 		var tenantUUID uuid.UUID
-		var coInvestigatorsJSON, keyPersonnelJSON, keywordsJSON, stateHistoryJSON, attachmentsJSON []byte
+		var coInvestigatorsJSON, keyPersonnelJSON, keywordsJSON, stateHistoryJSON, attachmentsJSON, subStatesJSON, sectionVersionsJSON []byte
 		var emb pgvector.Vector
 		var opportunityID, budgetID *uuid.UUID
 		var sponsorDeadline, internalDeadline *time.Time
@@ -553,6 +717,15 @@ func (r *ProposalRepository) Search(ctx context.Context, tenantID common.TenantI
 			&p.CreatedBy,
 			&p.UpdatedBy,
 			&p.Version,
+			&p.WorkflowVersion,
+			&subStatesJSON,
+			&p.ParentVersionID,
+			&p.IsLatestVersion,
+			&p.Type,
+			&p.PredecessorID,
+			&sectionVersionsJSON,
+			&p.DeadlineTimeZone,
+			&p.TemplateID,
 			&similarity,
 		)
 
@@ -572,6 +745,8 @@ func (r *ProposalRepository) Search(ctx context.Context, tenantID common.TenantI
 		_ = json.Unmarshal(keywordsJSON, &p.Keywords)
 		_ = json.Unmarshal(stateHistoryJSON, &p.StateHistory)
 		_ = json.Unmarshal(attachmentsJSON, &p.Attachments)
+		_ = json.Unmarshal(subStatesJSON, &p.SubStates)
+		_ = json.Unmarshal(sectionVersionsJSON, &p.SectionVersions)
 
 		results = append(results, &proposal.ProposalSearchResult{
 			Proposal:   &p,
@@ -611,7 +786,9 @@ func (r *ProposalRepository) GetUpcomingDeadlines(ctx context.Context, tenantID
 			project_start_date, project_end_date, department, research_area, keywords,
 			budget_id, irb_required, iacuc_required, ibc_required, export_control,
 			conflict_of_interest, embedding, state_history, attachments,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
 		FROM proposals
 		WHERE tenant_id = $1
 			AND deleted_at IS NULL
@@ -650,7 +827,9 @@ func (r *ProposalRepository) GetOverdue(ctx context.Context, tenantID common.Ten
 			project_start_date, project_end_date, department, research_area, keywords,
 			budget_id, irb_required, iacuc_required, ibc_required, export_control,
 			conflict_of_interest, embedding, state_history, attachments,
-			created_at, updated_at, created_by, updated_by, version
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
 		FROM proposals
 		WHERE tenant_id = $1
 			AND deleted_at IS NULL
@@ -678,6 +857,53 @@ func (r *ProposalRepository) GetOverdue(ctx context.Context, tenantID common.Ten
 	return proposals, nil
 }
 
+// FindInStates retrieves proposals of all tenants whose state, or the active
+// sub-state of one of their regions, is one of the given states.
+func (r *ProposalRepository) FindInStates(ctx context.Context, states []proposal.ProposalState) ([]*proposal.Proposal, error) {
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT id, tenant_id, title, short_title, abstract, state, proposal_number,
+			external_id, principal_investigator_id, co_investigators, key_personnel,
+			sponsor_id, opportunity_id, sponsor_deadline, internal_deadline,
+			project_start_date, project_end_date, department, research_area, keywords,
+			budget_id, irb_required, iacuc_required, ibc_required, export_control,
+			conflict_of_interest, embedding, state_history, attachments,
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
+		FROM proposals
+		WHERE deleted_at IS NULL
+			AND (state = ANY($1)
+				OR EXISTS (SELECT 1 FROM jsonb_each_text(sub_states) s WHERE s.value = ANY($1)))
+		ORDER BY updated_at ASC
+	`
+
+	names := make([]string, len(states))
+	for i, s := range states {
+		names[i] = s.String()
+	}
+
+	rows, err := r.pool.Query(ctx, query, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proposals by state: %w", err)
+	}
+	defer rows.Close()
+
+	var proposals []*proposal.Proposal
+	for rows.Next() {
+		p, err := r.scanProposalFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, p)
+	}
+
+	return proposals, nil
+}
+
 // CountByState returns the count of proposals by state.
 func (r *ProposalRepository) CountByState(ctx context.Context, tenantID common.TenantID) (map[proposal.ProposalState]int64, error) {
 	query := `
@@ -717,3 +943,57 @@ func (r *ProposalRepository) GetStateHistory(ctx context.Context, tenantID commo
 	}
 	return p.StateHistory, nil
 }
+
+// FindLineage retrieves the family of proposals sharing an original
+// proposal with the given one: its predecessors and everything derived from
+// the original.
+func (r *ProposalRepository) FindLineage(ctx context.Context, tenantID common.TenantID, id uuid.UUID) ([]*proposal.Proposal, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, predecessor_id
+			FROM proposals
+			WHERE id = $1 AND tenant_id = $2
+			UNION
+			SELECT p.id, p.predecessor_id
+			FROM proposals p
+			JOIN ancestors a ON p.id = a.predecessor_id
+			WHERE p.tenant_id = $2
+		), family AS (
+			SELECT id FROM ancestors WHERE predecessor_id IS NULL
+			UNION
+			SELECT p.id
+			FROM proposals p
+			JOIN family f ON p.predecessor_id = f.id
+			WHERE p.tenant_id = $2
+		)
+		SELECT id, tenant_id, title, short_title, abstract, state, proposal_number,
+			external_id, principal_investigator_id, co_investigators, key_personnel,
+			sponsor_id, opportunity_id, sponsor_deadline, internal_deadline,
+			project_start_date, project_end_date, department, research_area, keywords,
+			budget_id, irb_required, iacuc_required, ibc_required, export_control,
+			conflict_of_interest, embedding, state_history, attachments,
+			created_at, updated_at, created_by, updated_by, version, workflow_version,
+			sub_states, parent_version_id, is_latest_version,
+			proposal_type, predecessor_id, section_versions, deadline_time_zone, template_id
+		FROM proposals
+		WHERE id IN (SELECT id FROM family) AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, id, uuid.UUID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proposal lineage: %w", err)
+	}
+	defer rows.Close()
+
+	var proposals []*proposal.Proposal
+	for rows.Next() {
+		p, err := r.scanProposalFromRows(rows)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, p)
+	}
+
+	return proposals, rows.Err()
+}
@@ -0,0 +1,281 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// foreignKeyViolation is the PostgreSQL error code of a foreign key violation.
+const foreignKeyViolation = "23503"
+
+// RateAgreementRepository implements the budget.RateAgreementRepository interface.
+type RateAgreementRepository struct {
+	pool *Pool
+}
+
+// NewRateAgreementRepository creates a new rate agreement repository.
+func NewRateAgreementRepository(pool *Pool) *RateAgreementRepository {
+	return &RateAgreementRepository{pool: pool}
+}
+
+// SaveAgreement stores an agreement and replaces its rates in one
+// transaction. Saving an agreement that changed since it was loaded fails
+// with proposal.ErrVersionMismatch.
+func (r *RateAgreementRepository) SaveAgreement(ctx context.Context, a *budget.RateAgreement) error {
+	exclusionsJSON, err := json.Marshal(a.ExcludedItems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal MTDC exclusions: %w", err)
+	}
+
+	query := `
+		INSERT INTO rate_agreements (
+			id, tenant_id, name, cognizant_agency, agreement_date, status, rate_type, subaward_cap,
+			equipment_cap, excluded_items, notes, created_at, updated_at, created_by, updated_by, version
+		) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13, $14, $15, $16)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			cognizant_agency = EXCLUDED.cognizant_agency,
+			agreement_date = EXCLUDED.agreement_date,
+			status = EXCLUDED.status,
+			rate_type = EXCLUDED.rate_type,
+			subaward_cap = EXCLUDED.subaward_cap,
+			equipment_cap = EXCLUDED.equipment_cap,
+			excluded_items = EXCLUDED.excluded_items,
+			notes = EXCLUDED.notes,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			version = EXCLUDED.version
+		WHERE rate_agreements.version < EXCLUDED.version
+	`
+
+	tenantID := uuid.UUID(a.TenantID)
+	return r.pool.WithTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query,
+			a.ID, tenantID, a.Name, a.CognizantAgency, a.AgreementDate, string(a.Status), a.RateType, a.SubawardCap,
+			a.EquipmentCap, exclusionsJSON, a.Notes, a.CreatedAt, a.UpdatedAt, a.CreatedBy, a.UpdatedBy, a.Version)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+				return fmt.Errorf("%w: another agreement is named %q", budget.ErrInvalidRateAgreement, a.Name)
+			}
+			return fmt.Errorf("failed to save rate agreement: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return proposal.ErrVersionMismatch
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM negotiated_rates WHERE agreement_id = $1`, a.ID); err != nil {
+			return fmt.Errorf("failed to clear negotiated rates: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM fringe_rates WHERE agreement_id = $1`, a.ID); err != nil {
+			return fmt.Errorf("failed to clear fringe rates: %w", err)
+		}
+
+		for _, rate := range a.Rates {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO negotiated_rates (
+					tenant_id, agreement_id, activity_type, on_campus_rate, off_campus_rate, effective_from, effective_to
+				) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				tenantID, a.ID, string(rate.ActivityType), rate.OnCampusRate, rate.OffCampusRate,
+				rate.EffectiveFrom, nullableDate(rate.EffectiveTo))
+			if err != nil {
+				return fmt.Errorf("failed to save negotiated rate: %w", err)
+			}
+		}
+		for _, fringe := range a.FringeRates {
+			_, err := tx.Exec(ctx, `
+				INSERT INTO fringe_rates (
+					tenant_id, agreement_id, employee_class, rate, effective_from, effective_to
+				) VALUES ($1, $2, $3, $4, $5, $6)`,
+				tenantID, a.ID, fringe.EmployeeClass, fringe.Rate, fringe.EffectiveFrom, nullableDate(fringe.EffectiveTo))
+			if err != nil {
+				return fmt.Errorf("failed to save fringe rate: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// nullableDate maps the zero time of an open-ended range to NULL.
+func nullableDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// agreementColumns are the columns scanned by scanAgreement.
+const agreementColumns = `id, tenant_id, name, COALESCE(cognizant_agency, ''), agreement_date, status, rate_type,
+	subaward_cap, equipment_cap, excluded_items, COALESCE(notes, ''), created_at, updated_at, created_by, updated_by, version`
+
+// FindAgreement retrieves an agreement with its rates by ID.
+func (r *RateAgreementRepository) FindAgreement(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*budget.RateAgreement, error) {
+	query := `SELECT ` + agreementColumns + ` FROM rate_agreements WHERE id = $1 AND tenant_id = $2`
+
+	a, err := scanAgreement(r.pool.QueryRow(ctx, query, id, uuid.UUID(tenantID)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load rate agreement: %w", err)
+	}
+
+	if err := r.loadRates(ctx, []*budget.RateAgreement{a}); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListAgreements retrieves the tenant's agreements with their rates, the
+// latest agreement first.
+func (r *RateAgreementRepository) ListAgreements(ctx context.Context, tenantID common.TenantID) ([]*budget.RateAgreement, error) {
+	query := `
+		SELECT ` + agreementColumns + `
+		FROM rate_agreements
+		WHERE tenant_id = $1
+		ORDER BY COALESCE(agreement_date, created_at::date) DESC, name
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rate agreements: %w", err)
+	}
+	defer rows.Close()
+
+	agreements := make([]*budget.RateAgreement, 0)
+	for rows.Next() {
+		a, err := scanAgreement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rate agreement: %w", err)
+		}
+		agreements = append(agreements, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadRates(ctx, agreements); err != nil {
+		return nil, err
+	}
+	return agreements, nil
+}
+
+// DeleteAgreement removes an agreement and its rates. Agreements budgets
+// used are kept and fail with budget.ErrRateAgreementInUse.
+func (r *RateAgreementRepository) DeleteAgreement(ctx context.Context, tenantID common.TenantID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM rate_agreements WHERE id = $1 AND tenant_id = $2`, id, uuid.UUID(tenantID))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return budget.ErrRateAgreementInUse
+		}
+		return fmt.Errorf("failed to delete rate agreement: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return budget.ErrRateAgreementNotFound
+	}
+	return nil
+}
+
+// loadRates attaches the negotiated and fringe rates of agreements.
+func (r *RateAgreementRepository) loadRates(ctx context.Context, agreements []*budget.RateAgreement) error {
+	if len(agreements) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(agreements))
+	byID := make(map[uuid.UUID]*budget.RateAgreement, len(agreements))
+	for i, a := range agreements {
+		ids[i] = a.ID
+		byID[a.ID] = a
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT agreement_id, activity_type, on_campus_rate, off_campus_rate, effective_from, effective_to
+		FROM negotiated_rates
+		WHERE agreement_id = ANY($1)
+		ORDER BY agreement_id, activity_type, effective_from`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to query negotiated rates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var agreementID uuid.UUID
+		var rate budget.NegotiatedRate
+		var activity string
+		var effectiveTo *time.Time
+		if err := rows.Scan(&agreementID, &activity, &rate.OnCampusRate, &rate.OffCampusRate,
+			&rate.EffectiveFrom, &effectiveTo); err != nil {
+			return fmt.Errorf("failed to scan negotiated rate: %w", err)
+		}
+		rate.ActivityType = budget.ActivityType(activity)
+		if effectiveTo != nil {
+			rate.EffectiveTo = *effectiveTo
+		}
+		a := byID[agreementID]
+		a.Rates = append(a.Rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	fringeRows, err := r.pool.Query(ctx, `
+		SELECT agreement_id, employee_class, rate, effective_from, effective_to
+		FROM fringe_rates
+		WHERE agreement_id = ANY($1)
+		ORDER BY agreement_id, employee_class, effective_from`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to query fringe rates: %w", err)
+	}
+	defer fringeRows.Close()
+
+	for fringeRows.Next() {
+		var agreementID uuid.UUID
+		var fringe budget.FringeRate
+		var effectiveTo *time.Time
+		if err := fringeRows.Scan(&agreementID, &fringe.EmployeeClass, &fringe.Rate,
+			&fringe.EffectiveFrom, &effectiveTo); err != nil {
+			return fmt.Errorf("failed to scan fringe rate: %w", err)
+		}
+		if effectiveTo != nil {
+			fringe.EffectiveTo = *effectiveTo
+		}
+		a := byID[agreementID]
+		a.FringeRates = append(a.FringeRates, fringe)
+	}
+	return fringeRows.Err()
+}
+
+// scanAgreement scans a row of agreementColumns.
+func scanAgreement(row pgx.Row) (*budget.RateAgreement, error) {
+	var a budget.RateAgreement
+	var tenantUUID uuid.UUID
+	var status string
+	var exclusionsJSON []byte
+	if err := row.Scan(&a.ID, &tenantUUID, &a.Name, &a.CognizantAgency, &a.AgreementDate, &status, &a.RateType,
+		&a.SubawardCap, &a.EquipmentCap, &exclusionsJSON, &a.Notes, &a.CreatedAt, &a.UpdatedAt,
+		&a.CreatedBy, &a.UpdatedBy, &a.Version); err != nil {
+		return nil, err
+	}
+	a.TenantID = common.TenantID(tenantUUID)
+	a.Status = budget.AgreementStatus(status)
+
+	if err := json.Unmarshal(exclusionsJSON, &a.ExcludedItems); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal MTDC exclusions: %w", err)
+	}
+	a.Rates = make([]budget.NegotiatedRate, 0)
+	a.FringeRates = make([]budget.FringeRate, 0)
+	return &a, nil
+}
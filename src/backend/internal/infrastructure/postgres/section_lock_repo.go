@@ -0,0 +1,154 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+)
+
+// SectionLockRepository implements the proposal.SectionLockRepository interface.
+type SectionLockRepository struct {
+	pool *Pool
+}
+
+// NewSectionLockRepository creates a new section lock repository.
+func NewSectionLockRepository(pool *Pool) *SectionLockRepository {
+	return &SectionLockRepository{pool: pool}
+}
+
+// sectionLockColumns lists the columns scanned by scanSectionLock.
+const sectionLockColumns = `tenant_id, proposal_id, section, holder_id, acquired_at, heartbeat_at, expires_at`
+
+// AcquireLock takes a section lock when it is free, expired or already held
+// by the same user, and returns the lock in force afterwards.
+func (r *SectionLockRepository) AcquireLock(ctx context.Context, l *proposal.SectionLock) (*proposal.SectionLock, error) {
+	query := `
+		INSERT INTO proposal_section_locks (
+			tenant_id, proposal_id, section, holder_id, acquired_at, heartbeat_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (proposal_id, section) DO UPDATE SET
+			holder_id = EXCLUDED.holder_id,
+			acquired_at = CASE
+				WHEN proposal_section_locks.holder_id = EXCLUDED.holder_id
+					AND proposal_section_locks.expires_at > EXCLUDED.heartbeat_at
+				THEN proposal_section_locks.acquired_at
+				ELSE EXCLUDED.acquired_at
+			END,
+			heartbeat_at = EXCLUDED.heartbeat_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE proposal_section_locks.holder_id = EXCLUDED.holder_id
+			OR proposal_section_locks.expires_at <= EXCLUDED.heartbeat_at
+		RETURNING ` + sectionLockColumns
+
+	row := r.pool.QueryRow(ctx, query,
+		uuid.UUID(l.TenantID),
+		l.ProposalID,
+		string(l.Section),
+		l.HolderID,
+		l.AcquiredAt,
+		l.HeartbeatAt,
+		l.ExpiresAt,
+	)
+	acquired, err := scanSectionLock(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire section lock: %w", err)
+	}
+	if acquired != nil {
+		return acquired, nil
+	}
+
+	// Another user holds an active lock
+	query = `
+		SELECT ` + sectionLockColumns + `
+		FROM proposal_section_locks
+		WHERE tenant_id = $1 AND proposal_id = $2 AND section = $3
+	`
+	current, err := scanSectionLock(r.pool.QueryRow(ctx, query, uuid.UUID(l.TenantID), l.ProposalID, string(l.Section)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load section lock: %w", err)
+	}
+	return current, nil
+}
+
+// RenewLock extends an active lock held by the holder.
+func (r *SectionLockRepository) RenewLock(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, section proposal.Section, holderID uuid.UUID, at, expiresAt time.Time) (*proposal.SectionLock, error) {
+	query := `
+		UPDATE proposal_section_locks
+		SET heartbeat_at = $5, expires_at = $6
+		WHERE tenant_id = $1 AND proposal_id = $2 AND section = $3
+			AND holder_id = $4 AND expires_at > $5
+		RETURNING ` + sectionLockColumns
+
+	l, err := scanSectionLock(r.pool.QueryRow(ctx, query, uuid.UUID(tenantID), proposalID, string(section), holderID, at, expiresAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew section lock: %w", err)
+	}
+	return l, nil
+}
+
+// ReleaseLock removes a lock held by the holder.
+func (r *SectionLockRepository) ReleaseLock(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, section proposal.Section, holderID uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM proposal_section_locks
+		WHERE tenant_id = $1 AND proposal_id = $2 AND section = $3 AND holder_id = $4
+	`
+
+	result, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), proposalID, string(section), holderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to release section lock: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// FindLocks retrieves the active locks on a proposal ordered by section.
+func (r *SectionLockRepository) FindLocks(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, at time.Time) ([]proposal.SectionLock, error) {
+	query := `
+		SELECT ` + sectionLockColumns + `
+		FROM proposal_section_locks
+		WHERE tenant_id = $1 AND proposal_id = $2 AND expires_at > $3
+		ORDER BY section ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), proposalID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query section locks: %w", err)
+	}
+	defer rows.Close()
+
+	locks := make([]proposal.SectionLock, 0)
+	for rows.Next() {
+		l, err := scanSectionLock(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan section lock: %w", err)
+		}
+		locks = append(locks, *l)
+	}
+
+	return locks, rows.Err()
+}
+
+// scanSectionLock scans a section lock, returning nil if there is no row.
+func scanSectionLock(row pgx.Row) (*proposal.SectionLock, error) {
+	var l proposal.SectionLock
+	var tenantUUID uuid.UUID
+	var section string
+
+	err := row.Scan(&tenantUUID, &l.ProposalID, &section, &l.HolderID, &l.AcquiredAt, &l.HeartbeatAt, &l.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	l.TenantID = common.TenantID(tenantUUID)
+	l.Section = proposal.Section(section)
+	return &l, nil
+}
@@ -0,0 +1,71 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+)
+
+// SLABreachRepository implements the sla.BreachStore interface.
+type SLABreachRepository struct {
+	pool *Pool
+}
+
+// NewSLABreachRepository creates a new SLA breach repository.
+func NewSLABreachRepository(pool *Pool) *SLABreachRepository {
+	return &SLABreachRepository{pool: pool}
+}
+
+// IsRecorded reports whether a breach has already been recorded.
+func (r *SLABreachRepository) IsRecorded(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, breach proposal.SLABreach) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM sla_breaches
+			WHERE tenant_id = $1 AND proposal_id = $2 AND state = $3 AND region = $4 AND entered_at = $5
+		)
+	`
+
+	var recorded bool
+	err := r.pool.QueryRow(ctx, query,
+		uuid.UUID(tenantID),
+		proposalID,
+		breach.State.String(),
+		breach.Region,
+		breach.EnteredAt,
+	).Scan(&recorded)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up SLA breach: %w", err)
+	}
+
+	return recorded, nil
+}
+
+// RecordBreach stores a breach and reports whether it had not been recorded before.
+func (r *SLABreachRepository) RecordBreach(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, breach proposal.SLABreach, action string) (bool, error) {
+	query := `
+		INSERT INTO sla_breaches (
+			tenant_id, proposal_id, state, region, sla_hours, entered_at, due_at, action
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (proposal_id, state, region, entered_at) DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query,
+		uuid.UUID(tenantID),
+		proposalID,
+		breach.State.String(),
+		breach.Region,
+		breach.SLAHours,
+		breach.EnteredAt,
+		breach.DueAt,
+		action,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record SLA breach: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
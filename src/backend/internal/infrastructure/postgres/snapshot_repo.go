@@ -0,0 +1,138 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+)
+
+// SnapshotRepository implements the proposal.SnapshotRepository interface.
+type SnapshotRepository struct {
+	pool *Pool
+}
+
+// NewSnapshotRepository creates a new proposal snapshot repository.
+func NewSnapshotRepository(pool *Pool) *SnapshotRepository {
+	return &SnapshotRepository{pool: pool}
+}
+
+// SaveSnapshot persists a snapshot, numbering it after the proposal's
+// latest snapshot.
+func (r *SnapshotRepository) SaveSnapshot(ctx context.Context, s *proposal.Snapshot) error {
+	contentJSON, err := json.Marshal(s.Content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot content: %w", err)
+	}
+
+	query := `
+		INSERT INTO proposal_snapshots (
+			id, tenant_id, proposal_id, number, proposal_version, transition,
+			state, content, created_by, created_at
+		)
+		SELECT $1, $2, $3, COALESCE(MAX(number), 0) + 1, $4, $5, $6, $7, $8, $9
+		FROM proposal_snapshots
+		WHERE tenant_id = $2 AND proposal_id = $3
+		RETURNING number
+	`
+
+	err = r.pool.QueryRow(ctx, query,
+		s.ID,
+		uuid.UUID(s.TenantID),
+		s.ProposalID,
+		s.ProposalVersion,
+		string(s.Transition),
+		s.State.String(),
+		contentJSON,
+		s.CreatedBy,
+		s.CreatedAt,
+	).Scan(&s.Number)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// FindSnapshots retrieves the snapshots of a proposal ordered by number.
+func (r *SnapshotRepository) FindSnapshots(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID) ([]proposal.Snapshot, error) {
+	query := `
+		SELECT id, tenant_id, proposal_id, number, proposal_version, transition,
+			state, content, created_by, created_at
+		FROM proposal_snapshots
+		WHERE tenant_id = $1 AND proposal_id = $2
+		ORDER BY number ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), proposalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]proposal.Snapshot, 0)
+	for rows.Next() {
+		s, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *s)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// FindSnapshot retrieves a snapshot by number.
+func (r *SnapshotRepository) FindSnapshot(ctx context.Context, tenantID common.TenantID, proposalID uuid.UUID, number int) (*proposal.Snapshot, error) {
+	query := `
+		SELECT id, tenant_id, proposal_id, number, proposal_version, transition,
+			state, content, created_by, created_at
+		FROM proposal_snapshots
+		WHERE tenant_id = $1 AND proposal_id = $2 AND number = $3
+	`
+
+	s, err := scanSnapshot(r.pool.QueryRow(ctx, query, uuid.UUID(tenantID), proposalID, number))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// scanSnapshot scans a row into a Snapshot.
+func scanSnapshot(row pgx.Row) (*proposal.Snapshot, error) {
+	var s proposal.Snapshot
+	var tenant uuid.UUID
+	var contentJSON []byte
+
+	err := row.Scan(
+		&s.ID,
+		&tenant,
+		&s.ProposalID,
+		&s.Number,
+		&s.ProposalVersion,
+		&s.Transition,
+		&s.State,
+		&contentJSON,
+		&s.CreatedBy,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+	}
+
+	s.TenantID = common.TenantID(tenant)
+	if err := json.Unmarshal(contentJSON, &s.Content); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot content: %w", err)
+	}
+
+	return &s, nil
+}
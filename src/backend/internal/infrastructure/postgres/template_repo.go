@@ -0,0 +1,193 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation.
+const uniqueViolation = "23505"
+
+// TemplateRepository implements the proposal.ProposalTemplateRepository interface.
+type TemplateRepository struct {
+	pool *Pool
+}
+
+// NewTemplateRepository creates a new proposal template repository.
+func NewTemplateRepository(pool *Pool) *TemplateRepository {
+	return &TemplateRepository{pool: pool}
+}
+
+// SaveTemplate stores a template. Saving a template that changed since it
+// was loaded fails with proposal.ErrVersionMismatch.
+func (r *TemplateRepository) SaveTemplate(ctx context.Context, t *proposal.ProposalTemplate) error {
+	keywordsJSON, err := json.Marshal(t.Keywords)
+	if err != nil {
+		return fmt.Errorf("failed to marshal keywords: %w", err)
+	}
+	personnelJSON, err := json.Marshal(t.Personnel)
+	if err != nil {
+		return fmt.Errorf("failed to marshal personnel: %w", err)
+	}
+	complianceJSON, err := json.Marshal(t.Compliance)
+	if err != nil {
+		return fmt.Errorf("failed to marshal compliance flags: %w", err)
+	}
+	attachmentsJSON, err := json.Marshal(t.RequiredAttachments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal required attachments: %w", err)
+	}
+	var budgetJSON []byte
+	if t.Budget != nil {
+		if budgetJSON, err = json.Marshal(t.Budget); err != nil {
+			return fmt.Errorf("failed to marshal budget skeleton: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO proposal_templates (
+			id, tenant_id, name, description, department, opportunity_id, proposal_type,
+			research_area, keywords, personnel, compliance, budget_skeleton, required_attachments,
+			created_at, updated_at, created_by, updated_by, version
+		) VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, NULLIF($7, ''), NULLIF($8, ''),
+			$9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			department = EXCLUDED.department,
+			opportunity_id = EXCLUDED.opportunity_id,
+			proposal_type = EXCLUDED.proposal_type,
+			research_area = EXCLUDED.research_area,
+			keywords = EXCLUDED.keywords,
+			personnel = EXCLUDED.personnel,
+			compliance = EXCLUDED.compliance,
+			budget_skeleton = EXCLUDED.budget_skeleton,
+			required_attachments = EXCLUDED.required_attachments,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by,
+			version = EXCLUDED.version
+		WHERE proposal_templates.version < EXCLUDED.version
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		t.ID, uuid.UUID(t.TenantID), t.Name, t.Description, t.Department, t.OpportunityID, string(t.ProposalType),
+		t.ResearchArea, keywordsJSON, personnelJSON, complianceJSON, budgetJSON, attachmentsJSON,
+		t.CreatedAt, t.UpdatedAt, t.CreatedBy, t.UpdatedBy, t.Version)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return proposal.ErrTemplateNameTaken
+		}
+		return fmt.Errorf("failed to save proposal template: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return proposal.ErrVersionMismatch
+	}
+	return nil
+}
+
+// templateColumns are the columns scanned by scanTemplate.
+const templateColumns = `id, tenant_id, name, COALESCE(description, ''), COALESCE(department, ''), opportunity_id,
+	COALESCE(proposal_type, ''), COALESCE(research_area, ''), keywords, personnel, compliance,
+	budget_skeleton, required_attachments, created_at, updated_at, created_by, updated_by, version`
+
+// FindTemplate retrieves a template by ID.
+func (r *TemplateRepository) FindTemplate(ctx context.Context, tenantID common.TenantID, id uuid.UUID) (*proposal.ProposalTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM proposal_templates WHERE id = $1 AND tenant_id = $2`
+
+	t, err := scanTemplate(r.pool.QueryRow(ctx, query, id, uuid.UUID(tenantID)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load proposal template: %w", err)
+	}
+	return t, nil
+}
+
+// ListTemplates retrieves the templates usable for a department and
+// opportunity, ordered by name. Empty filter fields match every template.
+func (r *TemplateRepository) ListTemplates(ctx context.Context, tenantID common.TenantID, filter proposal.TemplateFilter) ([]*proposal.ProposalTemplate, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM proposal_templates
+		WHERE tenant_id = $1
+			AND ($2::text = '' OR department IS NULL OR department = $2)
+			AND ($3::uuid IS NULL OR opportunity_id IS NULL OR opportunity_id = $3)
+		ORDER BY name
+	`
+
+	rows, err := r.pool.Query(ctx, query, uuid.UUID(tenantID), filter.Department, filter.OpportunityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proposal templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]*proposal.ProposalTemplate, 0)
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan proposal template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, rows.Err()
+}
+
+// DeleteTemplate removes a template. Proposals created from it lose the
+// link but keep their data.
+func (r *TemplateRepository) DeleteTemplate(ctx context.Context, tenantID common.TenantID, id uuid.UUID) error {
+	result, err := r.pool.Exec(ctx, `DELETE FROM proposal_templates WHERE id = $1 AND tenant_id = $2`, id, uuid.UUID(tenantID))
+	if err != nil {
+		return fmt.Errorf("failed to delete proposal template: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return proposal.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// scanTemplate scans a row of templateColumns.
+func scanTemplate(row pgx.Row) (*proposal.ProposalTemplate, error) {
+	var t proposal.ProposalTemplate
+	var tenantUUID uuid.UUID
+	var proposalType string
+	var keywordsJSON, personnelJSON, complianceJSON, budgetJSON, attachmentsJSON []byte
+	if err := row.Scan(&t.ID, &tenantUUID, &t.Name, &t.Description, &t.Department, &t.OpportunityID,
+		&proposalType, &t.ResearchArea, &keywordsJSON, &personnelJSON, &complianceJSON,
+		&budgetJSON, &attachmentsJSON, &t.CreatedAt, &t.UpdatedAt, &t.CreatedBy, &t.UpdatedBy, &t.Version); err != nil {
+		return nil, err
+	}
+	t.TenantID = common.TenantID(tenantUUID)
+	t.ProposalType = proposal.ProposalType(proposalType)
+
+	if err := json.Unmarshal(keywordsJSON, &t.Keywords); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal keywords: %w", err)
+	}
+	if err := json.Unmarshal(personnelJSON, &t.Personnel); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal personnel: %w", err)
+	}
+	if err := json.Unmarshal(complianceJSON, &t.Compliance); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal compliance flags: %w", err)
+	}
+	if err := json.Unmarshal(attachmentsJSON, &t.RequiredAttachments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal required attachments: %w", err)
+	}
+	if budgetJSON != nil {
+		t.Budget = &proposal.BudgetSkeleton{}
+		if err := json.Unmarshal(budgetJSON, t.Budget); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal budget skeleton: %w", err)
+		}
+	}
+	return &t, nil
+}
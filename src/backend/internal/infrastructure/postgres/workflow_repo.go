@@ -0,0 +1,154 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+	"github.com/jackc/pgx/v5"
+)
+
+// WorkflowRepository implements the proposal.WorkflowStore interface.
+type WorkflowRepository struct {
+	pool *Pool
+}
+
+// NewWorkflowRepository creates a new workflow repository.
+func NewWorkflowRepository(pool *Pool) *WorkflowRepository {
+	return &WorkflowRepository{pool: pool}
+}
+
+// SaveDefinition persists a tenant's workflow document.
+func (r *WorkflowRepository) SaveDefinition(ctx context.Context, tenantID common.TenantID, def *statemachine.Definition) error {
+	document, err := json.Marshal(def)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow definition: %w", err)
+	}
+
+	query := `
+		INSERT INTO workflow_definitions (tenant_id, name, version, description, document)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), def.Name, def.Version, def.Description, document); err != nil {
+		return fmt.Errorf("failed to save workflow definition: %w", err)
+	}
+
+	return nil
+}
+
+// FindDefinition retrieves a version of a tenant's workflow.
+func (r *WorkflowRepository) FindDefinition(ctx context.Context, tenantID common.TenantID, name string, version int) (*statemachine.Definition, error) {
+	query := `
+		SELECT document
+		FROM workflow_definitions
+		WHERE tenant_id = $1 AND name = $2 AND version = $3
+	`
+
+	var document []byte
+	if err := r.pool.QueryRow(ctx, query, uuid.UUID(tenantID), name, version).Scan(&document); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find workflow definition: %w", err)
+	}
+
+	return statemachine.ParseDefinition(document, statemachine.FormatJSON)
+}
+
+// ListDefinitions retrieves all versions of a tenant's workflow.
+func (r *WorkflowRepository) ListDefinitions(ctx context.Context, tenantID common.TenantID, name string) ([]*statemachine.Definition, error) {
+	query := `
+		SELECT tenant_id, document
+		FROM workflow_definitions
+		WHERE tenant_id = $1 AND name = $2
+		ORDER BY version ASC
+	`
+
+	defs, err := r.queryDefinitions(ctx, query, uuid.UUID(tenantID), name)
+	if err != nil {
+		return nil, err
+	}
+	return defs[tenantID], nil
+}
+
+// ListAllDefinitions retrieves all versions of a workflow for every tenant.
+func (r *WorkflowRepository) ListAllDefinitions(ctx context.Context, name string) (map[common.TenantID][]*statemachine.Definition, error) {
+	query := `
+		SELECT tenant_id, document
+		FROM workflow_definitions
+		WHERE name = $1
+		ORDER BY tenant_id, version ASC
+	`
+
+	return r.queryDefinitions(ctx, query, name)
+}
+
+// queryDefinitions runs a query returning tenant IDs and workflow documents
+// and groups the documents by tenant.
+func (r *WorkflowRepository) queryDefinitions(ctx context.Context, query string, args ...interface{}) (map[common.TenantID][]*statemachine.Definition, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflow definitions: %w", err)
+	}
+	defer rows.Close()
+
+	defs := make(map[common.TenantID][]*statemachine.Definition)
+	for rows.Next() {
+		var tenantUUID uuid.UUID
+		var document []byte
+		if err := rows.Scan(&tenantUUID, &document); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow definition: %w", err)
+		}
+
+		def, err := statemachine.ParseDefinition(document, statemachine.FormatJSON)
+		if err != nil {
+			return nil, err
+		}
+		tenantID := common.TenantID(tenantUUID)
+		defs[tenantID] = append(defs[tenantID], def)
+	}
+
+	return defs, rows.Err()
+}
+
+// SavePin pins a tenant to a workflow version.
+func (r *WorkflowRepository) SavePin(ctx context.Context, tenantID common.TenantID, name string, version int) error {
+	query := `
+		INSERT INTO tenant_workflow_pins (tenant_id, workflow_name, version)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, workflow_name) DO UPDATE SET
+			version = EXCLUDED.version,
+			pinned_at = NOW()
+	`
+
+	if _, err := r.pool.Exec(ctx, query, uuid.UUID(tenantID), name, version); err != nil {
+		return fmt.Errorf("failed to save workflow pin: %w", err)
+	}
+
+	return nil
+}
+
+// FindPin retrieves the version of a workflow a tenant is pinned to.
+func (r *WorkflowRepository) FindPin(ctx context.Context, tenantID common.TenantID, name string) (int, error) {
+	query := `
+		SELECT version
+		FROM tenant_workflow_pins
+		WHERE tenant_id = $1 AND workflow_name = $2
+	`
+
+	var version int
+	if err := r.pool.QueryRow(ctx, query, uuid.UUID(tenantID), name).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to find workflow pin: %w", err)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,151 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	appauthz "github.com/huron-portland/grants-management/internal/application/authz"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/huron-portland/grants-management/pkg/policy"
+	"github.com/rs/zerolog/log"
+)
+
+// AuthzHandler handles authorization policy HTTP requests.
+type AuthzHandler struct {
+	service *appauthz.Service
+}
+
+// NewAuthzHandler creates a new authorization policy handler.
+func NewAuthzHandler(service *appauthz.Service) *AuthzHandler {
+	return &AuthzHandler{service: service}
+}
+
+// ListPolicies handles GET /api/v1/authz/policies
+func (h *AuthzHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	policies, err := h.service.ListPolicies(ctx, *tenantCtx)
+	if err != nil {
+		if errors.Is(err, appauthz.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
+		log.Error().Err(err).Msg("Failed to list policies")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list policies")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"policies": policies,
+	})
+}
+
+// SavePolicy handles PUT /api/v1/authz/policies/{id}
+func (h *AuthzHandler) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var cmd appauthz.SavePolicyCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	cmd.ID = chi.URLParam(r, "id")
+
+	saved, err := h.service.SavePolicy(ctx, *tenantCtx, cmd)
+	if err != nil {
+		log.Error().Err(err).Str("policy_id", cmd.ID).Msg("Failed to save policy")
+		switch {
+		case errors.Is(err, appauthz.ErrUnauthorized):
+			writeForbidden(w, err)
+		case errors.Is(err, policy.ErrInvalidPolicy):
+			writeError(w, http.StatusBadRequest, "INVALID_POLICY", err.Error())
+		case errors.Is(err, appauthz.ErrLockout):
+			writeError(w, http.StatusConflict, "POLICY_LOCKOUT", err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "SAVE_FAILED", "Failed to save policy")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, saved)
+}
+
+// DeletePolicy handles DELETE /api/v1/authz/policies/{id}
+func (h *AuthzHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if err := h.service.DeletePolicy(ctx, *tenantCtx, id); err != nil {
+		switch {
+		case errors.Is(err, appauthz.ErrUnauthorized):
+			writeForbidden(w, err)
+		case errors.Is(err, authz.ErrPolicyNotFound):
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Policy not found")
+		case errors.Is(err, appauthz.ErrLockout):
+			writeError(w, http.StatusConflict, "POLICY_LOCKOUT", err.Error())
+		default:
+			log.Error().Err(err).Str("policy_id", id).Msg("Failed to delete policy")
+			writeError(w, http.StatusInternalServerError, "DELETE_FAILED", "Failed to delete policy")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Explain handles POST /api/v1/authz/explain
+func (h *AuthzHandler) Explain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var cmd appauthz.ExplainCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	decision, err := h.service.Explain(ctx, *tenantCtx, cmd)
+	if err != nil {
+		switch {
+		case errors.Is(err, appauthz.ErrUnauthorized):
+			writeForbidden(w, err)
+		case errors.Is(err, appauthz.ErrActionRequired):
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		case errors.Is(err, proposal.ErrProposalNotFound):
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+		default:
+			log.Error().Err(err).Str("action", cmd.Action).Msg("Failed to explain decision")
+			writeError(w, http.StatusInternalServerError, "EXPLAIN_FAILED", "Failed to explain decision")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"decision":    decision,
+		"explanation": decision.Explain(),
+	})
+}
@@ -0,0 +1,150 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// GetAward handles GET /api/v1/proposals/{id}/award
+func (h *ProposalHandler) GetAward(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	award, err := h.service.GetAward(ctx, *tenantCtx, id)
+	if err != nil {
+		writeAwardError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, award)
+}
+
+// UpdateAwardSetup handles PUT /api/v1/proposals/{id}/award
+func (h *ProposalHandler) UpdateAwardSetup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	var cmd appproposal.UpdateAwardSetupCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	award, err := h.service.UpdateAwardSetup(ctx, *tenantCtx, id, cmd)
+	if err != nil {
+		writeAwardError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, award)
+}
+
+// CompleteAwardSetup handles POST /api/v1/proposals/{id}/award/complete-setup
+func (h *ProposalHandler) CompleteAwardSetup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	award, err := h.service.CompleteAwardSetup(ctx, *tenantCtx, id)
+	if err != nil {
+		writeAwardError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, award)
+}
+
+// RecordAwardModification handles POST /api/v1/proposals/{id}/award/modifications
+func (h *ProposalHandler) RecordAwardModification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	var input proposal.ModificationInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	modification, err := h.service.RecordAwardModification(ctx, *tenantCtx, id, input)
+	if err != nil {
+		writeAwardError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, modification)
+}
+
+// writeAwardError maps award errors to responses.
+func writeAwardError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrAwardsUnavailable):
+		writeError(w, http.StatusNotImplemented, "AWARDS_UNAVAILABLE", err.Error())
+	case errors.Is(err, proposal.ErrProposalNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+	case errors.Is(err, proposal.ErrAwardNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Award not found")
+	case errors.Is(err, proposal.ErrVersionMismatch):
+		writeError(w, http.StatusConflict, "VERSION_MISMATCH", "Award was modified by another user")
+	case errors.Is(err, proposal.ErrAwardSetupComplete):
+		writeError(w, http.StatusConflict, "AWARD_SETUP_COMPLETE", err.Error())
+	case errors.Is(err, proposal.ErrAwardSetupIncomplete):
+		writeError(w, http.StatusUnprocessableEntity, "AWARD_SETUP_INCOMPLETE", err.Error())
+	case errors.Is(err, proposal.ErrInvalidAward):
+		writeError(w, http.StatusBadRequest, "INVALID_AWARD", err.Error())
+	case errors.Is(err, proposal.ErrInvalidModification):
+		writeError(w, http.StatusBadRequest, "INVALID_MODIFICATION", err.Error())
+	default:
+		log.Error().Err(err).Msg("Award request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Award request failed")
+	}
+}
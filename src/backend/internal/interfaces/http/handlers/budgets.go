@@ -0,0 +1,368 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/application/ports"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// ListBudgets handles GET /api/v1/budgets
+func (h *ProposalHandler) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	filter := ports.BudgetListFilter{Limit: 20}
+	query := r.URL.Query()
+	if v, err := strconv.Atoi(query.Get("offset")); err == nil && v >= 0 {
+		filter.Offset = v
+	}
+	if v, err := strconv.Atoi(query.Get("limit")); err == nil && v > 0 && v <= 100 {
+		filter.Limit = v
+	}
+	for _, s := range query["status"] {
+		filter.Statuses = append(filter.Statuses, budget.BudgetStatus(s))
+	}
+	for _, raw := range query["proposal_id"] {
+		proposalID, err := uuid.Parse(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+			return
+		}
+		filter.ProposalIDs = append(filter.ProposalIDs, proposalID)
+	}
+
+	result, err := h.service.ListBudgets(ctx, *tenantCtx, filter)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// CreateBudget handles POST /api/v1/budgets
+func (h *ProposalHandler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var cmd appproposal.CreateBudgetCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	detail, err := h.service.CreateBudget(ctx, *tenantCtx, cmd)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, detail)
+}
+
+// GetBudget handles GET /api/v1/budgets/{id}
+func (h *ProposalHandler) GetBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetBudget(ctx, *tenantCtx, id)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// UpdateBudget handles PUT /api/v1/budgets/{id}
+func (h *ProposalHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+
+	var cmd appproposal.UpdateBudgetCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	detail, err := h.service.UpdateBudget(ctx, *tenantCtx, id, cmd)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// AddBudgetPeriod handles POST /api/v1/budgets/{id}/periods
+func (h *ProposalHandler) AddBudgetPeriod(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+
+	var cmd appproposal.AddBudgetPeriodCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	detail, err := h.service.AddBudgetPeriod(ctx, *tenantCtx, id, cmd)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, detail)
+}
+
+// RemoveBudgetPeriod handles DELETE /api/v1/budgets/{id}/periods/{periodId}
+func (h *ProposalHandler) RemoveBudgetPeriod(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+	periodID, ok := parseURLID(w, r, "periodId", "budget period")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.RemoveBudgetPeriod(ctx, *tenantCtx, id, periodID)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// AddLineItem handles POST /api/v1/budgets/{id}/periods/{periodId}/line-items
+func (h *ProposalHandler) AddLineItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+	periodID, ok := parseURLID(w, r, "periodId", "budget period")
+	if !ok {
+		return
+	}
+
+	var cmd appproposal.LineItemCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	detail, err := h.service.AddLineItem(ctx, *tenantCtx, id, periodID, cmd)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, detail)
+}
+
+// GetLineItem handles GET /api/v1/budgets/{id}/periods/{periodId}/line-items/{itemId}
+func (h *ProposalHandler) GetLineItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+	periodID, ok := parseURLID(w, r, "periodId", "budget period")
+	if !ok {
+		return
+	}
+	itemID, ok := parseURLID(w, r, "itemId", "line item")
+	if !ok {
+		return
+	}
+
+	item, err := h.service.GetLineItem(ctx, *tenantCtx, id, periodID, itemID)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+// UpdateLineItem handles PUT /api/v1/budgets/{id}/periods/{periodId}/line-items/{itemId}
+func (h *ProposalHandler) UpdateLineItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+	periodID, ok := parseURLID(w, r, "periodId", "budget period")
+	if !ok {
+		return
+	}
+	itemID, ok := parseURLID(w, r, "itemId", "line item")
+	if !ok {
+		return
+	}
+
+	var cmd appproposal.LineItemCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	detail, err := h.service.UpdateLineItem(ctx, *tenantCtx, id, periodID, itemID, cmd)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// RemoveLineItem handles DELETE /api/v1/budgets/{id}/periods/{periodId}/line-items/{itemId}
+func (h *ProposalHandler) RemoveLineItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "budget")
+	if !ok {
+		return
+	}
+	periodID, ok := parseURLID(w, r, "periodId", "budget period")
+	if !ok {
+		return
+	}
+	itemID, ok := parseURLID(w, r, "itemId", "line item")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.RemoveLineItem(ctx, *tenantCtx, id, periodID, itemID)
+	if err != nil {
+		writeBudgetError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// parseURLID parses a UUID URL parameter, writing a 400 response when it is
+// malformed.
+func parseURLID(w http.ResponseWriter, r *http.Request, param, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, param))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid "+name+" ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// writeBudgetError maps budget errors to responses.
+func writeBudgetError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrBudgetsUnavailable):
+		writeError(w, http.StatusNotImplemented, "BUDGETS_UNAVAILABLE", err.Error())
+	case errors.Is(err, appproposal.ErrRateAgreementsUnavailable):
+		writeError(w, http.StatusNotImplemented, "RATE_AGREEMENTS_UNAVAILABLE", err.Error())
+	case errors.Is(err, proposal.ErrProposalNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+	case errors.Is(err, budget.ErrBudgetNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Budget not found")
+	case errors.Is(err, budget.ErrPeriodNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Budget period not found")
+	case errors.Is(err, budget.ErrLineItemNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Budget line item not found")
+	case errors.Is(err, budget.ErrRateAgreementNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Rate agreement not found")
+	case errors.Is(err, budget.ErrBudgetExists):
+		writeError(w, http.StatusConflict, "BUDGET_EXISTS", err.Error())
+	case errors.Is(err, proposal.ErrVersionMismatch):
+		writeError(w, http.StatusConflict, "VERSION_MISMATCH", "Budget was modified by another user")
+	case errors.Is(err, proposal.ErrProposalNotEditable), errors.Is(err, budget.ErrBudgetNotEditable):
+		writeError(w, http.StatusConflict, "NOT_EDITABLE", err.Error())
+	case errors.Is(err, budget.ErrInvalidBudget):
+		writeError(w, http.StatusBadRequest, "INVALID_BUDGET", err.Error())
+	case errors.Is(err, budget.ErrInvalidLineItem):
+		writeError(w, http.StatusBadRequest, "INVALID_LINE_ITEM", err.Error())
+	case errors.Is(err, budget.ErrNoNegotiatedRate):
+		writeError(w, http.StatusUnprocessableEntity, "NO_NEGOTIATED_RATE", err.Error())
+	default:
+		log.Error().Err(err).Msg("Budget request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Budget request failed")
+	}
+}
@@ -0,0 +1,293 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appcalendar "github.com/huron-portland/grants-management/internal/application/calendar"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// CalendarHandler handles deadline rule, holiday and calendar feed HTTP requests.
+type CalendarHandler struct {
+	service *appcalendar.Service
+}
+
+// NewCalendarHandler creates a new calendar handler.
+func NewCalendarHandler(service *appcalendar.Service) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+// GetDeadlineRule handles GET /api/v1/settings/deadline-rule
+func (h *CalendarHandler) GetDeadlineRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	rule, err := h.service.GetDeadlineRule(ctx, *tenantCtx)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// PutDeadlineRule handles PUT /api/v1/settings/deadline-rule
+func (h *CalendarHandler) PutDeadlineRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var rule proposal.DeadlineRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	saved, err := h.service.PutDeadlineRule(ctx, *tenantCtx, rule)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, saved)
+}
+
+// DeleteDeadlineRule handles DELETE /api/v1/settings/deadline-rule
+func (h *CalendarHandler) DeleteDeadlineRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	if err := h.service.DeleteDeadlineRule(ctx, *tenantCtx); err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListHolidays handles GET /api/v1/settings/holidays
+func (h *CalendarHandler) ListHolidays(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	// Dates are given as YYYY-MM-DD; the window defaults to the current year
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+	var err error
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid from date, expected YYYY-MM-DD")
+			return
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid to date, expected YYYY-MM-DD")
+			return
+		}
+	}
+
+	holidays, err := h.service.ListHolidays(ctx, *tenantCtx, from, to)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"holidays": holidays,
+	})
+}
+
+// PutHolidays handles PUT /api/v1/settings/holidays
+func (h *CalendarHandler) PutHolidays(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var body struct {
+		Holidays []proposal.Holiday `json:"holidays"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if err := h.service.PutHolidays(ctx, *tenantCtx, body.Holidays); err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"holidays": body.Holidays,
+	})
+}
+
+// DeleteHoliday handles DELETE /api/v1/settings/holidays/{date}
+func (h *CalendarHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	if err := h.service.DeleteHoliday(ctx, *tenantCtx, chi.URLParam(r, "date")); err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListFeeds handles GET /api/v1/calendar/feeds
+func (h *CalendarHandler) ListFeeds(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	feeds, err := h.service.ListFeeds(ctx, *tenantCtx)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"feeds": feeds,
+	})
+}
+
+// CreateFeed handles POST /api/v1/calendar/feeds
+func (h *CalendarHandler) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var cmd appcalendar.CreateFeedCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	feed, err := h.service.CreateFeed(ctx, *tenantCtx, cmd)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"feed":  feed,
+		"url":   feedURL(r, feed.Path),
+		"token": feed.Token,
+	})
+}
+
+// RevokeFeed handles DELETE /api/v1/calendar/feeds/{id}
+func (h *CalendarHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid feed ID")
+		return
+	}
+
+	if err := h.service.RevokeFeed(ctx, *tenantCtx, id); err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Feed handles GET /calendar/{token}.ics. Calendar apps cannot send
+// credentials, so the token in the URL is the only one.
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(chi.URLParam(r, "file"), ".ics")
+	if token == "" || token == chi.URLParam(r, "file") {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Calendar feed not found")
+		return
+	}
+
+	body, err := h.service.RenderFeed(r.Context(), token)
+	if err != nil {
+		writeCalendarError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// feedURL returns the absolute subscription URL of a feed path on the
+// host that served the request.
+func feedURL(r *http.Request, path string) string {
+	scheme := "https"
+	if r.TLS == nil && r.Header.Get("X-Forwarded-Proto") != "https" {
+		scheme = "http"
+	}
+	return scheme + "://" + r.Host + path
+}
+
+// writeCalendarError maps calendar errors to responses.
+func writeCalendarError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, appcalendar.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appcalendar.ErrDeadlineRuleNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Deadline rule not found")
+	case errors.Is(err, proposal.ErrHolidayNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Holiday not found")
+	case errors.Is(err, proposal.ErrCalendarFeedNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Calendar feed not found")
+	case errors.Is(err, proposal.ErrInvalidDeadlineRule):
+		writeError(w, http.StatusBadRequest, "INVALID_DEADLINE_RULE", err.Error())
+	case errors.Is(err, proposal.ErrInvalidHoliday):
+		writeError(w, http.StatusBadRequest, "INVALID_HOLIDAY", err.Error())
+	case errors.Is(err, proposal.ErrInvalidCalendarFeed):
+		writeError(w, http.StatusBadRequest, "INVALID_CALENDAR_FEED", err.Error())
+	default:
+		log.Error().Err(err).Msg("Calendar request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Calendar request failed")
+	}
+}
@@ -0,0 +1,161 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// GetCloseout handles GET /api/v1/proposals/{id}/closeout
+func (h *ProposalHandler) GetCloseout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	checklist, err := h.service.GetCloseout(ctx, *tenantCtx, id)
+	if err != nil {
+		writeCloseoutError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checklist)
+}
+
+// ResolveCloseoutItem handles PUT /api/v1/proposals/{id}/closeout/items/{itemId}
+func (h *ProposalHandler) ResolveCloseoutItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+	itemID, err := uuid.Parse(chi.URLParam(r, "itemId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid closeout item ID")
+		return
+	}
+
+	var cmd appproposal.ResolveCloseoutItemCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	checklist, err := h.service.ResolveCloseoutItem(ctx, *tenantCtx, id, itemID, cmd)
+	if err != nil {
+		writeCloseoutError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checklist)
+}
+
+// ListCloseoutTemplates handles GET /api/v1/settings/closeout-templates
+func (h *ProposalHandler) ListCloseoutTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	templates, err := h.service.ListCloseoutTemplates(ctx, *tenantCtx)
+	if err != nil {
+		writeCloseoutError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"templates": templates,
+	})
+}
+
+// PutCloseoutTemplate handles PUT /api/v1/settings/closeout-templates/{sponsorType}
+func (h *ProposalHandler) PutCloseoutTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var tpl proposal.CloseoutTemplate
+	if err := json.NewDecoder(r.Body).Decode(&tpl); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	tpl.SponsorType = chi.URLParam(r, "sponsorType")
+
+	settings, err := h.service.PutCloseoutTemplate(ctx, *tenantCtx, tpl)
+	if err != nil {
+		writeCloseoutError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// DeleteCloseoutTemplate handles DELETE /api/v1/settings/closeout-templates/{sponsorType}
+func (h *ProposalHandler) DeleteCloseoutTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	if err := h.service.DeleteCloseoutTemplate(ctx, *tenantCtx, chi.URLParam(r, "sponsorType")); err != nil {
+		writeCloseoutError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeCloseoutError maps closeout errors to responses.
+func writeCloseoutError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrCloseoutUnavailable):
+		writeError(w, http.StatusNotImplemented, "CLOSEOUT_UNAVAILABLE", err.Error())
+	case errors.Is(err, proposal.ErrProposalNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+	case errors.Is(err, proposal.ErrCloseoutNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Closeout checklist not found")
+	case errors.Is(err, proposal.ErrCloseoutItemNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Closeout item not found")
+	case errors.Is(err, proposal.ErrVersionMismatch):
+		writeError(w, http.StatusConflict, "VERSION_MISMATCH", "Closeout checklist was modified by another user")
+	case errors.Is(err, proposal.ErrInvalidCloseoutItem):
+		writeError(w, http.StatusBadRequest, "INVALID_CLOSEOUT_ITEM", err.Error())
+	case errors.Is(err, proposal.ErrInvalidCloseoutTemplate):
+		writeError(w, http.StatusBadRequest, "INVALID_CLOSEOUT_TEMPLATE", err.Error())
+	default:
+		log.Error().Err(err).Msg("Closeout request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Closeout request failed")
+	}
+}
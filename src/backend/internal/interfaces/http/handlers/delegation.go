@@ -0,0 +1,110 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appdelegation "github.com/huron-portland/grants-management/internal/application/delegation"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// DelegationHandler handles role delegation HTTP requests.
+type DelegationHandler struct {
+	service *appdelegation.Service
+}
+
+// NewDelegationHandler creates a new delegation handler.
+func NewDelegationHandler(service *appdelegation.Service) *DelegationHandler {
+	return &DelegationHandler{service: service}
+}
+
+// List handles GET /api/v1/delegations
+func (h *DelegationHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	delegations, err := h.service.List(ctx, *tenantCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list delegations")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list delegations")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"delegations": delegations,
+	})
+}
+
+// Create handles POST /api/v1/delegations
+func (h *DelegationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var cmd appdelegation.CreateCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	d, err := h.service.Create(ctx, *tenantCtx, cmd)
+	if err != nil {
+		log.Error().Err(err).Str("role", cmd.Role).Msg("Failed to create delegation")
+		switch {
+		case errors.Is(err, appdelegation.ErrUnauthorized):
+			writeForbidden(w, err)
+		case errors.Is(err, proposal.ErrInvalidDelegation):
+			writeError(w, http.StatusBadRequest, "INVALID_DELEGATION", err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "CREATE_FAILED", "Failed to create delegation")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, d)
+}
+
+// Revoke handles DELETE /api/v1/delegations/{id}
+func (h *DelegationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid delegation ID")
+		return
+	}
+
+	d, err := h.service.Revoke(ctx, *tenantCtx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, proposal.ErrDelegationNotFound):
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Delegation not found")
+		case errors.Is(err, appdelegation.ErrUnauthorized):
+			writeForbidden(w, err)
+		default:
+			log.Error().Err(err).Str("id", id.String()).Msg("Failed to revoke delegation")
+			writeError(w, http.StatusInternalServerError, "REVOKE_FAILED", "Failed to revoke delegation")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, d)
+}
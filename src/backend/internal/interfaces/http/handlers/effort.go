@@ -0,0 +1,77 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/common"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultEffortMonths is the length of an effort timeline without an end month.
+const defaultEffortMonths = 36
+
+// EffortTimeline handles GET /api/v1/people/{id}/effort
+func (h *ProposalHandler) EffortTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	personID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid person ID")
+		return
+	}
+
+	// Months are given as YYYY-MM; the window starts at the current month
+	now := time.Now().UTC()
+	window := common.DateRange{StartDate: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)}
+	if from := r.URL.Query().Get("from"); from != "" {
+		if window.StartDate, err = time.Parse("2006-01", from); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid from month, expected YYYY-MM")
+			return
+		}
+	}
+	window.EndDate = window.StartDate.AddDate(0, defaultEffortMonths-1, 0)
+	if to := r.URL.Query().Get("to"); to != "" {
+		if window.EndDate, err = time.Parse("2006-01", to); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid to month, expected YYYY-MM")
+			return
+		}
+	}
+
+	timeline, err := h.service.GetEffortTimeline(ctx, *tenantCtx, personID, window)
+	if err != nil {
+		writeEffortError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, timeline)
+}
+
+// writeEffortError maps effort ledger errors to responses.
+func writeEffortError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrEffortUnavailable):
+		writeError(w, http.StatusNotImplemented, "EFFORT_UNAVAILABLE", err.Error())
+	case errors.Is(err, appproposal.ErrPersonNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Person not found")
+	case errors.Is(err, proposal.ErrInvalidEffortWindow):
+		writeError(w, http.StatusBadRequest, "INVALID_EFFORT_WINDOW", err.Error())
+	default:
+		log.Error().Err(err).Msg("Effort timeline request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Effort timeline request failed")
+	}
+}
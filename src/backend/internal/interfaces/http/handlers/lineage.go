@@ -0,0 +1,96 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// Derive handles POST /api/v1/proposals/{id}/derive
+func (h *ProposalHandler) Derive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	var req struct {
+		ProposalType string `json:"proposal_type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	next, err := h.service.Derive(ctx, *tenantCtx, appproposal.DeriveCommand{
+		PredecessorID: id,
+		Type:          proposal.ProposalType(req.ProposalType),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Str("proposal_type", req.ProposalType).Msg("Failed to derive proposal")
+		switch {
+		case errors.Is(err, proposal.ErrUnauthorized):
+			writeForbidden(w, err)
+		case errors.Is(err, proposal.ErrProposalNotFound):
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+		case errors.Is(err, proposal.ErrInvalidProposalType):
+			writeError(w, http.StatusBadRequest, "INVALID_PROPOSAL_TYPE", err.Error())
+		case errors.Is(err, proposal.ErrPredecessorNotEligible):
+			writeError(w, http.StatusConflict, "PREDECESSOR_NOT_ELIGIBLE", err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, "DERIVE_FAILED", "Failed to create proposal")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, next)
+}
+
+// Lineage handles GET /api/v1/proposals/{id}/lineage
+func (h *ProposalHandler) Lineage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	lineage, err := h.service.GetLineage(ctx, *tenantCtx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, proposal.ErrUnauthorized):
+			writeForbidden(w, err)
+		case errors.Is(err, proposal.ErrProposalNotFound):
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+		default:
+			log.Error().Err(err).Str("id", idStr).Msg("Failed to load proposal lineage")
+			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to load lineage")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lineage)
+}
@@ -0,0 +1,223 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// lockStreamWindow is how long a lock stream stays open. It ends before the
+// server's write timeout; clients reconnect and receive the full lock list.
+const lockStreamWindow = 25 * time.Second
+
+// ListLocks handles GET /api/v1/proposals/{id}/locks
+func (h *ProposalHandler) ListLocks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	locks, err := h.service.ListLocks(ctx, *tenantCtx, id)
+	if err != nil {
+		writeLockError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, locks)
+}
+
+// AcquireLock handles POST /api/v1/proposals/{id}/locks/{section}
+func (h *ProposalHandler) AcquireLock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	lock, err := h.service.AcquireLock(ctx, *tenantCtx, id, proposal.Section(chi.URLParam(r, "section")))
+	if err != nil {
+		writeLockError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lock)
+}
+
+// RenewLock handles POST /api/v1/proposals/{id}/locks/{section}/heartbeat
+func (h *ProposalHandler) RenewLock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	lock, err := h.service.RenewLock(ctx, *tenantCtx, id, proposal.Section(chi.URLParam(r, "section")))
+	if err != nil {
+		writeLockError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lock)
+}
+
+// ReleaseLock handles DELETE /api/v1/proposals/{id}/locks/{section}
+func (h *ProposalHandler) ReleaseLock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	if err := h.service.ReleaseLock(ctx, *tenantCtx, id, proposal.Section(chi.URLParam(r, "section"))); err != nil {
+		writeLockError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StreamLocks handles GET /api/v1/proposals/{id}/locks/stream
+// It sends the active locks as a "locks" server-sent event, then a "lock"
+// event for every change until the stream window closes. A lock whose TTL
+// ends without renewal arrives as an "expired" change. Changes are best
+// effort and may be missed while the server's notification listener
+// reconnects; the lock list sent on each reconnect is authoritative.
+func (h *ProposalHandler) StreamLocks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, "STREAMING_UNSUPPORTED", "Streaming is not supported")
+		return
+	}
+
+	// Subscribe before listing so no change between the two is missed
+	events, unsubscribe, err := h.service.SubscribeLocks(ctx, *tenantCtx, id)
+	if err != nil {
+		writeLockError(w, err)
+		return
+	}
+	defer unsubscribe()
+
+	locks, err := h.service.ListLocks(ctx, *tenantCtx, id)
+	if err != nil {
+		writeLockError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 1000\n\n")
+	if err := writeEvent(w, "locks", locks); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	window := time.NewTimer(lockStreamWindow)
+	defer window.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-window.C:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, "lock", event); err != nil {
+				log.Debug().Err(err).Str("id", id.String()).Msg("Lock stream closed")
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes a server-sent event with a JSON payload.
+func writeEvent(w http.ResponseWriter, name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload)
+	return err
+}
+
+// writeLockError maps section lock errors to responses.
+func writeLockError(w http.ResponseWriter, err error) {
+	var locked *proposal.SectionLockedError
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrLocksUnavailable):
+		writeError(w, http.StatusNotImplemented, "LOCKS_UNAVAILABLE", err.Error())
+	case errors.Is(err, proposal.ErrProposalNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+	case errors.Is(err, proposal.ErrInvalidSection):
+		writeError(w, http.StatusBadRequest, "INVALID_SECTION", err.Error())
+	case errors.As(err, &locked):
+		writeErrorDetails(w, http.StatusConflict, "SECTION_LOCKED", err.Error(), locked.Lock)
+	case errors.Is(err, proposal.ErrLockNotHeld):
+		writeError(w, http.StatusConflict, "LOCK_NOT_HELD", err.Error())
+	case errors.Is(err, proposal.ErrProposalNotEditable):
+		writeError(w, http.StatusConflict, "NOT_EDITABLE", "Proposal cannot be edited in current state")
+	default:
+		log.Error().Err(err).Msg("Section lock operation failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Section lock operation failed")
+	}
+}
@@ -0,0 +1,92 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// GetByNumber handles GET /api/v1/proposals/by-number/{number}
+func (h *ProposalHandler) GetByNumber(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	number := chi.URLParam(r, "number")
+	result, err := h.service.GetByNumber(ctx, *tenantCtx, number)
+	if err != nil {
+		writeNumberingError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GetNumberingScheme handles GET /api/v1/settings/proposal-numbering
+func (h *ProposalHandler) GetNumberingScheme(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	settings, err := h.service.GetNumberingScheme(ctx, *tenantCtx)
+	if err != nil {
+		writeNumberingError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateNumberingScheme handles PUT /api/v1/settings/proposal-numbering
+func (h *ProposalHandler) UpdateNumberingScheme(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var scheme proposal.NumberingScheme
+	if err := json.NewDecoder(r.Body).Decode(&scheme); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	settings, err := h.service.UpdateNumberingScheme(ctx, *tenantCtx, scheme)
+	if err != nil {
+		writeNumberingError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// writeNumberingError maps numbering errors to responses.
+func writeNumberingError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrNumberingUnavailable):
+		writeError(w, http.StatusNotImplemented, "NUMBERING_UNAVAILABLE", err.Error())
+	case errors.Is(err, proposal.ErrProposalNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+	case errors.Is(err, proposal.ErrInvalidNumberingScheme):
+		writeError(w, http.StatusBadRequest, "INVALID_NUMBERING_SCHEME", err.Error())
+	default:
+		log.Error().Err(err).Msg("Proposal numbering request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Proposal numbering request failed")
+	}
+}
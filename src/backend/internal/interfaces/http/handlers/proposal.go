@@ -3,14 +3,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
 	"github.com/huron-portland/grants-management/internal/domain/proposal"
 	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
 	"github.com/rs/zerolog/log"
 )
 
@@ -40,6 +43,10 @@ func (h *ProposalHandler) List(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.List(ctx, *tenantCtx, filter)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to list proposals")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list proposals")
 		return
 	}
@@ -70,6 +77,10 @@ func (h *ProposalHandler) Create(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.Create(ctx, *tenantCtx, cmd)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create proposal")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusBadRequest, "CREATE_FAILED", err.Error())
 		return
 	}
@@ -96,6 +107,10 @@ func (h *ProposalHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.GetByID(ctx, *tenantCtx, id)
 	if err != nil {
 		log.Error().Err(err).Str("id", idStr).Msg("Failed to get proposal")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
 		return
 	}
@@ -129,6 +144,29 @@ func (h *ProposalHandler) Update(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.Update(ctx, *tenantCtx, cmd)
 	if err != nil {
 		log.Error().Err(err).Str("id", idStr).Msg("Failed to update proposal")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
+		var fieldErr *proposal.FieldEditError
+		if errors.As(err, &fieldErr) {
+			writeErrorDetails(w, http.StatusConflict, "FIELDS_NOT_EDITABLE", fieldErr.Error(), fieldErr)
+			return
+		}
+		var lockedErr *proposal.SectionLockedError
+		if errors.As(err, &lockedErr) {
+			writeErrorDetails(w, http.StatusConflict, "SECTION_LOCKED", lockedErr.Error(), lockedErr.Lock)
+			return
+		}
+		var conflictErr *proposal.SectionConflictError
+		if errors.As(err, &conflictErr) {
+			writeErrorDetails(w, http.StatusConflict, "SECTION_CONFLICT", conflictErr.Error(), conflictErr)
+			return
+		}
+		if errors.Is(err, proposal.ErrPriorApprovalRequired) {
+			writeError(w, http.StatusUnprocessableEntity, "PRIOR_APPROVAL_REQUIRED", err.Error())
+			return
+		}
 		if err == proposal.ErrProposalNotEditable {
 			writeError(w, http.StatusConflict, "NOT_EDITABLE", "Proposal cannot be edited in current state")
 			return
@@ -144,6 +182,39 @@ func (h *ProposalHandler) Update(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, result)
 }
 
+// EditableFields handles GET /api/v1/proposals/{id}/editable-fields
+func (h *ProposalHandler) EditableFields(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	fields, err := h.service.GetEditableFields(ctx, *tenantCtx, id)
+	if err != nil {
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
+		if errors.Is(err, proposal.ErrProposalNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get editable fields")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get editable fields")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, fields)
+}
+
 // Delete handles DELETE /api/v1/proposals/{id}
 func (h *ProposalHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -162,6 +233,10 @@ func (h *ProposalHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.Delete(ctx, *tenantCtx, id); err != nil {
 		log.Error().Err(err).Str("id", idStr).Msg("Failed to delete proposal")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusBadRequest, "DELETE_FAILED", err.Error())
 		return
 	}
@@ -187,6 +262,7 @@ func (h *ProposalHandler) Transition(w http.ResponseWriter, r *http.Request) {
 
 	var req struct {
 		Transition      string `json:"transition"`
+		Region          string `json:"region"`
 		Comment         string `json:"comment"`
 		ExpectedVersion int    `json:"expected_version"`
 	}
@@ -198,6 +274,7 @@ func (h *ProposalHandler) Transition(w http.ResponseWriter, r *http.Request) {
 	cmd := appproposal.TransitionCommand{
 		ProposalID:      id,
 		Transition:      proposal.ProposalTransition(req.Transition),
+		Region:          req.Region,
 		Comment:         req.Comment,
 		ExpectedVersion: req.ExpectedVersion,
 	}
@@ -205,10 +282,25 @@ func (h *ProposalHandler) Transition(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.Transition(ctx, *tenantCtx, cmd)
 	if err != nil {
 		log.Error().Err(err).Str("id", idStr).Str("transition", req.Transition).Msg("Failed to transition proposal")
+		var rejection *statemachine.GuardRejection
+		if errors.As(err, &rejection) {
+			writeErrorDetails(w, http.StatusUnprocessableEntity, "TRANSITION_REJECTED", "Transition blocked by workflow guards", rejection)
+			return
+		}
 		if err == proposal.ErrInvalidTransition {
 			writeError(w, http.StatusBadRequest, "INVALID_TRANSITION", "Invalid state transition")
 			return
 		}
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
+		if errors.Is(err, statemachine.ErrRegionsIncomplete) || errors.Is(err, statemachine.ErrUnknownRegion) ||
+			errors.Is(err, statemachine.ErrRegionComplete) || errors.Is(err, statemachine.ErrNotComposite) ||
+			errors.Is(err, statemachine.ErrAmbiguousRegion) {
+			writeError(w, http.StatusConflict, "REGION_CONFLICT", err.Error())
+			return
+		}
 		if err == proposal.ErrVersionMismatch {
 			writeError(w, http.StatusConflict, "VERSION_MISMATCH", "Proposal was modified by another user")
 			return
@@ -245,6 +337,10 @@ func (h *ProposalHandler) Search(w http.ResponseWriter, r *http.Request) {
 	results, err := h.service.Search(ctx, *tenantCtx, query, limit)
 	if err != nil {
 		log.Error().Err(err).Str("query", query).Msg("Failed to search proposals")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "SEARCH_FAILED", "Search failed")
 		return
 	}
@@ -267,6 +363,10 @@ func (h *ProposalHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	dashboard, err := h.service.GetDashboard(ctx, *tenantCtx)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get dashboard data")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "DASHBOARD_FAILED", "Failed to get dashboard data")
 		return
 	}
@@ -293,6 +393,10 @@ func (h *ProposalHandler) UpcomingDeadlines(w http.ResponseWriter, r *http.Reque
 	proposals, err := h.service.GetDashboard(ctx, *tenantCtx)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get upcoming deadlines")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "FETCH_FAILED", "Failed to get upcoming deadlines")
 		return
 	}
@@ -315,6 +419,10 @@ func (h *ProposalHandler) Overdue(w http.ResponseWriter, r *http.Request) {
 	dashboard, err := h.service.GetDashboard(ctx, *tenantCtx)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get overdue proposals")
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusInternalServerError, "FETCH_FAILED", "Failed to get overdue proposals")
 		return
 	}
@@ -342,6 +450,10 @@ func (h *ProposalHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 
 	detail, err := h.service.GetByID(ctx, *tenantCtx, id)
 	if err != nil {
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
 		return
 	}
@@ -369,6 +481,10 @@ func (h *ProposalHandler) AvailableTransitions(w http.ResponseWriter, r *http.Re
 
 	detail, err := h.service.GetByID(ctx, *tenantCtx, id)
 	if err != nil {
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
 		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
 		return
 	}
@@ -379,6 +495,95 @@ func (h *ProposalHandler) AvailableTransitions(w http.ResponseWriter, r *http.Re
 	})
 }
 
+// ListApprovals handles GET /api/v1/proposals/{id}/approvals
+func (h *ProposalHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	overview, err := h.service.ListApprovals(ctx, *tenantCtx, id)
+	if err != nil {
+		if errors.Is(err, proposal.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
+		if errors.Is(err, appproposal.ErrApprovalsUnavailable) {
+			writeError(w, http.StatusNotImplemented, "APPROVALS_UNAVAILABLE", err.Error())
+			return
+		}
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, overview)
+}
+
+// CastApproval handles POST /api/v1/proposals/{id}/approvals
+func (h *ProposalHandler) CastApproval(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	var req struct {
+		Transition string `json:"transition"`
+		Decision   string `json:"decision"`
+		Comment    string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	cmd := appproposal.ApprovalCommand{
+		ProposalID: id,
+		Transition: proposal.ProposalTransition(req.Transition),
+		Decision:   proposal.ApprovalDecision(req.Decision),
+		Comment:    req.Comment,
+	}
+
+	result, err := h.service.CastApproval(ctx, *tenantCtx, cmd)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Str("decision", req.Decision).Msg("Failed to record approval")
+		switch {
+		case errors.Is(err, appproposal.ErrApprovalsUnavailable):
+			writeError(w, http.StatusNotImplemented, "APPROVALS_UNAVAILABLE", err.Error())
+		case errors.Is(err, proposal.ErrUnauthorized):
+			writeForbidden(w, err)
+		case errors.Is(err, proposal.ErrInvalidDecision):
+			writeError(w, http.StatusBadRequest, "INVALID_DECISION", err.Error())
+		case errors.Is(err, proposal.ErrNotApprover):
+			writeError(w, http.StatusForbidden, "NOT_APPROVER", err.Error())
+		case errors.Is(err, proposal.ErrNoApprovalSet), errors.Is(err, appproposal.ErrAmbiguousApprovalSet):
+			writeError(w, http.StatusConflict, "NO_APPROVAL_SET", err.Error())
+		default:
+			writeError(w, http.StatusBadRequest, "APPROVAL_FAILED", err.Error())
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, result)
+}
+
 // Helper functions
 
 func parseListFilter(r *http.Request) proposal.ListFilter {
@@ -411,6 +616,13 @@ func parseListFilter(r *http.Request) proposal.ListFilter {
 		}
 	}
 
+	if types := r.URL.Query()["type"]; len(types) > 0 {
+		filter.Types = make([]proposal.ProposalType, len(types))
+		for i, t := range types {
+			filter.Types[i] = proposal.ProposalType(t)
+		}
+	}
+
 	if departments := r.URL.Query()["department"]; len(departments) > 0 {
 		filter.Departments = departments
 	}
@@ -441,3 +653,28 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 		},
 	})
 }
+
+// writeErrorDetails writes an error response with structured details.
+func writeErrorDetails(w http.ResponseWriter, status int, code, message string, details interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"details": details,
+		},
+	})
+}
+
+// writeForbidden writes a 403 response, including the policy decision that
+// explains the denial when there is one.
+func writeForbidden(w http.ResponseWriter, err error) {
+	var denied *authz.DeniedError
+	if errors.As(err, &denied) {
+		writeErrorDetails(w, http.StatusForbidden, "FORBIDDEN", denied.Decision.Explain(), denied.Decision)
+		return
+	}
+	writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+}
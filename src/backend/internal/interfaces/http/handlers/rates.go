@@ -0,0 +1,153 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/budget"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// ListRateAgreements handles GET /api/v1/settings/rate-agreements
+func (h *ProposalHandler) ListRateAgreements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	agreements, err := h.service.ListRateAgreements(ctx, *tenantCtx)
+	if err != nil {
+		writeRateAgreementError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"rate_agreements": agreements,
+	})
+}
+
+// CreateRateAgreement handles POST /api/v1/settings/rate-agreements
+func (h *ProposalHandler) CreateRateAgreement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var cmd appproposal.RateAgreementCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	agreement, err := h.service.CreateRateAgreement(ctx, *tenantCtx, cmd)
+	if err != nil {
+		writeRateAgreementError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, agreement)
+}
+
+// GetRateAgreement handles GET /api/v1/settings/rate-agreements/{id}
+func (h *ProposalHandler) GetRateAgreement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "rate agreement")
+	if !ok {
+		return
+	}
+
+	agreement, err := h.service.GetRateAgreement(ctx, *tenantCtx, id)
+	if err != nil {
+		writeRateAgreementError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, agreement)
+}
+
+// UpdateRateAgreement handles PUT /api/v1/settings/rate-agreements/{id}
+func (h *ProposalHandler) UpdateRateAgreement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "rate agreement")
+	if !ok {
+		return
+	}
+
+	var cmd appproposal.RateAgreementCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	agreement, err := h.service.UpdateRateAgreement(ctx, *tenantCtx, id, cmd)
+	if err != nil {
+		writeRateAgreementError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, agreement)
+}
+
+// DeleteRateAgreement handles DELETE /api/v1/settings/rate-agreements/{id}
+func (h *ProposalHandler) DeleteRateAgreement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, ok := parseURLID(w, r, "id", "rate agreement")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRateAgreement(ctx, *tenantCtx, id); err != nil {
+		writeRateAgreementError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeRateAgreementError maps rate agreement errors to responses.
+func writeRateAgreementError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrRateAgreementsUnavailable):
+		writeError(w, http.StatusNotImplemented, "RATE_AGREEMENTS_UNAVAILABLE", err.Error())
+	case errors.Is(err, budget.ErrRateAgreementNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Rate agreement not found")
+	case errors.Is(err, budget.ErrRateAgreementInUse):
+		writeError(w, http.StatusConflict, "RATE_AGREEMENT_IN_USE", err.Error())
+	case errors.Is(err, proposal.ErrVersionMismatch):
+		writeError(w, http.StatusConflict, "VERSION_MISMATCH", "Rate agreement was modified by another user")
+	case errors.Is(err, budget.ErrInvalidRateAgreement):
+		writeError(w, http.StatusBadRequest, "INVALID_RATE_AGREEMENT", err.Error())
+	default:
+		log.Error().Err(err).Msg("Rate agreement request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Rate agreement request failed")
+	}
+}
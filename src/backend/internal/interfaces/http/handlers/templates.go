@@ -0,0 +1,167 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// ListTemplates handles GET /api/v1/proposal-templates
+func (h *ProposalHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	filter := proposal.TemplateFilter{Department: r.URL.Query().Get("department")}
+	if raw := r.URL.Query().Get("opportunity_id"); raw != "" {
+		opportunityID, err := uuid.Parse(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid opportunity ID")
+			return
+		}
+		filter.OpportunityID = &opportunityID
+	}
+
+	templates, err := h.service.ListTemplates(ctx, *tenantCtx, filter)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"templates": templates,
+	})
+}
+
+// CreateTemplate handles POST /api/v1/proposal-templates
+func (h *ProposalHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var cmd appproposal.TemplateCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	tpl, err := h.service.CreateTemplate(ctx, *tenantCtx, cmd)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, tpl)
+}
+
+// GetTemplate handles GET /api/v1/proposal-templates/{id}
+func (h *ProposalHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	tpl, err := h.service.GetTemplate(ctx, *tenantCtx, id)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tpl)
+}
+
+// UpdateTemplate handles PUT /api/v1/proposal-templates/{id}
+func (h *ProposalHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	var cmd appproposal.TemplateCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	tpl, err := h.service.UpdateTemplate(ctx, *tenantCtx, id, cmd)
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tpl)
+}
+
+// DeleteTemplate handles DELETE /api/v1/proposal-templates/{id}
+func (h *ProposalHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	if err := h.service.DeleteTemplate(ctx, *tenantCtx, id); err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTemplateError maps proposal template errors to responses.
+func writeTemplateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrTemplatesUnavailable):
+		writeError(w, http.StatusNotImplemented, "TEMPLATES_UNAVAILABLE", err.Error())
+	case errors.Is(err, proposal.ErrTemplateNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal template not found")
+	case errors.Is(err, proposal.ErrTemplateNameTaken):
+		writeError(w, http.StatusConflict, "TEMPLATE_NAME_TAKEN", err.Error())
+	case errors.Is(err, proposal.ErrVersionMismatch):
+		writeError(w, http.StatusConflict, "VERSION_MISMATCH", "Proposal template was modified by another user")
+	case errors.Is(err, proposal.ErrInvalidTemplate):
+		writeError(w, http.StatusBadRequest, "INVALID_TEMPLATE", err.Error())
+	default:
+		log.Error().Err(err).Msg("Proposal template request failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Proposal template request failed")
+	}
+}
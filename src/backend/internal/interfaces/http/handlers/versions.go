@@ -0,0 +1,198 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appproposal "github.com/huron-portland/grants-management/internal/application/proposal"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// ListVersions handles GET /api/v1/proposals/{id}/versions
+func (h *ProposalHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	history, err := h.service.ListVersions(ctx, *tenantCtx, id)
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+// GetVersion handles GET /api/v1/proposals/{id}/versions/{version}
+func (h *ProposalHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version <= 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_VERSION", "Version must be a positive integer")
+		return
+	}
+
+	snapshot, err := h.service.GetVersion(ctx, *tenantCtx, id, version)
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+// DiffVersions handles GET /api/v1/proposals/{id}/versions/diff?from=1&to=2
+// Omitting to compares against the current working copy.
+func (h *ProposalHandler) DiffVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil || from <= 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_VERSION", "from must be a positive version number")
+		return
+	}
+	to := 0
+	if v := r.URL.Query().Get("to"); v != "" {
+		to, err = strconv.Atoi(v)
+		if err != nil || to < 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_VERSION", "to must be a version number")
+			return
+		}
+	}
+
+	diff, err := h.service.DiffVersions(ctx, *tenantCtx, id, from, to)
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diff)
+}
+
+// RestoreVersion handles POST /api/v1/proposals/{id}/versions/{version}/restore
+func (h *ProposalHandler) RestoreVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version <= 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_VERSION", "Version must be a positive integer")
+		return
+	}
+
+	cmd := appproposal.RestoreCommand{ProposalID: id, Version: version}
+	if r.ContentLength > 0 {
+		var req struct {
+			ExpectedVersion int `json:"expected_version"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+			return
+		}
+		cmd.ExpectedVersion = req.ExpectedVersion
+	}
+
+	draft, err := h.service.RestoreVersion(ctx, *tenantCtx, cmd)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Int("version", version).Msg("Failed to restore proposal version")
+		writeVersionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, draft)
+}
+
+// ChangesSinceReview handles GET /api/v1/proposals/{id}/changes-since-review
+func (h *ProposalHandler) ChangesSinceReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Invalid proposal ID")
+		return
+	}
+
+	changes, err := h.service.ChangesSinceReview(ctx, *tenantCtx, id)
+	if err != nil {
+		writeVersionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// writeVersionError maps versioning errors to responses.
+func writeVersionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, proposal.ErrUnauthorized):
+		writeForbidden(w, err)
+	case errors.Is(err, appproposal.ErrSnapshotsUnavailable):
+		writeError(w, http.StatusNotImplemented, "VERSIONS_UNAVAILABLE", err.Error())
+	case errors.Is(err, proposal.ErrProposalNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Proposal not found")
+	case errors.Is(err, proposal.ErrSnapshotNotFound):
+		writeError(w, http.StatusNotFound, "VERSION_NOT_FOUND", err.Error())
+	case errors.Is(err, proposal.ErrNoPriorReview):
+		writeError(w, http.StatusNotFound, "NO_PRIOR_REVIEW", err.Error())
+	case errors.Is(err, proposal.ErrNotLatestVersion):
+		writeError(w, http.StatusConflict, "NOT_LATEST_VERSION", err.Error())
+	case errors.Is(err, proposal.ErrVersionMismatch):
+		writeError(w, http.StatusConflict, "VERSION_MISMATCH", "Proposal was modified by another user")
+	default:
+		log.Error().Err(err).Msg("Proposal versioning failed")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Proposal versioning failed")
+	}
+}
@@ -0,0 +1,236 @@
+// Package handlers provides HTTP handlers for the grants management API.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	appworkflow "github.com/huron-portland/grants-management/internal/application/workflow"
+	"github.com/huron-portland/grants-management/internal/domain/proposal"
+	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
+	"github.com/huron-portland/grants-management/pkg/statemachine"
+	"github.com/rs/zerolog/log"
+)
+
+// maxWorkflowDocumentSize limits the size of uploaded workflow documents.
+const maxWorkflowDocumentSize = 1 << 20
+
+// WorkflowHandler handles workflow definition HTTP requests.
+type WorkflowHandler struct {
+	service *appworkflow.Service
+}
+
+// NewWorkflowHandler creates a new workflow handler.
+func NewWorkflowHandler(service *appworkflow.Service) *WorkflowHandler {
+	return &WorkflowHandler{service: service}
+}
+
+// ListVersions handles GET /api/v1/workflows/proposal/versions
+func (h *WorkflowHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	versions, err := h.service.ListVersions(ctx, *tenantCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list workflow versions")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list workflow versions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"workflow": proposal.WorkflowName,
+		"versions": versions,
+	})
+}
+
+// GetVersion handles GET /api/v1/workflows/proposal/versions/{version}
+func (h *WorkflowHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_VERSION", "Invalid workflow version")
+		return
+	}
+
+	def, err := h.service.GetDefinition(ctx, *tenantCtx, version)
+	if err != nil {
+		if errors.Is(err, proposal.ErrWorkflowVersionNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Workflow version not found")
+			return
+		}
+		log.Error().Err(err).Int("version", version).Msg("Failed to get workflow version")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get workflow version")
+		return
+	}
+
+	if r.URL.Query().Get("format") == string(statemachine.FormatYAML) {
+		data, err := def.Marshal(statemachine.FormatYAML)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to encode workflow")
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, def)
+}
+
+// Diagram handles GET /api/v1/workflows/proposal/diagram
+func (h *WorkflowHandler) Diagram(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	format := statemachine.DiagramFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = statemachine.DiagramMermaid
+	}
+
+	version := 0
+	if v := r.URL.Query().Get("version"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_VERSION", "Invalid workflow version")
+			return
+		}
+		version = parsed
+	}
+
+	diagram, err := h.service.Diagram(ctx, *tenantCtx, version, format)
+	if err != nil {
+		if errors.Is(err, statemachine.ErrUnsupportedDiagramFormat) {
+			writeError(w, http.StatusBadRequest, "INVALID_FORMAT", "Format must be mermaid or dot")
+			return
+		}
+		if errors.Is(err, proposal.ErrWorkflowVersionNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Workflow version not found")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to render workflow diagram")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to render workflow diagram")
+		return
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	if format == statemachine.DiagramDOT {
+		contentType = "text/vnd.graphviz; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, diagram)
+}
+
+// Publish handles POST /api/v1/workflows/proposal/versions
+func (h *WorkflowHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxWorkflowDocumentSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	format := statemachine.FormatJSON
+	if r.URL.Query().Get("format") == string(statemachine.FormatYAML) ||
+		strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		format = statemachine.FormatYAML
+	}
+
+	def, err := h.service.Publish(ctx, *tenantCtx, data, format)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to publish workflow")
+		if errors.Is(err, appworkflow.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "INVALID_WORKFLOW", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, def)
+}
+
+// GetPin handles GET /api/v1/workflows/proposal/pin
+func (h *WorkflowHandler) GetPin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	version, err := h.service.PinnedVersion(ctx, *tenantCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get workflow pin")
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get workflow pin")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"workflow": proposal.WorkflowName,
+		"version":  version,
+	})
+}
+
+// Pin handles PUT /api/v1/workflows/proposal/pin
+func (h *WorkflowHandler) Pin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantCtx := middleware.GetTenantContext(ctx)
+	if tenantCtx == nil {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Missing tenant context")
+		return
+	}
+
+	var req struct {
+		Version int `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if err := h.service.Pin(ctx, *tenantCtx, req.Version); err != nil {
+		log.Error().Err(err).Int("version", req.Version).Msg("Failed to pin workflow version")
+		if errors.Is(err, appworkflow.ErrUnauthorized) {
+			writeForbidden(w, err)
+			return
+		}
+		if errors.Is(err, proposal.ErrWorkflowVersionNotFound) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Workflow version not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "PIN_FAILED", "Failed to pin workflow version")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"workflow": proposal.WorkflowName,
+		"version":  req.Version,
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
 	"github.com/huron-portland/grants-management/internal/domain/common"
 	"github.com/rs/zerolog/log"
 )
@@ -96,6 +97,7 @@ func extractTenantContext(r *http.Request, cfg TenantConfig) (*common.TenantCont
 	var tenantID common.TenantID
 	var userID uuid.UUID
 	var roles []string
+	var department string
 
 	// Try to extract from JWT token first
 	authHeader := r.Header.Get("Authorization")
@@ -106,6 +108,7 @@ func extractTenantContext(r *http.Request, cfg TenantConfig) (*common.TenantCont
 			tenantID = claims.TenantID
 			userID = claims.UserID
 			roles = claims.Roles
+			department = claims.Department
 		} else if cfg.RequireAuth {
 			return nil, err
 		}
@@ -129,18 +132,20 @@ func extractTenantContext(r *http.Request, cfg TenantConfig) (*common.TenantCont
 	}
 
 	return &common.TenantContext{
-		TenantID: tenantID,
-		UserID:   userID,
-		Roles:    roles,
+		TenantID:   tenantID,
+		UserID:     userID,
+		Roles:      roles,
+		Department: department,
 	}, nil
 }
 
 // JWTClaims represents the claims in a JWT token.
 type JWTClaims struct {
-	TenantID common.TenantID `json:"tenant_id"`
-	UserID   uuid.UUID       `json:"user_id"`
-	Email    string          `json:"email"`
-	Roles    []string        `json:"roles"`
+	TenantID   common.TenantID `json:"tenant_id"`
+	UserID     uuid.UUID       `json:"user_id"`
+	Email      string          `json:"email"`
+	Roles      []string        `json:"roles"`
+	Department string          `json:"department,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -180,8 +185,9 @@ func GetRequestID(ctx context.Context) string {
 	return ""
 }
 
-// RequireRole creates middleware that requires a specific role.
-func RequireRole(role string) func(next http.Handler) http.Handler {
+// RequirePermission creates middleware that requires the policy engine to
+// allow an action for the caller. Resource-level checks stay with the services.
+func RequirePermission(az authz.Authorizer, action string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			tc := GetTenantContext(r.Context())
@@ -190,35 +196,8 @@ func RequireRole(role string) func(next http.Handler) http.Handler {
 				return
 			}
 
-			if !tc.HasRole(role) {
-				http.Error(w, "Forbidden", http.StatusForbidden)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
-// RequireAnyRole creates middleware that requires any of the specified roles.
-func RequireAnyRole(roles ...string) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			tc := GetTenantContext(r.Context())
-			if tc == nil {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
-				return
-			}
-
-			hasRole := false
-			for _, role := range roles {
-				if tc.HasRole(role) {
-					hasRole = true
-					break
-				}
-			}
-
-			if !hasRole {
+			if err := authz.Check(r.Context(), az, *tc, authz.Request{Action: action}); err != nil {
+				log.Warn().Err(err).Str("path", r.URL.Path).Msg("Request denied by policy")
 				http.Error(w, "Forbidden", http.StatusForbidden)
 				return
 			}
@@ -9,6 +9,7 @@ import (
 	chimw "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-chi/httprate"
+	"github.com/huron-portland/grants-management/internal/domain/authz"
 	"github.com/huron-portland/grants-management/internal/interfaces/http/handlers"
 	"github.com/huron-portland/grants-management/internal/interfaces/http/middleware"
 )
@@ -21,6 +22,7 @@ type RouterConfig struct {
 	RateLimitReqs    int
 	RateLimitWindow  time.Duration
 	EnableProfiling  bool
+	Authorizer       authz.Authorizer // nil uses the built-in policies
 }
 
 // DefaultRouterConfig returns default router configuration.
@@ -37,7 +39,11 @@ func DefaultRouterConfig() RouterConfig {
 
 // Handlers contains all API handlers.
 type Handlers struct {
-	Proposal *handlers.ProposalHandler
+	Proposal   *handlers.ProposalHandler
+	Workflow   *handlers.WorkflowHandler
+	Delegation *handlers.DelegationHandler
+	Authz      *handlers.AuthzHandler
+	Calendar   *handlers.CalendarHandler
 }
 
 // NewRouter creates a new HTTP router.
@@ -73,6 +79,9 @@ func NewRouter(cfg RouterConfig, h Handlers) *chi.Mux {
 	r.Get("/health", healthHandler)
 	r.Get("/ready", readyHandler)
 
+	// Calendar feeds (authenticated by the token in the URL)
+	r.Get("/calendar/{file}", h.Calendar.Feed)
+
 	// API routes
 	r.Route("/api", func(r chi.Router) {
 		// Apply tenant middleware to all API routes
@@ -85,6 +94,7 @@ func NewRouter(cfg RouterConfig, h Handlers) *chi.Mux {
 				r.Get("/", h.Proposal.List)
 				r.Post("/", h.Proposal.Create)
 				r.Get("/search", h.Proposal.Search)
+				r.Get("/by-number/{number}", h.Proposal.GetByNumber)
 				r.Get("/dashboard", h.Proposal.Dashboard)
 				r.Get("/upcoming-deadlines", h.Proposal.UpcomingDeadlines)
 				r.Get("/overdue", h.Proposal.Overdue)
@@ -96,16 +106,107 @@ func NewRouter(cfg RouterConfig, h Handlers) *chi.Mux {
 					r.Post("/transition", h.Proposal.Transition)
 					r.Get("/history", h.Proposal.GetHistory)
 					r.Get("/available-transitions", h.Proposal.AvailableTransitions)
+					r.Get("/editable-fields", h.Proposal.EditableFields)
+					r.Get("/approvals", h.Proposal.ListApprovals)
+					r.Post("/approvals", h.Proposal.CastApproval)
+					r.Get("/versions", h.Proposal.ListVersions)
+					r.Get("/versions/diff", h.Proposal.DiffVersions)
+					r.Get("/versions/{version}", h.Proposal.GetVersion)
+					r.Post("/versions/{version}/restore", h.Proposal.RestoreVersion)
+					r.Get("/changes-since-review", h.Proposal.ChangesSinceReview)
+					r.Post("/derive", h.Proposal.Derive)
+					r.Get("/lineage", h.Proposal.Lineage)
+					r.Get("/locks", h.Proposal.ListLocks)
+					r.Get("/locks/stream", h.Proposal.StreamLocks)
+					r.Post("/locks/{section}", h.Proposal.AcquireLock)
+					r.Post("/locks/{section}/heartbeat", h.Proposal.RenewLock)
+					r.Delete("/locks/{section}", h.Proposal.ReleaseLock)
+					r.Get("/award", h.Proposal.GetAward)
+					r.Put("/award", h.Proposal.UpdateAwardSetup)
+					r.Post("/award/complete-setup", h.Proposal.CompleteAwardSetup)
+					r.Post("/award/modifications", h.Proposal.RecordAwardModification)
+					r.Get("/closeout", h.Proposal.GetCloseout)
+					r.Put("/closeout/items/{itemId}", h.Proposal.ResolveCloseoutItem)
 				})
 			})
 
-			// Budgets (placeholder)
+			// Proposal templates
+			r.Route("/proposal-templates", func(r chi.Router) {
+				r.Get("/", h.Proposal.ListTemplates)
+				r.Post("/", h.Proposal.CreateTemplate)
+				r.Get("/{id}", h.Proposal.GetTemplate)
+				r.Put("/{id}", h.Proposal.UpdateTemplate)
+				r.Delete("/{id}", h.Proposal.DeleteTemplate)
+			})
+
+			// Workflows
+			r.Route("/workflows/proposal", func(r chi.Router) {
+				r.Get("/versions", h.Workflow.ListVersions)
+				r.Post("/versions", h.Workflow.Publish)
+				r.Get("/versions/{version}", h.Workflow.GetVersion)
+				r.Get("/pin", h.Workflow.GetPin)
+				r.Put("/pin", h.Workflow.Pin)
+				r.Get("/diagram", h.Workflow.Diagram)
+			})
+
+			// Settings
+			r.Route("/settings", func(r chi.Router) {
+				r.Get("/proposal-numbering", h.Proposal.GetNumberingScheme)
+				r.Put("/proposal-numbering", h.Proposal.UpdateNumberingScheme)
+				r.Get("/deadline-rule", h.Calendar.GetDeadlineRule)
+				r.Put("/deadline-rule", h.Calendar.PutDeadlineRule)
+				r.Delete("/deadline-rule", h.Calendar.DeleteDeadlineRule)
+				r.Get("/holidays", h.Calendar.ListHolidays)
+				r.Put("/holidays", h.Calendar.PutHolidays)
+				r.Delete("/holidays/{date}", h.Calendar.DeleteHoliday)
+				r.Get("/closeout-templates", h.Proposal.ListCloseoutTemplates)
+				r.Put("/closeout-templates/{sponsorType}", h.Proposal.PutCloseoutTemplate)
+				r.Delete("/closeout-templates/{sponsorType}", h.Proposal.DeleteCloseoutTemplate)
+				r.Get("/rate-agreements", h.Proposal.ListRateAgreements)
+				r.Post("/rate-agreements", h.Proposal.CreateRateAgreement)
+				r.Get("/rate-agreements/{id}", h.Proposal.GetRateAgreement)
+				r.Put("/rate-agreements/{id}", h.Proposal.UpdateRateAgreement)
+				r.Delete("/rate-agreements/{id}", h.Proposal.DeleteRateAgreement)
+			})
+
+			// Calendar feeds
+			r.Route("/calendar/feeds", func(r chi.Router) {
+				r.Get("/", h.Calendar.ListFeeds)
+				r.Post("/", h.Calendar.CreateFeed)
+				r.Delete("/{id}", h.Calendar.RevokeFeed)
+			})
+
+			// Delegations
+			r.Route("/delegations", func(r chi.Router) {
+				r.Get("/", h.Delegation.List)
+				r.Post("/", h.Delegation.Create)
+				r.Delete("/{id}", h.Delegation.Revoke)
+			})
+
+			// Authorization policies
+			r.Route("/authz", func(r chi.Router) {
+				r.Post("/explain", h.Authz.Explain)
+				r.Route("/policies", func(r chi.Router) {
+					r.Use(middleware.RequirePermission(cfg.Authorizer, authz.ActionPolicyManage))
+					r.Get("/", h.Authz.ListPolicies)
+					r.Put("/{id}", h.Authz.SavePolicy)
+					r.Delete("/{id}", h.Authz.DeletePolicy)
+				})
+			})
+
+			// Budgets
 			r.Route("/budgets", func(r chi.Router) {
-				r.Get("/", notImplementedHandler)
-				r.Post("/", notImplementedHandler)
+				r.Get("/", h.Proposal.ListBudgets)
+				r.Post("/", h.Proposal.CreateBudget)
 				r.Route("/{id}", func(r chi.Router) {
-					r.Get("/", notImplementedHandler)
-					r.Put("/", notImplementedHandler)
+					r.Get("/", h.Proposal.GetBudget)
+					r.Put("/", h.Proposal.UpdateBudget)
+					r.Post("/periods", h.Proposal.AddBudgetPeriod)
+					r.Delete("/periods/{periodId}", h.Proposal.RemoveBudgetPeriod)
+					r.Post("/periods/{periodId}/line-items", h.Proposal.AddLineItem)
+					r.Get("/periods/{periodId}/line-items/{itemId}", h.Proposal.GetLineItem)
+					r.Put("/periods/{periodId}/line-items/{itemId}", h.Proposal.UpdateLineItem)
+					r.Delete("/periods/{periodId}/line-items/{itemId}", h.Proposal.RemoveLineItem)
 				})
 			})
 
@@ -119,6 +220,7 @@ func NewRouter(cfg RouterConfig, h Handlers) *chi.Mux {
 			r.Route("/people", func(r chi.Router) {
 				r.Get("/", notImplementedHandler)
 				r.Get("/search", notImplementedHandler)
+				r.Get("/{id}/effort", h.Proposal.EffortTimeline)
 			})
 
 			// Opportunities (placeholder)
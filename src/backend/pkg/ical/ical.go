@@ -0,0 +1,129 @@
+// Package ical provides a minimal RFC 5545 iCalendar writer for event feeds.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding.
+const maxLineOctets = 75
+
+// utcLayout formats DATE-TIME values in UTC.
+const utcLayout = "20060102T150405Z"
+
+// Calendar is a VCALENDAR of events.
+type Calendar struct {
+	// ProductID identifies the producing application, e.g.
+	// "-//Example//Grants//EN".
+	ProductID string
+
+	// Name is shown by calendar apps when subscribing.
+	Name string
+
+	Events []Event
+}
+
+// Event is a VEVENT at an instant, such as a deadline.
+type Event struct {
+	UID         string    // globally unique and stable across refreshes
+	Stamp       time.Time // when the event was last changed
+	Start       time.Time
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+}
+
+// WriteTo writes the calendar in iCalendar format.
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", c.ProductID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME", escape(c.Name))
+	}
+	for _, e := range c.Events {
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", escape(e.UID))
+		cw.line("DTSTAMP", e.Stamp.UTC().Format(utcLayout))
+		cw.line("DTSTART", e.Start.UTC().Format(utcLayout))
+		cw.line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			cw.line("DESCRIPTION", escape(e.Description))
+		}
+		if e.URL != "" {
+			cw.line("URL", e.URL)
+		}
+		if len(e.Categories) > 0 {
+			escaped := make([]string, len(e.Categories))
+			for i, category := range e.Categories {
+				escaped[i] = escape(category)
+			}
+			cw.line("CATEGORIES", strings.Join(escaped, ","))
+		}
+		cw.line("TRANSP", "TRANSPARENT")
+		cw.line("END", "VEVENT")
+	}
+	cw.line("END", "VCALENDAR")
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// contentWriter writes folded content lines, keeping the first error.
+type contentWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+// line writes a NAME:value content line, folding it at 75 octets without
+// splitting UTF-8 sequences.
+func (cw *contentWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+
+	line := name + ":" + value
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > maxLineOctets {
+			// Continuation lines start with a space, which counts toward the limit
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	n, err := cw.w.WriteString(b.String())
+	cw.n += int64(n)
+	cw.err = err
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
@@ -0,0 +1,82 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "NIH R01", want: "NIH R01"},
+		{in: "Budget; final, signed", want: `Budget\; final\, signed`},
+		{in: `C:\grants`, want: `C:\\grants`},
+		{in: "line one\r\nline two\nthree\rfour", want: `line one\nline two\nthree\nfour`},
+	}
+
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteToFoldsLongLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+	}{
+		{name: "short", summary: "Internal deadline"},
+		{name: "ASCII", summary: strings.Repeat("Deadline ", 30)},
+		{name: "two-byte runes", summary: strings.Repeat("é", 100)},
+		{name: "three-byte runes", summary: "x" + strings.Repeat("€", 60)},
+		{name: "four-byte runes", summary: strings.Repeat("🗓", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := Calendar{
+				ProductID: "-//Example//Grants//EN",
+				Events: []Event{{
+					UID:     "deadline-1@example.edu",
+					Stamp:   time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+					Start:   time.Date(2026, 11, 2, 17, 0, 0, 0, time.FixedZone("EST", -5*3600)),
+					Summary: tt.summary,
+				}},
+			}
+
+			var b strings.Builder
+			n, err := cal.WriteTo(&b)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			out := b.String()
+			if n != int64(len(out)) {
+				t.Errorf("reported %d bytes, wrote %d", n, len(out))
+			}
+			if !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+				t.Error("expected every line to end with CRLF")
+			}
+
+			for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+				if len(line) > maxLineOctets {
+					t.Errorf("line of %d octets: %q", len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line splits a UTF-8 sequence: %q", line)
+				}
+			}
+
+			unfolded := strings.ReplaceAll(out, "\r\n ", "")
+			if !strings.Contains(unfolded, "\r\nSUMMARY:"+tt.summary+"\r\n") {
+				t.Errorf("summary does not unfold to %q:\n%s", tt.summary, out)
+			}
+			if !strings.Contains(unfolded, "\r\nDTSTART:20261102T220000Z\r\n") {
+				t.Errorf("expected the start in UTC:\n%s", out)
+			}
+		})
+	}
+}
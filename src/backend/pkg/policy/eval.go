@@ -0,0 +1,424 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Input holds the attributes a condition is evaluated against, keyed by root
+// name (subject, resource, context).
+type Input map[string]interface{}
+
+// NewInput creates an input from attribute maps, normalizing their values.
+func NewInput(roots map[string]map[string]interface{}) Input {
+	in := make(Input, len(roots))
+	for name, attrs := range roots {
+		in[name] = normalize(attrs)
+	}
+	return in
+}
+
+// lookup resolves a dotted attribute path; missing attributes are nil.
+func (in Input) lookup(parts []string) interface{} {
+	var cur interface{} = map[string]interface{}(in)
+	for _, part := range parts {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return normalize(cur)
+}
+
+// normalize converts attribute values to the types conditions operate on:
+// nil, bool, float64, string, []interface{} and map[string]interface{}.
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil, bool, float64, string:
+		return x
+	case int:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case []string:
+		out := make([]interface{}, len(x))
+		for i, s := range x {
+			out[i] = s
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, item := range x {
+			out[i] = normalize(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, item := range x {
+			out[k] = normalize(item)
+		}
+		return out
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	}
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = normalize(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		out := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = normalize(iter.Value().Interface())
+		}
+		return out
+	}
+	return fmt.Sprint(v)
+}
+
+// ============================================================================
+// Expression nodes
+// ============================================================================
+
+// node carries the source text of an expression.
+type node struct {
+	src string
+}
+
+func (n node) String() string {
+	return n.src
+}
+
+type literal struct {
+	node
+	value interface{}
+}
+
+func (e *literal) Eval(Input) (interface{}, error) {
+	return e.value, nil
+}
+
+type path struct {
+	node
+	parts []string
+}
+
+func (e *path) Eval(in Input) (interface{}, error) {
+	return in.lookup(e.parts), nil
+}
+
+type list struct {
+	node
+	items []Expr
+}
+
+func (e *list) Eval(in Input) (interface{}, error) {
+	out := make([]interface{}, len(e.items))
+	for i, item := range e.items {
+		v, err := item.Eval(in)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+type unary struct {
+	node
+	x Expr
+}
+
+func (e *unary) Eval(in Input) (interface{}, error) {
+	b, err := evalBool(e.x, in)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type binary struct {
+	node
+	op          string
+	left, right Expr
+}
+
+func (e *binary) Eval(in Input) (interface{}, error) {
+	switch e.op {
+	case "&&", "||":
+		l, err := evalBool(e.left, in)
+		if err != nil {
+			return nil, err
+		}
+		if (e.op == "&&") != l {
+			return l, nil
+		}
+		return evalBool(e.right, in)
+	}
+
+	l, err := e.left.Eval(in)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.right.Eval(in)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==":
+		return reflect.DeepEqual(l, r), nil
+	case "!=":
+		return !reflect.DeepEqual(l, r), nil
+	case "in":
+		return contains(e, r, l)
+	default:
+		return compare(e, e.op, l, r)
+	}
+}
+
+type call struct {
+	node
+	name string
+	fn   function
+	args []Expr
+}
+
+func (e *call) Eval(in Input) (interface{}, error) {
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		v, err := arg.Eval(in)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return e.fn.call(e, args)
+}
+
+// evalBool evaluates an expression that must produce a boolean.
+func evalBool(e Expr, in Input) (bool, error) {
+	v, err := e.Eval(in)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %s is %s, not a boolean", ErrEvaluation, e, render(v))
+	}
+	return b, nil
+}
+
+// contains implements the in operator.
+func contains(e Expr, container, item interface{}) (interface{}, error) {
+	switch c := container.(type) {
+	case nil:
+		return false, nil
+	case []interface{}:
+		for _, v := range c {
+			if reflect.DeepEqual(v, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		key, ok := item.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found := c[key]
+		return found, nil
+	case string:
+		s, ok := item.(string)
+		return ok && strings.Contains(c, s), nil
+	}
+	return nil, fmt.Errorf("%w: %s: cannot search in %s", ErrEvaluation, e, render(container))
+}
+
+// compare implements the ordering operators on numbers and strings.
+func compare(e Expr, op string, l, r interface{}) (interface{}, error) {
+	var cmp int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: cannot compare %s with %s", ErrEvaluation, e, render(l), render(r))
+		}
+		switch {
+		case lv < rv:
+			cmp = -1
+		case lv > rv:
+			cmp = 1
+		}
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: cannot compare %s with %s", ErrEvaluation, e, render(l), render(r))
+		}
+		cmp = strings.Compare(lv, rv)
+	default:
+		return nil, fmt.Errorf("%w: %s: cannot order %s", ErrEvaluation, e, render(l))
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+type function struct {
+	arity int
+	call  func(e Expr, args []interface{}) (interface{}, error)
+}
+
+var functions = map[string]function{
+	// intersects reports whether two lists share an element.
+	"intersects": {arity: 2, call: func(e Expr, args []interface{}) (interface{}, error) {
+		for _, item := range asList(args[0]) {
+			if found, err := contains(e, args[1], item); err == nil && found.(bool) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}},
+
+	// size returns the length of a list, map or string; null has size 0.
+	"size": {arity: 1, call: func(e Expr, args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case nil:
+			return float64(0), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		case string:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("%w: %s: %s has no size", ErrEvaluation, e, render(args[0]))
+	}},
+
+	// starts_with reports whether a string has a prefix.
+	"starts_with": {arity: 2, call: func(e Expr, args []interface{}) (interface{}, error) {
+		s, ok1 := args[0].(string)
+		prefix, ok2 := args[1].(string)
+		return ok1 && ok2 && strings.HasPrefix(s, prefix), nil
+	}},
+}
+
+// asList returns a list value, or nil for anything else.
+func asList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// ============================================================================
+// Explanations
+// ============================================================================
+
+// explain returns the reasons a condition did not hold: the innermost clauses
+// that were false, with the values of the attributes they reference.
+func explain(e Expr, in Input) []string {
+	switch n := e.(type) {
+	case *binary:
+		switch n.op {
+		case "&&":
+			if ok, err := evalBool(n.left, in); err != nil || !ok {
+				return explain(n.left, in)
+			}
+			return explain(n.right, in)
+		case "||":
+			return append(explain(n.left, in), explain(n.right, in)...)
+		}
+	case *unary:
+		return []string{fmt.Sprintf("%s was false: %s held%s", n, n.x, describeAttributes(n.x, in))}
+	}
+
+	if _, err := evalBool(e, in); err != nil {
+		return []string{err.Error()}
+	}
+	return []string{fmt.Sprintf("%s was false%s", e, describeAttributes(e, in))}
+}
+
+// describeAttributes renders the attribute values referenced by an expression.
+func describeAttributes(e Expr, in Input) string {
+	var parts []string
+	seen := make(map[string]bool)
+	walk(e, func(x Expr) {
+		if p, ok := x.(*path); ok && !seen[p.src] {
+			seen[p.src] = true
+			parts = append(parts, fmt.Sprintf("%s = %s", p.src, render(in.lookup(p.parts))))
+		}
+	})
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// walk visits an expression and its children.
+func walk(e Expr, visit func(Expr)) {
+	visit(e)
+	switch n := e.(type) {
+	case *unary:
+		walk(n.x, visit)
+	case *binary:
+		walk(n.left, visit)
+		walk(n.right, visit)
+	case *list:
+		for _, item := range n.items {
+			walk(item, visit)
+		}
+	case *call:
+		for _, arg := range n.args {
+			walk(arg, visit)
+		}
+	}
+}
+
+// render formats a value for explanations.
+func render(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func testInput() Input {
+	return NewInput(map[string]map[string]interface{}{
+		"subject": {
+			"id":    "u1",
+			"roles": []string{"PI", "REVIEWER"},
+			"level": 3,
+		},
+		"resource": {
+			"pi_id":  "u1",
+			"state":  "DRAFT",
+			"title":  "Coastal erosion study",
+			"labels": map[string]interface{}{"urgent": true},
+			"amount": int64(250000),
+		},
+	})
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    bool
+		wantErr bool
+	}{
+		{name: "equality", src: `subject.id == resource.pi_id`, want: true},
+		{name: "ordering numbers", src: `resource.amount > 100000 && subject.level <= 3`, want: true},
+		{name: "ordering strings", src: `resource.state < "SUBMITTED"`, want: true},
+		{name: "short-circuit or", src: `true || subject.missing`, want: true},
+		{name: "short-circuit and", src: `false && subject.missing`, want: false},
+		{name: "negation", src: `!(resource.state == "SUBMITTED")`, want: true},
+		{name: "functions", src: `intersects(subject.roles, ["ADMIN", "PI"]) && size(subject.roles) == 2 && starts_with(resource.title, "Coastal")`, want: true},
+
+		{name: "in list attribute", src: `"REVIEWER" in subject.roles`, want: true},
+		{name: "in list literal", src: `resource.state in ["SUBMITTED", "APPROVED"]`, want: false},
+		{name: "in map keys", src: `"urgent" in resource.labels`, want: true},
+		{name: "in map with a non-string key", src: `1 in resource.labels`, want: false},
+		{name: "in string", src: `"erosion" in resource.title`, want: true},
+		{name: "in string with a non-string item", src: `1 in resource.title`, want: false},
+		{name: "in missing attribute", src: `"ADMIN" in subject.missing`, want: false},
+		{name: "in number", src: `"x" in resource.amount`, wantErr: true},
+
+		{name: "missing attribute is null", src: `subject.missing == null`, want: true},
+		{name: "path through a non-map is null", src: `resource.state.code == null`, want: true},
+		{name: "missing attribute is not a boolean", src: `subject.missing`, wantErr: true},
+		{name: "missing attribute cannot be ordered", src: `subject.missing > 1`, wantErr: true},
+		{name: "missing attribute has size 0", src: `size(subject.missing) == 0`, want: true},
+		{name: "mismatched comparison", src: `resource.amount > "big"`, wantErr: true},
+		{name: "non-boolean operand", src: `resource.state && true`, wantErr: true},
+	}
+
+	in := testInput()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Compile(tt.src)
+			if err != nil {
+				t.Fatalf("Compile(%q) failed: %v", tt.src, err)
+			}
+			got, err := evalBool(expr, in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrEvaluation) {
+					t.Fatalf("%s = %v, %v; want ErrEvaluation", tt.src, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s failed: %v", tt.src, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetEvaluate(t *testing.T) {
+	allowPI := Policy{ID: "pi-edits", Effect: EffectAllow, Actions: []string{"proposal.*"}, Condition: `subject.id == resource.pi_id`}
+
+	tests := []struct {
+		name     string
+		policies []Policy
+		action   string
+		allowed  bool
+		deniedBy []string
+	}{
+		{name: "allowed", policies: []Policy{allowPI}, action: "proposal.update", allowed: true},
+		{name: "no applicable policy", policies: []Policy{allowPI}, action: "budget.update"},
+		{
+			name: "deny overrides allow",
+			policies: []Policy{allowPI,
+				{ID: "frozen", Effect: EffectDeny, Actions: []string{"*"}, Condition: `resource.state == "DRAFT"`}},
+			action:   "proposal.update",
+			deniedBy: []string{"frozen"},
+		},
+		{
+			name: "erroring deny holds",
+			policies: []Policy{allowPI,
+				{ID: "cap", Effect: EffectDeny, Actions: []string{"proposal.update"}, Condition: `resource.missing > 100`}},
+			action:   "proposal.update",
+			deniedBy: []string{"cap"},
+		},
+		{
+			name:     "erroring allow does not hold",
+			policies: []Policy{{ID: "broken", Effect: EffectAllow, Actions: []string{"*"}, Condition: `resource.missing > 100`}},
+			action:   "proposal.update",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := NewSet(tt.policies)
+			if err != nil {
+				t.Fatalf("NewSet failed: %v", err)
+			}
+			d := set.Evaluate(tt.action, testInput())
+			if d.Allowed != tt.allowed {
+				t.Errorf("allowed = %v, want %v: %s", d.Allowed, tt.allowed, d.Explain())
+			}
+			if len(d.DeniedBy) != len(tt.deniedBy) || len(tt.deniedBy) > 0 && d.DeniedBy[0] != tt.deniedBy[0] {
+				t.Errorf("denied by %v, want %v", d.DeniedBy, tt.deniedBy)
+			}
+		})
+	}
+}
+
+func TestNewSetRejectsDuplicateIDs(t *testing.T) {
+	p := Policy{ID: "p", Effect: EffectAllow, Actions: []string{"*"}}
+	if _, err := NewSet([]Policy{p, p}); !errors.Is(err, ErrInvalidPolicy) {
+		t.Fatalf("expected ErrInvalidPolicy, got %v", err)
+	}
+}
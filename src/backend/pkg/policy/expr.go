@@ -0,0 +1,380 @@
+// Package policy provides an in-process attribute-based policy language.
+//
+// Conditions are small boolean expressions over the attributes of a request:
+//
+//	subject.id == resource.pi_id || "ADMIN" in subject.roles
+//	intersects(subject.roles, context.required_roles) && resource.state != "SUBMITTED"
+//
+// Supported are string, number, boolean, null and list literals, dotted
+// attribute paths, the comparison operators ==, !=, <, <=, >, >= and in, the
+// logical operators &&, || and !, parentheses and the functions intersects,
+// size and starts_with. Missing attributes evaluate to null.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidPolicy is returned when a policy or its condition is malformed.
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// ErrEvaluation is returned when a condition cannot be evaluated against its input.
+var ErrEvaluation = errors.New("policy evaluation failed")
+
+// Expr is a compiled condition.
+type Expr interface {
+	// Eval evaluates the expression against an input.
+	Eval(in Input) (interface{}, error)
+
+	// String returns the expression's source text.
+	String() string
+}
+
+// Compile parses a condition. An empty condition always holds.
+func Compile(src string) (Expr, error) {
+	if strings.TrimSpace(src) == "" {
+		return &literal{node: node{src: "true"}, value: true}, nil
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{src: src, tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return expr, nil
+}
+
+// ============================================================================
+// Lexer
+// ============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+	tokDot
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value interface{}
+	pos   int
+	end   int
+}
+
+// punctuation maps single-character tokens to their kinds.
+var punctuation = map[byte]tokenKind{
+	'(': tokLParen,
+	')': tokRParen,
+	'[': tokLBracket,
+	']': tokRBracket,
+	',': tokComma,
+	'.': tokDot,
+}
+
+// lex splits a condition into tokens.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case punctuation[c] != 0:
+			tokens = append(tokens, token{kind: punctuation[c], text: string(c), pos: i, end: i + 1})
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			var sb strings.Builder
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+				sb.WriteByte(src[j])
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("%w: unterminated string at offset %d", ErrInvalidPolicy, i)
+			}
+			tokens = append(tokens, token{kind: tokString, text: src[i : j+1], value: sb.String(), pos: i, end: j + 1})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i + 1
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid number %q at offset %d", ErrInvalidPolicy, src[i:j], i)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: src[i:j], value: n, pos: i, end: j})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[i:j], pos: i, end: j})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%w: unexpected character %q at offset %d", ErrInvalidPolicy, c, i)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i, end: i + len(op)})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src), end: len(src)}), nil
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+type parser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// lastEnd returns the end offset of the last consumed token.
+func (p *parser) lastEnd() int {
+	if p.pos == 0 {
+		return 0
+	}
+	return p.tokens[p.pos-1].end
+}
+
+func (p *parser) source(start int) node {
+	return node{src: strings.TrimSpace(p.src[start:p.lastEnd()])}
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidPolicy, fmt.Sprintf(format, args...), tok.pos)
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	tok := p.next()
+	if tok.kind != kind {
+		return p.errorf(tok, "expected %q", text)
+	}
+	return nil
+}
+
+// parseOr parses a || b || ...
+func (p *parser) parseOr() (Expr, error) {
+	start := p.peek().pos
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{node: p.source(start), op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses a && b && ...
+func (p *parser) parseAnd() (Expr, error) {
+	start := p.peek().pos
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOp && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{node: p.source(start), op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseNot parses !a.
+func (p *parser) parseNot() (Expr, error) {
+	start := p.peek().pos
+	if tok := p.peek(); tok.kind == tokOp && tok.text == "!" {
+		p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{node: p.source(start), x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses a single optional comparison.
+func (p *parser) parseComparison() (Expr, error) {
+	start := p.peek().pos
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	op := ""
+	switch {
+	case tok.kind == tokOp && tok.text != "&&" && tok.text != "||" && tok.text != "!":
+		op = tok.text
+	case tok.kind == tokIdent && tok.text == "in":
+		op = "in"
+	}
+	if op == "" {
+		return left, nil
+	}
+
+	p.next()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return &binary{node: p.source(start), op: op, left: left, right: right}, nil
+}
+
+// parsePrimary parses literals, paths, calls, lists and parenthesized expressions.
+func (p *parser) parsePrimary() (Expr, error) {
+	start := p.peek().pos
+	tok := p.next()
+
+	switch tok.kind {
+	case tokString, tokNumber:
+		return &literal{node: p.source(start), value: tok.value}, nil
+
+	case tokLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+
+	case tokLBracket:
+		var items []Expr
+		for p.peek().kind != tokRBracket {
+			item, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(tokRBracket, "]"); err != nil {
+			return nil, err
+		}
+		return &list{node: p.source(start), items: items}, nil
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return &literal{node: p.source(start), value: true}, nil
+		case "false":
+			return &literal{node: p.source(start), value: false}, nil
+		case "null":
+			return &literal{node: p.source(start), value: nil}, nil
+		}
+
+		if p.peek().kind == tokLParen {
+			return p.parseCall(start, tok)
+		}
+
+		parts := []string{tok.text}
+		for p.peek().kind == tokDot {
+			p.next()
+			part := p.next()
+			if part.kind != tokIdent {
+				return nil, p.errorf(part, "expected attribute name")
+			}
+			parts = append(parts, part.text)
+		}
+		return &path{node: p.source(start), parts: parts}, nil
+	}
+
+	if tok.kind == tokEOF {
+		return nil, p.errorf(tok, "unexpected end of condition")
+	}
+	return nil, p.errorf(tok, "unexpected %q", tok.text)
+}
+
+// parseCall parses a function call whose name has been consumed.
+func (p *parser) parseCall(start int, name token) (Expr, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, p.errorf(name, "unknown function %q", name.text)
+	}
+
+	p.next() // (
+	var args []Expr
+	for p.peek().kind != tokRParen {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+	if len(args) != fn.arity {
+		return nil, p.errorf(name, "%s expects %d arguments, got %d", name.text, fn.arity, len(args))
+	}
+	return &call{node: p.source(start), name: name.text, fn: fn, args: args}, nil
+}
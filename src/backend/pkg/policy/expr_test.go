@@ -0,0 +1,78 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string // source text of the compiled expression; empty for errors
+	}{
+		{name: "empty condition", src: "  ", want: "true"},
+		{name: "comparison", src: `subject.id == resource.pi_id`, want: `subject.id == resource.pi_id`},
+		{name: "logical operators", src: `!(a || b) && c`, want: `!(a || b) && c`},
+		{name: "in a list literal", src: `resource.state in ["DRAFT", 'IN_PROGRESS']`, want: `resource.state in ["DRAFT", 'IN_PROGRESS']`},
+		{name: "function call", src: `size(subject.roles) >= -1.5`, want: `size(subject.roles) >= -1.5`},
+		{name: "escaped quote", src: `resource.title == "say \"hi\""`, want: `resource.title == "say \"hi\""`},
+
+		{name: "unterminated string", src: `resource.title == "draft`},
+		{name: "unexpected character", src: `a = b`},
+		{name: "invalid number", src: `a == 1.2.3`},
+		{name: "missing operand", src: `subject.id ==`},
+		{name: "missing right operand of in", src: `"ADMIN" in`},
+		{name: "unclosed parenthesis", src: `(a && b`},
+		{name: "unclosed list", src: `a in ["x", "y"`},
+		{name: "trailing tokens", src: `a == b c`},
+		{name: "dangling dot", src: `subject.`},
+		{name: "unknown function", src: `contains(a, b)`},
+		{name: "wrong arity", src: `size(a, b)`},
+		{name: "dangling operator", src: `a &&`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Compile(tt.src)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidPolicy) {
+					t.Fatalf("Compile(%q) = %v, want ErrInvalidPolicy", tt.src, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compile(%q) failed: %v", tt.src, err)
+			}
+			if expr.String() != tt.want {
+				t.Errorf("Compile(%q) = %q, want %q", tt.src, expr.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		valid  bool
+	}{
+		{name: "valid", policy: Policy{ID: "p", Effect: EffectAllow, Actions: []string{"*"}}, valid: true},
+		{name: "missing id", policy: Policy{Effect: EffectAllow, Actions: []string{"*"}}},
+		{name: "unknown effect", policy: Policy{ID: "p", Effect: "maybe", Actions: []string{"*"}}},
+		{name: "no actions", policy: Policy{ID: "p", Effect: EffectDeny}},
+		{name: "malformed condition", policy: Policy{ID: "p", Effect: EffectDeny, Actions: []string{"*"}, Condition: "a =="}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidPolicy) {
+				t.Fatalf("expected ErrInvalidPolicy, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Effect is the outcome a policy produces when its condition holds.
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Policy grants or denies a set of actions when its condition holds.
+type Policy struct {
+	ID          string   `json:"id" yaml:"id"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Effect      Effect   `json:"effect" yaml:"effect"`
+	Actions     []string `json:"actions" yaml:"actions"` // exact names, "prefix.*" or "*"
+	Condition   string   `json:"condition,omitempty" yaml:"condition,omitempty"`
+}
+
+// Validate checks the policy's structure and compiles its condition.
+func (p Policy) Validate() error {
+	_, err := p.compile()
+	return err
+}
+
+// AppliesTo reports whether the policy governs an action.
+func (p Policy) AppliesTo(action string) bool {
+	for _, pattern := range p.Actions {
+		switch {
+		case pattern == "*" || pattern == action:
+			return true
+		case strings.HasSuffix(pattern, ".*") && strings.HasPrefix(action, strings.TrimSuffix(pattern, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// compile validates the policy and compiles its condition.
+func (p Policy) compile() (*rule, error) {
+	if p.ID == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidPolicy)
+	}
+	if p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return nil, fmt.Errorf("%w: policy %q has unknown effect %q", ErrInvalidPolicy, p.ID, p.Effect)
+	}
+	if len(p.Actions) == 0 {
+		return nil, fmt.Errorf("%w: policy %q has no actions", ErrInvalidPolicy, p.ID)
+	}
+
+	cond, err := Compile(p.Condition)
+	if err != nil {
+		return nil, fmt.Errorf("policy %q: %w", p.ID, err)
+	}
+	return &rule{policy: p, cond: cond}, nil
+}
+
+// rule is a policy with its compiled condition.
+type rule struct {
+	policy Policy
+	cond   Expr
+}
+
+// Set is a compiled collection of policies. Evaluation is deny-overrides and
+// default-deny: an action is allowed when at least one applicable allow
+// policy holds and no applicable deny policy holds.
+type Set struct {
+	rules []*rule
+}
+
+// NewSet compiles a policy set. Policy IDs must be unique.
+func NewSet(policies []Policy) (*Set, error) {
+	set := &Set{}
+	seen := make(map[string]bool)
+	for _, p := range policies {
+		if seen[p.ID] {
+			return nil, fmt.Errorf("%w: duplicate policy %q", ErrInvalidPolicy, p.ID)
+		}
+		seen[p.ID] = true
+
+		r, err := p.compile()
+		if err != nil {
+			return nil, err
+		}
+		set.rules = append(set.rules, r)
+	}
+	return set, nil
+}
+
+// Policies returns the policies of the set.
+func (s *Set) Policies() []Policy {
+	policies := make([]Policy, len(s.rules))
+	for i, r := range s.rules {
+		policies[i] = r.policy
+	}
+	return policies
+}
+
+// Evaluation records how a single policy was evaluated.
+type Evaluation struct {
+	PolicyID  string   `json:"policy_id"`
+	Effect    Effect   `json:"effect"`
+	Condition string   `json:"condition,omitempty"`
+	Matched   bool     `json:"matched"`
+	Reasons   []string `json:"reasons,omitempty"` // why the condition did not hold
+	Error     string   `json:"error,omitempty"`
+}
+
+// Decision is the result of evaluating an action against a policy set.
+type Decision struct {
+	Action      string       `json:"action"`
+	Allowed     bool         `json:"allowed"`
+	AllowedBy   []string     `json:"allowed_by,omitempty"`
+	DeniedBy    []string     `json:"denied_by,omitempty"`
+	Evaluations []Evaluation `json:"evaluations"`
+}
+
+// Evaluate decides an action. Applicable policies are all evaluated so the
+// decision can explain itself. A deny policy whose condition fails to
+// evaluate is treated as holding.
+func (s *Set) Evaluate(action string, in Input) *Decision {
+	d := &Decision{Action: action, Evaluations: []Evaluation{}}
+
+	for _, r := range s.rules {
+		if !r.policy.AppliesTo(action) {
+			continue
+		}
+
+		eval := Evaluation{
+			PolicyID:  r.policy.ID,
+			Effect:    r.policy.Effect,
+			Condition: r.policy.Condition,
+		}
+
+		ok, err := evalBool(r.cond, in)
+		switch {
+		case err != nil:
+			eval.Error = err.Error()
+			eval.Matched = r.policy.Effect == EffectDeny
+		case ok:
+			eval.Matched = true
+		default:
+			eval.Reasons = explain(r.cond, in)
+		}
+
+		if eval.Matched {
+			if r.policy.Effect == EffectDeny {
+				d.DeniedBy = append(d.DeniedBy, r.policy.ID)
+			} else {
+				d.AllowedBy = append(d.AllowedBy, r.policy.ID)
+			}
+		}
+		d.Evaluations = append(d.Evaluations, eval)
+	}
+
+	d.Allowed = len(d.AllowedBy) > 0 && len(d.DeniedBy) == 0
+	return d
+}
+
+// Explain summarizes why the decision was reached.
+func (d *Decision) Explain() string {
+	switch {
+	case len(d.DeniedBy) > 0:
+		return fmt.Sprintf("%s denied by policy %s", d.Action, strings.Join(d.DeniedBy, ", "))
+	case d.Allowed:
+		return fmt.Sprintf("%s allowed by policy %s", d.Action, strings.Join(d.AllowedBy, ", "))
+	case len(d.Evaluations) == 0:
+		return fmt.Sprintf("no policy governs %s", d.Action)
+	}
+
+	var reasons []string
+	for _, e := range d.Evaluations {
+		if e.Effect != EffectAllow {
+			continue
+		}
+		why := strings.Join(e.Reasons, "; ")
+		if e.Error != "" {
+			why = e.Error
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %s", e.PolicyID, why))
+	}
+	if len(reasons) == 0 {
+		return fmt.Sprintf("no policy grants %s", d.Action)
+	}
+	return fmt.Sprintf("no policy grants %s (%s)", d.Action, strings.Join(reasons, "; "))
+}
@@ -0,0 +1,232 @@
+// Package statemachine provides static analysis of state machine graphs.
+package statemachine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AnalysisOptions configures static analysis of a state machine.
+type AnalysisOptions[S State] struct {
+	// States lists every declared state, including states that no
+	// transition references. Defaults to the states found in transitions.
+	States []S
+
+	// IsTerminal reports whether a state is terminal. Defaults to treating
+	// states without outgoing transitions as terminal.
+	IsTerminal func(state S) bool
+
+	// HasMetadata reports whether a state has metadata. When nil, metadata
+	// is not checked.
+	HasMetadata func(state S) bool
+}
+
+// AnalysisReport lists structural problems found in a state machine.
+type AnalysisReport struct {
+	// Unreachable lists states that cannot be reached from the initial state.
+	Unreachable []string `json:"unreachable,omitempty"`
+
+	// DeadEnds lists non-terminal states without outgoing transitions.
+	DeadEnds []string `json:"dead_ends,omitempty"`
+
+	// NonTerminatingCycles lists cycles from which no terminal state can be reached.
+	NonTerminatingCycles [][]string `json:"non_terminating_cycles,omitempty"`
+
+	// MissingMetadata lists states without metadata.
+	MissingMetadata []string `json:"missing_metadata,omitempty"`
+}
+
+// OK reports whether the analysis found no problems.
+func (r AnalysisReport) OK() bool {
+	return len(r.Unreachable) == 0 && len(r.DeadEnds) == 0 &&
+		len(r.NonTerminatingCycles) == 0 && len(r.MissingMetadata) == 0
+}
+
+// Problems returns the findings as human-readable messages.
+func (r AnalysisReport) Problems() []string {
+	var problems []string
+	for _, s := range r.Unreachable {
+		problems = append(problems, fmt.Sprintf("state %s is unreachable from the initial state", s))
+	}
+	for _, s := range r.DeadEnds {
+		problems = append(problems, fmt.Sprintf("non-terminal state %s has no outgoing transitions", s))
+	}
+	for _, c := range r.NonTerminatingCycles {
+		problems = append(problems, fmt.Sprintf("cycle %v never reaches a terminal state", c))
+	}
+	for _, s := range r.MissingMetadata {
+		problems = append(problems, fmt.Sprintf("state %s has no metadata", s))
+	}
+	return problems
+}
+
+// Analyze inspects the transition graph for unreachable states, non-terminal
+// dead ends, cycles that never reach a terminal state and states without
+// metadata. Guards are ignored; every declared edge is assumed passable.
+func (sm *StateMachine[S, T]) Analyze(opts AnalysisOptions[S]) AnalysisReport {
+	sm.mu.RLock()
+	edges := make(map[S][]S)
+	declared := map[S]bool{sm.initialState: true}
+	for from, transitions := range sm.transitions {
+		declared[from] = true
+		for _, to := range transitions {
+			edges[from] = append(edges[from], to)
+			declared[to] = true
+		}
+	}
+	// Entering a composite state enters the initial state of each region
+	for state, c := range sm.composites {
+		declared[state] = true
+		for _, r := range c.regions {
+			edges[state] = append(edges[state], r.Initial)
+			declared[r.Initial] = true
+		}
+	}
+	initial := sm.initialState
+	sm.mu.RUnlock()
+
+	for _, s := range opts.States {
+		declared[s] = true
+	}
+	states := make([]S, 0, len(declared))
+	for s := range declared {
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+
+	isTerminal := opts.IsTerminal
+	if isTerminal == nil {
+		isTerminal = func(s S) bool { return len(edges[s]) == 0 }
+	}
+
+	var report AnalysisReport
+
+	// Forward reachability from the initial state
+	reachable := map[S]bool{initial: true}
+	queue := []S{initial}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[current] {
+			if !reachable[next] {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// Backward reachability from terminal states
+	reverse := make(map[S][]S)
+	for from, tos := range edges {
+		for _, to := range tos {
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+	terminates := make(map[S]bool)
+	queue = queue[:0]
+	for _, s := range states {
+		if isTerminal(s) {
+			terminates[s] = true
+			queue = append(queue, s)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, prev := range reverse[current] {
+			if !terminates[prev] {
+				terminates[prev] = true
+				queue = append(queue, prev)
+			}
+		}
+	}
+
+	for _, s := range states {
+		if !reachable[s] {
+			report.Unreachable = append(report.Unreachable, string(s))
+		}
+		if len(edges[s]) == 0 && !isTerminal(s) {
+			report.DeadEnds = append(report.DeadEnds, string(s))
+		}
+		if opts.HasMetadata != nil && !opts.HasMetadata(s) {
+			report.MissingMetadata = append(report.MissingMetadata, string(s))
+		}
+	}
+
+	for _, component := range stronglyConnected(states, edges) {
+		if terminates[component[0]] {
+			continue
+		}
+		if len(component) == 1 && !hasSelfLoop(edges, component[0]) {
+			continue
+		}
+		cycle := make([]string, len(component))
+		for i, s := range component {
+			cycle[i] = string(s)
+		}
+		sort.Strings(cycle)
+		report.NonTerminatingCycles = append(report.NonTerminatingCycles, cycle)
+	}
+
+	return report
+}
+
+// hasSelfLoop reports whether a state has a transition to itself.
+func hasSelfLoop[S State](edges map[S][]S, s S) bool {
+	for _, to := range edges[s] {
+		if to == s {
+			return true
+		}
+	}
+	return false
+}
+
+// stronglyConnected returns the strongly connected components of the graph
+// using Tarjan's algorithm.
+func stronglyConnected[S State](states []S, edges map[S][]S) [][]S {
+	index := 0
+	indices := make(map[S]int)
+	lowlink := make(map[S]int)
+	onStack := make(map[S]bool)
+	var stack []S
+	var components [][]S
+
+	var visit func(v S)
+	visit = func(v S) {
+		indices[v] = index
+		lowlink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range edges[v] {
+			if _, seen := indices[w]; !seen {
+				visit(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], indices[w])
+			}
+		}
+
+		if lowlink[v] == indices[v] {
+			var component []S
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, s := range states {
+		if _, seen := indices[s]; !seen {
+			visit(s)
+		}
+	}
+	return components
+}
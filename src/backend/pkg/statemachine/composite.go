@@ -0,0 +1,300 @@
+// Package statemachine provides composite states with parallel regions.
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrNotComposite is returned when a region is addressed in a simple state.
+var ErrNotComposite = errors.New("state is not composite")
+
+// ErrUnknownRegion is returned when a composite state has no such region.
+var ErrUnknownRegion = errors.New("unknown region")
+
+// ErrRegionComplete is returned when a transition is fired in a finished region.
+var ErrRegionComplete = errors.New("region is already complete")
+
+// ErrRegionsIncomplete is returned when a composite state is exited through
+// its completion transition before every region has finished.
+var ErrRegionsIncomplete = errors.New("not all regions are complete")
+
+// ErrRegionState is returned when a transition is fired from a region
+// sub-state outside its composite state.
+var ErrRegionState = errors.New("state is only active within a region")
+
+// ErrAmbiguousRegion is returned when no region is named for a transition
+// that more than one active region defines.
+var ErrAmbiguousRegion = errors.New("transition is defined in more than one region")
+
+// Region is a concurrent region of a composite state. A region starts in its
+// initial state and completes when one of its states transitions to the
+// composite state itself.
+type Region[S State] struct {
+	Name    string `json:"name"`
+	Initial S      `json:"initial"`
+	States  []S    `json:"states"`
+}
+
+// contains reports whether a state belongs to the region.
+func (r Region[S]) contains(state S) bool {
+	for _, s := range r.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// compositeState is a state made of parallel regions.
+type compositeState[S State, T Transition] struct {
+	completion T
+	regions    []Region[S]
+}
+
+// Configuration is the active state of a machine. For composite states it
+// also holds the active sub-state of each region; a region whose sub-state
+// equals the composite state is complete.
+type Configuration[S State] struct {
+	State   S            `json:"state"`
+	Regions map[string]S `json:"regions,omitempty"`
+
+	// Blocked is the rejection of the completion transition when every
+	// region is complete but the composite state could not be left.
+	Blocked *GuardRejection `json:"blocked,omitempty"`
+}
+
+// RegionComplete reports whether a region of the configuration has finished.
+func (c Configuration[S]) RegionComplete(region string) bool {
+	return c.Regions[region] == c.State
+}
+
+// AllRegionsComplete reports whether every region has finished.
+func (c Configuration[S]) AllRegionsComplete() bool {
+	for name := range c.Regions {
+		if !c.RegionComplete(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddComposite declares a state as composite with parallel regions. Once all
+// regions complete, the completion transition is fired to leave the state.
+func (sm *StateMachine[S, T]) AddComposite(state S, completion T, regions ...Region[S]) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.composites[state] = &compositeState[S, T]{completion: completion, regions: regions}
+}
+
+// IsComposite reports whether a state has parallel regions.
+func (sm *StateMachine[S, T]) IsComposite(state S) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	_, ok := sm.composites[state]
+	return ok
+}
+
+// Regions returns the regions of a composite state.
+func (sm *StateMachine[S, T]) Regions(state S) []Region[S] {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	if c, ok := sm.composites[state]; ok {
+		return c.regions
+	}
+	return nil
+}
+
+// CompletionTransition returns the transition that leaves a composite state.
+func (sm *StateMachine[S, T]) CompletionTransition(state S) (T, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	if c, ok := sm.composites[state]; ok {
+		return c.completion, true
+	}
+	var zero T
+	return zero, false
+}
+
+// inRegion reports whether a state is a sub-state of a composite region.
+// Its transitions, such as the one completing the region, apply only within
+// the composite state.
+func (sm *StateMachine[S, T]) inRegion(state S) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	for _, c := range sm.composites {
+		for _, r := range c.regions {
+			if r.contains(state) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Enter returns the configuration for entering a state. Composite states
+// start every region in its initial state.
+func (sm *StateMachine[S, T]) Enter(state S) Configuration[S] {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	cfg := Configuration[S]{State: state}
+	if c, ok := sm.composites[state]; ok {
+		cfg.Regions = make(map[string]S, len(c.regions))
+		for _, r := range c.regions {
+			cfg.Regions[r.Name] = r.Initial
+		}
+	}
+	return cfg
+}
+
+// FireIn performs a transition in a configuration. With a region name the
+// transition is fired from that region's active sub-state; otherwise it is
+// fired from the top-level state. A region transition to a state outside the
+// composite leaves the composite entirely. When the last region completes,
+// the completion transition is fired; if its guards reject, the composite
+// stays active with all regions complete and the rejection is returned in
+// the configuration's Blocked field. Any other failure of the completion
+// transition is returned as an error.
+func (sm *StateMachine[S, T]) FireIn(ctx context.Context, cfg Configuration[S], region string, transition T, payload any) (Configuration[S], error) {
+	sm.mu.RLock()
+	composite, isComposite := sm.composites[cfg.State]
+	sm.mu.RUnlock()
+
+	if region == "" {
+		if isComposite && transition == composite.completion && !cfg.AllRegionsComplete() {
+			return cfg, ErrRegionsIncomplete
+		}
+		if !isComposite && sm.inRegion(cfg.State) {
+			return cfg, fmt.Errorf("%w: %s", ErrRegionState, cfg.State)
+		}
+		next, err := sm.Fire(ctx, cfg.State, transition, payload)
+		if err != nil {
+			return cfg, err
+		}
+		return sm.Enter(next), nil
+	}
+
+	if !isComposite {
+		return cfg, fmt.Errorf("%w: %s", ErrNotComposite, cfg.State)
+	}
+
+	var active *Region[S]
+	for i := range composite.regions {
+		if composite.regions[i].Name == region {
+			active = &composite.regions[i]
+			break
+		}
+	}
+	current, ok := cfg.Regions[region]
+	if active == nil || !ok {
+		return cfg, fmt.Errorf("%w: %s in %s", ErrUnknownRegion, region, cfg.State)
+	}
+	if current == cfg.State {
+		return cfg, fmt.Errorf("%w: %s", ErrRegionComplete, region)
+	}
+
+	next, err := sm.Fire(ctx, current, transition, payload)
+	if err != nil {
+		return cfg, err
+	}
+
+	if next != cfg.State && !active.contains(next) {
+		return sm.Enter(next), nil
+	}
+
+	result := Configuration[S]{State: cfg.State, Regions: make(map[string]S, len(cfg.Regions))}
+	for name, s := range cfg.Regions {
+		result.Regions[name] = s
+	}
+	result.Regions[region] = next
+
+	if result.AllRegionsComplete() {
+		exit, err := sm.Fire(ctx, cfg.State, composite.completion, payload)
+		if err != nil {
+			var rejection *GuardRejection
+			if !errors.As(err, &rejection) {
+				return cfg, err
+			}
+			result.Blocked = rejection
+			return result, nil
+		}
+		return sm.Enter(exit), nil
+	}
+
+	return result, nil
+}
+
+// ResolveRegion returns the region a transition applies to when the caller
+// names none. Only the transition table is consulted, not guards, so a
+// transition a guard rejects still resolves to its region and the rejection
+// reaches the caller. Top-level transitions and transitions defined nowhere
+// resolve to the empty region; a transition defined in more than one active
+// region fails with ErrAmbiguousRegion.
+func (sm *StateMachine[S, T]) ResolveRegion(cfg Configuration[S], transition T) (string, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	composite, isComposite := sm.composites[cfg.State]
+	if !isComposite {
+		return "", nil
+	}
+	if _, ok := sm.transitions[cfg.State][transition]; ok {
+		if transition != composite.completion || cfg.AllRegionsComplete() {
+			return "", nil
+		}
+	}
+
+	var matches []string
+	for name, current := range cfg.Regions {
+		if current == cfg.State {
+			continue
+		}
+		if _, ok := sm.transitions[current][transition]; ok {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	}
+	sort.Strings(matches)
+	return "", fmt.Errorf("%w: name one of %s for %s in %s", ErrAmbiguousRegion, strings.Join(matches, ", "), transition, cfg.State)
+}
+
+// AvailableTransitionsIn returns the transitions that may be fired in a
+// configuration, keyed by region. Top-level transitions use the empty key.
+func (sm *StateMachine[S, T]) AvailableTransitionsIn(ctx context.Context, cfg Configuration[S], payload any) map[string][]T {
+	sm.mu.RLock()
+	composite, isComposite := sm.composites[cfg.State]
+	sm.mu.RUnlock()
+
+	result := make(map[string][]T)
+	if !isComposite && sm.inRegion(cfg.State) {
+		return result
+	}
+	for _, t := range sm.AvailableTransitions(ctx, cfg.State, payload) {
+		if isComposite && t == composite.completion && !cfg.AllRegionsComplete() {
+			continue
+		}
+		result[""] = append(result[""], t)
+	}
+
+	if isComposite {
+		for name, current := range cfg.Regions {
+			if current == cfg.State {
+				continue
+			}
+			if available := sm.AvailableTransitions(ctx, current, payload); len(available) > 0 {
+				result[name] = available
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,217 @@
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// reviewMachine returns a machine whose review state has a compliance and a
+// budget region. Both regions finish with the same transition, and the
+// completion transition is held while the payload is "hold".
+func reviewMachine() *StateMachine[testState, testTransition] {
+	sm := New[testState, testTransition]("draft")
+	sm.AddTransition("draft", "submit", "review")
+	sm.AddTransition("review", "approve", "approved")
+	sm.AddTransition("compliance_check", "check", "compliance_signoff")
+	sm.AddTransition("compliance_signoff", "finish", "review")
+	sm.AddTransition("budget_check", "finish", "review")
+	sm.AddTransition("budget_check", "return", "draft")
+	sm.AddComposite("review", "approve",
+		Region[testState]{Name: "compliance", Initial: "compliance_check", States: []testState{"compliance_check", "compliance_signoff"}},
+		Region[testState]{Name: "budget", Initial: "budget_check", States: []testState{"budget_check"}},
+	)
+	sm.AddGuardFunc("approve", "not-held", func(ctx context.Context, event TransitionEvent[testState, testTransition]) error {
+		if event.Payload == "hold" {
+			return errors.New("held")
+		}
+		return nil
+	})
+	return sm
+}
+
+// reviewIn returns the review configuration with the given region sub-states.
+func reviewIn(compliance, budget testState) Configuration[testState] {
+	return Configuration[testState]{State: "review", Regions: map[string]testState{"compliance": compliance, "budget": budget}}
+}
+
+func TestFireIn(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Configuration[testState]
+		region      string
+		transition  testTransition
+		payload     any
+		want        Configuration[testState]
+		wantErr     error
+		wantBlocked bool
+	}{
+		{
+			name:       "entering a composite starts every region",
+			cfg:        Configuration[testState]{State: "draft"},
+			transition: "submit",
+			want:       reviewIn("compliance_check", "budget_check"),
+		},
+		{
+			name:       "region transition advances its sub-state",
+			cfg:        reviewIn("compliance_check", "budget_check"),
+			region:     "compliance",
+			transition: "check",
+			want:       reviewIn("compliance_signoff", "budget_check"),
+		},
+		{
+			name:       "completing one region keeps the composite",
+			cfg:        reviewIn("compliance_signoff", "budget_check"),
+			region:     "budget",
+			transition: "finish",
+			want:       reviewIn("compliance_signoff", "review"),
+		},
+		{
+			name:       "completing the last region fires the completion",
+			cfg:        reviewIn("compliance_signoff", "review"),
+			region:     "compliance",
+			transition: "finish",
+			want:       Configuration[testState]{State: "approved"},
+		},
+		{
+			name:        "rejected completion blocks the composite",
+			cfg:         reviewIn("compliance_signoff", "review"),
+			region:      "compliance",
+			transition:  "finish",
+			payload:     "hold",
+			want:        reviewIn("review", "review"),
+			wantBlocked: true,
+		},
+		{
+			name:       "region transition out of the composite leaves it",
+			cfg:        reviewIn("compliance_signoff", "budget_check"),
+			region:     "budget",
+			transition: "return",
+			want:       Configuration[testState]{State: "draft"},
+		},
+		{
+			name:       "completion before every region finished",
+			cfg:        reviewIn("compliance_signoff", "review"),
+			transition: "approve",
+			wantErr:    ErrRegionsIncomplete,
+		},
+		{
+			name:       "region of a simple state",
+			cfg:        Configuration[testState]{State: "draft"},
+			region:     "budget",
+			transition: "finish",
+			wantErr:    ErrNotComposite,
+		},
+		{
+			name:       "unknown region",
+			cfg:        reviewIn("compliance_check", "budget_check"),
+			region:     "legal",
+			transition: "finish",
+			wantErr:    ErrUnknownRegion,
+		},
+		{
+			name:       "completed region",
+			cfg:        reviewIn("compliance_signoff", "review"),
+			region:     "budget",
+			transition: "finish",
+			wantErr:    ErrRegionComplete,
+		},
+		{
+			name:       "region sub-state outside its composite",
+			cfg:        Configuration[testState]{State: "budget_check"},
+			transition: "finish",
+			wantErr:    ErrRegionState,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reviewMachine().FireIn(context.Background(), tt.cfg, tt.region, tt.transition, tt.payload)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if !reflect.DeepEqual(got, tt.cfg) {
+					t.Errorf("configuration changed to %+v on failure", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if (got.Blocked != nil) != tt.wantBlocked {
+				t.Errorf("blocked = %v, want %v", got.Blocked, tt.wantBlocked)
+			}
+			got.Blocked = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRegion(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Configuration[testState]
+		transition testTransition
+		want       string
+		wantErr    error
+	}{
+		{name: "simple state", cfg: Configuration[testState]{State: "draft"}, transition: "submit"},
+		{name: "defined in one region", cfg: reviewIn("compliance_check", "budget_check"), transition: "check", want: "compliance"},
+		{name: "defined in several active regions", cfg: reviewIn("compliance_signoff", "budget_check"), transition: "finish", wantErr: ErrAmbiguousRegion},
+		{name: "other region already complete", cfg: reviewIn("compliance_signoff", "review"), transition: "finish", want: "compliance"},
+		{name: "completion once every region finished", cfg: reviewIn("review", "review"), transition: "approve"},
+		{name: "completion before every region finished", cfg: reviewIn("compliance_signoff", "review"), transition: "approve"},
+		{name: "defined nowhere", cfg: reviewIn("compliance_check", "budget_check"), transition: "archive"},
+	}
+
+	sm := reviewMachine()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sm.ResolveRegion(tt.cfg, tt.transition)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolved region %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailableTransitionsIn(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Configuration[testState]
+		want map[string][]testTransition
+	}{
+		{
+			name: "regions in progress hide the completion",
+			cfg:  reviewIn("compliance_check", "review"),
+			want: map[string][]testTransition{"compliance": {"check"}},
+		},
+		{
+			name: "completion offered once every region finished",
+			cfg:  reviewIn("review", "review"),
+			want: map[string][]testTransition{"": {"approve"}},
+		},
+		{
+			name: "region sub-state outside its composite",
+			cfg:  Configuration[testState]{State: "compliance_signoff"},
+			want: map[string][]testTransition{},
+		},
+	}
+
+	sm := reviewMachine()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sm.AvailableTransitionsIn(context.Background(), tt.cfg, nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
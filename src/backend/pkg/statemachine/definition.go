@@ -0,0 +1,370 @@
+// Package statemachine provides declarative workflow definitions.
+package statemachine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidDefinition is returned when a workflow definition fails validation.
+var ErrInvalidDefinition = errors.New("invalid workflow definition")
+
+// Format identifies the serialization format of a workflow document.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// DetectFormat infers the document format from a file name.
+func DetectFormat(fileName string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("unsupported workflow document extension %q", filepath.Ext(fileName))
+	}
+}
+
+// Definition is a versioned, declarative description of a workflow.
+type Definition struct {
+	Name         string                 `json:"name" yaml:"name"`
+	Version      int                    `json:"version" yaml:"version"`
+	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	InitialState string                 `json:"initial_state" yaml:"initial_state"`
+	States       []StateDefinition      `json:"states" yaml:"states"`
+	Transitions  []TransitionDefinition `json:"transitions" yaml:"transitions"`
+}
+
+// StateDefinition describes a single state and its metadata.
+type StateDefinition struct {
+	Name             string   `json:"name" yaml:"name"`
+	DisplayName      string   `json:"display_name,omitempty" yaml:"display_name,omitempty"`
+	Description      string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Terminal         bool     `json:"terminal,omitempty" yaml:"terminal,omitempty"`
+	RequiredRoles    []string `json:"required_roles,omitempty" yaml:"required_roles,omitempty"`
+	NotificationList []string `json:"notification_list,omitempty" yaml:"notification_list,omitempty"`
+	SLAHours         int      `json:"sla_hours,omitempty" yaml:"sla_hours,omitempty"`
+
+	// Regions makes the state composite; Completion is the transition fired
+	// once every region has finished.
+	Regions    []RegionDefinition `json:"regions,omitempty" yaml:"regions,omitempty"`
+	Completion string             `json:"completion,omitempty" yaml:"completion,omitempty"`
+
+	// Approvals gate transitions out of the state on votes from a set of approvers.
+	Approvals []ApprovalDefinition `json:"approvals,omitempty" yaml:"approvals,omitempty"`
+
+	// Edits lists which fields each role may change while in the state.
+	Edits []EditDefinition `json:"edits,omitempty" yaml:"edits,omitempty"`
+}
+
+// EditDefinition lets holders of any of the roles change the listed fields.
+// A role of "*" matches every user; PriorApproval marks changes that must
+// cite a sponsor prior approval.
+type EditDefinition struct {
+	Roles         []string `json:"roles" yaml:"roles"`
+	Fields        []string `json:"fields" yaml:"fields"`
+	PriorApproval bool     `json:"prior_approval,omitempty" yaml:"prior_approval,omitempty"`
+}
+
+// ApprovalDefinition describes the approver votes required before a
+// transition out of a state fires.
+type ApprovalDefinition struct {
+	Transition       string   `json:"transition" yaml:"transition"`
+	Rule             string   `json:"rule" yaml:"rule"`
+	Quorum           int      `json:"quorum,omitempty" yaml:"quorum,omitempty"`
+	Approvers        []string `json:"approvers" yaml:"approvers"`
+	RequiredRoles    []string `json:"required_roles,omitempty" yaml:"required_roles,omitempty"`
+	RejectTransition string   `json:"reject_transition,omitempty" yaml:"reject_transition,omitempty"`
+}
+
+// RegionDefinition describes a concurrent region of a composite state.
+type RegionDefinition struct {
+	Name    string   `json:"name" yaml:"name"`
+	Initial string   `json:"initial" yaml:"initial"`
+	States  []string `json:"states" yaml:"states"`
+}
+
+// TransitionDefinition describes an edge between two states.
+type TransitionDefinition struct {
+	From       string   `json:"from" yaml:"from"`
+	Transition string   `json:"transition" yaml:"transition"`
+	To         string   `json:"to" yaml:"to"`
+	Guards     []string `json:"guards,omitempty" yaml:"guards,omitempty"`
+}
+
+// ParseDefinition decodes a workflow document in the given format.
+func ParseDefinition(data []byte, format Format) (*Definition, error) {
+	var def Definition
+
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &def); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON workflow: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &def); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML workflow: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported workflow format %q", format)
+	}
+
+	return &def, nil
+}
+
+// Marshal encodes the definition in the given format.
+func (d *Definition) Marshal(format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(d, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(d)
+	default:
+		return nil, fmt.Errorf("unsupported workflow format %q", format)
+	}
+}
+
+// State returns the definition of a named state.
+func (d *Definition) State(name string) (StateDefinition, bool) {
+	for _, s := range d.States {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return StateDefinition{}, false
+}
+
+// ValidationError lists every problem found in a definition.
+type ValidationError struct {
+	Problems []string `json:"problems"`
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidDefinition, strings.Join(e.Problems, "; "))
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidDefinition).
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidDefinition
+}
+
+// Validate checks the definition for structural problems. Guard names are
+// checked against isGuardRegistered; pass nil to skip that check.
+func (d *Definition) Validate(isGuardRegistered func(name string) bool) error {
+	var problems []string
+
+	if d.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	if d.Version <= 0 {
+		problems = append(problems, "version must be a positive integer")
+	}
+	if len(d.States) == 0 {
+		problems = append(problems, "at least one state is required")
+	}
+
+	// Check states
+	declared := make(map[string]StateDefinition)
+	hasTerminal := false
+	for _, s := range d.States {
+		if s.Name == "" {
+			problems = append(problems, "state name is required")
+			continue
+		}
+		if _, dup := declared[s.Name]; dup {
+			problems = append(problems, fmt.Sprintf("state %s is declared more than once", s.Name))
+		}
+		if s.SLAHours < 0 {
+			problems = append(problems, fmt.Sprintf("state %s has a negative SLA", s.Name))
+		}
+		declared[s.Name] = s
+		if s.Terminal {
+			hasTerminal = true
+		}
+	}
+	if len(d.States) > 0 && !hasTerminal {
+		problems = append(problems, "at least one terminal state is required")
+	}
+	if _, ok := declared[d.InitialState]; !ok {
+		problems = append(problems, fmt.Sprintf("initial state %q is not declared", d.InitialState))
+	}
+
+	// Check transitions
+	edges := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, t := range d.Transitions {
+		if t.Transition == "" {
+			problems = append(problems, fmt.Sprintf("transition from %s has no name", t.From))
+		}
+		from, fromOK := declared[t.From]
+		if !fromOK {
+			problems = append(problems, fmt.Sprintf("transition %s references undeclared state %q", t.Transition, t.From))
+		}
+		if _, ok := declared[t.To]; !ok {
+			problems = append(problems, fmt.Sprintf("transition %s references undeclared state %q", t.Transition, t.To))
+		}
+		if fromOK && from.Terminal {
+			problems = append(problems, fmt.Sprintf("terminal state %s has outgoing transition %s", t.From, t.Transition))
+		}
+
+		key := t.From + "/" + t.Transition
+		if seen[key] {
+			problems = append(problems, fmt.Sprintf("transition %s is defined more than once from %s", t.Transition, t.From))
+		}
+		seen[key] = true
+
+		for _, g := range t.Guards {
+			if isGuardRegistered != nil && !isGuardRegistered(g) {
+				problems = append(problems, fmt.Sprintf("guard %q on %s/%s is not registered", g, t.From, t.Transition))
+			}
+		}
+
+		edges[t.From] = append(edges[t.From], t.To)
+	}
+
+	// Check composite states
+	inRegion := make(map[string]string)
+	for _, s := range d.States {
+		if len(s.Regions) == 0 {
+			continue
+		}
+		if s.Completion == "" {
+			problems = append(problems, fmt.Sprintf("composite state %s has no completion transition", s.Name))
+		} else if !seen[s.Name+"/"+s.Completion] {
+			problems = append(problems, fmt.Sprintf("completion transition %s of %s is not defined", s.Completion, s.Name))
+		}
+		for _, r := range s.Regions {
+			if r.Name == "" {
+				problems = append(problems, fmt.Sprintf("region of %s has no name", s.Name))
+			}
+			hasInitial := false
+			for _, rs := range r.States {
+				if _, ok := declared[rs]; !ok {
+					problems = append(problems, fmt.Sprintf("region %s of %s references undeclared state %q", r.Name, s.Name, rs))
+				}
+				if owner, dup := inRegion[rs]; dup {
+					problems = append(problems, fmt.Sprintf("state %s belongs to regions of both %s and %s", rs, owner, s.Name))
+				}
+				inRegion[rs] = s.Name
+				if rs == r.Initial {
+					hasInitial = true
+				}
+			}
+			if !hasInitial {
+				problems = append(problems, fmt.Sprintf("initial state %q of region %s is not in the region", r.Initial, r.Name))
+			}
+			edges[s.Name] = append(edges[s.Name], r.Initial)
+		}
+	}
+
+	// Check approval sets
+	for _, s := range d.States {
+		gated := make(map[string]bool)
+		for _, a := range s.Approvals {
+			if !seen[s.Name+"/"+a.Transition] {
+				problems = append(problems, fmt.Sprintf("approval transition %s of %s is not defined", a.Transition, s.Name))
+			}
+			if gated[a.Transition] {
+				problems = append(problems, fmt.Sprintf("transition %s of %s has more than one approval set", a.Transition, s.Name))
+			}
+			gated[a.Transition] = true
+			if a.RejectTransition != "" && !seen[s.Name+"/"+a.RejectTransition] {
+				problems = append(problems, fmt.Sprintf("approval reject transition %s of %s is not defined", a.RejectTransition, s.Name))
+			}
+			if len(a.Approvers) == 0 && len(a.RequiredRoles) == 0 {
+				problems = append(problems, fmt.Sprintf("approval set for %s/%s has no approvers", s.Name, a.Transition))
+			}
+		}
+	}
+
+	// Check edit rules
+	for _, s := range d.States {
+		for i, e := range s.Edits {
+			if len(e.Roles) == 0 {
+				problems = append(problems, fmt.Sprintf("edit rule %d of %s has no roles", i+1, s.Name))
+			}
+			if len(e.Fields) == 0 {
+				problems = append(problems, fmt.Sprintf("edit rule %d of %s has no fields", i+1, s.Name))
+			}
+		}
+	}
+
+	// Check reachability from the initial state
+	if _, ok := declared[d.InitialState]; ok {
+		reachable := map[string]bool{d.InitialState: true}
+		queue := []string{d.InitialState}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range edges[current] {
+				if !reachable[next] {
+					reachable[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+		for _, s := range d.States {
+			if s.Name != "" && !reachable[s.Name] {
+				problems = append(problems, fmt.Sprintf("state %s is unreachable from %s", s.Name, d.InitialState))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// GuardRegistry maps guard names used in workflow documents to guard functions.
+type GuardRegistry[S State, T Transition] map[string]GuardFunc[S, T]
+
+// Has reports whether a guard with the given name is registered.
+func (r GuardRegistry[S, T]) Has(name string) bool {
+	_, ok := r[name]
+	return ok
+}
+
+// Build validates a definition and constructs a state machine from it.
+func Build[S State, T Transition](def *Definition, guards GuardRegistry[S, T]) (*StateMachine[S, T], error) {
+	if err := def.Validate(guards.Has); err != nil {
+		return nil, err
+	}
+
+	sm := New[S, T](S(def.InitialState))
+	for _, t := range def.Transitions {
+		from := S(t.From)
+		sm.AddTransition(from, T(t.Transition), S(t.To))
+
+		for _, name := range t.Guards {
+			sm.AddStateGuard(from, T(t.Transition), name, guards[name])
+		}
+	}
+
+	for _, s := range def.States {
+		if len(s.Regions) == 0 {
+			continue
+		}
+		regions := make([]Region[S], 0, len(s.Regions))
+		for _, r := range s.Regions {
+			region := Region[S]{Name: r.Name, Initial: S(r.Initial)}
+			for _, rs := range r.States {
+				region.States = append(region.States, S(rs))
+			}
+			regions = append(regions, region)
+		}
+		sm.AddComposite(S(s.Name), T(s.Completion), regions...)
+	}
+
+	return sm, nil
+}
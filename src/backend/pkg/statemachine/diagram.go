@@ -0,0 +1,324 @@
+// Package statemachine provides Graphviz DOT and Mermaid diagram export.
+package statemachine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedDiagramFormat is returned for unknown diagram formats.
+var ErrUnsupportedDiagramFormat = errors.New("unsupported diagram format")
+
+// DiagramFormat is the output format of a workflow diagram.
+type DiagramFormat string
+
+// Supported diagram formats.
+const (
+	DiagramDOT     DiagramFormat = "dot"
+	DiagramMermaid DiagramFormat = "mermaid"
+)
+
+// StateAnnotation is additional information rendered for a state.
+type StateAnnotation struct {
+	Label    string
+	SLAHours int
+	Terminal bool
+}
+
+// DiagramOptions configures diagram export.
+type DiagramOptions[S State] struct {
+	// Title names the graph.
+	Title string
+
+	// States fixes the order states are rendered in. States not listed are
+	// appended in lexical order.
+	States []S
+
+	// Annotate returns the label, SLA and terminal flag of a state.
+	Annotate func(state S) StateAnnotation
+}
+
+// diagramEdge is a transition prepared for rendering.
+type diagramEdge struct {
+	from, to, transition string
+	guards               []string
+}
+
+// diagramRegion is a region of a composite state prepared for rendering.
+type diagramRegion struct {
+	name    string
+	initial string
+	states  []string
+}
+
+// diagramGraph is the deterministic, sorted view of a state machine used by
+// the renderers.
+type diagramGraph struct {
+	title       string
+	initial     string
+	states      []string
+	annotations map[string]StateAnnotation
+	edges       []diagramEdge
+	composites  map[string][]diagramRegion
+	regionOf    map[string]string
+}
+
+// Diagram renders the state machine in the given format.
+func (sm *StateMachine[S, T]) Diagram(format DiagramFormat, opts DiagramOptions[S]) (string, error) {
+	switch format {
+	case DiagramDOT:
+		return sm.ToDOT(opts), nil
+	case DiagramMermaid:
+		return sm.ToMermaid(opts), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedDiagramFormat, format)
+	}
+}
+
+// graph collects states, annotations and edges in a stable order.
+func (sm *StateMachine[S, T]) graph(opts DiagramOptions[S]) diagramGraph {
+	transitions := sm.GetAllTransitions()
+
+	seen := map[S]bool{sm.initialState: true}
+	for _, t := range transitions {
+		seen[t.From] = true
+		seen[t.To] = true
+	}
+
+	g := diagramGraph{
+		title:       opts.Title,
+		initial:     string(sm.initialState),
+		annotations: make(map[string]StateAnnotation),
+		composites:  make(map[string][]diagramRegion),
+		regionOf:    make(map[string]string),
+	}
+	if g.title == "" {
+		g.title = "workflow"
+	}
+
+	var extra []string
+	for _, s := range opts.States {
+		if seen[s] {
+			g.states = append(g.states, string(s))
+			delete(seen, s)
+		}
+	}
+	for s := range seen {
+		extra = append(extra, string(s))
+	}
+	sort.Strings(extra)
+	g.states = append(g.states, extra...)
+
+	sm.mu.RLock()
+	for state, c := range sm.composites {
+		for _, r := range c.regions {
+			region := diagramRegion{name: r.Name, initial: string(r.Initial)}
+			for _, rs := range r.States {
+				region.states = append(region.states, string(rs))
+				g.regionOf[string(rs)] = string(state)
+			}
+			g.composites[string(state)] = append(g.composites[string(state)], region)
+		}
+	}
+	sm.mu.RUnlock()
+	for _, regions := range g.composites {
+		sort.Slice(regions, func(i, j int) bool { return regions[i].name < regions[j].name })
+	}
+
+	outgoing := make(map[string]bool)
+	for _, t := range transitions {
+		outgoing[string(t.From)] = true
+		g.edges = append(g.edges, diagramEdge{
+			from:       string(t.From),
+			to:         string(t.To),
+			transition: string(t.Transition),
+			guards:     sm.GuardNames(t.From, t.Transition),
+		})
+	}
+
+	order := make(map[string]int, len(g.states))
+	for i, s := range g.states {
+		order[s] = i
+	}
+	sort.Slice(g.edges, func(i, j int) bool {
+		a, b := g.edges[i], g.edges[j]
+		if a.from != b.from {
+			return order[a.from] < order[b.from]
+		}
+		return a.transition < b.transition
+	})
+
+	for _, s := range g.states {
+		a := StateAnnotation{Terminal: !outgoing[s]}
+		if opts.Annotate != nil {
+			a = opts.Annotate(S(s))
+		}
+		g.annotations[s] = a
+	}
+
+	return g
+}
+
+// label returns the transition name followed by its guards.
+func (e diagramEdge) label() string {
+	if len(e.guards) == 0 {
+		return e.transition
+	}
+	return e.transition + " [" + strings.Join(e.guards, ", ") + "]"
+}
+
+// stateDetail returns the display name and SLA annotation of a state.
+func stateDetail(state string, a StateAnnotation) []string {
+	lines := []string{state}
+	if a.Label != "" && a.Label != state {
+		lines = []string{a.Label}
+	}
+	if a.SLAHours > 0 {
+		lines = append(lines, fmt.Sprintf("SLA %dh", a.SLAHours))
+	}
+	return lines
+}
+
+// ToDOT renders the state machine as a Graphviz DOT digraph.
+func (sm *StateMachine[S, T]) ToDOT(opts DiagramOptions[S]) string {
+	g := sm.graph(opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.title))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	b.WriteString("  \"__start\" [shape=point];\n")
+
+	node := func(indent, s string) {
+		a := g.annotations[s]
+		attrs := "label=" + dotQuote(strings.Join(stateDetail(s, a), "\n"))
+		if a.Terminal {
+			attrs += ", peripheries=2"
+		}
+		fmt.Fprintf(&b, "%s%s [%s];\n", indent, dotQuote(s), attrs)
+	}
+
+	for _, s := range g.states {
+		if _, nested := g.regionOf[s]; nested {
+			continue
+		}
+		node("  ", s)
+
+		// Composite states are drawn as a cluster per region
+		for _, r := range g.composites[s] {
+			fmt.Fprintf(&b, "  subgraph %s {\n", dotQuote("cluster_"+s+"_"+r.name))
+			fmt.Fprintf(&b, "    label=%s;\n    style=dashed;\n", dotQuote(s+" / "+r.name))
+			for _, rs := range r.states {
+				node("    ", rs)
+			}
+			b.WriteString("  }\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "  \"__start\" -> %s;\n", dotQuote(g.initial))
+	for _, s := range g.states {
+		for _, r := range g.composites[s] {
+			fmt.Fprintf(&b, "  %s -> %s [style=dashed, label=%s];\n", dotQuote(s), dotQuote(r.initial), dotQuote("region "+r.name))
+		}
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(e.from), dotQuote(e.to), dotQuote(e.label()))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ToMermaid renders the state machine as a Mermaid stateDiagram-v2.
+func (sm *StateMachine[S, T]) ToMermaid(opts DiagramOptions[S]) string {
+	g := sm.graph(opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: %s\n---\n", g.title)
+	b.WriteString("stateDiagram-v2\n")
+
+	describe := func(indent, s string) {
+		fmt.Fprintf(&b, "%s%s : %s\n", indent, s, mermaidEscape(strings.Join(stateDetail(s, g.annotations[s]), "<br/>")))
+	}
+
+	for _, s := range g.states {
+		if _, nested := g.regionOf[s]; !nested {
+			describe("    ", s)
+		}
+	}
+
+	// Composite states are drawn as nested blocks with one concurrent
+	// section per region; a transition back to the composite ends the region.
+	inside := make(map[int]bool)
+	for _, s := range g.states {
+		regions := g.composites[s]
+		if len(regions) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "    state %s {\n", s)
+		for i, r := range regions {
+			if i > 0 {
+				b.WriteString("        --\n")
+			}
+			for _, rs := range r.states {
+				describe("        ", rs)
+			}
+			fmt.Fprintf(&b, "        [*] --> %s\n", r.initial)
+			for j, e := range g.edges {
+				if g.regionOf[e.from] != s || !containsString(r.states, e.from) {
+					continue
+				}
+				switch {
+				case e.to == s:
+					fmt.Fprintf(&b, "        %s --> [*] : %s\n", e.from, mermaidEscape(e.label()))
+				case containsString(r.states, e.to):
+					fmt.Fprintf(&b, "        %s --> %s : %s\n", e.from, e.to, mermaidEscape(e.label()))
+				default:
+					continue
+				}
+				inside[j] = true
+			}
+		}
+		b.WriteString("    }\n")
+	}
+
+	fmt.Fprintf(&b, "    [*] --> %s\n", g.initial)
+	for j, e := range g.edges {
+		if !inside[j] {
+			fmt.Fprintf(&b, "    %s --> %s : %s\n", e.from, e.to, mermaidEscape(e.label()))
+		}
+	}
+
+	for _, s := range g.states {
+		if g.annotations[s].Terminal {
+			fmt.Fprintf(&b, "    %s --> [*]\n", s)
+		}
+	}
+
+	return b.String()
+}
+
+// containsString reports whether a slice contains a string.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// dotQuote quotes a DOT identifier or label.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidEscape removes characters that terminate Mermaid labels.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(":", "&#58;", ";", "&#59;", "\n", " ").Replace(s)
+}
@@ -0,0 +1,80 @@
+package statemachine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDiagram(t *testing.T) {
+	opts := DiagramOptions[testState]{
+		Title:  "review",
+		States: []testState{"draft", "review", "approved"},
+		Annotate: func(state testState) StateAnnotation {
+			switch state {
+			case "review":
+				return StateAnnotation{Label: "Under Review", SLAHours: 48}
+			case "approved":
+				return StateAnnotation{Terminal: true}
+			}
+			return StateAnnotation{}
+		},
+	}
+
+	tests := []struct {
+		format DiagramFormat
+		want   []string
+	}{
+		{
+			format: DiagramDOT,
+			want: []string{
+				"digraph \"review\" {\n",
+				"  \"review\" [label=\"Under Review\\nSLA 48h\"];\n",
+				"  \"approved\" [label=\"approved\", peripheries=2];\n",
+				"  subgraph \"cluster_review_budget\" {\n    label=\"review / budget\";\n    style=dashed;\n    \"budget_check\" [label=\"budget_check\"];\n  }\n",
+				"  \"__start\" -> \"draft\";\n",
+				"  \"review\" -> \"compliance_check\" [style=dashed, label=\"region compliance\"];\n",
+				"  \"review\" -> \"approved\" [label=\"approve [not-held]\"];\n",
+				"  \"budget_check\" -> \"draft\" [label=\"return\"];\n",
+			},
+		},
+		{
+			format: DiagramMermaid,
+			want: []string{
+				"---\ntitle: review\n---\nstateDiagram-v2\n",
+				"    review : Under Review<br/>SLA 48h\n",
+				"    state review {\n",
+				"        [*] --> budget_check\n        budget_check --> [*] : finish\n",
+				"        --\n",
+				"        compliance_check --> compliance_signoff : check\n",
+				"        compliance_signoff --> [*] : finish\n",
+				"    [*] --> draft\n",
+				"    review --> approved : approve [not-held]\n",
+				"    budget_check --> draft : return\n",
+				"    approved --> [*]\n",
+			},
+		},
+	}
+
+	sm := reviewMachine()
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			out, err := sm.Diagram(tt.format, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output is missing %q:\n%s", want, out)
+				}
+			}
+			if again, _ := sm.Diagram(tt.format, opts); again != out {
+				t.Error("output is not deterministic")
+			}
+		})
+	}
+
+	if _, err := sm.Diagram("svg", opts); !errors.Is(err, ErrUnsupportedDiagramFormat) {
+		t.Errorf("expected ErrUnsupportedDiagramFormat, got %v", err)
+	}
+}
@@ -2,7 +2,10 @@
 package statemachine
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -12,6 +15,12 @@ var ErrInvalidTransition = errors.New("invalid state transition")
 // ErrNoTransitions is returned when no transitions are defined.
 var ErrNoTransitions = errors.New("no transitions defined for state")
 
+// ErrGuardFailed is reported for boolean guards that return false.
+var ErrGuardFailed = errors.New("guard condition not met")
+
+// ErrHookFailed is returned when an exit or enter hook aborts a transition.
+var ErrHookFailed = errors.New("transition hook failed")
+
 // State represents a state type constraint.
 type State interface {
 	~string
@@ -22,14 +31,47 @@ type Transition interface {
 	~string
 }
 
+// TransitionEvent describes a transition that is being attempted.
+type TransitionEvent[S State, T Transition] struct {
+	From       S
+	To         S
+	Transition T
+	Payload    any
+}
+
+// GuardFunc decides whether a transition may proceed. A non-nil error blocks
+// the transition and explains why.
+type GuardFunc[S State, T Transition] func(ctx context.Context, event TransitionEvent[S, T]) error
+
+// HookFunc runs when a state is exited or entered. A non-nil error aborts the
+// transition.
+type HookFunc[S State, T Transition] func(ctx context.Context, event TransitionEvent[S, T]) error
+
+// Hook is a named exit or enter action with an optional compensating action
+// that is run if a later hook of the same transition fails.
+type Hook[S State, T Transition] struct {
+	Name     string
+	Run      HookFunc[S, T]
+	Rollback HookFunc[S, T]
+}
+
+// namedGuard pairs a guard with the name reported when it rejects.
+type namedGuard[S State, T Transition] struct {
+	name  string
+	guard GuardFunc[S, T]
+}
+
 // StateMachine is a generic state machine implementation.
 type StateMachine[S State, T Transition] struct {
 	mu           sync.RWMutex
 	initialState S
 	transitions  map[S]map[T]S
-	onEnter      map[S][]func(from S)
-	onExit       map[S][]func(to S)
-	guards       map[T][]func(from, to S) bool
+	onEnter      map[S][]Hook[S, T]
+	onExit       map[S][]Hook[S, T]
+	guards       map[T][]namedGuard[S, T]
+	stateGuards  map[S]map[T][]namedGuard[S, T]
+	destGuards   map[S][]namedGuard[S, T]
+	composites   map[S]*compositeState[S, T]
 }
 
 // New creates a new state machine with an initial state.
@@ -37,12 +79,64 @@ func New[S State, T Transition](initialState S) *StateMachine[S, T] {
 	return &StateMachine[S, T]{
 		initialState: initialState,
 		transitions:  make(map[S]map[T]S),
-		onEnter:      make(map[S][]func(from S)),
-		onExit:       make(map[S][]func(to S)),
-		guards:       make(map[T][]func(from, to S) bool),
+		onEnter:      make(map[S][]Hook[S, T]),
+		onExit:       make(map[S][]Hook[S, T]),
+		guards:       make(map[T][]namedGuard[S, T]),
+		stateGuards:  make(map[S]map[T][]namedGuard[S, T]),
+		destGuards:   make(map[S][]namedGuard[S, T]),
+		composites:   make(map[S]*compositeState[S, T]),
 	}
 }
 
+// GuardFailure records a single guard that blocked a transition.
+type GuardFailure struct {
+	Guard  string `json:"guard"`
+	Reason string `json:"reason"`
+	Err    error  `json:"-"`
+}
+
+// GuardRejection is returned when one or more guards block a transition. It
+// lists every failed guard, not only the first.
+type GuardRejection struct {
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Transition string         `json:"transition"`
+	Failures   []GuardFailure `json:"failures"`
+}
+
+// Error implements the error interface.
+func (r *GuardRejection) Error() string {
+	reasons := make([]string, len(r.Failures))
+	for i, f := range r.Failures {
+		reasons[i] = f.Guard + ": " + f.Reason
+	}
+	return fmt.Sprintf("transition %s from %s to %s rejected: %s", r.Transition, r.From, r.To, strings.Join(reasons, "; "))
+}
+
+// Unwrap allows errors.Is(err, ErrInvalidTransition).
+func (r *GuardRejection) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// HookError is returned when an exit or enter hook fails. Hooks that already
+// ran for the transition have been rolled back.
+type HookError struct {
+	Phase string `json:"phase"` // "exit" or "enter"
+	State string `json:"state"`
+	Hook  string `json:"hook"`
+	Err   error  `json:"-"`
+}
+
+// Error implements the error interface.
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook %q on %s failed: %v", e.Phase, e.Hook, e.State, e.Err)
+}
+
+// Unwrap allows errors.Is against ErrHookFailed and the hook's own error.
+func (e *HookError) Unwrap() []error {
+	return []error{ErrHookFailed, e.Err}
+}
+
 // AddTransition adds a valid transition from one state to another.
 func (sm *StateMachine[S, T]) AddTransition(from S, transition T, to S) {
 	sm.mu.Lock()
@@ -72,15 +166,7 @@ func (sm *StateMachine[S, T]) CanTransition(from S, transition T) bool {
 
 	if stateTransitions, ok := sm.transitions[from]; ok {
 		if to, exists := stateTransitions[transition]; exists {
-			// Check guards
-			if guards, hasGuards := sm.guards[transition]; hasGuards {
-				for _, guard := range guards {
-					if !guard(from, to) {
-						return false
-					}
-				}
-			}
-			return true
+			return sm.checkGuards(context.Background(), TransitionEvent[S, T]{From: from, To: to, Transition: transition}) == nil
 		}
 	}
 	return false
@@ -93,14 +179,9 @@ func (sm *StateMachine[S, T]) GetNextState(from S, transition T) (S, error) {
 
 	if stateTransitions, ok := sm.transitions[from]; ok {
 		if to, exists := stateTransitions[transition]; exists {
-			// Check guards
-			if guards, hasGuards := sm.guards[transition]; hasGuards {
-				for _, guard := range guards {
-					if !guard(from, to) {
-						var zero S
-						return zero, ErrInvalidTransition
-					}
-				}
+			if err := sm.checkGuards(context.Background(), TransitionEvent[S, T]{From: from, To: to, Transition: transition}); err != nil {
+				var zero S
+				return zero, err
 			}
 			return to, nil
 		}
@@ -111,23 +192,20 @@ func (sm *StateMachine[S, T]) GetNextState(from S, transition T) (S, error) {
 
 // GetAvailableTransitions returns all valid transitions from a state.
 func (sm *StateMachine[S, T]) GetAvailableTransitions(from S) []T {
+	return sm.AvailableTransitions(context.Background(), from, nil)
+}
+
+// AvailableTransitions returns all transitions from a state whose guards
+// accept the given payload.
+func (sm *StateMachine[S, T]) AvailableTransitions(ctx context.Context, from S, payload any) []T {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
 
 	var result []T
 	if stateTransitions, ok := sm.transitions[from]; ok {
 		for transition, to := range stateTransitions {
-			// Check guards
-			allowed := true
-			if guards, hasGuards := sm.guards[transition]; hasGuards {
-				for _, guard := range guards {
-					if !guard(from, to) {
-						allowed = false
-						break
-					}
-				}
-			}
-			if allowed {
+			event := TransitionEvent[S, T]{From: from, To: to, Transition: transition, Payload: payload}
+			if sm.checkGuards(ctx, event) == nil {
 				result = append(result, transition)
 			}
 		}
@@ -135,25 +213,148 @@ func (sm *StateMachine[S, T]) GetAvailableTransitions(from S) []T {
 	return result
 }
 
+// CanFire checks whether a transition may be fired with the given payload. It
+// returns nil, ErrInvalidTransition, or a *GuardRejection.
+func (sm *StateMachine[S, T]) CanFire(ctx context.Context, from S, transition T, payload any) error {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	to, exists := sm.transitions[from][transition]
+	if !exists {
+		return ErrInvalidTransition
+	}
+	return sm.checkGuards(ctx, TransitionEvent[S, T]{From: from, To: to, Transition: transition, Payload: payload})
+}
+
 // OnEnter registers a callback for when a state is entered.
 func (sm *StateMachine[S, T]) OnEnter(state S, callback func(from S)) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
-	sm.onEnter[state] = append(sm.onEnter[state], callback)
+	sm.onEnter[state] = append(sm.onEnter[state], Hook[S, T]{
+		Name: fmt.Sprintf("enter:%s#%d", state, len(sm.onEnter[state])+1),
+		Run: func(ctx context.Context, event TransitionEvent[S, T]) error {
+			callback(event.From)
+			return nil
+		},
+	})
 }
 
 // OnExit registers a callback for when a state is exited.
 func (sm *StateMachine[S, T]) OnExit(state S, callback func(to S)) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
-	sm.onExit[state] = append(sm.onExit[state], callback)
+	sm.onExit[state] = append(sm.onExit[state], Hook[S, T]{
+		Name: fmt.Sprintf("exit:%s#%d", state, len(sm.onExit[state])+1),
+		Run: func(ctx context.Context, event TransitionEvent[S, T]) error {
+			callback(event.To)
+			return nil
+		},
+	})
+}
+
+// AddEnterHook registers a hook that runs when a state is entered.
+func (sm *StateMachine[S, T]) AddEnterHook(state S, hook Hook[S, T]) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onEnter[state] = append(sm.onEnter[state], hook)
+}
+
+// AddExitHook registers a hook that runs when a state is exited.
+func (sm *StateMachine[S, T]) AddExitHook(state S, hook Hook[S, T]) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onExit[state] = append(sm.onExit[state], hook)
 }
 
 // AddGuard adds a guard condition for a transition.
 func (sm *StateMachine[S, T]) AddGuard(transition T, guard func(from, to S) bool) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
-	sm.guards[transition] = append(sm.guards[transition], guard)
+	sm.guards[transition] = append(sm.guards[transition], namedGuard[S, T]{
+		name: fmt.Sprintf("%s#%d", transition, len(sm.guards[transition])+1),
+		guard: func(ctx context.Context, event TransitionEvent[S, T]) error {
+			if !guard(event.From, event.To) {
+				return ErrGuardFailed
+			}
+			return nil
+		},
+	})
+}
+
+// AddGuardFunc adds a named, context-aware guard for a transition. The guard
+// applies to the transition from every state.
+func (sm *StateMachine[S, T]) AddGuardFunc(transition T, name string, guard GuardFunc[S, T]) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.guards[transition] = append(sm.guards[transition], namedGuard[S, T]{name: name, guard: guard})
+}
+
+// AddStateGuard adds a named guard that only applies to a transition taken
+// from the given state.
+func (sm *StateMachine[S, T]) AddStateGuard(from S, transition T, name string, guard GuardFunc[S, T]) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.stateGuards[from] == nil {
+		sm.stateGuards[from] = make(map[T][]namedGuard[S, T])
+	}
+	sm.stateGuards[from][transition] = append(sm.stateGuards[from][transition], namedGuard[S, T]{name: name, guard: guard})
+}
+
+// AddDestinationGuard adds a named guard that applies to every transition
+// into the given state.
+func (sm *StateMachine[S, T]) AddDestinationGuard(to S, name string, guard GuardFunc[S, T]) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.destGuards[to] = append(sm.destGuards[to], namedGuard[S, T]{name: name, guard: guard})
+}
+
+// GuardNames returns the names of the guards that apply to a transition taken
+// from the given state, in evaluation order.
+func (sm *StateMachine[S, T]) GuardNames(from S, transition T) []string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	to, exists := sm.transitions[from][transition]
+	if !exists {
+		return nil
+	}
+
+	var names []string
+	for _, g := range sm.guardsFor(TransitionEvent[S, T]{From: from, To: to, Transition: transition}) {
+		names = append(names, g.name)
+	}
+	return names
+}
+
+// guardsFor returns the transition, state-scoped and destination guards that
+// apply to an event. Callers must hold the lock.
+func (sm *StateMachine[S, T]) guardsFor(event TransitionEvent[S, T]) []namedGuard[S, T] {
+	var guards []namedGuard[S, T]
+	guards = append(guards, sm.guards[event.Transition]...)
+	guards = append(guards, sm.stateGuards[event.From][event.Transition]...)
+	guards = append(guards, sm.destGuards[event.To]...)
+	return guards
+}
+
+// checkGuards evaluates every applicable guard for a transition and collects
+// failures. Callers must hold the lock.
+func (sm *StateMachine[S, T]) checkGuards(ctx context.Context, event TransitionEvent[S, T]) error {
+	var failures []GuardFailure
+	for _, g := range sm.guardsFor(event) {
+		if err := g.guard(ctx, event); err != nil {
+			failures = append(failures, GuardFailure{Guard: g.name, Reason: err.Error(), Err: err})
+		}
+	}
+
+	if len(failures) > 0 {
+		return &GuardRejection{
+			From:       string(event.From),
+			To:         string(event.To),
+			Transition: string(event.Transition),
+			Failures:   failures,
+		}
+	}
+	return nil
 }
 
 // GetAllStates returns all states that have transitions defined.
@@ -183,48 +384,78 @@ func (sm *StateMachine[S, T]) InitialState() S {
 
 // ExecuteTransition performs a transition and triggers callbacks.
 func (sm *StateMachine[S, T]) ExecuteTransition(currentState S, transition T) (S, error) {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
+	return sm.Fire(context.Background(), currentState, transition, nil)
+}
+
+// Fire performs a transition with a payload. Guards are evaluated first and
+// all failures are reported in a *GuardRejection. Exit hooks of the current
+// state run before enter hooks of the next state; if any hook fails, the
+// hooks that already ran are rolled back in reverse order and a *HookError
+// is returned.
+func (sm *StateMachine[S, T]) Fire(ctx context.Context, currentState S, transition T, payload any) (S, error) {
+	var zero S
 
+	sm.mu.RLock()
 	stateTransitions, ok := sm.transitions[currentState]
 	if !ok {
-		var zero S
+		sm.mu.RUnlock()
 		return zero, ErrNoTransitions
 	}
 
 	nextState, exists := stateTransitions[transition]
 	if !exists {
-		var zero S
+		sm.mu.RUnlock()
 		return zero, ErrInvalidTransition
 	}
 
-	// Check guards
-	if guards, hasGuards := sm.guards[transition]; hasGuards {
-		for _, guard := range guards {
-			if !guard(currentState, nextState) {
-				var zero S
-				return zero, ErrInvalidTransition
+	event := TransitionEvent[S, T]{From: currentState, To: nextState, Transition: transition, Payload: payload}
+	if err := sm.checkGuards(ctx, event); err != nil {
+		sm.mu.RUnlock()
+		return zero, err
+	}
+
+	// Snapshot hooks so they can call back into the machine without deadlocking
+	exitHooks := append([]Hook[S, T](nil), sm.onExit[currentState]...)
+	enterHooks := append([]Hook[S, T](nil), sm.onEnter[nextState]...)
+	sm.mu.RUnlock()
+
+	var completed []Hook[S, T]
+	run := func(phase string, state S, hooks []Hook[S, T]) error {
+		for _, hook := range hooks {
+			if hook.Run == nil {
+				continue
 			}
+			if err := hook.Run(ctx, event); err != nil {
+				rollbackHooks(ctx, event, completed)
+				return &HookError{Phase: phase, State: string(state), Hook: hook.Name, Err: err}
+			}
+			completed = append(completed, hook)
 		}
+		return nil
 	}
 
-	// Execute exit callbacks
-	if exitCallbacks, hasExit := sm.onExit[currentState]; hasExit {
-		for _, callback := range exitCallbacks {
-			callback(nextState)
-		}
+	// Execute exit hooks
+	if err := run("exit", currentState, exitHooks); err != nil {
+		return zero, err
 	}
 
-	// Execute enter callbacks
-	if enterCallbacks, hasEnter := sm.onEnter[nextState]; hasEnter {
-		for _, callback := range enterCallbacks {
-			callback(currentState)
-		}
+	// Execute enter hooks
+	if err := run("enter", nextState, enterHooks); err != nil {
+		return zero, err
 	}
 
 	return nextState, nil
 }
 
+// rollbackHooks runs the compensating actions of completed hooks in reverse order.
+func rollbackHooks[S State, T Transition](ctx context.Context, event TransitionEvent[S, T], completed []Hook[S, T]) {
+	for i := len(completed) - 1; i >= 0; i-- {
+		if completed[i].Rollback != nil {
+			_ = completed[i].Rollback(ctx, event)
+		}
+	}
+}
+
 // Clone creates a copy of the state machine.
 func (sm *StateMachine[S, T]) Clone() *StateMachine[S, T] {
 	sm.mu.RLock()
@@ -238,6 +469,10 @@ func (sm *StateMachine[S, T]) Clone() *StateMachine[S, T] {
 		}
 	}
 
+	for state, c := range sm.composites {
+		clone.composites[state] = &compositeState[S, T]{completion: c.completion, regions: c.regions}
+	}
+
 	// Note: callbacks and guards are not cloned for safety
 
 	return clone
@@ -0,0 +1,190 @@
+package statemachine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testState string
+
+type testTransition string
+
+// recordingHook returns a hook that logs its run and rollback, failing its
+// run when fail is set.
+func recordingHook(name string, log *[]string, fail bool) Hook[testState, testTransition] {
+	return Hook[testState, testTransition]{
+		Name: name,
+		Run: func(ctx context.Context, event TransitionEvent[testState, testTransition]) error {
+			*log = append(*log, "run "+name)
+			if fail {
+				return errors.New(name + " failed")
+			}
+			return nil
+		},
+		Rollback: func(ctx context.Context, event TransitionEvent[testState, testTransition]) error {
+			*log = append(*log, "rollback "+name)
+			return nil
+		},
+	}
+}
+
+func TestFireRollsBackHooksInReverseOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		failing   string
+		wantPhase string
+		wantLog   []string
+	}{
+		{
+			name:    "all hooks succeed",
+			wantLog: []string{"run exit1", "run exit2", "run enter1", "run enter2"},
+		},
+		{
+			name:      "exit hook fails",
+			failing:   "exit2",
+			wantPhase: "exit",
+			wantLog:   []string{"run exit1", "run exit2", "rollback exit1"},
+		},
+		{
+			name:      "enter hook fails",
+			failing:   "enter2",
+			wantPhase: "enter",
+			wantLog:   []string{"run exit1", "run exit2", "run enter1", "run enter2", "rollback enter1", "rollback exit2", "rollback exit1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			sm := New[testState, testTransition]("draft")
+			sm.AddTransition("draft", "submit", "review")
+			for _, name := range []string{"exit1", "exit2"} {
+				sm.AddExitHook("draft", recordingHook(name, &log, name == tt.failing))
+			}
+			for _, name := range []string{"enter1", "enter2"} {
+				sm.AddEnterHook("review", recordingHook(name, &log, name == tt.failing))
+			}
+
+			next, err := sm.Fire(context.Background(), "draft", "submit", nil)
+			if tt.failing == "" {
+				if err != nil || next != "review" {
+					t.Fatalf("Fire = %q, %v, want review", next, err)
+				}
+			} else {
+				var hookErr *HookError
+				if !errors.As(err, &hookErr) || !errors.Is(err, ErrHookFailed) {
+					t.Fatalf("expected a *HookError, got %v", err)
+				}
+				if hookErr.Phase != tt.wantPhase || hookErr.Hook != tt.failing {
+					t.Errorf("got %s hook %q, want %s hook %q", hookErr.Phase, hookErr.Hook, tt.wantPhase, tt.failing)
+				}
+			}
+			if !reflect.DeepEqual(log, tt.wantLog) {
+				t.Errorf("hooks ran as %v, want %v", log, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestGuardRejectionListsEveryFailure(t *testing.T) {
+	reject := func(reason string) GuardFunc[testState, testTransition] {
+		return func(ctx context.Context, event TransitionEvent[testState, testTransition]) error {
+			if event.Payload == "allow" {
+				return nil
+			}
+			return errors.New(reason)
+		}
+	}
+
+	sm := New[testState, testTransition]("draft")
+	sm.AddTransition("draft", "submit", "review")
+	sm.AddTransition("returned", "submit", "review")
+	sm.AddTransition("draft", "withdraw", "withdrawn")
+	sm.AddGuardFunc("submit", "complete", reject("sections missing"))
+	sm.AddStateGuard("returned", "submit", "comments-addressed", reject("open comments"))
+	sm.AddDestinationGuard("review", "reviewer-assigned", reject("no reviewer"))
+	sm.AddGuard("withdraw", func(from, to testState) bool { return false })
+
+	tests := []struct {
+		name         string
+		from         testState
+		transition   testTransition
+		payload      any
+		wantGuards   []string
+		wantReasons  []string
+		wantNoReject bool
+	}{
+		{
+			name:        "transition and destination guards",
+			from:        "draft",
+			transition:  "submit",
+			wantGuards:  []string{"complete", "reviewer-assigned"},
+			wantReasons: []string{"sections missing", "no reviewer"},
+		},
+		{
+			name:        "state-scoped guard applies only from its state",
+			from:        "returned",
+			transition:  "submit",
+			wantGuards:  []string{"complete", "comments-addressed", "reviewer-assigned"},
+			wantReasons: []string{"sections missing", "open comments", "no reviewer"},
+		},
+		{
+			name:        "boolean guard",
+			from:        "draft",
+			transition:  "withdraw",
+			wantGuards:  []string{"withdraw#1"},
+			wantReasons: []string{ErrGuardFailed.Error()},
+		},
+		{
+			name:         "guards accept the payload",
+			from:         "returned",
+			transition:   "submit",
+			payload:      "allow",
+			wantNoReject: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sm.CanFire(context.Background(), tt.from, tt.transition, tt.payload)
+			if tt.wantNoReject {
+				if err != nil {
+					t.Fatalf("unexpected rejection: %v", err)
+				}
+				return
+			}
+
+			var rejection *GuardRejection
+			if !errors.As(err, &rejection) {
+				t.Fatalf("expected a *GuardRejection, got %v", err)
+			}
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Error("expected the rejection to match ErrInvalidTransition")
+			}
+			if rejection.From != string(tt.from) || rejection.Transition != string(tt.transition) {
+				t.Errorf("rejection is for %s from %s", rejection.Transition, rejection.From)
+			}
+
+			var guards, reasons []string
+			for _, f := range rejection.Failures {
+				guards = append(guards, f.Guard)
+				reasons = append(reasons, f.Reason)
+			}
+			if !reflect.DeepEqual(guards, tt.wantGuards) {
+				t.Errorf("failed guards %v, want %v", guards, tt.wantGuards)
+			}
+			if !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("reasons %v, want %v", reasons, tt.wantReasons)
+			}
+			if names := sm.GuardNames(tt.from, tt.transition); !reflect.DeepEqual(names, tt.wantGuards) {
+				t.Errorf("GuardNames = %v, want %v", names, tt.wantGuards)
+			}
+
+			if _, err := sm.Fire(context.Background(), tt.from, tt.transition, tt.payload); !errors.As(err, &rejection) {
+				t.Errorf("expected Fire to be rejected, got %v", err)
+			}
+		})
+	}
+}